@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	pkgApp "github.com/mateusmacedo/go-bff/pkg/application"
+	"github.com/mateusmacedo/go-bff/pkg/config"
+	zapAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/zaplogger/adapter"
+)
+
+type command struct {
+	name    string
+	args    string
+	summary string
+	run     func(ctx context.Context, cfg *config.Config, appLogger pkgApp.AppLogger, args []string) error
+}
+
+var commands = []command{
+	{name: "serve", summary: "serve the HTTP API", run: runServe},
+	{name: "worker", summary: "consume and execute messages without serving HTTP", run: runWorker},
+	{name: "migrate", args: "up | down [n] | status", summary: "apply, roll back or list schema migrations", run: runMigrate},
+	{name: "routes", summary: "print the HTTP routes and the message routing table", run: runRoutes},
+	{name: "version", summary: "print the build version"},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	name := os.Args[1]
+	switch name {
+	case "help", "-h", "-help", "--help":
+		usage()
+		return
+	case "version":
+		printVersion()
+		return
+	}
+
+	var selected *command
+	for i := range commands {
+		if commands[i].name == name {
+			selected = &commands[i]
+		}
+	}
+	if selected == nil {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+		usage()
+		os.Exit(2)
+	}
+
+	// Positional arguments precede the configuration flags.
+	args := os.Args[2:]
+	positional := 0
+	for positional < len(args) && !strings.HasPrefix(args[positional], "-") {
+		positional++
+	}
+	if positional > 0 && selected.args == "" {
+		fmt.Fprintf(os.Stderr, "command %q takes no arguments\n\n", name)
+		usage()
+		os.Exit(2)
+	}
+
+	cfg := config.MustLoad("bff "+name, args[positional:])
+
+	appLogger, err := zapAdapter.NewZapAppLogger()
+	if err != nil {
+		panic(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go handleShutdown(ctx, cancel, appLogger)
+
+	if err := selected.run(ctx, cfg, appLogger, args[:positional]); err != nil {
+		appLogger.Error(ctx, "Erro ao executar comando", map[string]interface{}{"command": name, "error": err})
+		cancel()
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: bff <command> [arguments] [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "commands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", c.name, c.summary)
+		if c.args != "" {
+			fmt.Fprintf(os.Stderr, "  %-8s   bff %s %s\n", "", c.name, c.args)
+		}
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, `run "bff <command> -h" for the configuration flags`)
+}
+
+func handleShutdown(ctx context.Context, cancel context.CancelFunc, appLogger pkgApp.AppLogger) {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	select {
+	case sig := <-sigChan:
+		appLogger.Info(ctx, "Sinal capturado", map[string]interface{}{"signal": sig})
+		cancel()
+	case <-ctx.Done():
+	}
+	signal.Stop(sigChan)
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mateusmacedo/go-bff/internal/busticket/infrastructure"
+	pkgApp "github.com/mateusmacedo/go-bff/pkg/application"
+	"github.com/mateusmacedo/go-bff/pkg/config"
+	sqlAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/sql/adapter"
+)
+
+func runMigrate(ctx context.Context, cfg *config.Config, appLogger pkgApp.AppLogger, args []string) error {
+	if len(args) == 0 {
+		return errors.New(`missing migrate action: up, down [n] or status`)
+	}
+	if cfg.Repository == "memory" || cfg.Repository == "redis" {
+		appLogger.Info(ctx, "Repositório sem esquema para migrar", map[string]interface{}{"repository": cfg.Repository})
+		return nil
+	}
+
+	migrator, db, err := newMigrator(cfg, appLogger)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	switch action := args[0]; {
+	case action == "up" && len(args) == 1:
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		appLogger.Info(ctx, "Migração concluída", map[string]interface{}{"applied": applied})
+	case action == "down" && len(args) <= 2:
+		steps := 1
+		if len(args) == 2 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps <= 0 {
+				return fmt.Errorf("invalid number of migrations to roll back %q", args[1])
+			}
+		}
+		rolledBack, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		appLogger.Info(ctx, "Migrações revertidas", map[string]interface{}{"rolledBack": rolledBack})
+	case action == "status" && len(args) == 1:
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		printMigrationStatus(statuses)
+	default:
+		return fmt.Errorf("invalid migrate arguments %q: want up, down [n] or status", args)
+	}
+	return nil
+}
+
+func newMigrator(cfg *config.Config, appLogger pkgApp.AppLogger) (*sqlAdapter.Migrator, *sql.DB, error) {
+	db, dialect, err := openDatabase(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	migrations, err := infrastructure.BusTicketMigrations(dialect)
+	if err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+	return sqlAdapter.NewMigrator(db, dialect, migrations, appLogger), db, nil
+}
+
+// migrateUp applies pending migrations on the repository's own connection,
+// which an in-memory SQLite database requires.
+func migrateUp(db *sql.DB, dialect sqlAdapter.Dialect, appLogger pkgApp.AppLogger) error {
+	migrations, err := infrastructure.BusTicketMigrations(dialect)
+	if err != nil {
+		return err
+	}
+
+	_, err = sqlAdapter.NewMigrator(db, dialect, migrations, appLogger).Up(context.Background())
+	return err
+}
+
+func printMigrationStatus(statuses []sqlAdapter.MigrationStatus) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, status := range statuses {
+		state, appliedAt := "pending", ""
+		if status.Applied {
+			state, appliedAt = "applied", status.AppliedAt.Format(time.RFC3339)
+		}
+		if status.Unknown {
+			state = "applied (unknown to this binary)"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+	}
+	w.Flush()
+}
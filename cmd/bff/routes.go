@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/mateusmacedo/go-bff/internal/busticket"
+	"github.com/mateusmacedo/go-bff/internal/busticket/infrastructure"
+	pkgApp "github.com/mateusmacedo/go-bff/pkg/application"
+	"github.com/mateusmacedo/go-bff/pkg/config"
+	pkgInfra "github.com/mateusmacedo/go-bff/pkg/infrastructure"
+	chiAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/chi/adapter"
+)
+
+// runRoutes prints the routes serve would expose, building the slice on
+// in-process buses so that no broker or database is needed.
+func runRoutes(ctx context.Context, cfg *config.Config, appLogger pkgApp.AppLogger, _ []string) error {
+	var routingTable *pkgInfra.RoutingTable
+	if cfg.Transport == "routed" {
+		table, err := loadRoutingTable(cfg.Routing.TableFile)
+		if err != nil {
+			return fmt.Errorf("loading routing table: %w", err)
+		}
+		routingTable = &table
+	}
+
+	busTickets := infrastructure.NewInMemoryBusTicketRepository(appLogger)
+	slice, err := busticket.NewBusTicketSlice(newLocalBuses(appLogger), uuid.NewString, appLogger, busticket.Repositories{
+		BusTickets: busTickets,
+		Routes:     infrastructure.NewInMemoryRouteRepository(appLogger),
+		Trips:      infrastructure.NewInMemoryTripRepository(appLogger),
+		SeatHolds:  infrastructure.NewInMemorySeatHoldRepository(busTickets, time.Now, appLogger),
+	})
+	if err != nil {
+		return err
+	}
+	router := chi.NewRouter()
+	chiAdapter.RegisterHealthRoutes(router, nil, cfg.Health.Timeout, appLogger)
+	registerRoutes(router, slice, routingTable)
+
+	err = chi.Walk(router, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		fmt.Printf("%-7s %s\n", method, route)
+		return nil
+	})
+	if err != nil || routingTable == nil {
+		return err
+	}
+
+	fmt.Println()
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(routingTable)
+}
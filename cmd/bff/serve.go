@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mateusmacedo/go-bff/internal/busticket"
+	"github.com/mateusmacedo/go-bff/internal/busticket/application"
+	"github.com/mateusmacedo/go-bff/internal/busticket/domain"
+	"github.com/mateusmacedo/go-bff/internal/busticket/infrastructure"
+	pkgApp "github.com/mateusmacedo/go-bff/pkg/application"
+	"github.com/mateusmacedo/go-bff/pkg/config"
+	pkgInfra "github.com/mateusmacedo/go-bff/pkg/infrastructure"
+	redisAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/redis/adapter"
+	sqlAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/sql/adapter"
+)
+
+func runServe(ctx context.Context, cfg *config.Config, appLogger pkgApp.AppLogger, _ []string) error {
+	// With gRPC the handlers run in the worker, so serve only dispatches.
+	role := busticket.RoleAll
+	if cfg.Role == "api" || cfg.Transport == "grpc" {
+		role = busticket.RoleAPI
+	}
+
+	var (
+		db      *sql.DB
+		dialect sqlAdapter.Dialect
+		err     error
+	)
+	if role == busticket.RoleAll || cfg.Transport == "sql" {
+		if db, dialect, err = openSharedDatabase(cfg); err != nil {
+			return err
+		}
+		if db != nil {
+			defer db.Close()
+		}
+	}
+
+	transport, err := openTransport(cfg, db, dialect, appLogger)
+	if err != nil {
+		return err
+	}
+	defer closeTransport(ctx, transport, appLogger)
+
+	buses, err := newSliceBuses(transport)
+	if err != nil {
+		return err
+	}
+
+	var repositories busticket.Repositories
+	if role == busticket.RoleAll {
+		if repositories, err = newRepositories(cfg, db, dialect, appLogger); err != nil {
+			return err
+		}
+	}
+
+	options := sliceOptions(cfg, db, busticket.WithRole(role))
+	if transport.queuesCommand("ReserveBusTicket") {
+		options = append(options, busticket.WithAsynchronousReservations())
+	}
+	slice, err := busticket.NewBusTicketSlice(buses, uuid.NewString, appLogger, repositories, options...)
+	if err != nil {
+		return err
+	}
+	if err := transport.startBridge(buses); err != nil {
+		return err
+	}
+	if role == busticket.RoleAll {
+		go releaseExpiredSeatHolds(ctx, cfg, buses, appLogger)
+	}
+	healthChecks := append(transport.healthChecks, slice.HealthChecks()...)
+	router := newRouter(ctx, cfg, appLogger, slice, transport.routingTable, healthChecks)
+
+	server := &http.Server{Addr: cfg.HTTP.Address, Handler: router}
+	return runHTTPServer(ctx, server, appLogger, cfg.HTTP.ShutdownTimeout)
+}
+
+// sliceOptions adds the options every process takes from cfg to options.
+// With the sql transport, handlers run in transactions on db so that their
+// events commit with their writes.
+func sliceOptions(cfg *config.Config, db *sql.DB, options ...busticket.SliceOption) []busticket.SliceOption {
+	options = append(options, conflictRetry(cfg), refundPolicy(cfg), seatHoldTTL(cfg))
+	if cfg.Transport == "sql" {
+		options = append(options, busticket.WithTransactions(db))
+	}
+	return options
+}
+
+// conflictRetry retries commands that fail on a concurrent update of a
+// ticket, up to commands.conflict_retries times.
+func conflictRetry(cfg *config.Config) busticket.SliceOption {
+	return busticket.WithConflictRetry(pkgInfra.RetryPolicy{
+		MaxAttempts: cfg.Commands.ConflictRetries + 1,
+		Backoff:     cfg.Commands.ConflictBackoff,
+	})
+}
+
+// refundPolicy refunds cancelled tickets as the cancellation section says,
+// whose fee tiers Validate has already checked.
+func refundPolicy(cfg *config.Config) busticket.SliceOption {
+	tiers, _ := cfg.Cancellation.ParseFeeTiers()
+	policy := domain.RefundPolicy{FullRefundNotice: cfg.Cancellation.FullRefundNotice}
+	for _, tier := range tiers {
+		policy.FeeTiers = append(policy.FeeTiers, domain.RefundFeeTier{Notice: tier.Notice, FeePercent: tier.FeePercent})
+	}
+	return busticket.WithRefundPolicy(policy)
+}
+
+// seatHoldTTL holds seats for seat_holds.ttl until they are confirmed.
+func seatHoldTTL(cfg *config.Config) busticket.SliceOption {
+	return busticket.WithSeatHoldTTL(cfg.SeatHolds.TTL)
+}
+
+// releaseExpiredSeatHolds dispatches ReleaseExpiredSeatHolds every
+// seat_holds.release_interval until ctx is done. Every process that executes
+// messages dispatches its own; concurrent releases do not repeat events.
+func releaseExpiredSeatHolds(ctx context.Context, cfg *config.Config, buses busticket.Buses, appLogger pkgApp.AppLogger) {
+	ticker := time.NewTicker(cfg.SeatHolds.ReleaseInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := buses.ReleaseExpiredSeatHolds.Dispatch(ctx, application.NewReleaseExpiredSeatHoldsCommand())
+			if err != nil && ctx.Err() == nil {
+				appLogger.Error(ctx, "Erro ao liberar reservas temporárias expiradas", map[string]interface{}{"error": err})
+			}
+		}
+	}
+}
+
+// newRepositories builds the repositories cfg selects; the postgres and
+// sqlite ones use db, opened by openSharedDatabase.
+func newRepositories(cfg *config.Config, db *sql.DB, dialect sqlAdapter.Dialect, appLogger pkgApp.AppLogger) (busticket.Repositories, error) {
+	switch cfg.Repository {
+	case "memory":
+		busTickets := infrastructure.NewInMemoryBusTicketRepository(appLogger)
+		return busticket.Repositories{
+			BusTickets: busTickets,
+			Routes:     infrastructure.NewInMemoryRouteRepository(appLogger),
+			Trips:      infrastructure.NewInMemoryTripRepository(appLogger),
+			SeatHolds:  infrastructure.NewInMemorySeatHoldRepository(busTickets, time.Now, appLogger),
+		}, nil
+	case "postgres", "sqlite":
+		if cfg.Database.AutoMigrate {
+			if err := migrateUp(db, dialect, appLogger); err != nil {
+				return busticket.Repositories{}, fmt.Errorf("migrating schema: %w", err)
+			}
+		}
+		var (
+			repositories busticket.Repositories
+			errs         [4]error
+		)
+		repositories.BusTickets, errs[0] = infrastructure.NewGormBusTicketRepository(db, dialect, time.Now, appLogger)
+		repositories.Routes, errs[1] = infrastructure.NewGormRouteRepository(db, dialect, appLogger)
+		repositories.Trips, errs[2] = infrastructure.NewGormTripRepository(db, dialect, appLogger)
+		repositories.SeatHolds, errs[3] = infrastructure.NewGormSeatHoldRepository(db, dialect, time.Now, appLogger)
+		if err := errors.Join(errs[:]...); err != nil {
+			return busticket.Repositories{}, fmt.Errorf("initializing repository: %w", err)
+		}
+		return repositories, nil
+	case "redis":
+		client := redisAdapter.NewRedisClient(redisAdapter.RedisClientConfig{
+			Addr:     cfg.Redis.Address,
+			Password: cfg.Redis.Password.Value(),
+			DB:       cfg.Redis.DB,
+		})
+		repositoryConfig := infrastructure.RedisBusTicketRepositoryConfig{
+			Retention: cfg.Redis.TicketRetention,
+		}
+		return busticket.Repositories{
+			BusTickets: infrastructure.NewRedisBusTicketRepository(client, repositoryConfig, time.Now, appLogger),
+			Routes:     infrastructure.NewRedisRouteRepository(client, repositoryConfig, appLogger),
+			Trips:      infrastructure.NewRedisTripRepository(client, repositoryConfig, appLogger),
+			SeatHolds:  infrastructure.NewRedisSeatHoldRepository(client, repositoryConfig, time.Now, appLogger),
+		}, nil
+	default:
+		return busticket.Repositories{}, fmt.Errorf("unknown repository %q", cfg.Repository)
+	}
+}
+
+// openDatabase opens the database behind the postgres and sqlite
+// repositories.
+func openDatabase(cfg *config.Config) (*sql.DB, sqlAdapter.Dialect, error) {
+	if cfg.Repository == "sqlite" {
+		db, err := sqlAdapter.NewSQLiteDB(cfg.Database.SQLitePath)
+		return db, sqlAdapter.SQLiteDialect{}, err
+	}
+	db, err := sqlAdapter.NewPostgresDB(cfg.Database.ConnectionString())
+	return db, sqlAdapter.PostgresDialect{}, err
+}
+
+// openSharedDatabase opens the database of the postgres and sqlite
+// repositories once, for them and the sql transport; db is nil for the
+// other repositories.
+func openSharedDatabase(cfg *config.Config) (*sql.DB, sqlAdapter.Dialect, error) {
+	if cfg.Repository != "postgres" && cfg.Repository != "sqlite" {
+		return nil, nil, nil
+	}
+	db, dialect, err := openDatabase(cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("connecting to the database: %w", err)
+	}
+	return db, dialect, nil
+}
+
+func closeTransport(ctx context.Context, transport *transport, appLogger pkgApp.AppLogger) {
+	if err := transport.Close(); err != nil {
+		appLogger.Error(ctx, "Erro ao encerrar transporte", map[string]interface{}{"error": err})
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/mateusmacedo/go-bff/internal/busticket"
+	"github.com/mateusmacedo/go-bff/internal/busticket/application"
+	"github.com/mateusmacedo/go-bff/internal/busticket/infrastructure"
+	pkgApp "github.com/mateusmacedo/go-bff/pkg/application"
+	"github.com/mateusmacedo/go-bff/pkg/config"
+	pkgInfra "github.com/mateusmacedo/go-bff/pkg/infrastructure"
+	chiAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/chi/adapter"
+	jwtAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/jwt/adapter"
+)
+
+// newRouter keeps the health routes outside authentication so that probes
+// need no credentials.
+func newRouter(ctx context.Context, cfg *config.Config, appLogger pkgApp.AppLogger, slice *busticket.BusTicketSlice, routingTable *pkgInfra.RoutingTable, healthChecks []pkgApp.HealthCheck) *chi.Mux {
+	router := chi.NewRouter()
+	chiAdapter.RegisterHealthRoutes(router, healthChecks, cfg.Health.Timeout, appLogger)
+	router.Group(func(router chi.Router) {
+		router.Use(newAuthenticationMiddleware(ctx, cfg.Auth, appLogger))
+		router.Use(chiAdapter.ResolveTenant([]string{application.RoleAdmin}, chiAdapter.TenantFromPrincipal(), chiAdapter.TenantFromHeader(chiAdapter.TenantIDHeader)))
+		registerRoutes(router, slice, routingTable)
+	})
+	return router
+}
+
+func newHealthServer(cfg *config.Config, appLogger pkgApp.AppLogger, healthChecks []pkgApp.HealthCheck) *http.Server {
+	router := chi.NewRouter()
+	chiAdapter.RegisterHealthRoutes(router, healthChecks, cfg.Health.Timeout, appLogger)
+	return &http.Server{Addr: cfg.Health.Address, Handler: router}
+}
+
+func registerRoutes(router chi.Router, slice *busticket.BusTicketSlice, routingTable *pkgInfra.RoutingTable) {
+	slice.RegisterRoutes(router,
+		infrastructure.WithRouteRequirements(infrastructure.ReserveBusTicketRoute, chiAdapter.RequireAuthenticated),
+		infrastructure.WithRouteRequirements(infrastructure.CancelBusTicketRoute, chiAdapter.RequireAuthenticated),
+		infrastructure.WithRouteRequirements(infrastructure.GetBusTicketByIDRoute, chiAdapter.RequireAuthenticated),
+		infrastructure.WithRouteRequirements(infrastructure.SearchBusTicketsRoute, chiAdapter.RequireAuthenticated),
+		infrastructure.WithRouteRequirements(infrastructure.CreateRouteRoute, chiAdapter.RequireAuthenticated),
+		infrastructure.WithRouteRequirements(infrastructure.GetRouteByIDRoute, chiAdapter.RequireAuthenticated),
+		infrastructure.WithRouteRequirements(infrastructure.CreateTripRoute, chiAdapter.RequireAuthenticated),
+		infrastructure.WithRouteRequirements(infrastructure.GetTripByIDRoute, chiAdapter.RequireAuthenticated),
+		infrastructure.WithRouteRequirements(infrastructure.CancelTripRoute, chiAdapter.RequireAuthenticated),
+		infrastructure.WithRouteRequirements(infrastructure.DelayTripRoute, chiAdapter.RequireAuthenticated),
+		infrastructure.WithRouteRequirements(infrastructure.HoldSeatRoute, chiAdapter.RequireAuthenticated),
+		infrastructure.WithRouteRequirements(infrastructure.ConfirmReservationRoute, chiAdapter.RequireAuthenticated),
+	)
+	if routingTable != nil {
+		router.With(chiAdapter.RequireAnyRole(application.RoleAdmin)).Get("/internal/routes", chiAdapter.RoutingTableHandler(*routingTable))
+	}
+}
+
+func newAuthenticationMiddleware(ctx context.Context, auth config.AuthConfig, appLogger pkgApp.AppLogger) func(http.Handler) http.Handler {
+	var keys jwtAdapter.KeyProvider
+	switch {
+	case auth.JWKSURL != "":
+		keys = jwtAdapter.NewURLJWKSProvider(auth.JWKSURL, nil, auth.JWKSRefresh, appLogger)
+	case auth.JWKSFile != "":
+		keys = jwtAdapter.NewFileJWKSProvider(auth.JWKSFile, auth.JWKSRefresh, appLogger)
+	case auth.TrustHeaders:
+		appLogger.Error(ctx, "WARNING: auth.trust_headers is enabled, any client can impersonate any principal; use it only in development", nil)
+		return chiAdapter.PrincipalFromHeaders
+	default:
+		// Without a verified identity source no request is authenticated, so
+		// every protected route answers 401.
+		appLogger.Error(ctx, "JWKS não configurado, nenhuma requisição será autenticada", nil)
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	authenticator := jwtAdapter.NewAuthenticator(keys, jwtAdapter.AuthenticatorConfig{
+		Issuer:   auth.Issuer,
+		Audience: auth.Audience,
+		ClaimsMapping: jwtAdapter.ClaimsMapping{
+			SubjectClaim: "sub",
+			RolesClaim:   "roles",
+			AttributeClaims: map[string]string{
+				"name":   application.PassengerNameAttribute,
+				"tenant": pkgApp.TenantAttribute,
+			},
+		},
+	})
+	return jwtAdapter.Authenticate(authenticator, appLogger)
+}
+
+// runHTTPServer serves until ctx is done and then shuts the server down,
+// giving in-flight requests up to timeout to finish.
+func runHTTPServer(ctx context.Context, server *http.Server, appLogger pkgApp.AppLogger, timeout time.Duration) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		appLogger.Info(ctx, "Server starting on:"+server.Addr, nil)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			serveErr <- err
+		}
+	}()
+
+	select {
+	case err := <-serveErr:
+		appLogger.Error(ctx, "Erro ao iniciar o servidor", map[string]interface{}{"error": err})
+		return err
+	case <-ctx.Done():
+	}
+
+	appLogger.Info(context.Background(), "Encerrando servidor...", nil)
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), timeout)
+	defer shutdownCancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		appLogger.Error(context.Background(), "Erro ao encerrar servidor", map[string]interface{}{"error": err})
+		return err
+	}
+	appLogger.Info(context.Background(), "Servidor encerrado", nil)
+	return nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mateusmacedo/go-bff/pkg/config"
+	chiAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/chi/adapter"
+	"github.com/mateusmacedo/go-bff/pkg/testkit"
+)
+
+func TestAuthenticationMiddlewareTrustHeaders(t *testing.T) {
+	tests := []struct {
+		name   string
+		auth   config.AuthConfig
+		status int
+	}{
+		{name: "ignored by default", auth: config.Default().Auth, status: http.StatusUnauthorized},
+		{name: "ignored when disabled", auth: config.AuthConfig{TrustHeaders: false}, status: http.StatusUnauthorized},
+		{name: "trusted when enabled", auth: config.AuthConfig{TrustHeaders: true}, status: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authenticate := newAuthenticationMiddleware(context.Background(), tt.auth, testkit.NewLogger(t))
+			handler := authenticate(chiAdapter.RequireAuthenticated(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})))
+
+			r := httptest.NewRequest(http.MethodGet, "/trips/t1", nil)
+			r.Header.Set(chiAdapter.PrincipalIDHeader, "root")
+			r.Header.Set(chiAdapter.PrincipalRolesHeader, "admin")
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.status {
+				t.Fatalf("request with principal headers = %d, want %d", w.Code, tt.status)
+			}
+		})
+	}
+}
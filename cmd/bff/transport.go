@@ -0,0 +1,562 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill-kafka/v2/pkg/kafka"
+	wmnats "github.com/ThreeDotsLabs/watermill-nats/v2/pkg/nats"
+	"github.com/ThreeDotsLabs/watermill-redisstream/pkg/redisstream"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/pubsub/gochannel"
+	"github.com/nats-io/nats.go"
+	"google.golang.org/grpc"
+
+	"github.com/mateusmacedo/go-bff/internal/busticket"
+	"github.com/mateusmacedo/go-bff/internal/busticket/application"
+	"github.com/mateusmacedo/go-bff/internal/busticket/domain"
+	pkgApp "github.com/mateusmacedo/go-bff/pkg/application"
+	"github.com/mateusmacedo/go-bff/pkg/config"
+	pkgDomain "github.com/mateusmacedo/go-bff/pkg/domain"
+	pkgInfra "github.com/mateusmacedo/go-bff/pkg/infrastructure"
+	boltAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/bolt/adapter"
+	channelsAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/channels/adapter"
+	grpcAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/grpc/adapter"
+	kafkaAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/kafka/adapter"
+	natsAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/nats/adapter"
+	redisAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/redis/adapter"
+	sqlAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/sql/adapter"
+	watermillLogAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/watermill/adapter"
+)
+
+// transport holds the connections behind the selected transport, closed in
+// reverse order of creation, and builds the slice's buses on top of them.
+type transport struct {
+	kind      string
+	appLogger pkgApp.AppLogger
+
+	pubSub          *gochannel.GoChannel
+	kafkaPublisher  *kafka.Publisher
+	kafkaSubscriber *kafka.Subscriber
+	redisPublisher  *redisstream.Publisher
+	redisSubscriber *redisstream.Subscriber
+	natsConn        *nats.Conn
+	natsPublisher   *wmnats.Publisher
+	natsSubscriber  *wmnats.Subscriber
+	natsQueueGroup  string
+	sqlPublisher    *sqlAdapter.Publisher
+	sqlSubscriber   *sqlAdapter.Subscriber
+	boltPublisher   *boltAdapter.Publisher
+	boltSubscriber  *boltAdapter.Subscriber
+	grpcConn        *grpc.ClientConn
+
+	// bridge connects the in-process buses of the memory transport to the
+	// broker of bridgePublisher and bridgeSubscriber.
+	bridge           config.BridgeConfig
+	bridgePublisher  message.Publisher
+	bridgeSubscriber message.Subscriber
+
+	routingTable *pkgInfra.RoutingTable
+	healthChecks []pkgApp.HealthCheck
+	closers      []func() error
+}
+
+// routedBackends are the transports a routing table may send messages to.
+var routedBackends = []string{"local", "kafka", "redis"}
+
+func (t *transport) onClose(closer func() error) {
+	t.closers = append(t.closers, closer)
+}
+
+// track closes bus together with the transport when it holds resources of
+// its own, such as subscriptions.
+func (t *transport) track(bus interface{}) {
+	if closer, ok := bus.(io.Closer); ok {
+		t.onClose(closer.Close)
+	}
+}
+
+// queuesCommand reports whether dispatching commandName returns once the
+// command is queued rather than after its handler ran: only the in-process
+// buses and gRPC wait for the handler.
+func (t *transport) queuesCommand(commandName string) bool {
+	switch t.kind {
+	case "memory", "grpc":
+		return false
+	case "routed":
+		return t.routingTable.CommandBackend(commandName) != "local"
+	default:
+		return true
+	}
+}
+
+func (t *transport) Close() error {
+	var errs []error
+	for i := len(t.closers) - 1; i >= 0; i-- {
+		if err := t.closers[i](); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	t.closers = nil
+	return errors.Join(errs...)
+}
+
+// openTransport opens the transport cfg selects; the sql transport uses db,
+// the database of the repositories, which it does not close.
+func openTransport(cfg *config.Config, db *sql.DB, dialect sqlAdapter.Dialect, appLogger pkgApp.AppLogger) (*transport, error) {
+	t := &transport{kind: cfg.Transport, appLogger: appLogger}
+	if err := t.open(cfg, db, dialect); err != nil {
+		t.Close()
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *transport) open(cfg *config.Config, db *sql.DB, dialect sqlAdapter.Dialect) error {
+	logger := watermillLogAdapter.NewWatermillLoggerAdapter(t.appLogger)
+
+	switch cfg.Transport {
+	case "memory":
+		return t.openBridge(cfg, logger)
+
+	case "channels":
+		t.pubSub = gochannel.NewGoChannel(gochannel.Config{}, logger)
+		t.onClose(t.pubSub.Close)
+		return nil
+
+	case "kafka":
+		return t.openKafka(cfg, logger)
+
+	case "redis":
+		return t.openRedis(cfg, logger)
+
+	case "nats":
+		return t.openNATS(cfg, logger)
+
+	case "sql":
+		if db == nil {
+			return fmt.Errorf("transport sql needs the postgres or sqlite repository, not %q", cfg.Repository)
+		}
+		t.healthChecks = append(t.healthChecks, sqlAdapter.NewDBHealthCheck("sql", db))
+
+		var err error
+		if t.sqlPublisher, err = sqlAdapter.NewPublisher(db, dialect, logger); err != nil {
+			return fmt.Errorf("creating SQL publisher: %w", err)
+		}
+		t.onClose(t.sqlPublisher.Close)
+
+		subscriberConfig := sqlAdapter.SubscriberConfig{
+			ConsumerGroup: cfg.SQL.ConsumerGroup,
+			PollInterval:  cfg.SQL.PollInterval,
+			ClaimTimeout:  cfg.SQL.ClaimTimeout,
+		}
+		// SQLite has no notifications, so its subscribers only poll.
+		if _, ok := dialect.(sqlAdapter.PostgresDialect); ok {
+			subscriberConfig.Listener = sqlAdapter.NewPostgresListener(cfg.Database.ConnectionString(), logger)
+		}
+		t.sqlSubscriber, err = sqlAdapter.NewSubscriber(db, dialect, subscriberConfig, logger)
+		if err != nil {
+			return fmt.Errorf("creating SQL subscriber: %w", err)
+		}
+		t.onClose(t.sqlSubscriber.Close)
+		return nil
+
+	case "bolt":
+		syncPolicy := boltAdapter.SyncInterval
+		if cfg.Bolt.SyncInterval == 0 {
+			syncPolicy = boltAdapter.SyncAlways
+		}
+		store, err := boltAdapter.OpenStore(boltAdapter.StoreConfig{
+			Path:            cfg.Bolt.Path,
+			Sync:            syncPolicy,
+			SyncInterval:    cfg.Bolt.SyncInterval,
+			Retention:       cfg.Bolt.Retention,
+			CompactInterval: cfg.Bolt.CompactInterval,
+		}, logger)
+		if err != nil {
+			return fmt.Errorf("opening bolt store: %w", err)
+		}
+		t.onClose(store.Close)
+
+		t.boltPublisher = boltAdapter.NewPublisher(store)
+		t.boltSubscriber = boltAdapter.NewSubscriber(store, boltAdapter.SubscriberConfig{
+			ConsumerGroup: cfg.Bolt.ConsumerGroup,
+		})
+		t.onClose(t.boltSubscriber.Close)
+		return nil
+
+	case "grpc":
+		clientConfig := grpcAdapter.ClientConfig{Target: cfg.GRPC.Target, Token: cfg.GRPC.Token.Value()}
+		if cfg.GRPC.TLS() {
+			credentials, err := grpcAdapter.NewClientTLS(grpcTLSConfig(cfg.GRPC))
+			if err != nil {
+				return err
+			}
+			clientConfig.Credentials = credentials
+		}
+		conn, err := grpcAdapter.NewClientConn(clientConfig)
+		if err != nil {
+			return fmt.Errorf("creating gRPC connection: %w", err)
+		}
+		t.onClose(conn.Close)
+		t.grpcConn = conn
+		t.healthChecks = append(t.healthChecks, grpcAdapter.NewConnHealthCheck(conn))
+		return nil
+
+	case "routed":
+		// Routes each message through the in-process, Kafka or Redis buses
+		// according to the routing table.
+		routingTable, err := loadRoutingTable(cfg.Routing.TableFile)
+		if err != nil {
+			return fmt.Errorf("loading routing table: %w", err)
+		}
+		if err := routingTable.Validate(routedBackends); err != nil {
+			return fmt.Errorf("loading routing table: %w", err)
+		}
+		t.routingTable = &routingTable
+
+		// Only the brokers the table sends messages to are opened and
+		// checked for readiness.
+		for _, backend := range routingTable.Backends() {
+			switch backend {
+			case "kafka":
+				err = t.openKafka(cfg, logger)
+			case "redis":
+				err = t.openRedis(cfg, logger)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown transport %q", cfg.Transport)
+	}
+}
+
+func (t *transport) openKafka(cfg *config.Config, logger watermill.LoggerAdapter) error {
+	kafkaConfig := kafkaAdapter.KafkaConfig{
+		Brokers:       cfg.Kafka.Brokers,
+		ConsumerGroup: cfg.Kafka.ConsumerGroup,
+		ClientID:      cfg.Kafka.ClientID,
+	}
+
+	t.healthChecks = append(t.healthChecks, kafkaAdapter.NewKafkaHealthCheck(kafkaConfig))
+
+	var err error
+	if t.kafkaPublisher, err = kafkaAdapter.NewKafkaPublisher(kafkaConfig, logger); err != nil {
+		return fmt.Errorf("creating Kafka publisher: %w", err)
+	}
+	t.onClose(t.kafkaPublisher.Close)
+
+	if t.kafkaSubscriber, err = kafkaAdapter.NewKafkaSubscriber(kafkaConfig, logger); err != nil {
+		return fmt.Errorf("creating Kafka subscriber: %w", err)
+	}
+	t.onClose(t.kafkaSubscriber.Close)
+	return nil
+}
+
+func (t *transport) openRedis(cfg *config.Config, logger watermill.LoggerAdapter) error {
+	client := redisAdapter.NewRedisClient(redisAdapter.RedisClientConfig{
+		Addr:     cfg.Redis.Address,
+		Password: cfg.Redis.Password.Value(),
+		DB:       cfg.Redis.DB,
+	})
+	t.onClose(client.Close)
+	t.healthChecks = append(t.healthChecks, redisAdapter.NewRedisHealthCheck(client))
+
+	var err error
+	if t.redisPublisher, err = redisAdapter.NewRedisPublisher(client, logger); err != nil {
+		return fmt.Errorf("creating Redis publisher: %w", err)
+	}
+	t.onClose(t.redisPublisher.Close)
+
+	t.redisSubscriber, err = redisAdapter.NewRedisSubscriber(client, redisAdapter.RedisStreamConfig{
+		ConsumerGroup: cfg.Redis.ConsumerGroup,
+		Consumer:      cfg.Redis.Consumer,
+	}, logger)
+	if err != nil {
+		return fmt.Errorf("creating Redis subscriber: %w", err)
+	}
+	t.onClose(t.redisSubscriber.Close)
+	return nil
+}
+
+func (t *transport) openNATS(cfg *config.Config, logger watermill.LoggerAdapter) error {
+	natsConfig := natsAdapter.NatsConfig{
+		URL:        cfg.NATS.URL,
+		QueueGroup: cfg.NATS.QueueGroup,
+		AckWait:    cfg.NATS.AckWait,
+		NakDelay:   cfg.NATS.NakDelay,
+		MaxDeliver: cfg.NATS.MaxDeliver,
+	}
+	conn, err := natsAdapter.NewNatsConnection(natsConfig)
+	if err != nil {
+		return fmt.Errorf("connecting to NATS: %w", err)
+	}
+	t.onClose(func() error {
+		conn.Close()
+		return nil
+	})
+	t.natsConn = conn
+	t.natsQueueGroup = natsConfig.QueueGroup
+	t.healthChecks = append(t.healthChecks, natsAdapter.NewNatsHealthCheck(conn))
+
+	if t.natsPublisher, err = natsAdapter.NewNatsPublisher(conn, natsConfig, logger); err != nil {
+		return fmt.Errorf("creating NATS publisher: %w", err)
+	}
+	t.onClose(t.natsPublisher.Close)
+
+	if t.natsSubscriber, err = natsAdapter.NewNatsSubscriber(conn, natsConfig, logger); err != nil {
+		return fmt.Errorf("creating NATS subscriber: %w", err)
+	}
+	t.onClose(t.natsSubscriber.Close)
+	return nil
+}
+
+// openBridge opens the broker of bridge.broker, if any, for startBridge.
+func (t *transport) openBridge(cfg *config.Config, logger watermill.LoggerAdapter) error {
+	t.bridge = cfg.Bridge
+	switch cfg.Bridge.Broker {
+	case "":
+		return nil
+	case "kafka":
+		if err := t.openKafka(cfg, logger); err != nil {
+			return err
+		}
+		t.bridgePublisher, t.bridgeSubscriber = t.kafkaPublisher, t.kafkaSubscriber
+	case "redis":
+		if err := t.openRedis(cfg, logger); err != nil {
+			return err
+		}
+		t.bridgePublisher, t.bridgeSubscriber = t.redisPublisher, t.redisSubscriber
+	case "nats":
+		if err := t.openNATS(cfg, logger); err != nil {
+			return err
+		}
+		t.bridgePublisher, t.bridgeSubscriber = t.natsPublisher, t.natsSubscriber
+	default:
+		return fmt.Errorf("unknown bridge broker %q", cfg.Bridge.Broker)
+	}
+
+	if len(cfg.Bridge.TenantTopics) > 0 {
+		t.bridgePublisher = watermillLogAdapter.NewTenantTopicPublisher(t.bridgePublisher, cfg.Bridge.TenantTopics)
+		t.bridgeSubscriber = watermillLogAdapter.NewTenantTopicSubscriber(t.bridgeSubscriber, cfg.Bridge.TenantTopics)
+	}
+	return nil
+}
+
+// bridgedEvents are the events of the slice the bridge may carry.
+var bridgedEvents = []string{"BusTicketBooked", "BusTicketCancelled", "SeatHoldExpired"}
+
+// startBridge connects the events of buses to the bridge broker. It runs
+// once the slice registered its handlers, so that no inbound event reaches a
+// bus without them.
+func (t *transport) startBridge(buses busticket.Buses) error {
+	if t.bridgePublisher == nil {
+		return nil
+	}
+	for _, eventName := range append(slices.Clone(t.bridge.Outbound), t.bridge.Inbound...) {
+		if !slices.Contains(bridgedEvents, eventName) {
+			return fmt.Errorf("bridge: unknown event %q, want one of %s", eventName, strings.Join(bridgedEvents, ", "))
+		}
+	}
+
+	// Replicas tell their own events apart by an origin unique to the process.
+	origin := watermill.NewShortUUID()
+	return errors.Join(
+		bridgeEvent(t, origin, "BusTicketBooked", buses.BusTicketBooked),
+		bridgeEvent(t, origin, "BusTicketCancelled", buses.BusTicketCancelled),
+		bridgeEvent(t, origin, "SeatHoldExpired", buses.SeatHoldExpired),
+	)
+}
+
+func bridgeEvent[T any](t *transport, origin, eventName string, bus pkgApp.EventBus[pkgDomain.Event[T], T]) error {
+	bridgeConfig := watermillLogAdapter.EventBridgeConfig{Origin: origin}
+	if slices.Contains(t.bridge.Outbound, eventName) {
+		bridgeConfig.Outbound = []string{eventName}
+	}
+	if slices.Contains(t.bridge.Inbound, eventName) {
+		bridgeConfig.Inbound = []string{eventName}
+	}
+	if bridgeConfig.Outbound == nil && bridgeConfig.Inbound == nil {
+		return nil
+	}
+
+	bridge := watermillLogAdapter.NewEventBridge[pkgDomain.Event[T], T](bus, t.bridgePublisher, t.bridgeSubscriber, bridgeConfig, t.appLogger)
+	t.onClose(bridge.Close)
+	if err := bridge.Start(); err != nil {
+		return fmt.Errorf("bridging %s: %w", eventName, err)
+	}
+	return nil
+}
+
+// newSliceBuses builds one bus per message of the bus ticket slice on t.
+func newSliceBuses(t *transport) (busticket.Buses, error) {
+	var (
+		buses busticket.Buses
+		errs  [16]error
+	)
+	buses.ReserveBusTicket, errs[0] = newCommandBus[application.ReserveBusTicketData](t)
+	buses.GetBusTicketByID, errs[1] = newQueryBus[application.GetBusTicketByIDData, domain.BusTicket](t)
+	buses.SearchBusTickets, errs[2] = newQueryBus[application.SearchBusTicketsData, application.SearchBusTicketsResult](t)
+	buses.BusTicketBooked, errs[3] = newEventBus[string](t)
+	buses.CreateRoute, errs[4] = newCommandBus[application.CreateRouteData](t)
+	buses.CreateTrip, errs[5] = newCommandBus[application.CreateTripData](t)
+	buses.CancelTrip, errs[6] = newCommandBus[application.CancelTripData](t)
+	buses.DelayTrip, errs[7] = newCommandBus[application.DelayTripData](t)
+	buses.GetRouteByID, errs[8] = newQueryBus[application.GetRouteByIDData, application.RouteDetails](t)
+	buses.GetTripByID, errs[9] = newQueryBus[application.GetTripByIDData, application.TripDetails](t)
+	buses.CancelBusTicket, errs[10] = newCommandBus[application.CancelBusTicketData](t)
+	buses.BusTicketCancelled, errs[11] = newEventBus[application.BusTicketCancelledData](t)
+	buses.HoldSeat, errs[12] = newCommandBus[application.HoldSeatData](t)
+	buses.ConfirmReservation, errs[13] = newCommandBus[application.ConfirmReservationData](t)
+	buses.ReleaseExpiredSeatHolds, errs[14] = newCommandBus[application.ReleaseExpiredSeatHoldsData](t)
+	buses.SeatHoldExpired, errs[15] = newEventBus[application.SeatHoldExpiredData](t)
+	return buses, errors.Join(errs[:]...)
+}
+
+// newLocalBuses builds in-process buses for processes that execute the
+// slice's messages themselves.
+func newLocalBuses(appLogger pkgApp.AppLogger) busticket.Buses {
+	buses, _ := newSliceBuses(&transport{kind: "memory", appLogger: appLogger})
+	return buses
+}
+
+func newCommandBus[T any](t *transport) (pkgApp.CommandBus[pkgDomain.Command[T], T], error) {
+	if t.kind != "routed" {
+		return commandBusOn[T](t, t.kind), nil
+	}
+	backends := make(map[string]pkgApp.CommandBus[pkgDomain.Command[T], T])
+	for _, backend := range t.routingTable.Backends() {
+		backends[backend] = commandBusOn[T](t, backend)
+	}
+	bus, err := pkgInfra.NewRoutingCommandBus(*t.routingTable, backends, t.appLogger)
+	if err != nil {
+		return nil, fmt.Errorf("creating command bus: %w", err)
+	}
+	return bus, nil
+}
+
+func commandBusOn[T any](t *transport, kind string) pkgApp.CommandBus[pkgDomain.Command[T], T] {
+	var bus pkgApp.CommandBus[pkgDomain.Command[T], T]
+	switch kind {
+	case "memory", "local":
+		bus = pkgInfra.NewSimpleCommandBus[pkgDomain.Command[T], T](t.appLogger)
+	case "channels":
+		bus = channelsAdapter.NewWatermillCommandBus[pkgDomain.Command[T], T](t.pubSub, t.pubSub, t.appLogger)
+	case "kafka":
+		bus = kafkaAdapter.NewKafkaCommandBus[pkgDomain.Command[T], T](t.kafkaPublisher, t.kafkaSubscriber, t.appLogger)
+	case "redis":
+		bus = redisAdapter.NewRedisCommandBus[pkgDomain.Command[T], T](t.redisPublisher, t.redisSubscriber, t.appLogger)
+	case "nats":
+		bus = natsAdapter.NewNatsCommandBus[pkgDomain.Command[T], T](t.natsPublisher, t.natsSubscriber, t.appLogger)
+	case "sql":
+		bus = sqlAdapter.NewSQLCommandBus[pkgDomain.Command[T], T](t.sqlPublisher, t.sqlSubscriber, t.appLogger)
+	case "bolt":
+		bus = boltAdapter.NewBoltCommandBus[pkgDomain.Command[T], T](t.boltPublisher, t.boltSubscriber, t.appLogger)
+	case "grpc":
+		bus = grpcAdapter.NewGRPCCommandBus[pkgDomain.Command[T], T](t.grpcConn, t.appLogger)
+	}
+	t.track(bus)
+	return bus
+}
+
+func newQueryBus[D, R any](t *transport) (pkgApp.QueryBus[pkgDomain.Query[D], D, R], error) {
+	if t.kind != "routed" {
+		return queryBusOn[D, R](t, t.kind), nil
+	}
+	backends := make(map[string]pkgApp.QueryBus[pkgDomain.Query[D], D, R])
+	for _, backend := range t.routingTable.Backends() {
+		backends[backend] = queryBusOn[D, R](t, backend)
+	}
+	bus, err := pkgInfra.NewRoutingQueryBus(*t.routingTable, backends, t.appLogger)
+	if err != nil {
+		return nil, fmt.Errorf("creating query bus: %w", err)
+	}
+	return bus, nil
+}
+
+func queryBusOn[D, R any](t *transport, kind string) pkgApp.QueryBus[pkgDomain.Query[D], D, R] {
+	var bus pkgApp.QueryBus[pkgDomain.Query[D], D, R]
+	switch kind {
+	case "memory", "local":
+		bus = pkgInfra.NewSimpleQueryBus[pkgDomain.Query[D], D, R](t.appLogger)
+	case "channels":
+		bus = channelsAdapter.NewWatermillQueryBus[pkgDomain.Query[D], D, R](t.pubSub, t.pubSub, t.appLogger)
+	case "kafka":
+		bus = kafkaAdapter.NewKafkaQueryBus[pkgDomain.Query[D], D, R](t.kafkaPublisher, t.kafkaSubscriber, t.appLogger)
+	case "redis":
+		bus = redisAdapter.NewRedisQueryBus[pkgDomain.Query[D], D, R](t.redisPublisher, t.redisSubscriber, t.appLogger)
+	case "nats":
+		bus = natsAdapter.NewNatsQueryBus[pkgDomain.Query[D], D, R](t.natsConn, t.natsQueueGroup, t.appLogger)
+	case "sql":
+		bus = sqlAdapter.NewSQLQueryBus[pkgDomain.Query[D], D, R](t.sqlPublisher, t.sqlSubscriber, t.appLogger)
+	case "bolt":
+		bus = boltAdapter.NewBoltQueryBus[pkgDomain.Query[D], D, R](t.boltPublisher, t.boltSubscriber, t.appLogger)
+	case "grpc":
+		bus = grpcAdapter.NewGRPCQueryBus[pkgDomain.Query[D], D, R](t.grpcConn, t.appLogger)
+	}
+	t.track(bus)
+	return bus
+}
+
+func newEventBus[T any](t *transport) (pkgApp.EventBus[pkgDomain.Event[T], T], error) {
+	if t.kind != "routed" {
+		return eventBusOn[T](t, t.kind), nil
+	}
+	backends := make(map[string]pkgApp.EventBus[pkgDomain.Event[T], T])
+	for _, backend := range t.routingTable.Backends() {
+		backends[backend] = eventBusOn[T](t, backend)
+	}
+	bus, err := pkgInfra.NewRoutingEventBus(*t.routingTable, backends, t.appLogger)
+	if err != nil {
+		return nil, fmt.Errorf("creating event bus: %w", err)
+	}
+	return bus, nil
+}
+
+func eventBusOn[T any](t *transport, kind string) pkgApp.EventBus[pkgDomain.Event[T], T] {
+	var bus pkgApp.EventBus[pkgDomain.Event[T], T]
+	switch kind {
+	case "memory", "local":
+		bus = pkgInfra.NewSimpleEventBus[pkgDomain.Event[T], T](t.appLogger)
+	case "channels":
+		bus = channelsAdapter.NewWatermillEventBus[pkgDomain.Event[T], T](t.pubSub, t.pubSub, t.appLogger)
+	case "kafka":
+		bus = kafkaAdapter.NewKafkaEventBus[pkgDomain.Event[T], T](t.kafkaPublisher, t.kafkaSubscriber, t.appLogger)
+	case "redis":
+		bus = redisAdapter.NewRedisEventBus[pkgDomain.Event[T], T](t.redisPublisher, t.redisSubscriber, t.appLogger)
+	case "nats":
+		bus = natsAdapter.NewNatsEventBus[pkgDomain.Event[T], T](t.natsPublisher, t.natsSubscriber, t.appLogger)
+	case "sql":
+		bus = sqlAdapter.NewSQLEventBus[pkgDomain.Event[T], T](t.sqlPublisher, t.sqlSubscriber, t.appLogger)
+	case "bolt":
+		bus = boltAdapter.NewBoltEventBus[pkgDomain.Event[T], T](t.boltPublisher, t.boltSubscriber, t.appLogger)
+	case "grpc":
+		bus = grpcAdapter.NewGRPCEventBus[pkgDomain.Event[T], T](t.grpcConn, grpcAdapter.EventBusConfig{}, t.appLogger)
+	}
+	t.track(bus)
+	return bus
+}
+
+// loadRoutingTable reads the table in routing.table_file; without one,
+// every message stays in process.
+func loadRoutingTable(path string) (pkgInfra.RoutingTable, error) {
+	if path == "" {
+		return pkgInfra.RoutingTable{Default: "local"}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return pkgInfra.RoutingTable{}, err
+	}
+	return pkgInfra.ParseRoutingTable(data)
+}
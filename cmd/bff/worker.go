@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+
+	"github.com/mateusmacedo/go-bff/internal/busticket"
+	pkgApp "github.com/mateusmacedo/go-bff/pkg/application"
+	"github.com/mateusmacedo/go-bff/pkg/config"
+	grpcAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/grpc/adapter"
+)
+
+// runWorker executes the slice's handlers for messages arriving through the
+// transport and serves only the health endpoints. With gRPC the worker is
+// the server the BFF dials instead.
+func runWorker(ctx context.Context, cfg *config.Config, appLogger pkgApp.AppLogger, _ []string) error {
+	switch cfg.Transport {
+	case "memory", "channels":
+		return fmt.Errorf("transport %q does not cross process boundaries, the worker needs a broker or gRPC", cfg.Transport)
+	case "grpc":
+		return runGRPCWorker(ctx, cfg, appLogger)
+	}
+
+	db, dialect, err := openSharedDatabase(cfg)
+	if err != nil {
+		return err
+	}
+	if db != nil {
+		defer db.Close()
+	}
+
+	transport, err := openTransport(cfg, db, dialect, appLogger)
+	if err != nil {
+		return err
+	}
+	defer closeTransport(ctx, transport, appLogger)
+
+	buses, err := newSliceBuses(transport)
+	if err != nil {
+		return err
+	}
+
+	repositories, err := newRepositories(cfg, db, dialect, appLogger)
+	if err != nil {
+		return err
+	}
+
+	slice, err := busticket.NewBusTicketSlice(buses, uuid.NewString, appLogger, repositories, sliceOptions(cfg, db, busticket.WithRole(busticket.RoleWorker))...)
+	if err != nil {
+		return err
+	}
+	go releaseExpiredSeatHolds(ctx, cfg, buses, appLogger)
+	appLogger.Info(ctx, "Worker consumindo mensagens", map[string]interface{}{"transport": cfg.Transport})
+
+	healthServer := newHealthServer(cfg, appLogger, append(transport.healthChecks, slice.HealthChecks()...))
+	return runHTTPServer(ctx, healthServer, appLogger, cfg.HTTP.ShutdownTimeout)
+}
+
+func runGRPCWorker(ctx context.Context, cfg *config.Config, appLogger pkgApp.AppLogger) error {
+	buses := newLocalBuses(appLogger)
+
+	db, dialect, err := openSharedDatabase(cfg)
+	if err != nil {
+		return err
+	}
+	if db != nil {
+		defer db.Close()
+	}
+
+	repositories, err := newRepositories(cfg, db, dialect, appLogger)
+	if err != nil {
+		return err
+	}
+
+	slice, err := busticket.NewBusTicketSlice(buses, uuid.NewString, appLogger, repositories, sliceOptions(cfg, db, busticket.WithRole(busticket.RoleWorker))...)
+	if err != nil {
+		return err
+	}
+	go releaseExpiredSeatHolds(ctx, cfg, buses, appLogger)
+
+	// Calls carry the principal of the BFF's caller, so the served buses
+	// check the same policies as the HTTP handlers.
+	served := busticket.AuthorizedBuses(buses, appLogger)
+	busServer := grpcAdapter.NewBusServer(appLogger)
+	grpcAdapter.ServeCommands(busServer, served.ReserveBusTicket, "ReserveBusTicket")
+	grpcAdapter.ServeCommands(busServer, served.CancelBusTicket, "CancelBusTicket")
+	grpcAdapter.ServeQueries(busServer, served.GetBusTicketByID, "GetBusTicketByID")
+	grpcAdapter.ServeQueries(busServer, served.SearchBusTickets, "SearchBusTickets")
+	grpcAdapter.ServeCommands(busServer, served.CreateRoute, "CreateRoute")
+	grpcAdapter.ServeCommands(busServer, served.CreateTrip, "CreateTrip")
+	grpcAdapter.ServeCommands(busServer, served.CancelTrip, "CancelTrip")
+	grpcAdapter.ServeCommands(busServer, served.DelayTrip, "DelayTrip")
+	grpcAdapter.ServeQueries(busServer, served.GetRouteByID, "GetRouteByID")
+	grpcAdapter.ServeQueries(busServer, served.GetTripByID, "GetTripByID")
+	grpcAdapter.ServeCommands(busServer, served.HoldSeat, "HoldSeat")
+	grpcAdapter.ServeCommands(busServer, served.ConfirmReservation, "ConfirmReservation")
+	err = errors.Join(
+		grpcAdapter.ServeEvents(busServer, buses.BusTicketBooked, "BusTicketBooked"),
+		grpcAdapter.ServeEvents(busServer, buses.BusTicketCancelled, "BusTicketCancelled"),
+		grpcAdapter.ServeEvents(busServer, buses.SeatHoldExpired, "SeatHoldExpired"),
+	)
+	if err != nil {
+		return err
+	}
+
+	serverConfig := grpcAdapter.ServerConfig{Token: cfg.GRPC.Token.Value()}
+	if cfg.GRPC.TLS() {
+		if serverConfig.Credentials, err = grpcAdapter.NewServerTLS(grpcTLSConfig(cfg.GRPC)); err != nil {
+			return err
+		}
+	}
+	server := grpc.NewServer(grpcAdapter.ServerOptions(serverConfig)...)
+	busServer.Register(server)
+
+	listener, err := net.Listen("tcp", cfg.GRPC.Address)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", cfg.GRPC.Address, err)
+	}
+
+	healthCtx, stopHealth := context.WithCancel(ctx)
+	defer stopHealth()
+	healthErr := make(chan error, 1)
+	go func() {
+		healthErr <- runHTTPServer(healthCtx, newHealthServer(cfg, appLogger, slice.HealthChecks()), appLogger, cfg.HTTP.ShutdownTimeout)
+	}()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		appLogger.Info(ctx, "Worker starting on:"+cfg.GRPC.Address, nil)
+		serveErr <- server.Serve(listener)
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case err := <-healthErr:
+		server.Stop()
+		return err
+	case <-ctx.Done():
+	}
+
+	appLogger.Info(context.Background(), "Encerrando servidor...", nil)
+	server.GracefulStop()
+	appLogger.Info(context.Background(), "Servidor encerrado", nil)
+	return <-healthErr
+}
+
+func grpcTLSConfig(cfg config.GRPCConfig) grpcAdapter.TLSConfig {
+	return grpcAdapter.TLSConfig{
+		CertFile:   cfg.TLSCert,
+		KeyFile:    cfg.TLSKey,
+		CAFile:     cfg.TLSCA,
+		ServerName: cfg.TLSServerName,
+	}
+}
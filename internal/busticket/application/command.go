@@ -6,12 +6,14 @@ import (
 	"github.com/mateusmacedo/go-bff/pkg/domain"
 )
 
+// ReserveBusTicketData books SeatNumber on the trip TripID; the ticket
+// copies the route and departure of the trip. ID is generated by the sender,
+// like CreateRouteData's, and one is generated by the handler when empty.
 type ReserveBusTicketData struct {
+	ID            string
 	PassengerName string
-	DepartureTime time.Time
+	TripID        string
 	SeatNumber    int
-	Origin        string
-	Destination   string
 }
 
 type reserveBusTicketCommand struct {
@@ -29,3 +31,187 @@ func (c reserveBusTicketCommand) Payload() ReserveBusTicketData {
 func NewReserveBusTicketCommand(data ReserveBusTicketData) domain.Command[ReserveBusTicketData] {
 	return reserveBusTicketCommand{data: data}
 }
+
+// CancelBusTicketData cancels a ticket. A non-zero ExpectedVersion makes the
+// cancellation conditional on the ticket still being at that version.
+type CancelBusTicketData struct {
+	ID              string
+	ExpectedVersion int64
+}
+
+type cancelBusTicketCommand struct {
+	data CancelBusTicketData
+}
+
+func (c cancelBusTicketCommand) CommandName() string {
+	return "CancelBusTicket"
+}
+
+func (c cancelBusTicketCommand) Payload() CancelBusTicketData {
+	return c.data
+}
+
+func NewCancelBusTicketCommand(data CancelBusTicketData) domain.Command[CancelBusTicketData] {
+	return cancelBusTicketCommand{data: data}
+}
+
+// CreateRouteData carries the ID of the new route, generated by the sender
+// so that it can refer to the route once the command is handled.
+type CreateRouteData struct {
+	ID          string
+	Origin      string
+	Destination string
+}
+
+type createRouteCommand struct {
+	data CreateRouteData
+}
+
+func (c createRouteCommand) CommandName() string {
+	return "CreateRoute"
+}
+
+func (c createRouteCommand) Payload() CreateRouteData {
+	return c.data
+}
+
+func NewCreateRouteCommand(data CreateRouteData) domain.Command[CreateRouteData] {
+	return createRouteCommand{data: data}
+}
+
+// CreateTripData carries the ID of the new trip, generated by the sender
+// like CreateRouteData's, and its fare in cents.
+type CreateTripData struct {
+	ID            string
+	RouteID       string
+	DepartureTime time.Time
+	Vehicle       string
+	Capacity      int
+	Fare          int64
+}
+
+type createTripCommand struct {
+	data CreateTripData
+}
+
+func (c createTripCommand) CommandName() string {
+	return "CreateTrip"
+}
+
+func (c createTripCommand) Payload() CreateTripData {
+	return c.data
+}
+
+func NewCreateTripCommand(data CreateTripData) domain.Command[CreateTripData] {
+	return createTripCommand{data: data}
+}
+
+type CancelTripData struct {
+	ID string
+}
+
+type cancelTripCommand struct {
+	data CancelTripData
+}
+
+func (c cancelTripCommand) CommandName() string {
+	return "CancelTrip"
+}
+
+func (c cancelTripCommand) Payload() CancelTripData {
+	return c.data
+}
+
+func NewCancelTripCommand(data CancelTripData) domain.Command[CancelTripData] {
+	return cancelTripCommand{data: data}
+}
+
+// DelayTripData moves the departure of a trip to DepartureTime, which makes
+// the command safe to run again.
+type DelayTripData struct {
+	ID            string
+	DepartureTime time.Time
+}
+
+type delayTripCommand struct {
+	data DelayTripData
+}
+
+func (c delayTripCommand) CommandName() string {
+	return "DelayTrip"
+}
+
+func (c delayTripCommand) Payload() DelayTripData {
+	return c.data
+}
+
+func NewDelayTripCommand(data DelayTripData) domain.Command[DelayTripData] {
+	return delayTripCommand{data: data}
+}
+
+// HoldSeatData blocks SeatNumber of the trip TripID for PassengerName while
+// they pay. ID is generated by the sender, like CreateRouteData's, and
+// becomes the ID of the ticket once the reservation is confirmed.
+type HoldSeatData struct {
+	ID            string
+	TripID        string
+	SeatNumber    int
+	PassengerName string
+}
+
+type holdSeatCommand struct {
+	data HoldSeatData
+}
+
+func (c holdSeatCommand) CommandName() string {
+	return "HoldSeat"
+}
+
+func (c holdSeatCommand) Payload() HoldSeatData {
+	return c.data
+}
+
+func NewHoldSeatCommand(data HoldSeatData) domain.Command[HoldSeatData] {
+	return holdSeatCommand{data: data}
+}
+
+// ConfirmReservationData turns the unexpired hold HoldID into a ticket.
+type ConfirmReservationData struct {
+	HoldID string
+}
+
+type confirmReservationCommand struct {
+	data ConfirmReservationData
+}
+
+func (c confirmReservationCommand) CommandName() string {
+	return "ConfirmReservation"
+}
+
+func (c confirmReservationCommand) Payload() ConfirmReservationData {
+	return c.data
+}
+
+func NewConfirmReservationCommand(data ConfirmReservationData) domain.Command[ConfirmReservationData] {
+	return confirmReservationCommand{data: data}
+}
+
+// ReleaseExpiredSeatHoldsData releases the expired holds of every tenant;
+// it is dispatched periodically rather than by users.
+type ReleaseExpiredSeatHoldsData struct{}
+
+type releaseExpiredSeatHoldsCommand struct {
+	data ReleaseExpiredSeatHoldsData
+}
+
+func (c releaseExpiredSeatHoldsCommand) CommandName() string {
+	return "ReleaseExpiredSeatHolds"
+}
+
+func (c releaseExpiredSeatHoldsCommand) Payload() ReleaseExpiredSeatHoldsData {
+	return c.data
+}
+
+func NewReleaseExpiredSeatHoldsCommand() domain.Command[ReleaseExpiredSeatHoldsData] {
+	return releaseExpiredSeatHoldsCommand{}
+}
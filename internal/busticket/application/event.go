@@ -1,6 +1,8 @@
 package application
 
 import (
+	"time"
+
 	"github.com/mateusmacedo/go-bff/pkg/domain"
 )
 
@@ -19,3 +21,56 @@ func (e busTicketBookedEvent) Payload() string {
 func NewBusTicketBookedEvent(data string) domain.Event[string] {
 	return busTicketBookedEvent{data: data}
 }
+
+// BusTicketCancelledData is the ticket a cancellation released the seat of,
+// with the part of its price refunded, both in cents.
+type BusTicketCancelledData struct {
+	BusTicketID   string
+	TripID        string
+	PassengerName string
+	SeatNumber    int
+	Price         int64
+	Refund        int64
+}
+
+type busTicketCancelledEvent struct {
+	data BusTicketCancelledData
+}
+
+func (e busTicketCancelledEvent) EventName() string {
+	return "BusTicketCancelled"
+}
+
+func (e busTicketCancelledEvent) Payload() BusTicketCancelledData {
+	return e.data
+}
+
+func NewBusTicketCancelledEvent(data BusTicketCancelledData) domain.Event[BusTicketCancelledData] {
+	return busTicketCancelledEvent{data: data}
+}
+
+// SeatHoldExpiredData is a hold released unconfirmed, whose seat is free
+// again.
+type SeatHoldExpiredData struct {
+	HoldID        string
+	TripID        string
+	SeatNumber    int
+	PassengerName string
+	ExpiresAt     time.Time
+}
+
+type seatHoldExpiredEvent struct {
+	data SeatHoldExpiredData
+}
+
+func (e seatHoldExpiredEvent) EventName() string {
+	return "SeatHoldExpired"
+}
+
+func (e seatHoldExpiredEvent) Payload() SeatHoldExpiredData {
+	return e.data
+}
+
+func NewSeatHoldExpiredEvent(data SeatHoldExpiredData) domain.Event[SeatHoldExpiredData] {
+	return seatHoldExpiredEvent{data: data}
+}
@@ -2,6 +2,8 @@ package application
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/mateusmacedo/go-bff/internal/busticket/domain"
 	pkgApp "github.com/mateusmacedo/go-bff/pkg/application"
@@ -11,7 +13,11 @@ import (
 type reserveBusTicketHandler struct {
 	eventBus    pkgApp.EventBus[pkgDomain.Event[string], string]
 	repository  domain.BusTicketRepository
+	holds       domain.SeatHoldRepository
+	routes      domain.RouteRepository
+	trips       domain.TripRepository
 	idGenerator pkgDomain.IDGenerator[string]
+	clock       pkgDomain.Clock
 	logger      pkgApp.AppLogger
 }
 
@@ -22,13 +28,43 @@ func (h *reserveBusTicketHandler) Handle(ctx context.Context, command pkgDomain.
 	}
 
 	data := command.Payload()
+	trip, err := bookableTrip(ctx, h.trips, data.TripID, h.clock(), h.logger)
+	if err != nil {
+		return err
+	}
+	route, err := h.routes.FindByID(ctx, trip.RouteID)
+	if err != nil {
+		pkgApp.LogError(ctx, h.logger, "Erro ao buscar rota", err, map[string]interface{}{"route_id": trip.RouteID})
+		return err
+	}
+
+	if data.ID == "" {
+		data.ID = h.idGenerator()
+	}
 	busTicket := domain.BusTicket{
-		ID:            h.idGenerator(),
+		ID:            data.ID,
+		TripID:        trip.ID,
 		PassengerName: data.PassengerName,
-		DepartureTime: data.DepartureTime,
+		DepartureTime: trip.DepartureTime,
 		SeatNumber:    data.SeatNumber,
-		Origin:        data.Origin,
-		Destination:   data.Destination,
+		Origin:        route.Origin,
+		Destination:   route.Destination,
+		Status:        domain.BusTicketReserved,
+		Price:         trip.Fare,
+	}
+
+	inventory, err := tripInventory(ctx, h.repository, h.holds, trip)
+	if err != nil {
+		pkgApp.LogError(ctx, h.logger, "Erro ao buscar assentos da viagem", err, map[string]interface{}{"trip": trip.ID})
+		return err
+	}
+	if len(inventory.Available()) == 0 {
+		pkgApp.LogInfo(ctx, h.logger, "Viagem lotada", map[string]interface{}{"trip": trip.ID})
+		return domain.ErrTripFull
+	}
+	if err := inventory.Reserve(busTicket.SeatNumber, busTicket.ID); err != nil {
+		pkgApp.LogError(ctx, h.logger, "Assento indisponível", err, map[string]interface{}{"bus_ticket": busTicket})
+		return err
 	}
 
 	h.logger.Info(ctx, "Salvando passagem", map[string]interface{}{"id": busTicket.ID})
@@ -47,40 +83,206 @@ func (h *reserveBusTicketHandler) Handle(ctx context.Context, command pkgDomain.
 	return nil
 }
 
-func NewReserveBusTicketHandler(eventBus pkgApp.EventBus[pkgDomain.Event[string], string], repo domain.BusTicketRepository, idGenerator pkgDomain.IDGenerator[string], logger pkgApp.AppLogger) pkgApp.CommandHandler[pkgDomain.Command[ReserveBusTicketData], ReserveBusTicketData] {
+func NewReserveBusTicketHandler(
+	eventBus pkgApp.EventBus[pkgDomain.Event[string], string],
+	repo domain.BusTicketRepository,
+	holds domain.SeatHoldRepository,
+	routes domain.RouteRepository,
+	trips domain.TripRepository,
+	idGenerator pkgDomain.IDGenerator[string],
+	clock pkgDomain.Clock,
+	logger pkgApp.AppLogger,
+) pkgApp.CommandHandler[pkgDomain.Command[ReserveBusTicketData], ReserveBusTicketData] {
 	return &reserveBusTicketHandler{
 		eventBus:    eventBus,
 		repository:  repo,
+		holds:       holds,
+		routes:      routes,
+		trips:       trips,
 		idGenerator: idGenerator,
+		clock:       clock,
 		logger:      logger,
 	}
 }
 
-type findBusTicketHandler struct {
+// bookableTrip loads the trip whose seats are being booked and fails unless
+// they can be at now.
+func bookableTrip(ctx context.Context, trips domain.TripRepository, tripID string, now time.Time, logger pkgApp.AppLogger) (domain.Trip, error) {
+	trip, err := trips.FindByID(ctx, tripID)
+	if err != nil {
+		pkgApp.LogError(ctx, logger, "Erro ao buscar viagem", err, map[string]interface{}{"trip_id": tripID})
+		return domain.Trip{}, err
+	}
+	if err := trip.Bookable(now); err != nil {
+		pkgApp.LogInfo(ctx, logger, "Viagem indisponível para reservas", map[string]interface{}{"trip_id": tripID, "error": err.Error()})
+		return domain.Trip{}, err
+	}
+	return trip, nil
+}
+
+type cancelBusTicketHandler struct {
+	eventBus   pkgApp.EventBus[pkgDomain.Event[BusTicketCancelledData], BusTicketCancelledData]
 	repository domain.BusTicketRepository
+	trips      domain.TripRepository
+	policy     domain.RefundPolicy
+	clock      pkgDomain.Clock
 	logger     pkgApp.AppLogger
 }
 
-func (h *findBusTicketHandler) Handle(ctx context.Context, query pkgDomain.Query[FindBusTicketData]) ([]domain.BusTicket, error) {
+// Handle refunds tickets of trips the operator cancelled in full and others
+// as the refund policy allows at the time of cancellation. Other passengers'
+// tickets are reported to passengers as not found, as the get-by-ID handler
+// does. Without an expected version the ticket is updated at the version
+// just read, so a conflict means it changed in between and the command may
+// be retried.
+func (h *cancelBusTicketHandler) Handle(ctx context.Context, command pkgDomain.Command[CancelBusTicketData]) error {
 	if ctx.Err() != nil {
 		pkgApp.LogError(ctx, h.logger, "Contexto cancelado", ctx.Err(), nil)
-		return nil, ctx.Err()
+		return ctx.Err()
+	}
+
+	data := command.Payload()
+	busTicket, err := h.repository.FindByID(ctx, data.ID)
+	if err != nil {
+		pkgApp.LogError(ctx, h.logger, "Erro ao buscar passagem", err, map[string]interface{}{"id": data.ID})
+		return err
+	}
+
+	if principal, ok := pkgApp.PrincipalFromContext(ctx); ok && !canSeeAllBusTickets(principal) &&
+		principal.Attribute(PassengerNameAttribute) != busTicket.PassengerName {
+		pkgApp.LogInfo(ctx, h.logger, "Passagem de outro passageiro", map[string]interface{}{"id": data.ID, "principal": principal.ID})
+		return domain.ErrBusTicketNotFound
+	}
+
+	if data.ExpectedVersion != 0 && data.ExpectedVersion != busTicket.Version {
+		pkgApp.LogInfo(ctx, h.logger, "Versão da passagem desatualizada", map[string]interface{}{"id": data.ID, "expected_version": data.ExpectedVersion, "version": busTicket.Version})
+		return domain.ErrConcurrencyConflict
+	}
+
+	refund := h.policy.Refund(busTicket.Price, busTicket.DepartureTime, h.clock())
+	if busTicket.TripID != "" {
+		trip, err := h.trips.FindByID(ctx, busTicket.TripID)
+		if err != nil && !errors.Is(err, domain.ErrTripNotFound) {
+			pkgApp.LogError(ctx, h.logger, "Erro ao buscar viagem", err, map[string]interface{}{"trip_id": busTicket.TripID})
+			return err
+		}
+		if err == nil && trip.Status == domain.TripCancelled {
+			refund = busTicket.Price
+		}
+	}
+
+	if err := busTicket.Cancel(refund); err != nil {
+		pkgApp.LogInfo(ctx, h.logger, "Passagem já cancelada", map[string]interface{}{"id": data.ID})
+		return err
+	}
+	if err := h.repository.Update(ctx, busTicket); err != nil {
+		pkgApp.LogError(ctx, h.logger, "Erro ao cancelar passagem", err, map[string]interface{}{"bus_ticket": busTicket})
+		return err
+	}
+
+	event := NewBusTicketCancelledEvent(BusTicketCancelledData{
+		BusTicketID:   busTicket.ID,
+		TripID:        busTicket.TripID,
+		PassengerName: busTicket.PassengerName,
+		SeatNumber:    busTicket.SeatNumber,
+		Price:         busTicket.Price,
+		Refund:        busTicket.Refund,
+	})
+	if err := h.eventBus.Publish(ctx, event); err != nil {
+		pkgApp.LogError(ctx, h.logger, "Erro ao publicar evento", err, nil)
+		return err
+	}
+
+	pkgApp.LogInfo(ctx, h.logger, "Passagem cancelada", map[string]interface{}{"bus_ticket": busTicket})
+	return nil
+}
+
+func NewCancelBusTicketHandler(
+	eventBus pkgApp.EventBus[pkgDomain.Event[BusTicketCancelledData], BusTicketCancelledData],
+	repo domain.BusTicketRepository,
+	trips domain.TripRepository,
+	policy domain.RefundPolicy,
+	clock pkgDomain.Clock,
+	logger pkgApp.AppLogger,
+) pkgApp.CommandHandler[pkgDomain.Command[CancelBusTicketData], CancelBusTicketData] {
+	return &cancelBusTicketHandler{
+		eventBus:   eventBus,
+		repository: repo,
+		trips:      trips,
+		policy:     policy,
+		clock:      clock,
+		logger:     logger,
+	}
+}
+
+type getBusTicketByIDHandler struct {
+	repository domain.BusTicketRepository
+	logger     pkgApp.AppLogger
+}
+
+// Handle hides other passengers' tickets from passengers, reporting them as
+// not found; the bus authorizer cannot, as it only sees the ID.
+func (h *getBusTicketByIDHandler) Handle(ctx context.Context, query pkgDomain.Query[GetBusTicketByIDData]) (domain.BusTicket, error) {
+	if ctx.Err() != nil {
+		pkgApp.LogError(ctx, h.logger, "Contexto cancelado", ctx.Err(), nil)
+		return domain.BusTicket{}, ctx.Err()
 	}
 
 	data := query.Payload()
-	busTicket, err := h.repository.FindByPassengerName(ctx, data.PassengerName)
-	pkgApp.LogInfo(ctx, h.logger, "BusTicket encontrado", map[string]interface{}{"bus_ticket": busTicket})
+	busTicket, err := h.repository.FindByID(ctx, data.ID)
 	if err != nil {
-		pkgApp.LogError(ctx, h.logger, "Erro ao encontrar passagem", err, map[string]interface{}{"passenger_name": data.PassengerName})
-		return nil, err
+		pkgApp.LogError(ctx, h.logger, "Erro ao buscar passagem", err, map[string]interface{}{"id": data.ID})
+		return domain.BusTicket{}, err
+	}
+
+	if principal, ok := pkgApp.PrincipalFromContext(ctx); ok && !canSeeAllBusTickets(principal) &&
+		principal.Attribute(PassengerNameAttribute) != busTicket.PassengerName {
+		pkgApp.LogInfo(ctx, h.logger, "Passagem de outro passageiro", map[string]interface{}{"id": data.ID, "principal": principal.ID})
+		return domain.BusTicket{}, domain.ErrBusTicketNotFound
 	}
 
 	pkgApp.LogInfo(ctx, h.logger, "Passagem encontrada", map[string]interface{}{"bus_ticket": busTicket})
 	return busTicket, nil
 }
 
-func NewFindBusTicketHandler(repo domain.BusTicketRepository, logger pkgApp.AppLogger) pkgApp.QueryHandler[pkgDomain.Query[FindBusTicketData], FindBusTicketData, []domain.BusTicket] {
-	return &findBusTicketHandler{
+func NewGetBusTicketByIDHandler(repo domain.BusTicketRepository, logger pkgApp.AppLogger) pkgApp.QueryHandler[pkgDomain.Query[GetBusTicketByIDData], GetBusTicketByIDData, domain.BusTicket] {
+	return &getBusTicketByIDHandler{
+		repository: repo,
+		logger:     logger,
+	}
+}
+
+type searchBusTicketsHandler struct {
+	repository domain.BusTicketRepository
+	logger     pkgApp.AppLogger
+}
+
+func (h *searchBusTicketsHandler) Handle(ctx context.Context, query pkgDomain.Query[SearchBusTicketsData]) (SearchBusTicketsResult, error) {
+	if ctx.Err() != nil {
+		pkgApp.LogError(ctx, h.logger, "Contexto cancelado", ctx.Err(), nil)
+		return SearchBusTicketsResult{}, ctx.Err()
+	}
+
+	data := query.Payload()
+	filter := data.Filter()
+	page, err := h.repository.Search(ctx, filter, domain.PageRequest{Cursor: data.Cursor, Limit: data.Limit})
+	if err != nil {
+		pkgApp.LogError(ctx, h.logger, "Erro ao pesquisar passagens", err, map[string]interface{}{"filter": filter})
+		return SearchBusTicketsResult{}, err
+	}
+
+	total, err := h.repository.Count(ctx, filter)
+	if err != nil {
+		pkgApp.LogError(ctx, h.logger, "Erro ao contar passagens", err, map[string]interface{}{"filter": filter})
+		return SearchBusTicketsResult{}, err
+	}
+
+	pkgApp.LogInfo(ctx, h.logger, "Passagens pesquisadas", map[string]interface{}{"filter": filter, "total": total})
+	return SearchBusTicketsResult{BusTicketPage: page, Total: total}, nil
+}
+
+func NewSearchBusTicketsHandler(repo domain.BusTicketRepository, logger pkgApp.AppLogger) pkgApp.QueryHandler[pkgDomain.Query[SearchBusTicketsData], SearchBusTicketsData, SearchBusTicketsResult] {
+	return &searchBusTicketsHandler{
 		repository: repo,
 		logger:     logger,
 	}
@@ -105,3 +307,23 @@ func NewBusTicketBookedEventHandler(logger pkgApp.AppLogger) pkgApp.EventHandler
 		logger: logger,
 	}
 }
+
+type busTicketCancelledEventHandler struct {
+	logger pkgApp.AppLogger
+}
+
+func (h *busTicketCancelledEventHandler) Handle(ctx context.Context, event pkgDomain.Event[BusTicketCancelledData]) error {
+	if ctx.Err() != nil {
+		pkgApp.LogError(ctx, h.logger, "Contexto cancelado", ctx.Err(), nil)
+		return ctx.Err()
+	}
+
+	pkgApp.LogInfo(ctx, h.logger, "Evento recebido", map[string]interface{}{"event": event.Payload()})
+	return nil
+}
+
+func NewBusTicketCancelledEventHandler(logger pkgApp.AppLogger) pkgApp.EventHandler[pkgDomain.Event[BusTicketCancelledData], BusTicketCancelledData] {
+	return &busTicketCancelledEventHandler{
+		logger: logger,
+	}
+}
@@ -0,0 +1,126 @@
+package application
+
+import (
+	pkgApp "github.com/mateusmacedo/go-bff/pkg/application"
+)
+
+const (
+	RolePassenger = "passenger"
+	RoleAgent     = "agent"
+	RoleAdmin     = "admin"
+
+	PassengerNameAttribute = "passenger_name"
+)
+
+func NewReserveBusTicketAuthorizer() *pkgApp.Authorizer[ReserveBusTicketData] {
+	authorizer := pkgApp.NewAuthorizer[ReserveBusTicketData]()
+	authorizer.Register("ReserveBusTicket", pkgApp.AnyOf(
+		pkgApp.RequireAnyRole[ReserveBusTicketData](RoleAdmin, RoleAgent),
+		pkgApp.AllOf(
+			pkgApp.RequireAnyRole[ReserveBusTicketData](RolePassenger),
+			pkgApp.RequireAttribute(PassengerNameAttribute, func(data ReserveBusTicketData) string {
+				return data.PassengerName
+			}),
+		),
+	))
+	return authorizer
+}
+
+// NewGetBusTicketByIDAuthorizer admits passengers to the query; the handler
+// then only returns their own tickets.
+func NewGetBusTicketByIDAuthorizer() *pkgApp.Authorizer[GetBusTicketByIDData] {
+	authorizer := pkgApp.NewAuthorizer[GetBusTicketByIDData]()
+	authorizer.Register("GetBusTicketByID", pkgApp.RequireAnyRole[GetBusTicketByIDData](RoleAdmin, RoleAgent, RolePassenger))
+	return authorizer
+}
+
+// NewSearchBusTicketsAuthorizer lets passengers search only their own
+// tickets, by passing their name as the passenger filter.
+func NewSearchBusTicketsAuthorizer() *pkgApp.Authorizer[SearchBusTicketsData] {
+	authorizer := pkgApp.NewAuthorizer[SearchBusTicketsData]()
+	authorizer.Register("SearchBusTickets", pkgApp.AnyOf(
+		pkgApp.RequireAnyRole[SearchBusTicketsData](RoleAdmin, RoleAgent),
+		pkgApp.AllOf(
+			pkgApp.RequireAnyRole[SearchBusTicketsData](RolePassenger),
+			pkgApp.RequireAttribute(PassengerNameAttribute, func(data SearchBusTicketsData) string {
+				return data.PassengerName
+			}),
+		),
+	))
+	return authorizer
+}
+
+// NewCancelBusTicketAuthorizer admits passengers to the command; the
+// handler then only cancels their own tickets.
+func NewCancelBusTicketAuthorizer() *pkgApp.Authorizer[CancelBusTicketData] {
+	authorizer := pkgApp.NewAuthorizer[CancelBusTicketData]()
+	authorizer.Register("CancelBusTicket", pkgApp.RequireAnyRole[CancelBusTicketData](RoleAdmin, RoleAgent, RolePassenger))
+	return authorizer
+}
+
+// NewHoldSeatAuthorizer lets passengers hold seats only for themselves, as
+// they reserve them.
+func NewHoldSeatAuthorizer() *pkgApp.Authorizer[HoldSeatData] {
+	authorizer := pkgApp.NewAuthorizer[HoldSeatData]()
+	authorizer.Register("HoldSeat", pkgApp.AnyOf(
+		pkgApp.RequireAnyRole[HoldSeatData](RoleAdmin, RoleAgent),
+		pkgApp.AllOf(
+			pkgApp.RequireAnyRole[HoldSeatData](RolePassenger),
+			pkgApp.RequireAttribute(PassengerNameAttribute, func(data HoldSeatData) string {
+				return data.PassengerName
+			}),
+		),
+	))
+	return authorizer
+}
+
+// NewConfirmReservationAuthorizer admits passengers to the command; the
+// handler then only confirms their own holds.
+func NewConfirmReservationAuthorizer() *pkgApp.Authorizer[ConfirmReservationData] {
+	authorizer := pkgApp.NewAuthorizer[ConfirmReservationData]()
+	authorizer.Register("ConfirmReservation", pkgApp.RequireAnyRole[ConfirmReservationData](RoleAdmin, RoleAgent, RolePassenger))
+	return authorizer
+}
+
+// Routes and trips are managed by admins and agents, and read by everyone
+// the ticket queries admit.
+
+func NewCreateRouteAuthorizer() *pkgApp.Authorizer[CreateRouteData] {
+	authorizer := pkgApp.NewAuthorizer[CreateRouteData]()
+	authorizer.Register("CreateRoute", pkgApp.RequireAnyRole[CreateRouteData](RoleAdmin, RoleAgent))
+	return authorizer
+}
+
+func NewCreateTripAuthorizer() *pkgApp.Authorizer[CreateTripData] {
+	authorizer := pkgApp.NewAuthorizer[CreateTripData]()
+	authorizer.Register("CreateTrip", pkgApp.RequireAnyRole[CreateTripData](RoleAdmin, RoleAgent))
+	return authorizer
+}
+
+func NewCancelTripAuthorizer() *pkgApp.Authorizer[CancelTripData] {
+	authorizer := pkgApp.NewAuthorizer[CancelTripData]()
+	authorizer.Register("CancelTrip", pkgApp.RequireAnyRole[CancelTripData](RoleAdmin, RoleAgent))
+	return authorizer
+}
+
+func NewDelayTripAuthorizer() *pkgApp.Authorizer[DelayTripData] {
+	authorizer := pkgApp.NewAuthorizer[DelayTripData]()
+	authorizer.Register("DelayTrip", pkgApp.RequireAnyRole[DelayTripData](RoleAdmin, RoleAgent))
+	return authorizer
+}
+
+func NewGetRouteByIDAuthorizer() *pkgApp.Authorizer[GetRouteByIDData] {
+	authorizer := pkgApp.NewAuthorizer[GetRouteByIDData]()
+	authorizer.Register("GetRouteByID", pkgApp.RequireAnyRole[GetRouteByIDData](RoleAdmin, RoleAgent, RolePassenger))
+	return authorizer
+}
+
+func NewGetTripByIDAuthorizer() *pkgApp.Authorizer[GetTripByIDData] {
+	authorizer := pkgApp.NewAuthorizer[GetTripByIDData]()
+	authorizer.Register("GetTripByID", pkgApp.RequireAnyRole[GetTripByIDData](RoleAdmin, RoleAgent, RolePassenger))
+	return authorizer
+}
+
+func canSeeAllBusTickets(principal pkgApp.Principal) bool {
+	return principal.HasRole(RoleAdmin) || principal.HasRole(RoleAgent)
+}
@@ -1,25 +1,126 @@
 package application
 
 import (
+	"time"
+
+	busTicketDomain "github.com/mateusmacedo/go-bff/internal/busticket/domain"
 	"github.com/mateusmacedo/go-bff/pkg/domain"
 )
 
-type FindBusTicketData struct {
+type GetBusTicketByIDData struct {
+	ID string
+}
+
+type getBusTicketByIDQuery struct {
+	data GetBusTicketByIDData
+}
+
+func (q getBusTicketByIDQuery) QueryName() string {
+	return "GetBusTicketByID"
+}
+
+func (q getBusTicketByIDQuery) Payload() GetBusTicketByIDData {
+	return q.data
+}
+
+func NewGetBusTicketByIDQuery(data GetBusTicketByIDData) domain.Query[GetBusTicketByIDData] {
+	return getBusTicketByIDQuery{data: data}
+}
+
+type SearchBusTicketsData struct {
 	PassengerName string
+	Origin        string
+	Destination   string
+	DepartureFrom time.Time
+	DepartureTo   time.Time
+	Cursor        string
+	Limit         int
+}
+
+func (d SearchBusTicketsData) Filter() busTicketDomain.BusTicketFilter {
+	return busTicketDomain.BusTicketFilter{
+		PassengerName: d.PassengerName,
+		Origin:        d.Origin,
+		Destination:   d.Destination,
+		DepartureFrom: d.DepartureFrom,
+		DepartureTo:   d.DepartureTo,
+	}
+}
+
+// SearchBusTicketsResult is one page of the search and the number of
+// tickets matching it across all pages.
+type SearchBusTicketsResult struct {
+	busTicketDomain.BusTicketPage
+	Total int `json:"total"`
+}
+
+type searchBusTicketsQuery struct {
+	data SearchBusTicketsData
+}
+
+func (q searchBusTicketsQuery) QueryName() string {
+	return "SearchBusTickets"
+}
+
+func (q searchBusTicketsQuery) Payload() SearchBusTicketsData {
+	return q.data
+}
+
+func NewSearchBusTicketsQuery(data SearchBusTicketsData) domain.Query[SearchBusTicketsData] {
+	return searchBusTicketsQuery{data: data}
+}
+
+type GetRouteByIDData struct {
+	ID string
+}
+
+// RouteDetails is a route with its trips.
+type RouteDetails struct {
+	busTicketDomain.Route
+	Trips []TripDetails `json:"trips"`
+}
+
+type getRouteByIDQuery struct {
+	data GetRouteByIDData
+}
+
+func (q getRouteByIDQuery) QueryName() string {
+	return "GetRouteByID"
+}
+
+func (q getRouteByIDQuery) Payload() GetRouteByIDData {
+	return q.data
+}
+
+func NewGetRouteByIDQuery(data GetRouteByIDData) domain.Query[GetRouteByIDData] {
+	return getRouteByIDQuery{data: data}
+}
+
+type GetTripByIDData struct {
+	ID string
+}
+
+// TripDetails is a trip with its status at the time of the query, its route
+// and the number of its seats neither booked nor held.
+type TripDetails struct {
+	busTicketDomain.Trip
+	Origin         string `json:"origin"`
+	Destination    string `json:"destination"`
+	AvailableSeats int    `json:"availableSeats"`
 }
 
-type findBusTicketQuery struct {
-	data FindBusTicketData
+type getTripByIDQuery struct {
+	data GetTripByIDData
 }
 
-func (q findBusTicketQuery) QueryName() string {
-	return "FindBusTicket"
+func (q getTripByIDQuery) QueryName() string {
+	return "GetTripByID"
 }
 
-func (q findBusTicketQuery) Payload() FindBusTicketData {
+func (q getTripByIDQuery) Payload() GetTripByIDData {
 	return q.data
 }
 
-func NewFindBusTicketQuery(data FindBusTicketData) domain.Query[FindBusTicketData] {
-	return findBusTicketQuery{data: data}
+func NewGetTripByIDQuery(data GetTripByIDData) domain.Query[GetTripByIDData] {
+	return getTripByIDQuery{data: data}
 }
@@ -0,0 +1,315 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mateusmacedo/go-bff/internal/busticket/domain"
+	pkgApp "github.com/mateusmacedo/go-bff/pkg/application"
+	pkgDomain "github.com/mateusmacedo/go-bff/pkg/domain"
+)
+
+type holdSeatHandler struct {
+	repository domain.BusTicketRepository
+	holds      domain.SeatHoldRepository
+	trips      domain.TripRepository
+	ttl        time.Duration
+	clock      pkgDomain.Clock
+	logger     pkgApp.AppLogger
+}
+
+func (h *holdSeatHandler) Handle(ctx context.Context, command pkgDomain.Command[HoldSeatData]) error {
+	if ctx.Err() != nil {
+		pkgApp.LogError(ctx, h.logger, "Contexto cancelado", ctx.Err(), nil)
+		return ctx.Err()
+	}
+
+	data := command.Payload()
+	now := h.clock()
+	trip, err := bookableTrip(ctx, h.trips, data.TripID, now, h.logger)
+	if err != nil {
+		return err
+	}
+	hold, err := domain.NewSeatHold(data.ID, trip, data.SeatNumber, data.PassengerName, h.ttl, now)
+	if err != nil {
+		pkgApp.LogError(ctx, h.logger, "Reserva temporária inválida", err, map[string]interface{}{"seat_hold": data})
+		return err
+	}
+
+	inventory, err := tripInventory(ctx, h.repository, h.holds, trip)
+	if err != nil {
+		pkgApp.LogError(ctx, h.logger, "Erro ao buscar assentos da viagem", err, map[string]interface{}{"trip": trip.ID})
+		return err
+	}
+	if len(inventory.Available()) == 0 {
+		pkgApp.LogInfo(ctx, h.logger, "Viagem lotada", map[string]interface{}{"trip": trip.ID})
+		return domain.ErrTripFull
+	}
+	if err := inventory.Hold(hold.SeatNumber, hold.ID); err != nil {
+		pkgApp.LogError(ctx, h.logger, "Assento indisponível", err, map[string]interface{}{"seat_hold": hold})
+		return err
+	}
+
+	if err := h.holds.Save(ctx, hold); err != nil {
+		pkgApp.LogError(ctx, h.logger, "Erro ao salvar reserva temporária", err, map[string]interface{}{"seat_hold": hold})
+		return err
+	}
+
+	pkgApp.LogInfo(ctx, h.logger, "Assento reservado temporariamente", map[string]interface{}{"seat_hold": hold})
+	return nil
+}
+
+func NewHoldSeatHandler(
+	repo domain.BusTicketRepository,
+	holds domain.SeatHoldRepository,
+	trips domain.TripRepository,
+	ttl time.Duration,
+	clock pkgDomain.Clock,
+	logger pkgApp.AppLogger,
+) pkgApp.CommandHandler[pkgDomain.Command[HoldSeatData], HoldSeatData] {
+	return &holdSeatHandler{
+		repository: repo,
+		holds:      holds,
+		trips:      trips,
+		ttl:        ttl,
+		clock:      clock,
+		logger:     logger,
+	}
+}
+
+type confirmReservationHandler struct {
+	eventBus   pkgApp.EventBus[pkgDomain.Event[string], string]
+	repository domain.BusTicketRepository
+	holds      domain.SeatHoldRepository
+	routes     domain.RouteRepository
+	trips      domain.TripRepository
+	clock      pkgDomain.Clock
+	logger     pkgApp.AppLogger
+}
+
+// Handle saves the ticket of the hold under the hold's ID before deleting
+// the hold, so running the command again after a failed delete finds the
+// ticket and only deletes the hold. Admins and agents confirm any hold; a
+// passenger confirms only holds under their own name, and another
+// passenger's hold is reported as not found.
+func (h *confirmReservationHandler) Handle(ctx context.Context, command pkgDomain.Command[ConfirmReservationData]) error {
+	if ctx.Err() != nil {
+		pkgApp.LogError(ctx, h.logger, "Contexto cancelado", ctx.Err(), nil)
+		return ctx.Err()
+	}
+
+	data := command.Payload()
+	hold, err := h.holds.FindByID(ctx, data.HoldID)
+	if errors.Is(err, domain.ErrSeatHoldNotFound) && h.confirmed(ctx, data.HoldID) {
+		pkgApp.LogInfo(ctx, h.logger, "Reserva já confirmada", map[string]interface{}{"id": data.HoldID})
+		return nil
+	}
+	if err != nil {
+		pkgApp.LogError(ctx, h.logger, "Erro ao buscar reserva temporária", err, map[string]interface{}{"id": data.HoldID})
+		return err
+	}
+
+	if principal, ok := pkgApp.PrincipalFromContext(ctx); ok && !canSeeAllBusTickets(principal) &&
+		principal.Attribute(PassengerNameAttribute) != hold.PassengerName {
+		pkgApp.LogInfo(ctx, h.logger, "Reserva temporária de outro passageiro", map[string]interface{}{"id": data.HoldID, "principal": principal.ID})
+		return domain.ErrSeatHoldNotFound
+	}
+
+	if _, err := h.repository.FindByID(ctx, hold.ID); err == nil {
+		pkgApp.LogInfo(ctx, h.logger, "Reserva já confirmada", map[string]interface{}{"id": hold.ID})
+		return h.deleteHold(ctx, hold)
+	} else if !errors.Is(err, domain.ErrBusTicketNotFound) {
+		pkgApp.LogError(ctx, h.logger, "Erro ao buscar passagem", err, map[string]interface{}{"id": hold.ID})
+		return err
+	}
+
+	now := h.clock()
+	if hold.Expired(now) {
+		pkgApp.LogInfo(ctx, h.logger, "Reserva temporária expirada", map[string]interface{}{"seat_hold": hold})
+		return domain.ErrSeatHoldExpired
+	}
+	trip, err := bookableTrip(ctx, h.trips, hold.TripID, now, h.logger)
+	if err != nil {
+		return err
+	}
+	route, err := h.routes.FindByID(ctx, trip.RouteID)
+	if err != nil {
+		pkgApp.LogError(ctx, h.logger, "Erro ao buscar rota", err, map[string]interface{}{"route_id": trip.RouteID})
+		return err
+	}
+
+	busTicket := domain.BusTicket{
+		ID:            hold.ID,
+		TripID:        trip.ID,
+		PassengerName: hold.PassengerName,
+		DepartureTime: trip.DepartureTime,
+		SeatNumber:    hold.SeatNumber,
+		Origin:        route.Origin,
+		Destination:   route.Destination,
+		Status:        domain.BusTicketReserved,
+		Price:         trip.Fare,
+	}
+
+	inventory, err := tripInventory(ctx, h.repository, h.holds, trip)
+	if err != nil {
+		pkgApp.LogError(ctx, h.logger, "Erro ao buscar assentos da viagem", err, map[string]interface{}{"trip": trip.ID})
+		return err
+	}
+	if err := inventory.Reserve(busTicket.SeatNumber, busTicket.ID); err != nil {
+		pkgApp.LogError(ctx, h.logger, "Assento indisponível", err, map[string]interface{}{"bus_ticket": busTicket})
+		return err
+	}
+	if err := h.repository.Save(ctx, busTicket); err != nil {
+		pkgApp.LogError(ctx, h.logger, "Erro ao salvar passagem", err, map[string]interface{}{"bus_ticket": busTicket})
+		return err
+	}
+	if err := h.deleteHold(ctx, hold); err != nil {
+		return err
+	}
+
+	event := NewBusTicketBookedEvent("BusTicket successfully booked for " + busTicket.PassengerName)
+	if err := h.eventBus.Publish(ctx, event); err != nil {
+		pkgApp.LogError(ctx, h.logger, "Erro ao publicar evento", err, nil)
+		return err
+	}
+
+	pkgApp.LogInfo(ctx, h.logger, "Reserva confirmada", map[string]interface{}{"bus_ticket": busTicket})
+	return nil
+}
+
+// deleteHold tolerates a hold the periodic release deleted in between: its
+// ticket is saved either way.
+func (h *confirmReservationHandler) deleteHold(ctx context.Context, hold domain.SeatHold) error {
+	if err := h.holds.Delete(ctx, hold.ID); err != nil && !errors.Is(err, domain.ErrSeatHoldNotFound) {
+		pkgApp.LogError(ctx, h.logger, "Erro ao remover reserva temporária", err, map[string]interface{}{"seat_hold": hold})
+		return err
+	}
+	return nil
+}
+
+// confirmed tells whether the ticket of a deleted hold exists and belongs to
+// the caller, so that retrying a confirmation succeeds.
+func (h *confirmReservationHandler) confirmed(ctx context.Context, holdID string) bool {
+	busTicket, err := h.repository.FindByID(ctx, holdID)
+	if err != nil {
+		return false
+	}
+	principal, ok := pkgApp.PrincipalFromContext(ctx)
+	return !ok || canSeeAllBusTickets(principal) || principal.Attribute(PassengerNameAttribute) == busTicket.PassengerName
+}
+
+func NewConfirmReservationHandler(
+	eventBus pkgApp.EventBus[pkgDomain.Event[string], string],
+	repo domain.BusTicketRepository,
+	holds domain.SeatHoldRepository,
+	routes domain.RouteRepository,
+	trips domain.TripRepository,
+	clock pkgDomain.Clock,
+	logger pkgApp.AppLogger,
+) pkgApp.CommandHandler[pkgDomain.Command[ConfirmReservationData], ConfirmReservationData] {
+	return &confirmReservationHandler{
+		eventBus:   eventBus,
+		repository: repo,
+		holds:      holds,
+		routes:     routes,
+		trips:      trips,
+		clock:      clock,
+		logger:     logger,
+	}
+}
+
+type releaseExpiredSeatHoldsHandler struct {
+	eventBus pkgApp.EventBus[pkgDomain.Event[SeatHoldExpiredData], SeatHoldExpiredData]
+	holds    domain.SeatHoldRepository
+	logger   pkgApp.AppLogger
+}
+
+func (h *releaseExpiredSeatHoldsHandler) Handle(ctx context.Context, _ pkgDomain.Command[ReleaseExpiredSeatHoldsData]) error {
+	if ctx.Err() != nil {
+		pkgApp.LogError(ctx, h.logger, "Contexto cancelado", ctx.Err(), nil)
+		return ctx.Err()
+	}
+	return releaseExpiredSeatHolds(ctx, h.holds, h.eventBus, h.logger)
+}
+
+func NewReleaseExpiredSeatHoldsHandler(
+	eventBus pkgApp.EventBus[pkgDomain.Event[SeatHoldExpiredData], SeatHoldExpiredData],
+	holds domain.SeatHoldRepository,
+	logger pkgApp.AppLogger,
+) pkgApp.CommandHandler[pkgDomain.Command[ReleaseExpiredSeatHoldsData], ReleaseExpiredSeatHoldsData] {
+	return &releaseExpiredSeatHoldsHandler{
+		eventBus: eventBus,
+		holds:    holds,
+		logger:   logger,
+	}
+}
+
+// releaseExpiredSeatHolds publishes SeatHoldExpired for each hold it
+// releases, in the context of the hold's tenant, even when the release
+// failed partway. Holds are deleted before their events are published, so a
+// failed publish loses the events of the holds left in the batch.
+func releaseExpiredSeatHolds(
+	ctx context.Context,
+	holds domain.SeatHoldRepository,
+	eventBus pkgApp.EventBus[pkgDomain.Event[SeatHoldExpiredData], SeatHoldExpiredData],
+	logger pkgApp.AppLogger,
+) error {
+	released, err := holds.ReleaseExpired(ctx)
+	if err != nil {
+		pkgApp.LogError(ctx, logger, "Erro ao liberar reservas temporárias expiradas", err, map[string]interface{}{"seat_holds": len(released)})
+	}
+
+	for _, hold := range released {
+		event := NewSeatHoldExpiredEvent(SeatHoldExpiredData{
+			HoldID:        hold.ID,
+			TripID:        hold.TripID,
+			SeatNumber:    hold.SeatNumber,
+			PassengerName: hold.PassengerName,
+			ExpiresAt:     hold.ExpiresAt,
+		})
+		if err := eventBus.Publish(pkgApp.WithTenant(ctx, hold.TenantID), event); err != nil {
+			pkgApp.LogError(ctx, logger, "Erro ao publicar evento", err, map[string]interface{}{"seat_hold": hold})
+			return err
+		}
+	}
+
+	if len(released) > 0 {
+		pkgApp.LogInfo(ctx, logger, "Reservas temporárias expiradas liberadas", map[string]interface{}{"seat_holds": len(released)})
+	}
+	return err
+}
+
+type seatHoldExpiredEventHandler struct {
+	logger pkgApp.AppLogger
+}
+
+func (h *seatHoldExpiredEventHandler) Handle(ctx context.Context, event pkgDomain.Event[SeatHoldExpiredData]) error {
+	if ctx.Err() != nil {
+		pkgApp.LogError(ctx, h.logger, "Contexto cancelado", ctx.Err(), nil)
+		return ctx.Err()
+	}
+
+	pkgApp.LogInfo(ctx, h.logger, "Evento recebido", map[string]interface{}{"event": event.Payload()})
+	return nil
+}
+
+func NewSeatHoldExpiredEventHandler(logger pkgApp.AppLogger) pkgApp.EventHandler[pkgDomain.Event[SeatHoldExpiredData], SeatHoldExpiredData] {
+	return &seatHoldExpiredEventHandler{
+		logger: logger,
+	}
+}
+
+// tripInventory builds the seat map of trip from its tickets and unexpired
+// holds.
+func tripInventory(ctx context.Context, repository domain.BusTicketRepository, holds domain.SeatHoldRepository, trip domain.Trip) (domain.SeatInventory, error) {
+	busTickets, err := repository.FindByTrip(ctx, trip.Key())
+	if err != nil {
+		return domain.SeatInventory{}, err
+	}
+	tripHolds, err := holds.FindByTrip(ctx, trip.ID)
+	if err != nil {
+		return domain.SeatInventory{}, err
+	}
+	return domain.NewSeatInventory(trip.Key(), trip.Capacity, busTickets, tripHolds), nil
+}
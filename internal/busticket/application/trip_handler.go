@@ -0,0 +1,305 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/mateusmacedo/go-bff/internal/busticket/domain"
+	pkgApp "github.com/mateusmacedo/go-bff/pkg/application"
+	pkgDomain "github.com/mateusmacedo/go-bff/pkg/domain"
+)
+
+type createRouteHandler struct {
+	routes domain.RouteRepository
+	logger pkgApp.AppLogger
+}
+
+func (h *createRouteHandler) Handle(ctx context.Context, command pkgDomain.Command[CreateRouteData]) error {
+	if ctx.Err() != nil {
+		pkgApp.LogError(ctx, h.logger, "Contexto cancelado", ctx.Err(), nil)
+		return ctx.Err()
+	}
+
+	data := command.Payload()
+	route, err := domain.NewRoute(data.ID, data.Origin, data.Destination)
+	if err != nil {
+		pkgApp.LogError(ctx, h.logger, "Rota inválida", err, map[string]interface{}{"route": data})
+		return err
+	}
+	if err := h.routes.Save(ctx, route); err != nil {
+		pkgApp.LogError(ctx, h.logger, "Erro ao salvar rota", err, map[string]interface{}{"route": route})
+		return err
+	}
+
+	pkgApp.LogInfo(ctx, h.logger, "Rota criada", map[string]interface{}{"route": route})
+	return nil
+}
+
+func NewCreateRouteHandler(routes domain.RouteRepository, logger pkgApp.AppLogger) pkgApp.CommandHandler[pkgDomain.Command[CreateRouteData], CreateRouteData] {
+	return &createRouteHandler{
+		routes: routes,
+		logger: logger,
+	}
+}
+
+type createTripHandler struct {
+	routes domain.RouteRepository
+	trips  domain.TripRepository
+	clock  pkgDomain.Clock
+	logger pkgApp.AppLogger
+}
+
+func (h *createTripHandler) Handle(ctx context.Context, command pkgDomain.Command[CreateTripData]) error {
+	if ctx.Err() != nil {
+		pkgApp.LogError(ctx, h.logger, "Contexto cancelado", ctx.Err(), nil)
+		return ctx.Err()
+	}
+
+	data := command.Payload()
+	route, err := h.routes.FindByID(ctx, data.RouteID)
+	if err != nil {
+		pkgApp.LogError(ctx, h.logger, "Erro ao buscar rota", err, map[string]interface{}{"route_id": data.RouteID})
+		return err
+	}
+
+	trip, err := domain.NewTrip(data.ID, route, data.DepartureTime, data.Vehicle, data.Capacity, data.Fare, h.clock())
+	if err != nil {
+		pkgApp.LogError(ctx, h.logger, "Viagem inválida", err, map[string]interface{}{"trip": data})
+		return err
+	}
+	if err := h.trips.Save(ctx, trip); err != nil {
+		pkgApp.LogError(ctx, h.logger, "Erro ao salvar viagem", err, map[string]interface{}{"trip": trip})
+		return err
+	}
+
+	pkgApp.LogInfo(ctx, h.logger, "Viagem criada", map[string]interface{}{"trip": trip})
+	return nil
+}
+
+func NewCreateTripHandler(routes domain.RouteRepository, trips domain.TripRepository, clock pkgDomain.Clock, logger pkgApp.AppLogger) pkgApp.CommandHandler[pkgDomain.Command[CreateTripData], CreateTripData] {
+	return &createTripHandler{
+		routes: routes,
+		trips:  trips,
+		clock:  clock,
+		logger: logger,
+	}
+}
+
+type cancelTripHandler struct {
+	trips  domain.TripRepository
+	clock  pkgDomain.Clock
+	logger pkgApp.AppLogger
+}
+
+// Handle only stops new reservations; the trip's tickets are kept.
+func (h *cancelTripHandler) Handle(ctx context.Context, command pkgDomain.Command[CancelTripData]) error {
+	if ctx.Err() != nil {
+		pkgApp.LogError(ctx, h.logger, "Contexto cancelado", ctx.Err(), nil)
+		return ctx.Err()
+	}
+
+	data := command.Payload()
+	trip, err := h.trips.FindByID(ctx, data.ID)
+	if err != nil {
+		pkgApp.LogError(ctx, h.logger, "Erro ao buscar viagem", err, map[string]interface{}{"trip_id": data.ID})
+		return err
+	}
+	if err := trip.Cancel(h.clock()); err != nil {
+		pkgApp.LogError(ctx, h.logger, "Viagem não pode ser cancelada", err, map[string]interface{}{"trip": trip})
+		return err
+	}
+	if err := h.trips.Update(ctx, trip); err != nil {
+		pkgApp.LogError(ctx, h.logger, "Erro ao cancelar viagem", err, map[string]interface{}{"trip": trip})
+		return err
+	}
+
+	pkgApp.LogInfo(ctx, h.logger, "Viagem cancelada", map[string]interface{}{"trip": trip})
+	return nil
+}
+
+func NewCancelTripHandler(trips domain.TripRepository, clock pkgDomain.Clock, logger pkgApp.AppLogger) pkgApp.CommandHandler[pkgDomain.Command[CancelTripData], CancelTripData] {
+	return &cancelTripHandler{
+		trips:  trips,
+		clock:  clock,
+		logger: logger,
+	}
+}
+
+type delayTripHandler struct {
+	repository domain.BusTicketRepository
+	trips      domain.TripRepository
+	clock      pkgDomain.Clock
+	logger     pkgApp.AppLogger
+}
+
+// Handle moves the departure of the trip and then of its tickets. A trip
+// already at the new departure is not delayed again, so that running the
+// command once more finishes the tickets a failed run left behind.
+func (h *delayTripHandler) Handle(ctx context.Context, command pkgDomain.Command[DelayTripData]) error {
+	if ctx.Err() != nil {
+		pkgApp.LogError(ctx, h.logger, "Contexto cancelado", ctx.Err(), nil)
+		return ctx.Err()
+	}
+
+	data := command.Payload()
+	trip, err := h.trips.FindByID(ctx, data.ID)
+	if err != nil {
+		pkgApp.LogError(ctx, h.logger, "Erro ao buscar viagem", err, map[string]interface{}{"trip_id": data.ID})
+		return err
+	}
+	if !trip.DepartureTime.Equal(data.DepartureTime) {
+		if err := trip.Delay(data.DepartureTime, h.clock()); err != nil {
+			pkgApp.LogError(ctx, h.logger, "Viagem não pode ser atrasada", err, map[string]interface{}{"trip": trip})
+			return err
+		}
+		if err := h.trips.Update(ctx, trip); err != nil {
+			pkgApp.LogError(ctx, h.logger, "Erro ao atrasar viagem", err, map[string]interface{}{"trip": trip})
+			return err
+		}
+	}
+
+	busTickets, err := h.repository.FindByTrip(ctx, trip.Key())
+	if err != nil {
+		pkgApp.LogError(ctx, h.logger, "Erro ao buscar passagens da viagem", err, map[string]interface{}{"trip_id": trip.ID})
+		return err
+	}
+	for _, busTicket := range busTickets {
+		if busTicket.DepartureTime.Equal(trip.DepartureTime) {
+			continue
+		}
+		busTicket.DepartureTime = trip.DepartureTime
+		if err := h.repository.Update(ctx, busTicket); err != nil {
+			pkgApp.LogError(ctx, h.logger, "Erro ao atualizar partida da passagem", err, map[string]interface{}{"bus_ticket": busTicket})
+			return err
+		}
+	}
+
+	pkgApp.LogInfo(ctx, h.logger, "Viagem atrasada", map[string]interface{}{"trip": trip, "bus_tickets": len(busTickets)})
+	return nil
+}
+
+func NewDelayTripHandler(repo domain.BusTicketRepository, trips domain.TripRepository, clock pkgDomain.Clock, logger pkgApp.AppLogger) pkgApp.CommandHandler[pkgDomain.Command[DelayTripData], DelayTripData] {
+	return &delayTripHandler{
+		repository: repo,
+		trips:      trips,
+		clock:      clock,
+		logger:     logger,
+	}
+}
+
+type getRouteByIDHandler struct {
+	repository domain.BusTicketRepository
+	holds      domain.SeatHoldRepository
+	routes     domain.RouteRepository
+	trips      domain.TripRepository
+	clock      pkgDomain.Clock
+	logger     pkgApp.AppLogger
+}
+
+func (h *getRouteByIDHandler) Handle(ctx context.Context, query pkgDomain.Query[GetRouteByIDData]) (RouteDetails, error) {
+	if ctx.Err() != nil {
+		pkgApp.LogError(ctx, h.logger, "Contexto cancelado", ctx.Err(), nil)
+		return RouteDetails{}, ctx.Err()
+	}
+
+	data := query.Payload()
+	route, err := h.routes.FindByID(ctx, data.ID)
+	if err != nil {
+		pkgApp.LogError(ctx, h.logger, "Erro ao buscar rota", err, map[string]interface{}{"id": data.ID})
+		return RouteDetails{}, err
+	}
+	trips, err := h.trips.FindByRoute(ctx, route.ID)
+	if err != nil {
+		pkgApp.LogError(ctx, h.logger, "Erro ao buscar viagens da rota", err, map[string]interface{}{"id": data.ID})
+		return RouteDetails{}, err
+	}
+
+	details := RouteDetails{Route: route, Trips: make([]TripDetails, 0, len(trips))}
+	for _, trip := range trips {
+		tripDetails, err := describeTrip(ctx, h.repository, h.holds, trip, route, h.clock())
+		if err != nil {
+			pkgApp.LogError(ctx, h.logger, "Erro ao buscar assentos da viagem", err, map[string]interface{}{"trip_id": trip.ID})
+			return RouteDetails{}, err
+		}
+		details.Trips = append(details.Trips, tripDetails)
+	}
+
+	pkgApp.LogInfo(ctx, h.logger, "Rota encontrada", map[string]interface{}{"route": route, "trips": len(trips)})
+	return details, nil
+}
+
+func NewGetRouteByIDHandler(repo domain.BusTicketRepository, holds domain.SeatHoldRepository, routes domain.RouteRepository, trips domain.TripRepository, clock pkgDomain.Clock, logger pkgApp.AppLogger) pkgApp.QueryHandler[pkgDomain.Query[GetRouteByIDData], GetRouteByIDData, RouteDetails] {
+	return &getRouteByIDHandler{
+		repository: repo,
+		holds:      holds,
+		routes:     routes,
+		trips:      trips,
+		clock:      clock,
+		logger:     logger,
+	}
+}
+
+type getTripByIDHandler struct {
+	repository domain.BusTicketRepository
+	holds      domain.SeatHoldRepository
+	routes     domain.RouteRepository
+	trips      domain.TripRepository
+	clock      pkgDomain.Clock
+	logger     pkgApp.AppLogger
+}
+
+func (h *getTripByIDHandler) Handle(ctx context.Context, query pkgDomain.Query[GetTripByIDData]) (TripDetails, error) {
+	if ctx.Err() != nil {
+		pkgApp.LogError(ctx, h.logger, "Contexto cancelado", ctx.Err(), nil)
+		return TripDetails{}, ctx.Err()
+	}
+
+	data := query.Payload()
+	trip, err := h.trips.FindByID(ctx, data.ID)
+	if err != nil {
+		pkgApp.LogError(ctx, h.logger, "Erro ao buscar viagem", err, map[string]interface{}{"id": data.ID})
+		return TripDetails{}, err
+	}
+	route, err := h.routes.FindByID(ctx, trip.RouteID)
+	if err != nil {
+		pkgApp.LogError(ctx, h.logger, "Erro ao buscar rota", err, map[string]interface{}{"route_id": trip.RouteID})
+		return TripDetails{}, err
+	}
+
+	details, err := describeTrip(ctx, h.repository, h.holds, trip, route, h.clock())
+	if err != nil {
+		pkgApp.LogError(ctx, h.logger, "Erro ao buscar assentos da viagem", err, map[string]interface{}{"trip_id": trip.ID})
+		return TripDetails{}, err
+	}
+
+	pkgApp.LogInfo(ctx, h.logger, "Viagem encontrada", map[string]interface{}{"trip": details})
+	return details, nil
+}
+
+func NewGetTripByIDHandler(repo domain.BusTicketRepository, holds domain.SeatHoldRepository, routes domain.RouteRepository, trips domain.TripRepository, clock pkgDomain.Clock, logger pkgApp.AppLogger) pkgApp.QueryHandler[pkgDomain.Query[GetTripByIDData], GetTripByIDData, TripDetails] {
+	return &getTripByIDHandler{
+		repository: repo,
+		holds:      holds,
+		routes:     routes,
+		trips:      trips,
+		clock:      clock,
+		logger:     logger,
+	}
+}
+
+// describeTrip counts the seats of trip neither booked nor held and reports
+// its status at now.
+func describeTrip(ctx context.Context, repository domain.BusTicketRepository, holds domain.SeatHoldRepository, trip domain.Trip, route domain.Route, now time.Time) (TripDetails, error) {
+	inventory, err := tripInventory(ctx, repository, holds, trip)
+	if err != nil {
+		return TripDetails{}, err
+	}
+
+	trip.Status = trip.StatusAt(now)
+	return TripDetails{
+		Trip:           trip,
+		Origin:         route.Origin,
+		Destination:    route.Destination,
+		AvailableSeats: len(inventory.Available()),
+	}, nil
+}
@@ -0,0 +1,78 @@
+package busticket_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/mateusmacedo/go-bff/internal/busticket"
+	"github.com/mateusmacedo/go-bff/internal/busticket/application"
+	bustickettestkit "github.com/mateusmacedo/go-bff/internal/busticket/testkit"
+	pkgApp "github.com/mateusmacedo/go-bff/pkg/application"
+	pkgDomain "github.com/mateusmacedo/go-bff/pkg/domain"
+	"github.com/mateusmacedo/go-bff/pkg/testkit"
+)
+
+func TestAuthorizedBuses(t *testing.T) {
+	cancelTrip := testkit.NewRecordingCommandBus[pkgDomain.Command[application.CancelTripData], application.CancelTripData]()
+	reserve := testkit.NewRecordingCommandBus[pkgDomain.Command[application.ReserveBusTicketData], application.ReserveBusTicketData]()
+	authorized := busticket.AuthorizedBuses(busticket.Buses{CancelTrip: cancelTrip, ReserveBusTicket: reserve}, testkit.NewLogger(t))
+
+	passenger := pkgApp.Principal{ID: "ana", Roles: []string{application.RolePassenger}, Attributes: map[string]string{application.PassengerNameAttribute: "Ana"}}
+	agent := pkgApp.Principal{ID: "bia", Roles: []string{application.RoleAgent}}
+	as := func(principal pkgApp.Principal) context.Context {
+		return pkgApp.WithPrincipal(context.Background(), principal)
+	}
+
+	tests := []struct {
+		name     string
+		dispatch func() error
+		allowed  bool
+	}{
+		{"refuses a trip cancellation without a principal", func() error {
+			return authorized.CancelTrip.Dispatch(context.Background(), application.NewCancelTripCommand(application.CancelTripData{ID: "t1"}))
+		}, false},
+		{"refuses a trip cancellation by a passenger", func() error {
+			return authorized.CancelTrip.Dispatch(as(passenger), application.NewCancelTripCommand(application.CancelTripData{ID: "t1"}))
+		}, false},
+		{"passes a trip cancellation by an agent on", func() error {
+			return authorized.CancelTrip.Dispatch(as(agent), application.NewCancelTripCommand(application.CancelTripData{ID: "t1"}))
+		}, true},
+		{"refuses a reservation for another passenger", func() error {
+			return authorized.ReserveBusTicket.Dispatch(as(passenger), application.NewReserveBusTicketCommand(application.ReserveBusTicketData{TripID: "t1", PassengerName: "Bia", SeatNumber: 1}))
+		}, false},
+		{"passes a passenger's own reservation on", func() error {
+			return authorized.ReserveBusTicket.Dispatch(as(passenger), application.NewReserveBusTicketCommand(application.ReserveBusTicketData{TripID: "t1", PassengerName: "Ana", SeatNumber: 1}))
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cancelTrip.Reset()
+			reserve.Reset()
+
+			err := tt.dispatch()
+			dispatched := len(cancelTrip.Dispatched()) + len(reserve.Dispatched())
+			if tt.allowed && (err != nil || dispatched != 1) {
+				t.Fatalf("Dispatch() error = %v with %d dispatches, want it passed on", err, dispatched)
+			}
+			if !tt.allowed && (!errors.Is(err, pkgApp.ErrForbidden) || dispatched != 0) {
+				t.Fatalf("Dispatch() error = %v with %d dispatches, want %v before the bus", err, dispatched, pkgApp.ErrForbidden)
+			}
+		})
+	}
+}
+
+func TestForbiddenRequests(t *testing.T) {
+	h := bustickettestkit.NewHarness(t)
+	trip := saveTrip(t, h, 2)
+	reserveSeat := application.ReserveBusTicketData{TripID: trip.ID, PassengerName: "Bia", SeatNumber: 1}
+
+	resp, body := h.ReserveBusTicket(reserveSeat, bustickettestkit.AsPassenger("Ana"))
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("reserving for another passenger = %d %s, want %d", resp.StatusCode, body, http.StatusForbidden)
+	}
+	if resp, body := h.ReserveBusTicket(reserveSeat, bustickettestkit.AsPassenger("Bia")); resp.StatusCode != http.StatusCreated {
+		t.Fatalf("reserving the refused seat = %d %s, want %d", resp.StatusCode, body, http.StatusCreated)
+	}
+}
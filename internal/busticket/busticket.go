@@ -1,6 +1,10 @@
 package busticket
 
 import (
+	"database/sql"
+	"errors"
+	"time"
+
 	"github.com/go-chi/chi/v5"
 
 	"github.com/mateusmacedo/go-bff/internal/busticket/application"
@@ -8,46 +12,259 @@ import (
 	"github.com/mateusmacedo/go-bff/internal/busticket/infrastructure"
 	pkgApp "github.com/mateusmacedo/go-bff/pkg/application"
 	pkgDomain "github.com/mateusmacedo/go-bff/pkg/domain"
+	pkgInfra "github.com/mateusmacedo/go-bff/pkg/infrastructure"
+	sqlAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/sql/adapter"
+)
+
+// Role selects which side of the slice a process runs: RoleAPI serves HTTP
+// and dispatches messages, RoleWorker handles them, RoleAll does both.
+type Role int
+
+const (
+	RoleAll Role = iota
+	RoleAPI
+	RoleWorker
 )
 
+func (r Role) servesHTTP() bool {
+	return r != RoleWorker
+}
+
+func (r Role) handlesMessages() bool {
+	return r != RoleAPI
+}
+
+type sliceOptions struct {
+	role          Role
+	conflictRetry *pkgInfra.RetryPolicy
+	clock         pkgDomain.Clock
+	refundPolicy  domain.RefundPolicy
+	seatHoldTTL   time.Duration
+	transactions  *sql.DB
+	asyncReserve  bool
+}
+
+type SliceOption func(*sliceOptions)
+
+func WithRole(role Role) SliceOption {
+	return func(o *sliceOptions) {
+		o.role = role
+	}
+}
+
+// WithConflictRetry retries the slice's commands that fail on a concurrent
+// update of a ticket or trip, where the handlers run.
+func WithConflictRetry(policy pkgInfra.RetryPolicy) SliceOption {
+	return func(o *sliceOptions) {
+		o.conflictRetry = &policy
+	}
+}
+
+// WithClock replaces time.Now as the time handlers check trips against.
+func WithClock(clock pkgDomain.Clock) SliceOption {
+	return func(o *sliceOptions) {
+		o.clock = clock
+	}
+}
+
+// WithRefundPolicy replaces domain.DefaultRefundPolicy as the refunds of
+// cancelled tickets.
+func WithRefundPolicy(policy domain.RefundPolicy) SliceOption {
+	return func(o *sliceOptions) {
+		o.refundPolicy = policy
+	}
+}
+
+// WithSeatHoldTTL replaces domain.DefaultSeatHoldTTL as how long seats stay
+// held before their reservation is confirmed.
+func WithSeatHoldTTL(ttl time.Duration) SliceOption {
+	return func(o *sliceOptions) {
+		o.seatHoldTTL = ttl
+	}
+}
+
+// WithTransactions runs the slice's command handlers in transactions on db,
+// which the SQL repositories and the SQL transport's publisher join, so that
+// a handler's writes and the events it publishes commit together.
+func WithTransactions(db *sql.DB) SliceOption {
+	return func(o *sliceOptions) {
+		o.transactions = db
+	}
+}
+
+// WithAsynchronousReservations tells the HTTP API that the ReserveBusTicket
+// bus returns once the command is queued, before a handler saved the ticket,
+// so reservations are answered with 202 Accepted instead of 201 Created.
+func WithAsynchronousReservations() SliceOption {
+	return func(o *sliceOptions) {
+		o.asyncReserve = true
+	}
+}
+
+// Repositories are where the slice keeps its aggregates.
+type Repositories struct {
+	BusTickets domain.BusTicketRepository
+	Routes     domain.RouteRepository
+	Trips      domain.TripRepository
+	SeatHolds  domain.SeatHoldRepository
+}
+
+// Buses are the buses the slice's messages travel on, one per message type.
+type Buses struct {
+	ReserveBusTicket   pkgApp.CommandBus[pkgDomain.Command[application.ReserveBusTicketData], application.ReserveBusTicketData]
+	GetBusTicketByID   pkgApp.QueryBus[pkgDomain.Query[application.GetBusTicketByIDData], application.GetBusTicketByIDData, domain.BusTicket]
+	SearchBusTickets   pkgApp.QueryBus[pkgDomain.Query[application.SearchBusTicketsData], application.SearchBusTicketsData, application.SearchBusTicketsResult]
+	CancelBusTicket    pkgApp.CommandBus[pkgDomain.Command[application.CancelBusTicketData], application.CancelBusTicketData]
+	BusTicketBooked    pkgApp.EventBus[pkgDomain.Event[string], string]
+	BusTicketCancelled pkgApp.EventBus[pkgDomain.Event[application.BusTicketCancelledData], application.BusTicketCancelledData]
+	CreateRoute        pkgApp.CommandBus[pkgDomain.Command[application.CreateRouteData], application.CreateRouteData]
+	CreateTrip         pkgApp.CommandBus[pkgDomain.Command[application.CreateTripData], application.CreateTripData]
+	CancelTrip         pkgApp.CommandBus[pkgDomain.Command[application.CancelTripData], application.CancelTripData]
+	DelayTrip          pkgApp.CommandBus[pkgDomain.Command[application.DelayTripData], application.DelayTripData]
+	GetRouteByID       pkgApp.QueryBus[pkgDomain.Query[application.GetRouteByIDData], application.GetRouteByIDData, application.RouteDetails]
+	GetTripByID        pkgApp.QueryBus[pkgDomain.Query[application.GetTripByIDData], application.GetTripByIDData, application.TripDetails]
+	HoldSeat           pkgApp.CommandBus[pkgDomain.Command[application.HoldSeatData], application.HoldSeatData]
+	ConfirmReservation pkgApp.CommandBus[pkgDomain.Command[application.ConfirmReservationData], application.ConfirmReservationData]
+	// ReleaseExpiredSeatHolds is dispatched periodically by the processes
+	// that handle messages, not over HTTP.
+	ReleaseExpiredSeatHolds pkgApp.CommandBus[pkgDomain.Command[application.ReleaseExpiredSeatHoldsData], application.ReleaseExpiredSeatHoldsData]
+	SeatHoldExpired         pkgApp.EventBus[pkgDomain.Event[application.SeatHoldExpiredData], application.SeatHoldExpiredData]
+}
+
 type BusTicketSlice struct {
-	httpHandler *infrastructure.BusTicketHTTPHandler
+	httpHandler     *infrastructure.BusTicketHTTPHandler
+	tripHTTPHandler *infrastructure.TripHTTPHandler
+	healthChecks    []pkgApp.HealthCheck
 }
 
 func NewBusTicketSlice(
-	commandBus pkgApp.CommandBus[pkgDomain.Command[application.ReserveBusTicketData], application.ReserveBusTicketData],
-	queryBus pkgApp.QueryBus[pkgDomain.Query[application.FindBusTicketData], application.FindBusTicketData, []domain.BusTicket],
+	buses Buses,
 	idGenerator pkgDomain.IDGenerator[string],
 	logger pkgApp.AppLogger,
-	eventBus pkgApp.EventBus[pkgDomain.Event[string], string],
-	repository domain.BusTicketRepository,
-) *BusTicketSlice {
-	registerHandlers(commandBus, queryBus, eventBus, repository, idGenerator, logger)
+	repositories Repositories,
+	options ...SliceOption,
+) (*BusTicketSlice, error) {
+	sliceOptions := &sliceOptions{role: RoleAll, clock: time.Now, refundPolicy: domain.DefaultRefundPolicy, seatHoldTTL: domain.DefaultSeatHoldTTL}
+	for _, option := range options {
+		option(sliceOptions)
+	}
 
-	httpHandler := infrastructure.NewBusTicketHTTPHandler(commandBus, queryBus)
+	slice := &BusTicketSlice{}
+	if sliceOptions.role.handlesMessages() {
+		handlerBuses := buses
+		if policy := sliceOptions.conflictRetry; policy != nil {
+			handlerBuses.ReserveBusTicket = pkgInfra.NewRetryingCommandBus(buses.ReserveBusTicket, *policy, logger)
+			handlerBuses.CancelBusTicket = pkgInfra.NewRetryingCommandBus(buses.CancelBusTicket, *policy, logger)
+			handlerBuses.CancelTrip = pkgInfra.NewRetryingCommandBus(buses.CancelTrip, *policy, logger)
+			handlerBuses.DelayTrip = pkgInfra.NewRetryingCommandBus(buses.DelayTrip, *policy, logger)
+			handlerBuses.ConfirmReservation = pkgInfra.NewRetryingCommandBus(buses.ConfirmReservation, *policy, logger)
+		}
+		// Each retry gets a transaction of its own, so they wrap the retries.
+		if db := sliceOptions.transactions; db != nil {
+			handlerBuses.ReserveBusTicket = sqlAdapter.NewTransactionalCommandBus(handlerBuses.ReserveBusTicket, db, logger)
+			handlerBuses.CancelBusTicket = sqlAdapter.NewTransactionalCommandBus(handlerBuses.CancelBusTicket, db, logger)
+			handlerBuses.CreateRoute = sqlAdapter.NewTransactionalCommandBus(handlerBuses.CreateRoute, db, logger)
+			handlerBuses.CreateTrip = sqlAdapter.NewTransactionalCommandBus(handlerBuses.CreateTrip, db, logger)
+			handlerBuses.CancelTrip = sqlAdapter.NewTransactionalCommandBus(handlerBuses.CancelTrip, db, logger)
+			handlerBuses.DelayTrip = sqlAdapter.NewTransactionalCommandBus(handlerBuses.DelayTrip, db, logger)
+			handlerBuses.HoldSeat = sqlAdapter.NewTransactionalCommandBus(handlerBuses.HoldSeat, db, logger)
+			handlerBuses.ConfirmReservation = sqlAdapter.NewTransactionalCommandBus(handlerBuses.ConfirmReservation, db, logger)
+			handlerBuses.ReleaseExpiredSeatHolds = sqlAdapter.NewTransactionalCommandBus(handlerBuses.ReleaseExpiredSeatHolds, db, logger)
+		}
+		if err := RegisterHandlers(handlerBuses, repositories, idGenerator, sliceOptions.clock, sliceOptions.refundPolicy, sliceOptions.seatHoldTTL, logger); err != nil {
+			return nil, err
+		}
+		if pinger, ok := repositories.BusTickets.(pkgApp.Pinger); ok {
+			slice.healthChecks = append(slice.healthChecks, pkgApp.HealthCheck{Name: "repository", Check: pinger.Ping})
+		}
+	}
+	if sliceOptions.role.servesHTTP() {
+		authorized := AuthorizedBuses(buses, logger)
+		slice.httpHandler = infrastructure.NewBusTicketHTTPHandler(
+			authorized.ReserveBusTicket,
+			authorized.CancelBusTicket,
+			authorized.GetBusTicketByID,
+			authorized.SearchBusTickets,
+			idGenerator,
+			sliceOptions.asyncReserve,
+		)
+		slice.tripHTTPHandler = infrastructure.NewTripHTTPHandler(infrastructure.TripBuses{
+			CreateRoute:        authorized.CreateRoute,
+			CreateTrip:         authorized.CreateTrip,
+			CancelTrip:         authorized.CancelTrip,
+			DelayTrip:          authorized.DelayTrip,
+			GetRouteByID:       authorized.GetRouteByID,
+			GetTripByID:        authorized.GetTripByID,
+			HoldSeat:           authorized.HoldSeat,
+			ConfirmReservation: authorized.ConfirmReservation,
+		}, idGenerator)
+	}
+	return slice, nil
+}
+
+// AuthorizedBuses checks the policies of the messages clients dispatch
+// before they reach buses. Every entry point of clients, such as the HTTP
+// handlers or a gRPC bus server, dispatches through them.
+func AuthorizedBuses(buses Buses, logger pkgApp.AppLogger) Buses {
+	buses.ReserveBusTicket = pkgInfra.NewAuthorizedCommandBus(buses.ReserveBusTicket, application.NewReserveBusTicketAuthorizer(), logger)
+	buses.CancelBusTicket = pkgInfra.NewAuthorizedCommandBus(buses.CancelBusTicket, application.NewCancelBusTicketAuthorizer(), logger)
+	buses.GetBusTicketByID = pkgInfra.NewAuthorizedQueryBus(buses.GetBusTicketByID, application.NewGetBusTicketByIDAuthorizer(), logger)
+	buses.SearchBusTickets = pkgInfra.NewAuthorizedQueryBus(buses.SearchBusTickets, application.NewSearchBusTicketsAuthorizer(), logger)
+	buses.CreateRoute = pkgInfra.NewAuthorizedCommandBus(buses.CreateRoute, application.NewCreateRouteAuthorizer(), logger)
+	buses.CreateTrip = pkgInfra.NewAuthorizedCommandBus(buses.CreateTrip, application.NewCreateTripAuthorizer(), logger)
+	buses.CancelTrip = pkgInfra.NewAuthorizedCommandBus(buses.CancelTrip, application.NewCancelTripAuthorizer(), logger)
+	buses.DelayTrip = pkgInfra.NewAuthorizedCommandBus(buses.DelayTrip, application.NewDelayTripAuthorizer(), logger)
+	buses.GetRouteByID = pkgInfra.NewAuthorizedQueryBus(buses.GetRouteByID, application.NewGetRouteByIDAuthorizer(), logger)
+	buses.GetTripByID = pkgInfra.NewAuthorizedQueryBus(buses.GetTripByID, application.NewGetTripByIDAuthorizer(), logger)
+	buses.HoldSeat = pkgInfra.NewAuthorizedCommandBus(buses.HoldSeat, application.NewHoldSeatAuthorizer(), logger)
+	buses.ConfirmReservation = pkgInfra.NewAuthorizedCommandBus(buses.ConfirmReservation, application.NewConfirmReservationAuthorizer(), logger)
+	return buses
+}
 
-	return &BusTicketSlice{
-		httpHandler: httpHandler,
+// RegisterRoutes does nothing for RoleWorker slices.
+func (s *BusTicketSlice) RegisterRoutes(router chi.Router, options ...infrastructure.RouteOption) {
+	if s.httpHandler == nil {
+		return
 	}
+	s.httpHandler.RegisterRoutes(router, options...)
+	s.tripHTTPHandler.RegisterRoutes(router, options...)
 }
 
-func (s *BusTicketSlice) RegisterRoutes(router *chi.Mux) {
-	s.httpHandler.RegisterRoutes(router)
+// HealthChecks lists the dependencies the slice needs in its role; the
+// repository is only checked where messages are handled.
+func (s *BusTicketSlice) HealthChecks() []pkgApp.HealthCheck {
+	return s.healthChecks
 }
 
-func registerHandlers(
-	commandBus pkgApp.CommandBus[pkgDomain.Command[application.ReserveBusTicketData], application.ReserveBusTicketData],
-	queryBus pkgApp.QueryBus[pkgDomain.Query[application.FindBusTicketData], application.FindBusTicketData, []domain.BusTicket],
-	eventBus pkgApp.EventBus[pkgDomain.Event[string], string],
-	repository domain.BusTicketRepository,
+// RegisterHandlers wires the slice's handlers without the HTTP side, for
+// processes that only execute messages. It fails when a bus cannot
+// subscribe to one of the messages.
+func RegisterHandlers(
+	buses Buses,
+	repositories Repositories,
 	idGenerator pkgDomain.IDGenerator[string],
+	clock pkgDomain.Clock,
+	refundPolicy domain.RefundPolicy,
+	seatHoldTTL time.Duration,
 	logger pkgApp.AppLogger,
-) {
-	commandHandler := application.NewReserveBusTicketHandler(eventBus, repository, idGenerator, logger)
-	queryHandler := application.NewFindBusTicketHandler(repository, logger)
-	eventHandler := application.NewBusTicketBookedEventHandler(logger)
-
-	commandBus.RegisterHandler("ReserveBusTicket", commandHandler)
-	queryBus.RegisterHandler("FindBusTicket", queryHandler)
-	eventBus.RegisterHandler("BusTicketBooked", eventHandler)
+) error {
+	tickets, routes, trips, holds := repositories.BusTickets, repositories.Routes, repositories.Trips, repositories.SeatHolds
+	return errors.Join(
+		buses.ReserveBusTicket.RegisterHandler("ReserveBusTicket", application.NewReserveBusTicketHandler(buses.BusTicketBooked, tickets, holds, routes, trips, idGenerator, clock, logger)),
+		buses.CancelBusTicket.RegisterHandler("CancelBusTicket", application.NewCancelBusTicketHandler(buses.BusTicketCancelled, tickets, trips, refundPolicy, clock, logger)),
+		buses.GetBusTicketByID.RegisterHandler("GetBusTicketByID", application.NewGetBusTicketByIDHandler(tickets, logger)),
+		buses.SearchBusTickets.RegisterHandler("SearchBusTickets", application.NewSearchBusTicketsHandler(tickets, logger)),
+		buses.BusTicketBooked.RegisterHandler("BusTicketBooked", application.NewBusTicketBookedEventHandler(logger)),
+		buses.BusTicketCancelled.RegisterHandler("BusTicketCancelled", application.NewBusTicketCancelledEventHandler(logger)),
+		buses.CreateRoute.RegisterHandler("CreateRoute", application.NewCreateRouteHandler(routes, logger)),
+		buses.CreateTrip.RegisterHandler("CreateTrip", application.NewCreateTripHandler(routes, trips, clock, logger)),
+		buses.CancelTrip.RegisterHandler("CancelTrip", application.NewCancelTripHandler(trips, clock, logger)),
+		buses.DelayTrip.RegisterHandler("DelayTrip", application.NewDelayTripHandler(tickets, trips, clock, logger)),
+		buses.GetRouteByID.RegisterHandler("GetRouteByID", application.NewGetRouteByIDHandler(tickets, holds, routes, trips, clock, logger)),
+		buses.GetTripByID.RegisterHandler("GetTripByID", application.NewGetTripByIDHandler(tickets, holds, routes, trips, clock, logger)),
+		buses.HoldSeat.RegisterHandler("HoldSeat", application.NewHoldSeatHandler(tickets, holds, trips, seatHoldTTL, clock, logger)),
+		buses.ConfirmReservation.RegisterHandler("ConfirmReservation", application.NewConfirmReservationHandler(buses.BusTicketBooked, tickets, holds, routes, trips, clock, logger)),
+		buses.ReleaseExpiredSeatHolds.RegisterHandler("ReleaseExpiredSeatHolds", application.NewReleaseExpiredSeatHoldsHandler(buses.SeatHoldExpired, holds, logger)),
+		buses.SeatHoldExpired.RegisterHandler("SeatHoldExpired", application.NewSeatHoldExpiredEventHandler(logger)),
+	)
 }
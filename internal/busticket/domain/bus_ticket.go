@@ -2,21 +2,84 @@ package domain
 
 import (
 	"context"
+	"fmt"
 	"time"
+
+	pkgDomain "github.com/mateusmacedo/go-bff/pkg/domain"
 )
 
+type BusTicketStatus string
+
+// A ticket is reserved until it is cancelled, which releases its seat.
+// Tickets stored before cancellations existed have no status and count as
+// reserved.
+const (
+	BusTicketReserved  BusTicketStatus = "reserved"
+	BusTicketCancelled BusTicketStatus = "cancelled"
+)
+
+// BusTicket copies the route, departure and fare of its trip. TripID is
+// empty on tickets reserved before trips existed. Price and Refund are in
+// cents.
 type BusTicket struct {
-	ID            string    `json:"id" gorm:"primaryKey"`
-	PassengerName string    `json:"passengerName" gorm:"index"`
-	DepartureTime time.Time `json:"departureTime"`
-	SeatNumber    int       `json:"seatNumber"`
-	Origin        string    `json:"origin"`
-	Destination   string    `json:"destination"`
+	ID            string          `json:"id" gorm:"primaryKey"`
+	TenantID      string          `json:"tenantId,omitempty" gorm:"index"`
+	TripID        string          `json:"tripId,omitempty" gorm:"index"`
+	PassengerName string          `json:"passengerName" gorm:"index"`
+	DepartureTime time.Time       `json:"departureTime"`
+	SeatNumber    int             `json:"seatNumber"`
+	Origin        string          `json:"origin"`
+	Destination   string          `json:"destination"`
+	Status        BusTicketStatus `json:"status"`
+	Price         int64           `json:"price"`
+	Refund        int64           `json:"refund"`
+	// Version counts the writes of the ticket, starting at 1 when saved.
+	Version int64 `json:"version"`
+}
+
+var (
+	ErrBusTicketNotFound   = fmt.Errorf("bus ticket %w", pkgDomain.ErrNotFound)
+	ErrConcurrencyConflict = fmt.Errorf("bus ticket %w", pkgDomain.ErrConcurrencyConflict)
+	ErrBusTicketCancelled  = fmt.Errorf("bus ticket cancelled: %w", pkgDomain.ErrConflict)
+)
+
+// HoldsSeat reports whether the ticket still takes its seat on the trip.
+func (bt BusTicket) HoldsSeat() bool {
+	return bt.Status != BusTicketCancelled
+}
+
+// Cancel releases the seat of the ticket, refunding refund of its price.
+func (bt *BusTicket) Cancel(refund int64) error {
+	if bt.Status == BusTicketCancelled {
+		return ErrBusTicketCancelled
+	}
+	bt.Status = BusTicketCancelled
+	bt.Refund = refund
+	return nil
 }
 
+// BusTicketRepository keeps each seat of a trip on at most one ticket: Save
+// and Update return a *SeatUnavailableError instead of double booking.
 type BusTicketRepository interface {
+	// Save stores a new ticket at version 1, whatever busTicket.Version is.
+	// It also returns a *SeatUnavailableError while an unexpired hold other
+	// than the ticket's own, which shares its ID, takes the seat; the check
+	// and the write are atomic.
 	Save(ctx context.Context, busTicket BusTicket) error
 
+	// FindByID returns ErrBusTicketNotFound when the tenant has no ticket id.
+	FindByID(ctx context.Context, id string) (BusTicket, error)
 	FindByPassengerName(ctx context.Context, passengerName string) ([]BusTicket, error)
+	// FindByTrip returns the tickets holding seats of trip, leaving out
+	// cancelled ones.
+	FindByTrip(ctx context.Context, trip TripKey) ([]BusTicket, error)
+	// Search returns a page of the tickets matching filter, ordered by
+	// departure time and then ID.
+	Search(ctx context.Context, filter BusTicketFilter, page PageRequest) (BusTicketPage, error)
+	Count(ctx context.Context, filter BusTicketFilter) (int, error)
+	// Update stores busTicket at busTicket.Version+1 if the stored ticket is
+	// still at busTicket.Version, and returns ErrConcurrencyConflict if not.
 	Update(ctx context.Context, busTicket BusTicket) error
+	// Delete returns ErrBusTicketNotFound when the tenant has no ticket id.
+	Delete(ctx context.Context, id string) error
 }
@@ -0,0 +1,50 @@
+package domain
+
+import "time"
+
+// RefundPolicy refunds a ticket cancelled at least FullRefundNotice before
+// departure in full and one cancelled on shorter notice less the fee of the
+// tier with the longest notice still given. Nothing is refunded once the
+// bus departed, nor when no tier applies.
+type RefundPolicy struct {
+	FullRefundNotice time.Duration
+	FeeTiers         []RefundFeeTier
+}
+
+type RefundFeeTier struct {
+	Notice     time.Duration
+	FeePercent int
+}
+
+// DefaultRefundPolicy refunds in full up to a day before departure, keeps a
+// quarter of the price up to six hours before and half of it after that.
+var DefaultRefundPolicy = RefundPolicy{
+	FullRefundNotice: 24 * time.Hour,
+	FeeTiers: []RefundFeeTier{
+		{Notice: 6 * time.Hour, FeePercent: 25},
+		{Notice: 0, FeePercent: 50},
+	},
+}
+
+// Refund is the part of price refunded for a ticket departing at
+// departureTime and cancelled at now.
+func (p RefundPolicy) Refund(price int64, departureTime, now time.Time) int64 {
+	notice := departureTime.Sub(now)
+	switch {
+	case notice <= 0:
+		return 0
+	case notice >= p.FullRefundNotice:
+		return price
+	}
+
+	tier, found := RefundFeeTier{}, false
+	for _, candidate := range p.FeeTiers {
+		if candidate.Notice <= notice && (!found || candidate.Notice > tier.Notice) {
+			tier, found = candidate, true
+		}
+	}
+	if !found {
+		return 0
+	}
+	return price - price*int64(tier.FeePercent)/100
+}
@@ -0,0 +1,56 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mateusmacedo/go-bff/internal/busticket/domain"
+)
+
+func TestRefundPolicyRefund(t *testing.T) {
+	departure := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	const price = 12000
+
+	tests := []struct {
+		name   string
+		policy domain.RefundPolicy
+		notice time.Duration
+		want   int64
+	}{
+		{name: "full refund notice exactly", policy: domain.DefaultRefundPolicy, notice: 24 * time.Hour, want: price},
+		{name: "more than the full refund notice", policy: domain.DefaultRefundPolicy, notice: 72 * time.Hour, want: price},
+		{name: "just under the full refund notice", policy: domain.DefaultRefundPolicy, notice: 24*time.Hour - time.Second, want: 9000},
+		{name: "first tier notice exactly", policy: domain.DefaultRefundPolicy, notice: 6 * time.Hour, want: 9000},
+		{name: "just under the first tier notice", policy: domain.DefaultRefundPolicy, notice: 6*time.Hour - time.Second, want: 6000},
+		{name: "moments before departure", policy: domain.DefaultRefundPolicy, notice: time.Second, want: 6000},
+		{name: "at departure", policy: domain.DefaultRefundPolicy, notice: 0, want: 0},
+		{name: "after departure", policy: domain.DefaultRefundPolicy, notice: -time.Hour, want: 0},
+		{
+			name:   "tiers in any order",
+			policy: domain.RefundPolicy{FullRefundNotice: 24 * time.Hour, FeeTiers: []domain.RefundFeeTier{{Notice: 0, FeePercent: 50}, {Notice: 6 * time.Hour, FeePercent: 25}}},
+			notice: 6 * time.Hour,
+			want:   9000,
+		},
+		{
+			name:   "shorter notice than every tier",
+			policy: domain.RefundPolicy{FullRefundNotice: 24 * time.Hour, FeeTiers: []domain.RefundFeeTier{{Notice: 6 * time.Hour, FeePercent: 25}}},
+			notice: time.Hour,
+			want:   0,
+		},
+		{name: "no tiers", policy: domain.RefundPolicy{FullRefundNotice: 24 * time.Hour}, notice: time.Hour, want: 0},
+		{name: "no tiers with the full refund notice", policy: domain.RefundPolicy{FullRefundNotice: 24 * time.Hour}, notice: 24 * time.Hour, want: price},
+		{
+			name:   "whole fee",
+			policy: domain.RefundPolicy{FullRefundNotice: 24 * time.Hour, FeeTiers: []domain.RefundFeeTier{{Notice: 0, FeePercent: 100}}},
+			notice: time.Hour,
+			want:   0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Refund(price, departure, departure.Add(-tt.notice)); got != tt.want {
+				t.Fatalf("Refund() with %s notice = %d, want %d", tt.notice, got, tt.want)
+			}
+		})
+	}
+}
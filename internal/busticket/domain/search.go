@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	pkgDomain "github.com/mateusmacedo/go-bff/pkg/domain"
+)
+
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 200
+)
+
+var ErrInvalidCursor = fmt.Errorf("%w cursor", pkgDomain.ErrInvalid)
+
+// BusTicketFilter selects tickets; zero fields match every ticket. The
+// departure range includes DepartureFrom and excludes DepartureTo.
+type BusTicketFilter struct {
+	PassengerName string
+	Origin        string
+	Destination   string
+	DepartureFrom time.Time
+	DepartureTo   time.Time
+}
+
+func (f BusTicketFilter) Matches(busTicket BusTicket) bool {
+	return (f.PassengerName == "" || busTicket.PassengerName == f.PassengerName) &&
+		(f.Origin == "" || busTicket.Origin == f.Origin) &&
+		(f.Destination == "" || busTicket.Destination == f.Destination) &&
+		(f.DepartureFrom.IsZero() || !busTicket.DepartureTime.Before(f.DepartureFrom)) &&
+		(f.DepartureTo.IsZero() || busTicket.DepartureTime.Before(f.DepartureTo))
+}
+
+// PageRequest asks for the Limit tickets after Cursor, the NextCursor of the
+// previous page; an empty Cursor starts from the first ticket.
+type PageRequest struct {
+	Cursor string
+	Limit  int
+}
+
+// PageLimit clamps Limit to (0, MaxPageLimit], defaulting to
+// DefaultPageLimit.
+func (p PageRequest) PageLimit() int {
+	switch {
+	case p.Limit <= 0:
+		return DefaultPageLimit
+	case p.Limit > MaxPageLimit:
+		return MaxPageLimit
+	default:
+		return p.Limit
+	}
+}
+
+type BusTicketPage struct {
+	BusTickets []BusTicket `json:"busTickets"`
+	// NextCursor is empty on the last page.
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// Cursor is the position of a ticket in the search order. It only holds the
+// ordering keys, so pages stay stable while tickets are added or removed.
+type Cursor struct {
+	DepartureTime time.Time
+	ID            string
+}
+
+func CursorOf(busTicket BusTicket) Cursor {
+	return Cursor{DepartureTime: busTicket.DepartureTime, ID: busTicket.ID}
+}
+
+// IsZero reports whether c is the start of the results.
+func (c Cursor) IsZero() bool {
+	return c.ID == ""
+}
+
+// Precedes reports whether busTicket sorts after the cursor, i.e. belongs to
+// the following pages.
+func (c Cursor) Precedes(busTicket BusTicket) bool {
+	return c.IsZero() || LessBusTicket(BusTicket{DepartureTime: c.DepartureTime, ID: c.ID}, busTicket)
+}
+
+func (c Cursor) Encode() string {
+	raw := c.DepartureTime.UTC().Format(time.RFC3339Nano) + "|" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses an encoded cursor; the empty string decodes to the
+// zero Cursor.
+func DecodeCursor(encoded string) (Cursor, error) {
+	if encoded == "" {
+		return Cursor{}, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	departure, id, found := strings.Cut(string(raw), "|")
+	if !found || id == "" {
+		return Cursor{}, ErrInvalidCursor
+	}
+	departureTime, err := time.Parse(time.RFC3339Nano, departure)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	return Cursor{DepartureTime: departureTime, ID: id}, nil
+}
+
+// LessBusTicket is the search order: departure time, then ID.
+func LessBusTicket(a, b BusTicket) bool {
+	if !a.DepartureTime.Equal(b.DepartureTime) {
+		return a.DepartureTime.Before(b.DepartureTime)
+	}
+	return a.ID < b.ID
+}
+
+// PaginateBusTickets pages tickets already filtered, for repositories that
+// search in memory.
+func PaginateBusTickets(busTickets []BusTicket, page PageRequest) (BusTicketPage, error) {
+	cursor, err := DecodeCursor(page.Cursor)
+	if err != nil {
+		return BusTicketPage{}, err
+	}
+
+	sort.Slice(busTickets, func(i, j int) bool {
+		return LessBusTicket(busTickets[i], busTickets[j])
+	})
+
+	start := sort.Search(len(busTickets), func(i int) bool {
+		return cursor.Precedes(busTickets[i])
+	})
+	return NewBusTicketPage(busTickets[start:], page.PageLimit()), nil
+}
+
+// NewBusTicketPage keeps the first limit of busTickets, which are sorted and
+// may hold one extra ticket to tell whether another page follows.
+func NewBusTicketPage(busTickets []BusTicket, limit int) BusTicketPage {
+	page := BusTicketPage{BusTickets: busTickets}
+	if len(busTickets) > limit {
+		page.BusTickets = busTickets[:limit]
+		page.NextCursor = CursorOf(busTickets[limit-1]).Encode()
+	}
+	if page.BusTickets == nil {
+		page.BusTickets = []BusTicket{}
+	}
+	return page
+}
@@ -0,0 +1,162 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	pkgDomain "github.com/mateusmacedo/go-bff/pkg/domain"
+)
+
+// DefaultSeatCapacity is the number of seats of the trips of tickets
+// reserved before trips carried their own capacity.
+const DefaultSeatCapacity = 44
+
+var (
+	ErrSeatUnavailable = errors.New("seat unavailable")
+	ErrInvalidSeat     = fmt.Errorf("%w seat", pkgDomain.ErrInvalid)
+)
+
+// SeatUnavailableError reports a seat already taken on a trip. It matches
+// both ErrSeatUnavailable and pkgDomain.ErrConflict.
+type SeatUnavailableError struct {
+	Trip       TripKey
+	SeatNumber int
+}
+
+func (e *SeatUnavailableError) Error() string {
+	return fmt.Sprintf("seat %d unavailable on trip %s", e.SeatNumber, e.Trip)
+}
+
+func (e *SeatUnavailableError) Is(target error) bool {
+	return target == ErrSeatUnavailable || target == pkgDomain.ErrConflict
+}
+
+// TripKey identifies the trip whose seats a ticket holds; a seat is held by
+// at most one ticket per tenant and trip. Tickets of a Trip are keyed by its
+// TripID alone, so that delays keep their seats; tickets reserved before
+// trips existed are keyed by their Origin, Destination and DepartureTime.
+type TripKey struct {
+	TripID        string    `json:"tripId,omitempty"`
+	Origin        string    `json:"origin,omitempty"`
+	Destination   string    `json:"destination,omitempty"`
+	DepartureTime time.Time `json:"departureTime,omitempty"`
+}
+
+func (bt BusTicket) Trip() TripKey {
+	if bt.TripID != "" {
+		return TripKey{TripID: bt.TripID}
+	}
+	return TripKey{Origin: bt.Origin, Destination: bt.Destination, DepartureTime: bt.DepartureTime}
+}
+
+func (k TripKey) Equal(other TripKey) bool {
+	return k.TripID == other.TripID && k.Origin == other.Origin && k.Destination == other.Destination && k.DepartureTime.Equal(other.DepartureTime)
+}
+
+func (k TripKey) String() string {
+	if k.TripID != "" {
+		return k.TripID
+	}
+	return k.Origin + "|" + k.Destination + "|" + k.DepartureTime.UTC().Format(time.RFC3339Nano)
+}
+
+type SeatState string
+
+const (
+	SeatAvailable SeatState = "available"
+	SeatBooked    SeatState = "booked"
+	SeatHeld      SeatState = "held"
+)
+
+// SeatInventory is the seat map of one trip, built from its tickets and the
+// holds on its seats.
+type SeatInventory struct {
+	Trip     TripKey
+	Capacity int
+	booked   map[int]string
+	held     map[int]string
+}
+
+// NewSeatInventory leaves out the holds in holds that are not on trip; only
+// trips with an ID have holds.
+func NewSeatInventory(trip TripKey, capacity int, busTickets []BusTicket, holds []SeatHold) SeatInventory {
+	inventory := SeatInventory{Trip: trip, Capacity: capacity, booked: make(map[int]string), held: make(map[int]string)}
+	for _, busTicket := range busTickets {
+		if busTicket.HoldsSeat() && busTicket.Trip().Equal(trip) {
+			inventory.booked[busTicket.SeatNumber] = busTicket.ID
+		}
+	}
+	for _, hold := range holds {
+		if trip.TripID != "" && hold.TripID == trip.TripID {
+			inventory.held[hold.SeatNumber] = hold.ID
+		}
+	}
+	return inventory
+}
+
+func (i SeatInventory) State(seatNumber int) SeatState {
+	if _, booked := i.booked[seatNumber]; booked {
+		return SeatBooked
+	}
+	if _, held := i.held[seatNumber]; held {
+		return SeatHeld
+	}
+	return SeatAvailable
+}
+
+// Available lists the free seats in ascending order.
+func (i SeatInventory) Available() []int {
+	seats := make([]int, 0, i.Capacity)
+	for seat := 1; seat <= i.Capacity; seat++ {
+		if i.State(seat) == SeatAvailable {
+			seats = append(seats, seat)
+		}
+	}
+	return seats
+}
+
+// Booked lists the taken seats in ascending order.
+func (i SeatInventory) Booked() []int {
+	seats := make([]int, 0, len(i.booked))
+	for seat := range i.booked {
+		seats = append(seats, seat)
+	}
+	sort.Ints(seats)
+	return seats
+}
+
+// Reserve books seatNumber for busTicketID in the inventory. It only guards
+// against seats known to be taken or held; the repository enforces the rule
+// atomically when the ticket is saved. A ticket confirming a hold has the
+// hold's ID, so the seat it holds is not in its way.
+func (i SeatInventory) Reserve(seatNumber int, busTicketID string) error {
+	if err := i.claim(seatNumber, busTicketID); err != nil {
+		return err
+	}
+	i.booked[seatNumber] = busTicketID
+	return nil
+}
+
+// Hold blocks seatNumber for holdID in the inventory, as Reserve books it.
+func (i SeatInventory) Hold(seatNumber int, holdID string) error {
+	if err := i.claim(seatNumber, holdID); err != nil {
+		return err
+	}
+	i.held[seatNumber] = holdID
+	return nil
+}
+
+func (i SeatInventory) claim(seatNumber int, id string) error {
+	if seatNumber < 1 || seatNumber > i.Capacity {
+		return fmt.Errorf("%w %d: trip has %d seats", ErrInvalidSeat, seatNumber, i.Capacity)
+	}
+	if holder, booked := i.booked[seatNumber]; booked && holder != id {
+		return &SeatUnavailableError{Trip: i.Trip, SeatNumber: seatNumber}
+	}
+	if holder, held := i.held[seatNumber]; held && holder != id {
+		return &SeatUnavailableError{Trip: i.Trip, SeatNumber: seatNumber}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	pkgDomain "github.com/mateusmacedo/go-bff/pkg/domain"
+)
+
+// DefaultSeatHoldTTL is how long a seat is held unless configured otherwise.
+const DefaultSeatHoldTTL = 10 * time.Minute
+
+// SeatHold blocks a seat of a trip for a passenger until ExpiresAt, while
+// they pay for it. Confirming the hold turns it into a ticket with the same
+// ID; otherwise it is released once expired.
+type SeatHold struct {
+	ID            string    `json:"id" gorm:"primaryKey"`
+	TenantID      string    `json:"tenantId,omitempty"`
+	TripID        string    `json:"tripId"`
+	SeatNumber    int       `json:"seatNumber"`
+	PassengerName string    `json:"passengerName"`
+	ExpiresAt     time.Time `json:"expiresAt"`
+}
+
+var (
+	ErrSeatHoldNotFound = fmt.Errorf("seat hold %w", pkgDomain.ErrNotFound)
+	ErrSeatHoldExpired  = fmt.Errorf("seat hold expired: %w", pkgDomain.ErrConflict)
+	ErrInvalidSeatHold  = fmt.Errorf("%w seat hold", pkgDomain.ErrInvalid)
+)
+
+// NewSeatHold holds seatNumber of trip for passengerName during ttl from now.
+func NewSeatHold(id string, trip Trip, seatNumber int, passengerName string, ttl time.Duration, now time.Time) (SeatHold, error) {
+	hold := SeatHold{
+		ID:            id,
+		TripID:        trip.ID,
+		SeatNumber:    seatNumber,
+		PassengerName: strings.TrimSpace(passengerName),
+		ExpiresAt:     now.Add(ttl).UTC(),
+	}
+	switch {
+	case hold.ID == "":
+		return SeatHold{}, fmt.Errorf("%w: id is required", ErrInvalidSeatHold)
+	case hold.PassengerName == "":
+		return SeatHold{}, fmt.Errorf("%w: passenger name is required", ErrInvalidSeatHold)
+	case ttl <= 0:
+		return SeatHold{}, fmt.Errorf("%w: ttl %s must be positive", ErrInvalidSeatHold, ttl)
+	}
+	return hold, nil
+}
+
+func (h SeatHold) Expired(now time.Time) bool {
+	return !now.Before(h.ExpiresAt)
+}
+
+// SeatHoldRepository stores holds per tenant. Repositories tell expired
+// holds apart with the clock they are built with.
+type SeatHoldRepository interface {
+	// Save stores hold, or returns a *SeatUnavailableError while another
+	// unexpired hold or a ticket takes its seat; the check and the write are
+	// atomic. Expired holds take no seat, though they are kept until
+	// released.
+	Save(ctx context.Context, hold SeatHold) error
+	// FindByID returns ErrSeatHoldNotFound when the tenant has no hold id,
+	// expired or not.
+	FindByID(ctx context.Context, id string) (SeatHold, error)
+	// FindByTrip returns the unexpired holds of a trip ordered by seat.
+	FindByTrip(ctx context.Context, tripID string) ([]SeatHold, error)
+	// Delete returns ErrSeatHoldNotFound when the tenant has no hold id.
+	Delete(ctx context.Context, id string) error
+	// ReleaseExpired deletes the expired holds of every tenant and returns
+	// them, including on error those deleted before it. Each hold is returned
+	// by one call only, however many run at once.
+	ReleaseExpired(ctx context.Context) ([]SeatHold, error)
+}
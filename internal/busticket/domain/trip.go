@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	pkgDomain "github.com/mateusmacedo/go-bff/pkg/domain"
+)
+
+// Route is a line served by the tenant's buses from Origin to Destination.
+type Route struct {
+	ID          string `json:"id" gorm:"primaryKey"`
+	TenantID    string `json:"tenantId,omitempty" gorm:"index"`
+	Origin      string `json:"origin"`
+	Destination string `json:"destination"`
+}
+
+type TripStatus string
+
+// A trip is stored as scheduled, delayed or cancelled; departed is only
+// derived, once its departure time has passed.
+const (
+	TripScheduled TripStatus = "scheduled"
+	TripDelayed   TripStatus = "delayed"
+	TripCancelled TripStatus = "cancelled"
+	TripDeparted  TripStatus = "departed"
+)
+
+// Trip is one departure of a vehicle on a route, with the seats its tickets
+// are reserved on. Fare is the price of a seat in cents.
+type Trip struct {
+	ID            string     `json:"id" gorm:"primaryKey"`
+	TenantID      string     `json:"tenantId,omitempty" gorm:"index"`
+	RouteID       string     `json:"routeId" gorm:"index"`
+	DepartureTime time.Time  `json:"departureTime"`
+	Vehicle       string     `json:"vehicle"`
+	Capacity      int        `json:"capacity"`
+	Fare          int64      `json:"fare"`
+	Status        TripStatus `json:"status"`
+	// Version counts the writes of the trip, starting at 1 when saved.
+	Version int64 `json:"version"`
+}
+
+var (
+	ErrRouteNotFound           = fmt.Errorf("route %w", pkgDomain.ErrNotFound)
+	ErrTripNotFound            = fmt.Errorf("trip %w", pkgDomain.ErrNotFound)
+	ErrInvalidRoute            = fmt.Errorf("%w route", pkgDomain.ErrInvalid)
+	ErrInvalidTrip             = fmt.Errorf("%w trip", pkgDomain.ErrInvalid)
+	ErrTripFull                = fmt.Errorf("trip full: %w", pkgDomain.ErrConflict)
+	ErrTripCancelled           = fmt.Errorf("trip cancelled: %w", pkgDomain.ErrConflict)
+	ErrTripDeparted            = fmt.Errorf("trip departed: %w", pkgDomain.ErrConflict)
+	ErrTripConcurrencyConflict = fmt.Errorf("trip %w", pkgDomain.ErrConcurrencyConflict)
+)
+
+func NewRoute(id, origin, destination string) (Route, error) {
+	route := Route{ID: id, Origin: strings.TrimSpace(origin), Destination: strings.TrimSpace(destination)}
+	switch {
+	case route.ID == "":
+		return Route{}, fmt.Errorf("%w: id is required", ErrInvalidRoute)
+	case route.Origin == "" || route.Destination == "":
+		return Route{}, fmt.Errorf("%w: origin and destination are required", ErrInvalidRoute)
+	case route.Origin == route.Destination:
+		return Route{}, fmt.Errorf("%w: origin and destination must differ", ErrInvalidRoute)
+	}
+	return route, nil
+}
+
+// NewTrip schedules a departure of vehicle on route; trips can only be
+// scheduled ahead of now.
+func NewTrip(id string, route Route, departureTime time.Time, vehicle string, capacity int, fare int64, now time.Time) (Trip, error) {
+	trip := Trip{
+		ID:            id,
+		RouteID:       route.ID,
+		DepartureTime: departureTime.UTC(),
+		Vehicle:       strings.TrimSpace(vehicle),
+		Capacity:      capacity,
+		Fare:          fare,
+		Status:        TripScheduled,
+	}
+	switch {
+	case trip.ID == "":
+		return Trip{}, fmt.Errorf("%w: id is required", ErrInvalidTrip)
+	case trip.Vehicle == "":
+		return Trip{}, fmt.Errorf("%w: vehicle is required", ErrInvalidTrip)
+	case trip.Capacity < 1:
+		return Trip{}, fmt.Errorf("%w: capacity %d must be positive", ErrInvalidTrip, trip.Capacity)
+	case trip.Fare < 0:
+		return Trip{}, fmt.Errorf("%w: fare %d must not be negative", ErrInvalidTrip, trip.Fare)
+	case !trip.DepartureTime.After(now):
+		return Trip{}, fmt.Errorf("%w: departure %s is not in the future", ErrInvalidTrip, trip.DepartureTime.Format(time.RFC3339))
+	}
+	return trip, nil
+}
+
+func (t Trip) Key() TripKey {
+	return TripKey{TripID: t.ID}
+}
+
+// StatusAt is the status of the trip at now, departed once its departure
+// time has passed unless it was cancelled.
+func (t Trip) StatusAt(now time.Time) TripStatus {
+	if t.Status != TripCancelled && !now.Before(t.DepartureTime) {
+		return TripDeparted
+	}
+	return t.Status
+}
+
+// Bookable reports why seats of the trip cannot be reserved at now, if so.
+func (t Trip) Bookable(now time.Time) error {
+	switch t.StatusAt(now) {
+	case TripCancelled:
+		return ErrTripCancelled
+	case TripDeparted:
+		return ErrTripDeparted
+	}
+	return nil
+}
+
+func (t *Trip) Cancel(now time.Time) error {
+	if err := t.Bookable(now); err != nil {
+		return err
+	}
+	t.Status = TripCancelled
+	return nil
+}
+
+// Delay moves the departure of the trip to a later departureTime.
+func (t *Trip) Delay(departureTime time.Time, now time.Time) error {
+	if err := t.Bookable(now); err != nil {
+		return err
+	}
+	if !departureTime.After(t.DepartureTime) {
+		return fmt.Errorf("%w: new departure %s is not after %s", ErrInvalidTrip,
+			departureTime.UTC().Format(time.RFC3339), t.DepartureTime.UTC().Format(time.RFC3339))
+	}
+	t.DepartureTime = departureTime.UTC()
+	t.Status = TripDelayed
+	return nil
+}
+
+type RouteRepository interface {
+	Save(ctx context.Context, route Route) error
+	// FindByID returns ErrRouteNotFound when the tenant has no route id.
+	FindByID(ctx context.Context, id string) (Route, error)
+}
+
+type TripRepository interface {
+	// Save stores a new trip at version 1, whatever trip.Version is.
+	Save(ctx context.Context, trip Trip) error
+	// FindByID returns ErrTripNotFound when the tenant has no trip id.
+	FindByID(ctx context.Context, id string) (Trip, error)
+	// FindByRoute returns the trips of a route ordered by departure time.
+	FindByRoute(ctx context.Context, routeID string) ([]Trip, error)
+	// Update stores trip at trip.Version+1 if the stored trip is still at
+	// trip.Version, and returns ErrTripConcurrencyConflict if not.
+	Update(ctx context.Context, trip Trip) error
+}
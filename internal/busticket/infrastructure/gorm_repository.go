@@ -2,37 +2,99 @@ package infrastructure
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 
 	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 
 	"github.com/mateusmacedo/go-bff/internal/busticket/domain"
 	"github.com/mateusmacedo/go-bff/pkg/application"
+	pkgDomain "github.com/mateusmacedo/go-bff/pkg/domain"
+	sqlAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/sql/adapter"
+)
+
+// The unique seat indexes of migration 0005: tripSeatIndex covers tickets
+// without a trip and tripIDSeatIndex those of a trip. Since migration 0006
+// both leave cancelled tickets out.
+const (
+	tripSeatIndex   = "idx_bus_tickets_trip_seat"
+	tripIDSeatIndex = "idx_bus_tickets_trip_id_seat"
 )
 
 type gormBusTicketRepository struct {
-	db     *gorm.DB
-	logger application.AppLogger
+	db      *gorm.DB
+	dialect sqlAdapter.Dialect
+	clock   pkgDomain.Clock
+	logger  application.AppLogger
 }
 
-func NewGormBusTicketRepository(dsn string, logger application.AppLogger) (domain.BusTicketRepository, error) {
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+// NewGormBusTicketRepository stores tickets in db, opened for dialect, and
+// tells the expired holds of the seat hold table apart with clock. It
+// refuses to start while bus ticket migrations are pending; run
+// "bff migrate up" first.
+func NewGormBusTicketRepository(db *sql.DB, dialect sqlAdapter.Dialect, clock pkgDomain.Clock, logger application.AppLogger) (domain.BusTicketRepository, error) {
+	gormDB, err := openGorm(db, dialect, logger)
 	if err != nil {
 		return nil, err
 	}
 
-	if err = db.AutoMigrate(&domain.BusTicket{}); err != nil {
+	return &gormBusTicketRepository{
+		db:      gormDB,
+		dialect: dialect,
+		clock:   clock,
+		logger:  logger,
+	}, nil
+}
+
+// openGorm opens db with GORM once the bus ticket schema is up to date.
+func openGorm(db *sql.DB, dialect sqlAdapter.Dialect, logger application.AppLogger) (*gorm.DB, error) {
+	migrations, err := BusTicketMigrations(dialect)
+	if err != nil {
 		return nil, err
 	}
 
-	return &gormBusTicketRepository{
-		db:     db,
-		logger: logger,
-	}, nil
+	if err = sqlAdapter.NewMigrator(db, dialect, migrations, logger).Check(context.Background()); err != nil {
+		return nil, err
+	}
+
+	dialector, err := gormDialector(db, dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	return gorm.Open(dialector, &gorm.Config{})
 }
 
+func gormDialector(db *sql.DB, dialect sqlAdapter.Dialect) (gorm.Dialector, error) {
+	switch dialect.Name() {
+	case sqlAdapter.PostgresDialect{}.Name():
+		return postgres.New(postgres.Config{Conn: db}), nil
+	case sqlAdapter.SQLiteDialect{}.Name():
+		return sqlite.New(sqlite.Config{DriverName: "sqlite", Conn: db}), nil
+	default:
+		return nil, fmt.Errorf("no GORM dialector for dialect %q", dialect.Name())
+	}
+}
+
+// Save checks the holds on the seat in the transaction of the insert, under
+// the seat's lock.
 func (r *gormBusTicketRepository) Save(ctx context.Context, busTicket domain.BusTicket) error {
-	if err := r.db.WithContext(ctx).Create(&busTicket).Error; err != nil {
+	busTicket.TenantID = application.TenantID(ctx)
+	busTicket.DepartureTime = busTicket.DepartureTime.UTC()
+	busTicket.Version = 1
+	err := r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockSeat(tx, r.dialect, busTicket); err != nil {
+			return err
+		}
+		if err := tx.Create(&busTicket).Error; err != nil {
+			return r.seatError(err, busTicket)
+		}
+		return seatHeld(tx, busTicket, r.clock().UTC())
+	})
+	if err != nil {
 		application.LogError(ctx, r.logger, "failed to save busTicket", err, map[string]interface{}{
 			"busTicket": busTicket,
 		})
@@ -45,10 +107,25 @@ func (r *gormBusTicketRepository) Save(ctx context.Context, busTicket domain.Bus
 	return nil
 }
 
+func (r *gormBusTicketRepository) FindByID(ctx context.Context, id string) (domain.BusTicket, error) {
+	var busTicket domain.BusTicket
+	err := r.conn(ctx).Scopes(tenantScope(ctx)).Where("id = ?", id).Take(&busTicket).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.BusTicket{}, domain.ErrBusTicketNotFound
+	}
+	if err != nil {
+		application.LogError(ctx, r.logger, "failed to find busTicket", err, map[string]interface{}{
+			"id": id,
+		})
+		return domain.BusTicket{}, err
+	}
+	return busTicket, nil
+}
+
 func (r *gormBusTicketRepository) FindByPassengerName(ctx context.Context, passengerName string) ([]domain.BusTicket, error) {
 	var busTickets []domain.BusTicket
 
-	if err := r.db.WithContext(ctx).Where("passenger_name = ?", passengerName).Find(&busTickets).Error; err != nil {
+	if err := r.conn(ctx).Scopes(tenantScope(ctx)).Where("passenger_name = ?", passengerName).Find(&busTickets).Error; err != nil {
 		application.LogError(ctx, r.logger, "failed to find busTickets", err, map[string]interface{}{
 			"passengerName": passengerName,
 		})
@@ -63,12 +140,36 @@ func (r *gormBusTicketRepository) FindByPassengerName(ctx context.Context, passe
 	return busTickets, nil
 }
 
+// Update is a compare-and-swap on the version column.
 func (r *gormBusTicketRepository) Update(ctx context.Context, busTicket domain.BusTicket) error {
-	if err := r.db.WithContext(ctx).Model(&domain.BusTicket{}).Where("id = ?", busTicket.ID).Updates(busTicket).Error; err != nil {
+	busTicket.TenantID = application.TenantID(ctx)
+	busTicket.DepartureTime = busTicket.DepartureTime.UTC()
+	expected := busTicket.Version
+	busTicket.Version++
+	result := r.conn(ctx).Model(&domain.BusTicket{}).Scopes(tenantScope(ctx)).
+		Where("id = ? AND version = ?", busTicket.ID, expected).
+		Select("trip_id", "passenger_name", "departure_time", "seat_number", "origin", "destination", "status", "price", "refund", "version").
+		Updates(busTicket)
+	if err := result.Error; err != nil {
 		application.LogError(ctx, r.logger, "failed to update busTicket", err, map[string]interface{}{
 			"busTicket": busTicket,
 		})
-		return err
+		return r.seatError(err, busTicket)
+	}
+
+	if result.RowsAffected == 0 {
+		// Either the ticket is gone or another writer moved its version on.
+		if _, err := r.FindByID(ctx, busTicket.ID); err != nil {
+			application.LogInfo(ctx, r.logger, "busTicket not found", map[string]interface{}{
+				"busTicket": busTicket,
+			})
+			return err
+		}
+		application.LogInfo(ctx, r.logger, "busTicket version conflict", map[string]interface{}{
+			"busTicket": busTicket,
+			"expected":  expected,
+		})
+		return domain.ErrConcurrencyConflict
 	}
 
 	application.LogInfo(ctx, r.logger, "busTicket updated", map[string]interface{}{
@@ -77,3 +178,140 @@ func (r *gormBusTicketRepository) Update(ctx context.Context, busTicket domain.B
 
 	return nil
 }
+
+func (r *gormBusTicketRepository) FindByTrip(ctx context.Context, trip domain.TripKey) ([]domain.BusTicket, error) {
+	query := r.conn(ctx).Scopes(tenantScope(ctx)).Where("status <> ?", domain.BusTicketCancelled)
+	if trip.TripID != "" {
+		query = query.Where("trip_id = ?", trip.TripID)
+	} else {
+		query = query.Where("trip_id = '' AND origin = ? AND destination = ? AND departure_time = ?", trip.Origin, trip.Destination, trip.DepartureTime.UTC())
+	}
+
+	var busTickets []domain.BusTicket
+	err := query.Order("seat_number").Find(&busTickets).Error
+	if err != nil {
+		application.LogError(ctx, r.logger, "failed to find busTickets of trip", err, map[string]interface{}{
+			"trip": trip.String(),
+		})
+		return nil, err
+	}
+	return busTickets, nil
+}
+
+// seatError turns a violation of a trip seat index into the domain error.
+func (r *gormBusTicketRepository) seatError(err error, busTicket domain.BusTicket) error {
+	if r.dialect.IsUniqueViolation(err, tripSeatIndex) || r.dialect.IsUniqueViolation(err, tripIDSeatIndex) {
+		return &domain.SeatUnavailableError{Trip: busTicket.Trip(), SeatNumber: busTicket.SeatNumber}
+	}
+	return err
+}
+
+// Search pages with a keyset on (departure_time, id), which the ORDER BY
+// makes stable.
+func (r *gormBusTicketRepository) Search(ctx context.Context, filter domain.BusTicketFilter, page domain.PageRequest) (domain.BusTicketPage, error) {
+	cursor, err := domain.DecodeCursor(page.Cursor)
+	if err != nil {
+		return domain.BusTicketPage{}, err
+	}
+
+	query := r.conn(ctx).Scopes(tenantScope(ctx), filterScope(filter))
+	if !cursor.IsZero() {
+		departureTime := cursor.DepartureTime.UTC()
+		query = query.Where("departure_time > ? OR (departure_time = ? AND id > ?)", departureTime, departureTime, cursor.ID)
+	}
+
+	limit := page.PageLimit()
+	var busTickets []domain.BusTicket
+	if err := query.Order("departure_time, id").Limit(limit + 1).Find(&busTickets).Error; err != nil {
+		application.LogError(ctx, r.logger, "failed to search busTickets", err, map[string]interface{}{
+			"filter": filter,
+		})
+		return domain.BusTicketPage{}, err
+	}
+
+	result := domain.NewBusTicketPage(busTickets, limit)
+	application.LogInfo(ctx, r.logger, "busTickets searched", map[string]interface{}{
+		"filter": filter,
+		"count":  len(result.BusTickets),
+	})
+	return result, nil
+}
+
+func (r *gormBusTicketRepository) Count(ctx context.Context, filter domain.BusTicketFilter) (int, error) {
+	var count int64
+	if err := r.conn(ctx).Model(&domain.BusTicket{}).Scopes(tenantScope(ctx), filterScope(filter)).Count(&count).Error; err != nil {
+		application.LogError(ctx, r.logger, "failed to count busTickets", err, map[string]interface{}{
+			"filter": filter,
+		})
+		return 0, err
+	}
+	return int(count), nil
+}
+
+func (r *gormBusTicketRepository) Delete(ctx context.Context, id string) error {
+	result := r.conn(ctx).Scopes(tenantScope(ctx)).Where("id = ?", id).Delete(&domain.BusTicket{})
+	if err := result.Error; err != nil {
+		application.LogError(ctx, r.logger, "failed to delete busTicket", err, map[string]interface{}{
+			"id": id,
+		})
+		return err
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrBusTicketNotFound
+	}
+
+	application.LogInfo(ctx, r.logger, "busTicket deleted", map[string]interface{}{
+		"id": id,
+	})
+	return nil
+}
+
+func (r *gormBusTicketRepository) Ping(ctx context.Context) error {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+func (r *gormBusTicketRepository) conn(ctx context.Context) *gorm.DB {
+	return conn(ctx, r.db)
+}
+
+// conn joins the transaction put in ctx by sqlAdapter.WithTx, so tickets and
+// the messages published alongside them commit together.
+func conn(ctx context.Context, gormDB *gorm.DB) *gorm.DB {
+	db := gormDB.WithContext(ctx)
+	if tx, ok := sqlAdapter.TxFromContext(ctx); ok {
+		db.Statement.ConnPool = tx
+	}
+	return db
+}
+
+func tenantScope(ctx context.Context) func(db *gorm.DB) *gorm.DB {
+	tenantID := application.TenantID(ctx)
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("tenant_id = ?", tenantID)
+	}
+}
+
+func filterScope(filter domain.BusTicketFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filter.PassengerName != "" {
+			db = db.Where("passenger_name = ?", filter.PassengerName)
+		}
+		if filter.Origin != "" {
+			db = db.Where("origin = ?", filter.Origin)
+		}
+		if filter.Destination != "" {
+			db = db.Where("destination = ?", filter.Destination)
+		}
+		if !filter.DepartureFrom.IsZero() {
+			db = db.Where("departure_time >= ?", filter.DepartureFrom.UTC())
+		}
+		if !filter.DepartureTo.IsZero() {
+			db = db.Where("departure_time < ?", filter.DepartureTo.UTC())
+		}
+		return db
+	}
+}
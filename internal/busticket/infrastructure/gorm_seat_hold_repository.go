@@ -0,0 +1,192 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/mateusmacedo/go-bff/internal/busticket/domain"
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	pkgDomain "github.com/mateusmacedo/go-bff/pkg/domain"
+	sqlAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/sql/adapter"
+)
+
+type gormSeatHoldRepository struct {
+	db      *gorm.DB
+	dialect sqlAdapter.Dialect
+	clock   pkgDomain.Clock
+	logger  application.AppLogger
+}
+
+// NewGormSeatHoldRepository stores holds in db, opened for dialect, under
+// the same migrations as the tickets, and tells expired holds apart with
+// clock.
+func NewGormSeatHoldRepository(db *sql.DB, dialect sqlAdapter.Dialect, clock pkgDomain.Clock, logger application.AppLogger) (domain.SeatHoldRepository, error) {
+	gormDB, err := openGorm(db, dialect, logger)
+	if err != nil {
+		return nil, err
+	}
+	return &gormSeatHoldRepository{db: gormDB, dialect: dialect, clock: clock, logger: logger}, nil
+}
+
+// Save checks the other holds and the tickets on the seat in the
+// transaction of the insert, under the seat's lock.
+func (r *gormSeatHoldRepository) Save(ctx context.Context, hold domain.SeatHold) error {
+	hold.TenantID = application.TenantID(ctx)
+	hold.ExpiresAt = hold.ExpiresAt.UTC()
+	seat := domain.BusTicket{ID: hold.ID, TenantID: hold.TenantID, TripID: hold.TripID, SeatNumber: hold.SeatNumber}
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := lockSeat(tx, r.dialect, seat); err != nil {
+			return err
+		}
+		if err := tx.Create(&hold).Error; err != nil {
+			return err
+		}
+		if err := seatHeld(tx, seat, r.clock().UTC()); err != nil {
+			return err
+		}
+		return seatBooked(tx, seat)
+	})
+	if err != nil {
+		application.LogError(ctx, r.logger, "failed to save seatHold", err, map[string]interface{}{
+			"seatHold": hold,
+		})
+		return err
+	}
+
+	application.LogInfo(ctx, r.logger, "seatHold saved", map[string]interface{}{
+		"seatHold": hold,
+	})
+	return nil
+}
+
+func (r *gormSeatHoldRepository) FindByID(ctx context.Context, id string) (domain.SeatHold, error) {
+	var hold domain.SeatHold
+	err := conn(ctx, r.db).Scopes(tenantScope(ctx)).Where("id = ?", id).Take(&hold).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.SeatHold{}, domain.ErrSeatHoldNotFound
+	}
+	if err != nil {
+		application.LogError(ctx, r.logger, "failed to find seatHold", err, map[string]interface{}{
+			"id": id,
+		})
+		return domain.SeatHold{}, err
+	}
+	return hold, nil
+}
+
+func (r *gormSeatHoldRepository) FindByTrip(ctx context.Context, tripID string) ([]domain.SeatHold, error) {
+	var holds []domain.SeatHold
+	err := conn(ctx, r.db).Scopes(tenantScope(ctx)).
+		Where("trip_id = ? AND expires_at > ?", tripID, r.clock().UTC()).
+		Order("seat_number").Find(&holds).Error
+	if err != nil {
+		application.LogError(ctx, r.logger, "failed to find seatHolds of trip", err, map[string]interface{}{
+			"tripId": tripID,
+		})
+		return nil, err
+	}
+	return holds, nil
+}
+
+func (r *gormSeatHoldRepository) Delete(ctx context.Context, id string) error {
+	result := conn(ctx, r.db).Scopes(tenantScope(ctx)).Where("id = ?", id).Delete(&domain.SeatHold{})
+	if err := result.Error; err != nil {
+		application.LogError(ctx, r.logger, "failed to delete seatHold", err, map[string]interface{}{
+			"id": id,
+		})
+		return err
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrSeatHoldNotFound
+	}
+
+	application.LogInfo(ctx, r.logger, "seatHold deleted", map[string]interface{}{
+		"id": id,
+	})
+	return nil
+}
+
+// ReleaseExpired deletes the expired holds one by one, still expired, and
+// returns those whose delete removed a row; a concurrent release or confirm
+// that got there first keeps the others.
+func (r *gormSeatHoldRepository) ReleaseExpired(ctx context.Context) ([]domain.SeatHold, error) {
+	now := r.clock().UTC()
+	var expired []domain.SeatHold
+	if err := conn(ctx, r.db).Where("expires_at <= ?", now).Order("trip_id, seat_number, tenant_id").Find(&expired).Error; err != nil {
+		application.LogError(ctx, r.logger, "failed to find expired seatHolds", err, nil)
+		return nil, err
+	}
+
+	var released []domain.SeatHold
+	for _, hold := range expired {
+		result := conn(ctx, r.db).Where("id = ? AND tenant_id = ? AND expires_at <= ?", hold.ID, hold.TenantID, now).Delete(&domain.SeatHold{})
+		if err := result.Error; err != nil {
+			application.LogError(ctx, r.logger, "failed to release seatHold", err, map[string]interface{}{
+				"seatHold": hold,
+			})
+			return released, err
+		}
+		if result.RowsAffected > 0 {
+			released = append(released, hold)
+		}
+	}
+
+	if len(released) > 0 {
+		application.LogInfo(ctx, r.logger, "expired seatHolds released", map[string]interface{}{
+			"count": len(released),
+		})
+	}
+	return released, nil
+}
+
+// lockSeat serializes, until tx ends, the writers of the tickets and holds
+// of busTicket's seat on Postgres, where each checks the table the other
+// writes. SQLite already serializes every writer from its first write on.
+func lockSeat(tx *gorm.DB, dialect sqlAdapter.Dialect, busTicket domain.BusTicket) error {
+	if busTicket.TripID == "" || dialect.Name() != (sqlAdapter.PostgresDialect{}).Name() {
+		return nil
+	}
+	key := fmt.Sprintf("seat:%s:%s:%d", busTicket.TenantID, busTicket.TripID, busTicket.SeatNumber)
+	return tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", key).Error
+}
+
+// seatHeld fails while a hold unexpired at now, other than busTicket's own,
+// takes its seat.
+func seatHeld(tx *gorm.DB, busTicket domain.BusTicket, now time.Time) error {
+	if busTicket.TripID == "" || !busTicket.HoldsSeat() {
+		return nil
+	}
+	var holds int64
+	err := tx.Model(&domain.SeatHold{}).
+		Where("tenant_id = ? AND trip_id = ? AND seat_number = ? AND id <> ? AND expires_at > ?",
+			busTicket.TenantID, busTicket.TripID, busTicket.SeatNumber, busTicket.ID, now).
+		Count(&holds).Error
+	if err != nil {
+		return err
+	}
+	if holds > 0 {
+		return &domain.SeatUnavailableError{Trip: busTicket.Trip(), SeatNumber: busTicket.SeatNumber}
+	}
+	return nil
+}
+
+// seatBooked fails while a ticket takes the seat of busTicket.
+func seatBooked(tx *gorm.DB, busTicket domain.BusTicket) error {
+	var busTickets int64
+	err := tx.Model(&domain.BusTicket{}).
+		Where("tenant_id = ? AND trip_id = ? AND seat_number = ? AND status <> ?",
+			busTicket.TenantID, busTicket.TripID, busTicket.SeatNumber, domain.BusTicketCancelled).
+		Count(&busTickets).Error
+	if err != nil {
+		return err
+	}
+	if busTickets > 0 {
+		return &domain.SeatUnavailableError{Trip: busTicket.Trip(), SeatNumber: busTicket.SeatNumber}
+	}
+	return nil
+}
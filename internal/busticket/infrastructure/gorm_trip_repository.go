@@ -0,0 +1,151 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/mateusmacedo/go-bff/internal/busticket/domain"
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	sqlAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/sql/adapter"
+)
+
+type gormRouteRepository struct {
+	db     *gorm.DB
+	logger application.AppLogger
+}
+
+// NewGormRouteRepository stores routes in db, opened for dialect, under the
+// same migrations as the tickets.
+func NewGormRouteRepository(db *sql.DB, dialect sqlAdapter.Dialect, logger application.AppLogger) (domain.RouteRepository, error) {
+	gormDB, err := openGorm(db, dialect, logger)
+	if err != nil {
+		return nil, err
+	}
+	return &gormRouteRepository{db: gormDB, logger: logger}, nil
+}
+
+func (r *gormRouteRepository) Save(ctx context.Context, route domain.Route) error {
+	route.TenantID = application.TenantID(ctx)
+	if err := conn(ctx, r.db).Create(&route).Error; err != nil {
+		application.LogError(ctx, r.logger, "failed to save route", err, map[string]interface{}{
+			"route": route,
+		})
+		return err
+	}
+
+	application.LogInfo(ctx, r.logger, "route saved", map[string]interface{}{
+		"route": route,
+	})
+	return nil
+}
+
+func (r *gormRouteRepository) FindByID(ctx context.Context, id string) (domain.Route, error) {
+	var route domain.Route
+	err := conn(ctx, r.db).Scopes(tenantScope(ctx)).Where("id = ?", id).Take(&route).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Route{}, domain.ErrRouteNotFound
+	}
+	if err != nil {
+		application.LogError(ctx, r.logger, "failed to find route", err, map[string]interface{}{
+			"id": id,
+		})
+		return domain.Route{}, err
+	}
+	return route, nil
+}
+
+type gormTripRepository struct {
+	db     *gorm.DB
+	logger application.AppLogger
+}
+
+// NewGormTripRepository stores trips in db, opened for dialect, under the
+// same migrations as the tickets.
+func NewGormTripRepository(db *sql.DB, dialect sqlAdapter.Dialect, logger application.AppLogger) (domain.TripRepository, error) {
+	gormDB, err := openGorm(db, dialect, logger)
+	if err != nil {
+		return nil, err
+	}
+	return &gormTripRepository{db: gormDB, logger: logger}, nil
+}
+
+func (r *gormTripRepository) Save(ctx context.Context, trip domain.Trip) error {
+	trip.TenantID = application.TenantID(ctx)
+	trip.DepartureTime = trip.DepartureTime.UTC()
+	trip.Version = 1
+	if err := conn(ctx, r.db).Create(&trip).Error; err != nil {
+		application.LogError(ctx, r.logger, "failed to save trip", err, map[string]interface{}{
+			"trip": trip,
+		})
+		return err
+	}
+
+	application.LogInfo(ctx, r.logger, "trip saved", map[string]interface{}{
+		"trip": trip,
+	})
+	return nil
+}
+
+func (r *gormTripRepository) FindByID(ctx context.Context, id string) (domain.Trip, error) {
+	var trip domain.Trip
+	err := conn(ctx, r.db).Scopes(tenantScope(ctx)).Where("id = ?", id).Take(&trip).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Trip{}, domain.ErrTripNotFound
+	}
+	if err != nil {
+		application.LogError(ctx, r.logger, "failed to find trip", err, map[string]interface{}{
+			"id": id,
+		})
+		return domain.Trip{}, err
+	}
+	return trip, nil
+}
+
+func (r *gormTripRepository) FindByRoute(ctx context.Context, routeID string) ([]domain.Trip, error) {
+	var trips []domain.Trip
+	err := conn(ctx, r.db).Scopes(tenantScope(ctx)).Where("route_id = ?", routeID).Order("departure_time, id").Find(&trips).Error
+	if err != nil {
+		application.LogError(ctx, r.logger, "failed to find trips of route", err, map[string]interface{}{
+			"routeId": routeID,
+		})
+		return nil, err
+	}
+	return trips, nil
+}
+
+// Update is a compare-and-swap on the version column.
+func (r *gormTripRepository) Update(ctx context.Context, trip domain.Trip) error {
+	trip.TenantID = application.TenantID(ctx)
+	trip.DepartureTime = trip.DepartureTime.UTC()
+	expected := trip.Version
+	trip.Version++
+	result := conn(ctx, r.db).Model(&domain.Trip{}).Scopes(tenantScope(ctx)).
+		Where("id = ? AND version = ?", trip.ID, expected).
+		Select("route_id", "departure_time", "vehicle", "capacity", "fare", "status", "version").
+		Updates(trip)
+	if err := result.Error; err != nil {
+		application.LogError(ctx, r.logger, "failed to update trip", err, map[string]interface{}{
+			"trip": trip,
+		})
+		return err
+	}
+
+	if result.RowsAffected == 0 {
+		if _, err := r.FindByID(ctx, trip.ID); err != nil {
+			return err
+		}
+		application.LogInfo(ctx, r.logger, "trip version conflict", map[string]interface{}{
+			"trip":     trip,
+			"expected": expected,
+		})
+		return domain.ErrTripConcurrencyConflict
+	}
+
+	application.LogInfo(ctx, r.logger, "trip updated", map[string]interface{}{
+		"trip": trip,
+	})
+	return nil
+}
@@ -3,7 +3,12 @@ package infrastructure
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -15,26 +20,43 @@ import (
 )
 
 type BusTicketHTTPHandler struct {
-	commandBus pkgApp.CommandBus[pkgDomain.Command[application.ReserveBusTicketData], application.ReserveBusTicketData]
-	queryBus   pkgApp.QueryBus[pkgDomain.Query[application.FindBusTicketData], application.FindBusTicketData, []domain.BusTicket]
+	commandBus       pkgApp.CommandBus[pkgDomain.Command[application.ReserveBusTicketData], application.ReserveBusTicketData]
+	cancelCommandBus pkgApp.CommandBus[pkgDomain.Command[application.CancelBusTicketData], application.CancelBusTicketData]
+	getQueryBus      pkgApp.QueryBus[pkgDomain.Query[application.GetBusTicketByIDData], application.GetBusTicketByIDData, domain.BusTicket]
+	searchQueryBus   pkgApp.QueryBus[pkgDomain.Query[application.SearchBusTicketsData], application.SearchBusTicketsData, application.SearchBusTicketsResult]
+	idGenerator      pkgDomain.IDGenerator[string]
+	asyncReserve     bool
 }
 
 func NewBusTicketHTTPHandler(
 	commandBus pkgApp.CommandBus[pkgDomain.Command[application.ReserveBusTicketData], application.ReserveBusTicketData],
-	queryBus pkgApp.QueryBus[pkgDomain.Query[application.FindBusTicketData], application.FindBusTicketData, []domain.BusTicket],
+	cancelCommandBus pkgApp.CommandBus[pkgDomain.Command[application.CancelBusTicketData], application.CancelBusTicketData],
+	getQueryBus pkgApp.QueryBus[pkgDomain.Query[application.GetBusTicketByIDData], application.GetBusTicketByIDData, domain.BusTicket],
+	searchQueryBus pkgApp.QueryBus[pkgDomain.Query[application.SearchBusTicketsData], application.SearchBusTicketsData, application.SearchBusTicketsResult],
+	idGenerator pkgDomain.IDGenerator[string],
+	asyncReserve bool,
 ) *BusTicketHTTPHandler {
 	return &BusTicketHTTPHandler{
-		commandBus: commandBus,
-		queryBus:   queryBus,
+		commandBus:       commandBus,
+		cancelCommandBus: cancelCommandBus,
+		getQueryBus:      getQueryBus,
+		searchQueryBus:   searchQueryBus,
+		idGenerator:      idGenerator,
+		asyncReserve:     asyncReserve,
 	}
 }
 
+// HandleReserveBusTicket reserves a ticket whose ID it generates. Once the
+// ticket is saved it answers with its location and ETag; when the command
+// was only queued it answers 202 Accepted with the ID to look the ticket up
+// by.
 func (h *BusTicketHTTPHandler) HandleReserveBusTicket(w http.ResponseWriter, r *http.Request) {
 	var cmd application.ReserveBusTicketData
 	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
 		handleError(w, "Invalid request", http.StatusBadRequest)
 		return
 	}
+	cmd.ID = h.idGenerator()
 
 	command := application.NewReserveBusTicketCommand(cmd)
 
@@ -42,44 +64,207 @@ func (h *BusTicketHTTPHandler) HandleReserveBusTicket(w http.ResponseWriter, r *
 	defer cancel()
 
 	if err := h.commandBus.Dispatch(ctx, command); err != nil {
-		handleError(w, err.Error(), http.StatusInternalServerError)
+		handleError(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	if h.asyncReserve {
+		writeAccepted(w, "Bus ticket reservation accepted", cmd)
+		return
+	}
+	// Repositories save new tickets at version 1.
+	w.Header().Set("ETag", etag(1))
+	writeCreated(w, "/bustickets/"+cmd.ID, "Bus ticket reserved", cmd)
+}
+
+func (h *BusTicketHTTPHandler) HandleGetBusTicketByID(w http.ResponseWriter, r *http.Request) {
+	query := application.NewGetBusTicketByIDQuery(application.GetBusTicketByIDData{
+		ID: chi.URLParam(r, "busTicketID"),
+	})
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	busTicket, err := h.getQueryBus.Dispatch(ctx, query)
+	if err != nil {
+		handleError(w, err.Error(), statusFromError(err))
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	if err := json.NewEncoder(w).Encode(map[string]interface{}{"message": "Bus ticket reserved", "data": cmd}); err != nil {
+	w.Header().Set("ETag", etag(busTicket.Version))
+	if err := json.NewEncoder(w).Encode(busTicket); err != nil {
 		handleError(w, err.Error(), http.StatusInternalServerError)
 	}
 }
 
-func (h *BusTicketHTTPHandler) HandleFindBusTicket(w http.ResponseWriter, r *http.Request) {
-	busTicketID := chi.URLParam(r, "busTicketID")
-	findBusTicketData := application.FindBusTicketData{
-		PassengerName: busTicketID,
+// HandleCancelBusTicket cancels a ticket. With If-Match, the cancellation
+// only happens while the ticket still has that ETag. The refund is on the
+// cancelled ticket.
+func (h *BusTicketHTTPHandler) HandleCancelBusTicket(w http.ResponseWriter, r *http.Request) {
+	data := application.CancelBusTicketData{ID: chi.URLParam(r, "busTicketID")}
+	var ok bool
+	if data.ExpectedVersion, ok = expectedVersion(r); !ok {
+		handleError(w, "If-Match does not match the bus ticket", http.StatusPreconditionFailed)
+		return
 	}
-	query := application.NewFindBusTicketQuery(findBusTicketData)
 
 	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 	defer cancel()
 
-	busTicket, err := h.queryBus.Dispatch(ctx, query)
+	if err := h.cancelCommandBus.Dispatch(ctx, application.NewCancelBusTicketCommand(data)); err != nil {
+		handleError(w, err.Error(), conditionalStatusFromError(r, err))
+		return
+	}
+
+	writeOK(w, "Bus ticket cancelled", data)
+}
+
+// expectedVersion is the ticket version If-Match requires, 0 when any will
+// do; ok is false when If-Match names no ticket version.
+func expectedVersion(r *http.Request) (version int64, ok bool) {
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" || ifMatch == "*" {
+		return 0, true
+	}
+	return versionFromETag(ifMatch)
+}
+
+// conditionalStatusFromError answers a concurrency conflict with 412 when
+// the request was conditional.
+func conditionalStatusFromError(r *http.Request, err error) int {
+	if r.Header.Get("If-Match") != "" && errors.Is(err, pkgDomain.ErrConcurrencyConflict) {
+		return http.StatusPreconditionFailed
+	}
+	return statusFromError(err)
+}
+
+// etag is the strong entity tag of a ticket at version.
+func etag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+func versionFromETag(tag string) (int64, bool) {
+	unquoted, err := strconv.Unquote(strings.TrimSpace(tag))
 	if err != nil {
-		handleError(w, err.Error(), http.StatusInternalServerError)
+		return 0, false
+	}
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	return version, err == nil && version > 0
+}
+
+// searchBusTicketsResponse links the page to itself and, while there are
+// more results, to the next page.
+type searchBusTicketsResponse struct {
+	application.SearchBusTicketsResult
+	Links map[string]string `json:"links"`
+}
+
+func (h *BusTicketHTTPHandler) HandleSearchBusTickets(w http.ResponseWriter, r *http.Request) {
+	data, err := searchBusTicketsData(r.URL.Query())
+	if err != nil {
+		handleError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	result, err := h.searchQueryBus.Dispatch(ctx, application.NewSearchBusTicketsQuery(data))
+	if err != nil {
+		handleError(w, err.Error(), statusFromError(err))
 		return
 	}
 
+	response := searchBusTicketsResponse{
+		SearchBusTicketsResult: result,
+		Links:                  map[string]string{"self": r.URL.RequestURI()},
+	}
+	if result.NextCursor != "" {
+		next := r.URL.Query()
+		next.Set("cursor", result.NextCursor)
+		response.Links["next"] = r.URL.Path + "?" + next.Encode()
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(busTicket); err != nil {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(response); err != nil {
 		handleError(w, err.Error(), http.StatusInternalServerError)
 	}
 }
 
-func (h *BusTicketHTTPHandler) RegisterRoutes(router chi.Router) {
-	router.Post("/bustickets", h.HandleReserveBusTicket)
-	router.Get("/bustickets/{busTicketID}", h.HandleFindBusTicket)
+// searchBusTicketsData reads the search from the query string; from and to
+// are RFC 3339 timestamps.
+func searchBusTicketsData(values url.Values) (application.SearchBusTicketsData, error) {
+	data := application.SearchBusTicketsData{
+		PassengerName: values.Get("passenger"),
+		Origin:        values.Get("origin"),
+		Destination:   values.Get("destination"),
+		Cursor:        values.Get("cursor"),
+	}
+
+	var err error
+	if from := values.Get("from"); from != "" {
+		if data.DepartureFrom, err = time.Parse(time.RFC3339, from); err != nil {
+			return data, fmt.Errorf("invalid from: %w", err)
+		}
+	}
+	if to := values.Get("to"); to != "" {
+		if data.DepartureTo, err = time.Parse(time.RFC3339, to); err != nil {
+			return data, fmt.Errorf("invalid to: %w", err)
+		}
+	}
+	if limit := values.Get("limit"); limit != "" {
+		if data.Limit, err = strconv.Atoi(limit); err != nil || data.Limit < 1 {
+			return data, fmt.Errorf("invalid limit %q", limit)
+		}
+	}
+	return data, nil
+}
+
+const (
+	ReserveBusTicketRoute = "ReserveBusTicket"
+	CancelBusTicketRoute  = "CancelBusTicket"
+	GetBusTicketByIDRoute = "GetBusTicketByID"
+	SearchBusTicketsRoute = "SearchBusTickets"
+)
+
+type RouteOption func(requirements map[string][]func(http.Handler) http.Handler)
+
+func WithRouteRequirements(route string, middlewares ...func(http.Handler) http.Handler) RouteOption {
+	return func(requirements map[string][]func(http.Handler) http.Handler) {
+		requirements[route] = append(requirements[route], middlewares...)
+	}
+}
+
+func (h *BusTicketHTTPHandler) RegisterRoutes(router chi.Router, options ...RouteOption) {
+	requirements := make(map[string][]func(http.Handler) http.Handler)
+	for _, option := range options {
+		option(requirements)
+	}
+
+	router.With(requirements[ReserveBusTicketRoute]...).Post("/bustickets", h.HandleReserveBusTicket)
+	router.With(requirements[SearchBusTicketsRoute]...).Get("/bustickets", h.HandleSearchBusTickets)
+	router.With(requirements[GetBusTicketByIDRoute]...).Get("/bustickets/{busTicketID}", h.HandleGetBusTicketByID)
+	router.With(requirements[CancelBusTicketRoute]...).Post("/bustickets/{busTicketID}/cancel", h.HandleCancelBusTicket)
 }
 
 func handleError(w http.ResponseWriter, message string, statusCode int) {
 	http.Error(w, message, statusCode)
 }
+
+func statusFromError(err error) int {
+	switch {
+	case errors.Is(err, pkgApp.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, pkgDomain.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, pkgDomain.ErrInvalid):
+		return http.StatusBadRequest
+	case errors.Is(err, pkgDomain.ErrConflict), errors.Is(err, pkgDomain.ErrConcurrencyConflict):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -11,43 +11,75 @@ import (
 )
 
 type InMemoryBusTicketRepository struct {
-	mu     sync.RWMutex
-	data   map[string]domain.BusTicket
+	mu   sync.RWMutex
+	data map[string]map[string]domain.BusTicket
+	// holds is the hold repository built on this one, which shares mu.
+	holds  *InMemorySeatHoldRepository
 	logger pkgApp.AppLogger
 }
 
 func NewInMemoryBusTicketRepository(logger pkgApp.AppLogger) *InMemoryBusTicketRepository {
 	return &InMemoryBusTicketRepository{
-		data:   make(map[string]domain.BusTicket),
+		data:   make(map[string]map[string]domain.BusTicket),
 		logger: logger,
 	}
 }
 
+func (r *InMemoryBusTicketRepository) partition(ctx context.Context) map[string]domain.BusTicket {
+	tenantID := application.TenantID(ctx)
+	if _, exists := r.data[tenantID]; !exists {
+		r.data[tenantID] = make(map[string]domain.BusTicket)
+	}
+	return r.data[tenantID]
+}
+
 func (r *InMemoryBusTicketRepository) Save(ctx context.Context, busTicket domain.BusTicket) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, exists := r.data[busTicket.ID]; exists {
+	busTicket.TenantID = application.TenantID(ctx)
+	busTicket.Version = 1
+	data := r.partition(ctx)
+	if _, exists := data[busTicket.ID]; exists {
 		application.LogInfo(ctx, r.logger, "busTicket already exists", map[string]interface{}{
 			"busTicket": busTicket,
 		})
 		return errors.New("busTicket already exists")
 	}
+	if err := seatTaken(data, busTicket); err != nil {
+		return err
+	}
+	if r.holds != nil {
+		if err := r.holds.seatHeld(busTicket); err != nil {
+			return err
+		}
+	}
 
 	application.LogInfo(ctx, r.logger, "busTicket saved", map[string]interface{}{
 		"busTicket": busTicket,
 	})
-	r.data[busTicket.ID] = busTicket
+	data[busTicket.ID] = busTicket
 
 	return nil
 }
 
+func (r *InMemoryBusTicketRepository) FindByID(ctx context.Context, id string) (domain.BusTicket, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	busTicket, exists := r.data[application.TenantID(ctx)][id]
+	if !exists {
+		return domain.BusTicket{}, domain.ErrBusTicketNotFound
+	}
+	return busTicket, nil
+}
+
 func (r *InMemoryBusTicketRepository) FindByPassengerName(ctx context.Context, passengerName string) ([]domain.BusTicket, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	var busTickets []domain.BusTicket
-	for _, busTicket := range r.data {
+	for _, busTicket := range r.data[application.TenantID(ctx)] {
 		if busTicket.PassengerName == passengerName {
 			busTickets = append(busTickets, busTicket)
 		}
@@ -61,28 +93,118 @@ func (r *InMemoryBusTicketRepository) FindByPassengerName(ctx context.Context, p
 	return busTickets, nil
 }
 
+func (r *InMemoryBusTicketRepository) FindByTrip(ctx context.Context, trip domain.TripKey) ([]domain.BusTicket, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var busTickets []domain.BusTicket
+	for _, busTicket := range r.data[application.TenantID(ctx)] {
+		if busTicket.HoldsSeat() && busTicket.Trip().Equal(trip) {
+			busTickets = append(busTickets, busTicket)
+		}
+	}
+	return busTickets, nil
+}
+
+// seatTaken runs under the write lock, which makes the check and the write
+// that follows it atomic.
+func seatTaken(data map[string]domain.BusTicket, busTicket domain.BusTicket) error {
+	if !busTicket.HoldsSeat() {
+		return nil
+	}
+	for _, other := range data {
+		if other.ID != busTicket.ID && other.HoldsSeat() && other.SeatNumber == busTicket.SeatNumber && other.Trip().Equal(busTicket.Trip()) {
+			return &domain.SeatUnavailableError{Trip: busTicket.Trip(), SeatNumber: busTicket.SeatNumber}
+		}
+	}
+	return nil
+}
+
 func (r *InMemoryBusTicketRepository) Update(ctx context.Context, busTicket domain.BusTicket) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, exists := r.data[busTicket.ID]; !exists {
+	busTicket.TenantID = application.TenantID(ctx)
+	data := r.data[busTicket.TenantID]
+	stored, exists := data[busTicket.ID]
+	if !exists {
 		application.LogInfo(ctx, r.logger, "busTicket not found", map[string]interface{}{
 			"busTicket": busTicket,
 		})
-		return errors.New("busTicket not found")
+		return domain.ErrBusTicketNotFound
+	}
+	if stored.Version != busTicket.Version {
+		return domain.ErrConcurrencyConflict
 	}
+	if err := seatTaken(data, busTicket); err != nil {
+		return err
+	}
+	busTicket.Version++
 
 	application.LogInfo(ctx, r.logger, "busTicket updated", map[string]interface{}{
 		"busTicket": busTicket,
 	})
 
-	r.data[busTicket.ID] = busTicket
+	data[busTicket.ID] = busTicket
 
 	return nil
 }
 
+func (r *InMemoryBusTicketRepository) Search(ctx context.Context, filter domain.BusTicketFilter, page domain.PageRequest) (domain.BusTicketPage, error) {
+	result, err := domain.PaginateBusTickets(r.matching(ctx, filter), page)
+	if err != nil {
+		return domain.BusTicketPage{}, err
+	}
+
+	application.LogInfo(ctx, r.logger, "busTickets searched", map[string]interface{}{
+		"filter": filter,
+		"count":  len(result.BusTickets),
+	})
+	return result, nil
+}
+
+func (r *InMemoryBusTicketRepository) Count(ctx context.Context, filter domain.BusTicketFilter) (int, error) {
+	return len(r.matching(ctx, filter)), nil
+}
+
+func (r *InMemoryBusTicketRepository) matching(ctx context.Context, filter domain.BusTicketFilter) []domain.BusTicket {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var busTickets []domain.BusTicket
+	for _, busTicket := range r.data[application.TenantID(ctx)] {
+		if filter.Matches(busTicket) {
+			busTickets = append(busTickets, busTicket)
+		}
+	}
+	return busTickets
+}
+
+func (r *InMemoryBusTicketRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data := r.data[application.TenantID(ctx)]
+	if _, exists := data[id]; !exists {
+		return domain.ErrBusTicketNotFound
+	}
+	delete(data, id)
+
+	application.LogInfo(ctx, r.logger, "busTicket deleted", map[string]interface{}{
+		"id": id,
+	})
+	return nil
+}
+
 func (r *InMemoryBusTicketRepository) GetData() map[string]domain.BusTicket {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	return r.data
+
+	data := make(map[string]domain.BusTicket)
+	for _, partition := range r.data {
+		for id, busTicket := range partition {
+			data[id] = busTicket
+		}
+	}
+	return data
 }
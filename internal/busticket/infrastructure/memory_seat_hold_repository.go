@@ -0,0 +1,159 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/mateusmacedo/go-bff/internal/busticket/domain"
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	pkgDomain "github.com/mateusmacedo/go-bff/pkg/domain"
+)
+
+type InMemorySeatHoldRepository struct {
+	mu      *sync.RWMutex
+	data    map[string]map[string]domain.SeatHold
+	tickets *InMemoryBusTicketRepository
+	clock   pkgDomain.Clock
+	logger  application.AppLogger
+}
+
+// NewInMemorySeatHoldRepository holds the seats of the trips whose tickets
+// tickets stores, under the same lock, so that neither repository gives a
+// seat the other one took. It tells expired holds apart with clock.
+func NewInMemorySeatHoldRepository(tickets *InMemoryBusTicketRepository, clock pkgDomain.Clock, logger application.AppLogger) *InMemorySeatHoldRepository {
+	r := &InMemorySeatHoldRepository{
+		mu:      &tickets.mu,
+		data:    make(map[string]map[string]domain.SeatHold),
+		tickets: tickets,
+		clock:   clock,
+		logger:  logger,
+	}
+	tickets.mu.Lock()
+	tickets.holds = r
+	tickets.mu.Unlock()
+	return r
+}
+
+func (r *InMemorySeatHoldRepository) Save(ctx context.Context, hold domain.SeatHold) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	hold.TenantID = application.TenantID(ctx)
+	data, exists := r.data[hold.TenantID]
+	if !exists {
+		data = make(map[string]domain.SeatHold)
+		r.data[hold.TenantID] = data
+	}
+	if _, exists := data[hold.ID]; exists {
+		return errors.New("seat hold already exists")
+	}
+	ticket := domain.BusTicket{ID: hold.ID, TenantID: hold.TenantID, TripID: hold.TripID, SeatNumber: hold.SeatNumber}
+	if err := r.seatHeld(ticket); err != nil {
+		return err
+	}
+	if err := seatTaken(r.tickets.data[hold.TenantID], ticket); err != nil {
+		return err
+	}
+	data[hold.ID] = hold
+
+	application.LogInfo(ctx, r.logger, "seatHold saved", map[string]interface{}{
+		"seatHold": hold,
+	})
+	return nil
+}
+
+func (r *InMemorySeatHoldRepository) FindByID(ctx context.Context, id string) (domain.SeatHold, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	hold, exists := r.data[application.TenantID(ctx)][id]
+	if !exists {
+		return domain.SeatHold{}, domain.ErrSeatHoldNotFound
+	}
+	return hold, nil
+}
+
+func (r *InMemorySeatHoldRepository) FindByTrip(ctx context.Context, tripID string) ([]domain.SeatHold, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := r.clock()
+	var holds []domain.SeatHold
+	for _, hold := range r.data[application.TenantID(ctx)] {
+		if hold.TripID == tripID && !hold.Expired(now) {
+			holds = append(holds, hold)
+		}
+	}
+	sortSeatHolds(holds)
+	return holds, nil
+}
+
+func (r *InMemorySeatHoldRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data := r.data[application.TenantID(ctx)]
+	if _, exists := data[id]; !exists {
+		return domain.ErrSeatHoldNotFound
+	}
+	delete(data, id)
+
+	application.LogInfo(ctx, r.logger, "seatHold deleted", map[string]interface{}{
+		"id": id,
+	})
+	return nil
+}
+
+func (r *InMemorySeatHoldRepository) ReleaseExpired(ctx context.Context) ([]domain.SeatHold, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.clock()
+	var released []domain.SeatHold
+	for _, data := range r.data {
+		for id, hold := range data {
+			if hold.Expired(now) {
+				delete(data, id)
+				released = append(released, hold)
+			}
+		}
+	}
+	sortSeatHolds(released)
+
+	if len(released) > 0 {
+		application.LogInfo(ctx, r.logger, "expired seatHolds released", map[string]interface{}{
+			"count": len(released),
+		})
+	}
+	return released, nil
+}
+
+// seatHeld runs under the write lock and fails while an unexpired hold
+// other than busTicket's own takes its seat.
+func (r *InMemorySeatHoldRepository) seatHeld(busTicket domain.BusTicket) error {
+	if busTicket.TripID == "" || !busTicket.HoldsSeat() {
+		return nil
+	}
+	now := r.clock()
+	for _, hold := range r.data[busTicket.TenantID] {
+		if hold.ID != busTicket.ID && hold.TripID == busTicket.TripID && hold.SeatNumber == busTicket.SeatNumber && !hold.Expired(now) {
+			return &domain.SeatUnavailableError{Trip: busTicket.Trip(), SeatNumber: busTicket.SeatNumber}
+		}
+	}
+	return nil
+}
+
+// sortSeatHolds orders holds by trip, seat and then tenant.
+func sortSeatHolds(holds []domain.SeatHold) {
+	sort.Slice(holds, func(i, j int) bool {
+		if holds[i].TripID != holds[j].TripID {
+			return holds[i].TripID < holds[j].TripID
+		}
+		if holds[i].SeatNumber != holds[j].SeatNumber {
+			return holds[i].SeatNumber < holds[j].SeatNumber
+		}
+		return holds[i].TenantID < holds[j].TenantID
+	})
+}
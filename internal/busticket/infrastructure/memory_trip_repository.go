@@ -0,0 +1,148 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/mateusmacedo/go-bff/internal/busticket/domain"
+	"github.com/mateusmacedo/go-bff/pkg/application"
+)
+
+type InMemoryRouteRepository struct {
+	mu     sync.RWMutex
+	data   map[string]map[string]domain.Route
+	logger application.AppLogger
+}
+
+func NewInMemoryRouteRepository(logger application.AppLogger) *InMemoryRouteRepository {
+	return &InMemoryRouteRepository{
+		data:   make(map[string]map[string]domain.Route),
+		logger: logger,
+	}
+}
+
+func (r *InMemoryRouteRepository) Save(ctx context.Context, route domain.Route) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	route.TenantID = application.TenantID(ctx)
+	data, exists := r.data[route.TenantID]
+	if !exists {
+		data = make(map[string]domain.Route)
+		r.data[route.TenantID] = data
+	}
+	if _, exists := data[route.ID]; exists {
+		return errors.New("route already exists")
+	}
+	data[route.ID] = route
+
+	application.LogInfo(ctx, r.logger, "route saved", map[string]interface{}{
+		"route": route,
+	})
+	return nil
+}
+
+func (r *InMemoryRouteRepository) FindByID(ctx context.Context, id string) (domain.Route, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	route, exists := r.data[application.TenantID(ctx)][id]
+	if !exists {
+		return domain.Route{}, domain.ErrRouteNotFound
+	}
+	return route, nil
+}
+
+type InMemoryTripRepository struct {
+	mu     sync.RWMutex
+	data   map[string]map[string]domain.Trip
+	logger application.AppLogger
+}
+
+func NewInMemoryTripRepository(logger application.AppLogger) *InMemoryTripRepository {
+	return &InMemoryTripRepository{
+		data:   make(map[string]map[string]domain.Trip),
+		logger: logger,
+	}
+}
+
+func (r *InMemoryTripRepository) Save(ctx context.Context, trip domain.Trip) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	trip.TenantID = application.TenantID(ctx)
+	trip.Version = 1
+	data, exists := r.data[trip.TenantID]
+	if !exists {
+		data = make(map[string]domain.Trip)
+		r.data[trip.TenantID] = data
+	}
+	if _, exists := data[trip.ID]; exists {
+		return errors.New("trip already exists")
+	}
+	data[trip.ID] = trip
+
+	application.LogInfo(ctx, r.logger, "trip saved", map[string]interface{}{
+		"trip": trip,
+	})
+	return nil
+}
+
+func (r *InMemoryTripRepository) FindByID(ctx context.Context, id string) (domain.Trip, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	trip, exists := r.data[application.TenantID(ctx)][id]
+	if !exists {
+		return domain.Trip{}, domain.ErrTripNotFound
+	}
+	return trip, nil
+}
+
+func (r *InMemoryTripRepository) FindByRoute(ctx context.Context, routeID string) ([]domain.Trip, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var trips []domain.Trip
+	for _, trip := range r.data[application.TenantID(ctx)] {
+		if trip.RouteID == routeID {
+			trips = append(trips, trip)
+		}
+	}
+	sortTrips(trips)
+	return trips, nil
+}
+
+func (r *InMemoryTripRepository) Update(ctx context.Context, trip domain.Trip) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	trip.TenantID = application.TenantID(ctx)
+	data := r.data[trip.TenantID]
+	stored, exists := data[trip.ID]
+	if !exists {
+		return domain.ErrTripNotFound
+	}
+	if stored.Version != trip.Version {
+		return domain.ErrTripConcurrencyConflict
+	}
+	trip.Version++
+	data[trip.ID] = trip
+
+	application.LogInfo(ctx, r.logger, "trip updated", map[string]interface{}{
+		"trip": trip,
+	})
+	return nil
+}
+
+// sortTrips orders trips by departure time and then ID.
+func sortTrips(trips []domain.Trip) {
+	sort.Slice(trips, func(i, j int) bool {
+		if !trips[i].DepartureTime.Equal(trips[j].DepartureTime) {
+			return trips[i].DepartureTime.Before(trips[j].DepartureTime)
+		}
+		return trips[i].ID < trips[j].ID
+	})
+}
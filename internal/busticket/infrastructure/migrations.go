@@ -0,0 +1,18 @@
+package infrastructure
+
+import (
+	"embed"
+	"path"
+
+	sqlAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/sql/adapter"
+)
+
+//go:embed migrations/*/*.sql
+var migrationFiles embed.FS
+
+// BusTicketMigrations returns the versioned bus ticket schema embedded in the
+// binary. Each dialect keeps its own copy under migrations/<dialect>, with
+// the same versions.
+func BusTicketMigrations(dialect sqlAdapter.Dialect) ([]sqlAdapter.Migration, error) {
+	return sqlAdapter.LoadMigrations(migrationFiles, path.Join("migrations", dialect.Name()))
+}
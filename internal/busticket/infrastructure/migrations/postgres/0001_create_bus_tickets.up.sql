@@ -0,0 +1,13 @@
+-- Matches the table AutoMigrate used to create, so existing databases adopt it.
+CREATE TABLE IF NOT EXISTS bus_tickets (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT,
+    passenger_name TEXT,
+    departure_time TIMESTAMP WITH TIME ZONE,
+    seat_number BIGINT,
+    origin TEXT,
+    destination TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_bus_tickets_tenant_id ON bus_tickets (tenant_id);
+CREATE INDEX IF NOT EXISTS idx_bus_tickets_passenger_name ON bus_tickets (passenger_name);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_bus_tickets_trip_seat;
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS routes (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT,
+    origin TEXT,
+    destination TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_routes_tenant_id ON routes (tenant_id);
+
+CREATE TABLE IF NOT EXISTS trips (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT,
+    route_id TEXT,
+    departure_time TIMESTAMP WITH TIME ZONE,
+    vehicle TEXT,
+    capacity BIGINT,
+    status TEXT,
+    version BIGINT NOT NULL DEFAULT 1
+);
+
+CREATE INDEX IF NOT EXISTS idx_trips_tenant_id ON trips (tenant_id);
+CREATE INDEX IF NOT EXISTS idx_trips_route_id ON trips (route_id);
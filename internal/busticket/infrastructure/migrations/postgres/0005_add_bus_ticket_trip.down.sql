@@ -0,0 +1,8 @@
+-- Fails while tickets of different trips share a seat and departure.
+DROP INDEX IF EXISTS idx_bus_tickets_trip_id_seat;
+DROP INDEX IF EXISTS idx_bus_tickets_trip_seat;
+CREATE UNIQUE INDEX idx_bus_tickets_trip_seat
+    ON bus_tickets (tenant_id, origin, destination, departure_time, seat_number);
+
+DROP INDEX IF EXISTS idx_bus_tickets_trip_id;
+ALTER TABLE bus_tickets DROP COLUMN IF EXISTS trip_id;
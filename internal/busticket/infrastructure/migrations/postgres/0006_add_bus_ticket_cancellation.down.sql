@@ -0,0 +1,14 @@
+-- Fails while a cancelled ticket shares its seat with a reserved one.
+DROP INDEX IF EXISTS idx_bus_tickets_trip_id_seat;
+CREATE UNIQUE INDEX idx_bus_tickets_trip_id_seat
+    ON bus_tickets (tenant_id, trip_id, seat_number)
+    WHERE trip_id <> '';
+DROP INDEX IF EXISTS idx_bus_tickets_trip_seat;
+CREATE UNIQUE INDEX idx_bus_tickets_trip_seat
+    ON bus_tickets (tenant_id, origin, destination, departure_time, seat_number)
+    WHERE trip_id = '';
+
+ALTER TABLE trips DROP COLUMN IF EXISTS fare;
+ALTER TABLE bus_tickets DROP COLUMN IF EXISTS refund;
+ALTER TABLE bus_tickets DROP COLUMN IF EXISTS price;
+ALTER TABLE bus_tickets DROP COLUMN IF EXISTS status;
@@ -0,0 +1,16 @@
+-- Cancelled tickets stay in the table but release their seats, so only
+-- reserved ones count in the unique seat indexes. Prices and refunds are in
+-- cents; existing tickets and trips had no price.
+ALTER TABLE bus_tickets ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'reserved';
+ALTER TABLE bus_tickets ADD COLUMN IF NOT EXISTS price BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE bus_tickets ADD COLUMN IF NOT EXISTS refund BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS fare BIGINT NOT NULL DEFAULT 0;
+
+DROP INDEX IF EXISTS idx_bus_tickets_trip_seat;
+CREATE UNIQUE INDEX idx_bus_tickets_trip_seat
+    ON bus_tickets (tenant_id, origin, destination, departure_time, seat_number)
+    WHERE trip_id = '' AND status <> 'cancelled';
+DROP INDEX IF EXISTS idx_bus_tickets_trip_id_seat;
+CREATE UNIQUE INDEX idx_bus_tickets_trip_id_seat
+    ON bus_tickets (tenant_id, trip_id, seat_number)
+    WHERE trip_id <> '' AND status <> 'cancelled';
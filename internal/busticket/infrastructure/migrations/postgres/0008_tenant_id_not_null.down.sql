@@ -0,0 +1,4 @@
+-- Backfilled tenants stay ''.
+ALTER TABLE trips ALTER COLUMN tenant_id DROP NOT NULL, ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE routes ALTER COLUMN tenant_id DROP NOT NULL, ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE bus_tickets ALTER COLUMN tenant_id DROP NOT NULL, ALTER COLUMN tenant_id DROP DEFAULT;
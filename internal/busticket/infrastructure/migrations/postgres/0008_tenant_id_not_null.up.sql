@@ -0,0 +1,11 @@
+-- Rows written before tenants, or by the AutoMigrate schema, may have a NULL
+-- tenant, which neither the single-tenant scope ('') nor the unique seat
+-- indexes, where NULLs are distinct, would match.
+UPDATE bus_tickets SET tenant_id = '' WHERE tenant_id IS NULL;
+ALTER TABLE bus_tickets ALTER COLUMN tenant_id SET DEFAULT '', ALTER COLUMN tenant_id SET NOT NULL;
+
+UPDATE routes SET tenant_id = '' WHERE tenant_id IS NULL;
+ALTER TABLE routes ALTER COLUMN tenant_id SET DEFAULT '', ALTER COLUMN tenant_id SET NOT NULL;
+
+UPDATE trips SET tenant_id = '' WHERE tenant_id IS NULL;
+ALTER TABLE trips ALTER COLUMN tenant_id SET DEFAULT '', ALTER COLUMN tenant_id SET NOT NULL;
@@ -0,0 +1,5 @@
+-- Holds stop taking their seats once expired, before they are released, so
+-- a seat may have one unexpired hold and expired ones. Repositories check
+-- the seat's holds and tickets under a lock instead of the unique index.
+DROP INDEX IF EXISTS idx_seat_holds_trip_seat;
+CREATE INDEX IF NOT EXISTS idx_seat_holds_trip_seat ON seat_holds (tenant_id, trip_id, seat_number);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS bus_tickets (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT,
+    passenger_name TEXT,
+    departure_time DATETIME,
+    seat_number INTEGER,
+    origin TEXT,
+    destination TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_bus_tickets_tenant_id ON bus_tickets (tenant_id);
+CREATE INDEX IF NOT EXISTS idx_bus_tickets_passenger_name ON bus_tickets (passenger_name);
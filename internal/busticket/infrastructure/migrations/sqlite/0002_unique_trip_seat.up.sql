@@ -0,0 +1,4 @@
+-- A seat is held by at most one ticket per tenant and trip. Fails while the
+-- table already holds double bookings; resolve them before migrating.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bus_tickets_trip_seat
+    ON bus_tickets (tenant_id, origin, destination, departure_time, seat_number);
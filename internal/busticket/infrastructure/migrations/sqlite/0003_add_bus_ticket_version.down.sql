@@ -0,0 +1 @@
+ALTER TABLE bus_tickets DROP COLUMN version;
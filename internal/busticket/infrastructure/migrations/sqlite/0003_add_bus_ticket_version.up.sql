@@ -0,0 +1,2 @@
+-- Existing tickets start at version 1, as newly saved ones do.
+ALTER TABLE bus_tickets ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
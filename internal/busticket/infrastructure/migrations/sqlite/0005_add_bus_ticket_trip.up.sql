@@ -0,0 +1,15 @@
+-- Existing tickets have no trip and keep their seats unique per route and
+-- departure; tickets of a trip have them unique per trip instead, so that
+-- delaying the trip keeps them and two buses leaving together do not share
+-- seat numbers.
+ALTER TABLE bus_tickets ADD COLUMN trip_id TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_bus_tickets_trip_id ON bus_tickets (trip_id);
+
+DROP INDEX IF EXISTS idx_bus_tickets_trip_seat;
+CREATE UNIQUE INDEX idx_bus_tickets_trip_seat
+    ON bus_tickets (tenant_id, origin, destination, departure_time, seat_number)
+    WHERE trip_id = '';
+CREATE UNIQUE INDEX idx_bus_tickets_trip_id_seat
+    ON bus_tickets (tenant_id, trip_id, seat_number)
+    WHERE trip_id <> '';
@@ -0,0 +1,13 @@
+-- A hold keeps its seat until it is released, even once expired, so seats
+-- are unique among all the holds of a trip.
+CREATE TABLE IF NOT EXISTS seat_holds (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT NOT NULL DEFAULT '',
+    trip_id TEXT NOT NULL,
+    seat_number INTEGER NOT NULL,
+    passenger_name TEXT NOT NULL,
+    expires_at DATETIME NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_seat_holds_trip_seat ON seat_holds (tenant_id, trip_id, seat_number);
+CREATE INDEX IF NOT EXISTS idx_seat_holds_expires_at ON seat_holds (expires_at);
@@ -0,0 +1,63 @@
+-- Backfilled tenants stay ''.
+CREATE TABLE bus_tickets_new (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT,
+    passenger_name TEXT,
+    departure_time DATETIME,
+    seat_number INTEGER,
+    origin TEXT,
+    destination TEXT,
+    version INTEGER NOT NULL DEFAULT 1,
+    trip_id TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'reserved',
+    price BIGINT NOT NULL DEFAULT 0,
+    refund BIGINT NOT NULL DEFAULT 0
+);
+INSERT INTO bus_tickets_new
+    SELECT id, tenant_id, passenger_name, departure_time, seat_number, origin, destination,
+        version, trip_id, status, price, refund
+    FROM bus_tickets;
+DROP TABLE bus_tickets;
+ALTER TABLE bus_tickets_new RENAME TO bus_tickets;
+
+CREATE INDEX idx_bus_tickets_tenant_id ON bus_tickets (tenant_id);
+CREATE INDEX idx_bus_tickets_passenger_name ON bus_tickets (passenger_name);
+CREATE INDEX idx_bus_tickets_trip_id ON bus_tickets (trip_id);
+CREATE UNIQUE INDEX idx_bus_tickets_trip_seat
+    ON bus_tickets (tenant_id, origin, destination, departure_time, seat_number)
+    WHERE trip_id = '' AND status <> 'cancelled';
+CREATE UNIQUE INDEX idx_bus_tickets_trip_id_seat
+    ON bus_tickets (tenant_id, trip_id, seat_number)
+    WHERE trip_id <> '' AND status <> 'cancelled';
+
+CREATE TABLE routes_new (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT,
+    origin TEXT,
+    destination TEXT
+);
+INSERT INTO routes_new SELECT id, tenant_id, origin, destination FROM routes;
+DROP TABLE routes;
+ALTER TABLE routes_new RENAME TO routes;
+
+CREATE INDEX idx_routes_tenant_id ON routes (tenant_id);
+
+CREATE TABLE trips_new (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT,
+    route_id TEXT,
+    departure_time DATETIME,
+    vehicle TEXT,
+    capacity INTEGER,
+    status TEXT,
+    version INTEGER NOT NULL DEFAULT 1,
+    fare BIGINT NOT NULL DEFAULT 0
+);
+INSERT INTO trips_new
+    SELECT id, tenant_id, route_id, departure_time, vehicle, capacity, status, version, fare
+    FROM trips;
+DROP TABLE trips;
+ALTER TABLE trips_new RENAME TO trips;
+
+CREATE INDEX idx_trips_tenant_id ON trips (tenant_id);
+CREATE INDEX idx_trips_route_id ON trips (route_id);
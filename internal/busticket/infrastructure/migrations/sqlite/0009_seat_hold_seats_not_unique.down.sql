@@ -0,0 +1,11 @@
+-- Only the latest hold of each seat is kept.
+DELETE FROM seat_holds WHERE EXISTS (
+    SELECT 1 FROM seat_holds later
+    WHERE later.tenant_id = seat_holds.tenant_id
+        AND later.trip_id = seat_holds.trip_id
+        AND later.seat_number = seat_holds.seat_number
+        AND (later.expires_at > seat_holds.expires_at
+            OR (later.expires_at = seat_holds.expires_at AND later.id > seat_holds.id))
+);
+DROP INDEX IF EXISTS idx_seat_holds_trip_seat;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_seat_holds_trip_seat ON seat_holds (tenant_id, trip_id, seat_number);
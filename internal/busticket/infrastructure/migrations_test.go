@@ -0,0 +1,160 @@
+package infrastructure_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mateusmacedo/go-bff/internal/busticket/infrastructure"
+	sqlAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/sql/adapter"
+	"github.com/mateusmacedo/go-bff/pkg/testkit"
+)
+
+func TestBusTicketMigrations(t *testing.T) {
+	ctx := context.Background()
+	migrations, err := infrastructure.BusTicketMigrations(sqlAdapter.SQLiteDialect{})
+	if err != nil {
+		t.Fatalf("BusTicketMigrations() error = %v", err)
+	}
+	last := migrations[len(migrations)-1]
+
+	t.Run("applies every migration once", func(t *testing.T) {
+		migrator := sqlAdapter.NewMigrator(openSQLite(t, filepath.Join(t.TempDir(), "bff.db")), sqlAdapter.SQLiteDialect{}, migrations, testkit.NewLogger(t))
+		if err := migrator.Check(ctx); !errors.Is(err, sqlAdapter.ErrSchemaBehind) {
+			t.Fatalf("Check() before Up error = %v, want %v", err, sqlAdapter.ErrSchemaBehind)
+		}
+
+		if applied, err := migrator.Up(ctx); err != nil || applied != len(migrations) {
+			t.Fatalf("Up() = %d, %v, want %d applied", applied, err, len(migrations))
+		}
+		if applied, err := migrator.Up(ctx); err != nil || applied != 0 {
+			t.Fatalf("Up() again = %d, %v, want nothing applied", applied, err)
+		}
+		expectStatus(t, migrator, len(migrations))
+		if err := migrator.Check(ctx); err != nil {
+			t.Fatalf("Check() error = %v", err)
+		}
+	})
+
+	t.Run("rolls back the last migration", func(t *testing.T) {
+		migrator := sqlAdapter.NewMigrator(openSQLite(t, filepath.Join(t.TempDir(), "bff.db")), sqlAdapter.SQLiteDialect{}, migrations, testkit.NewLogger(t))
+		if _, err := migrator.Up(ctx); err != nil {
+			t.Fatalf("Up() error = %v", err)
+		}
+
+		if rolledBack, err := migrator.Down(ctx, 1); err != nil || rolledBack != 1 {
+			t.Fatalf("Down(1) = %d, %v, want 1 rolled back", rolledBack, err)
+		}
+		expectStatus(t, migrator, len(migrations)-1)
+		err := migrator.Check(ctx)
+		if !errors.Is(err, sqlAdapter.ErrSchemaBehind) || !strings.Contains(err.Error(), last.Name) {
+			t.Fatalf("Check() error = %v, want %v naming %s", err, sqlAdapter.ErrSchemaBehind, last.Name)
+		}
+
+		if applied, err := migrator.Up(ctx); err != nil || applied != 1 {
+			t.Fatalf("Up() after Down = %d, %v, want 1 applied", applied, err)
+		}
+	})
+
+	t.Run("rolls every migration back", func(t *testing.T) {
+		migrator := sqlAdapter.NewMigrator(openSQLite(t, filepath.Join(t.TempDir(), "bff.db")), sqlAdapter.SQLiteDialect{}, migrations, testkit.NewLogger(t))
+		if _, err := migrator.Up(ctx); err != nil {
+			t.Fatalf("Up() error = %v", err)
+		}
+		if rolledBack, err := migrator.Down(ctx, len(migrations)+1); err != nil || rolledBack != len(migrations) {
+			t.Fatalf("Down() = %d, %v, want %d rolled back", rolledBack, err, len(migrations))
+		}
+		expectStatus(t, migrator, 0)
+	})
+
+	t.Run("accepts versions applied by a newer binary", func(t *testing.T) {
+		db := openSQLite(t, filepath.Join(t.TempDir(), "bff.db"))
+		if _, err := sqlAdapter.NewMigrator(db, sqlAdapter.SQLiteDialect{}, migrations, testkit.NewLogger(t)).Up(ctx); err != nil {
+			t.Fatalf("Up() error = %v", err)
+		}
+		older := sqlAdapter.NewMigrator(db, sqlAdapter.SQLiteDialect{}, migrations[:len(migrations)-1], testkit.NewLogger(t))
+
+		statuses, err := older.Status(ctx)
+		if err != nil {
+			t.Fatalf("Status() error = %v", err)
+		}
+		if newest := statuses[len(statuses)-1]; newest.Version != last.Version || !newest.Applied || !newest.Unknown {
+			t.Fatalf("Status() of %d = %+v, want it applied and unknown", last.Version, newest)
+		}
+		if err := older.Check(ctx); err != nil {
+			t.Fatalf("Check() error = %v", err)
+		}
+		if _, err := older.Down(ctx, 1); err == nil || !strings.Contains(err.Error(), "not known to this binary") {
+			t.Fatalf("Down(1) error = %v, want the unknown version refused", err)
+		}
+	})
+
+	t.Run("waits for the migration lock", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "bff.db")
+		migrator := sqlAdapter.NewMigrator(openSQLite(t, path), sqlAdapter.SQLiteDialect{}, migrations, testkit.NewLogger(t))
+
+		// Another migrator holds the lock, as one booting in another pod.
+		holder, err := openSQLite(t, path).Conn(ctx)
+		if err != nil {
+			t.Fatalf("Conn() error = %v", err)
+		}
+		defer holder.Close()
+		for _, statement := range (sqlAdapter.SQLiteDialect{}).BeginMigrations() {
+			if _, err := holder.ExecContext(ctx, statement); err != nil {
+				t.Fatalf("locking migrations: %v", err)
+			}
+		}
+
+		done := make(chan error, 1)
+		go func() {
+			_, err := migrator.Up(ctx)
+			done <- err
+		}()
+		select {
+		case err := <-done:
+			t.Fatalf("Up() returned %v while another migrator held the lock", err)
+		case <-time.After(200 * time.Millisecond):
+		}
+
+		if _, err := holder.ExecContext(ctx, "COMMIT"); err != nil {
+			t.Fatalf("unlocking migrations: %v", err)
+		}
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("Up() error = %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Up() still waiting after the lock was released")
+		}
+		expectStatus(t, migrator, len(migrations))
+	})
+}
+
+func openSQLite(t *testing.T, path string) *sql.DB {
+	t.Helper()
+	db, err := sqlAdapter.NewSQLiteDB(path)
+	if err != nil {
+		t.Fatalf("NewSQLiteDB() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// expectStatus checks that exactly the first applied migrations are applied.
+func expectStatus(t *testing.T, migrator *sqlAdapter.Migrator, applied int) {
+	t.Helper()
+	statuses, err := migrator.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	for i, status := range statuses {
+		if status.Applied != (i < applied) || status.Unknown {
+			t.Fatalf("Status() of %d_%s = %+v, want the first %d applied", status.Version, status.Name, status, applied)
+		}
+	}
+}
@@ -0,0 +1,595 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/mateusmacedo/go-bff/internal/busticket/domain"
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	pkgDomain "github.com/mateusmacedo/go-bff/pkg/domain"
+)
+
+const (
+	defaultRedisKeyPrefix = "bff:bustickets"
+	redisUpdateAttempts   = 5
+	redisScanBatch        = 100
+	// redisSortableTime has a fixed width so that departure index members
+	// sort lexicographically in departure order.
+	redisSortableTime = "2006-01-02T15:04:05.000000000Z"
+)
+
+// RedisBusTicketRepositoryConfig configures the Redis repository. Tickets
+// are archived out of Redis Retention after departure; zero keeps them.
+type RedisBusTicketRepositoryConfig struct {
+	KeyPrefix string
+	Retention time.Duration
+}
+
+// redisBusTicketRepository keeps each ticket in a hash and, per tenant, a
+// set of ticket IDs for every passenger name, a sorted set of
+// "<departure>|<id>" members searched with ZRANGEBYLEX and a hash of seat
+// numbers to ticket IDs for every trip. Index entries whose ticket expired
+// are pruned when read.
+type redisBusTicketRepository struct {
+	client redis.UniversalClient
+	config RedisBusTicketRepositoryConfig
+	clock  pkgDomain.Clock
+	logger application.AppLogger
+}
+
+// NewRedisBusTicketRepository tells the expired holds of the seat hold
+// repository built with config apart with clock.
+func NewRedisBusTicketRepository(client redis.UniversalClient, config RedisBusTicketRepositoryConfig, clock pkgDomain.Clock, logger application.AppLogger) domain.BusTicketRepository {
+	if config.KeyPrefix == "" {
+		config.KeyPrefix = defaultRedisKeyPrefix
+	}
+	return &redisBusTicketRepository{
+		client: client,
+		config: config,
+		clock:  clock,
+		logger: logger,
+	}
+}
+
+func (r *redisBusTicketRepository) Save(ctx context.Context, busTicket domain.BusTicket) error {
+	busTicket.TenantID = application.TenantID(ctx)
+	busTicket.Version = 1
+	key := r.ticketKey(busTicket.TenantID, busTicket.ID)
+
+	err := r.watch(ctx, func(tx *redis.Tx) error {
+		exists, err := tx.Exists(ctx, key).Result()
+		if err != nil {
+			return err
+		}
+		if exists > 0 {
+			return errors.New("busTicket already exists")
+		}
+		if err := r.claimSeat(ctx, tx, busTicket); err != nil {
+			return err
+		}
+		if err := redisSeatHeld(ctx, tx, r.config.KeyPrefix, busTicket, r.clock()); err != nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			r.write(ctx, pipe, busTicket)
+			return nil
+		})
+		return err
+	}, key)
+	if err != nil {
+		application.LogError(ctx, r.logger, "failed to save busTicket", err, map[string]interface{}{
+			"busTicket": busTicket,
+		})
+		return err
+	}
+
+	application.LogInfo(ctx, r.logger, "busTicket saved", map[string]interface{}{
+		"busTicket": busTicket,
+	})
+	return nil
+}
+
+func (r *redisBusTicketRepository) FindByID(ctx context.Context, id string) (domain.BusTicket, error) {
+	fields, err := r.client.HGetAll(ctx, r.ticketKey(application.TenantID(ctx), id)).Result()
+	if err != nil {
+		application.LogError(ctx, r.logger, "failed to find busTicket", err, map[string]interface{}{
+			"id": id,
+		})
+		return domain.BusTicket{}, err
+	}
+	if len(fields) == 0 {
+		return domain.BusTicket{}, domain.ErrBusTicketNotFound
+	}
+	return decodeBusTicket(fields)
+}
+
+func (r *redisBusTicketRepository) FindByPassengerName(ctx context.Context, passengerName string) ([]domain.BusTicket, error) {
+	busTickets, err := r.passengerTickets(ctx, application.TenantID(ctx), passengerName)
+	if err != nil {
+		application.LogError(ctx, r.logger, "failed to find busTickets", err, map[string]interface{}{
+			"passengerName": passengerName,
+		})
+		return nil, err
+	}
+
+	application.LogInfo(ctx, r.logger, "busTickets found", map[string]interface{}{
+		"passengerName": passengerName,
+		"busTickets":    busTickets,
+	})
+
+	return busTickets, nil
+}
+
+// FindByTrip reads the trip's seat map, which holds the IDs of its tickets.
+func (r *redisBusTicketRepository) FindByTrip(ctx context.Context, trip domain.TripKey) ([]domain.BusTicket, error) {
+	tenantID := application.TenantID(ctx)
+	seats, err := r.client.HGetAll(ctx, r.seatsKey(tenantID, trip)).Result()
+	if err != nil {
+		application.LogError(ctx, r.logger, "failed to find busTickets of trip", err, map[string]interface{}{
+			"trip": trip.String(),
+		})
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(seats))
+	for _, id := range seats {
+		ids = append(ids, id)
+	}
+	busTickets, _, err := r.load(ctx, tenantID, ids)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(busTickets, func(i, j int) bool {
+		return busTickets[i].SeatNumber < busTickets[j].SeatNumber
+	})
+	return busTickets, nil
+}
+
+func (r *redisBusTicketRepository) Search(ctx context.Context, filter domain.BusTicketFilter, page domain.PageRequest) (domain.BusTicketPage, error) {
+	cursor, err := domain.DecodeCursor(page.Cursor)
+	if err != nil {
+		return domain.BusTicketPage{}, err
+	}
+
+	limit := page.PageLimit()
+	busTickets, err := r.search(ctx, filter, cursor, limit+1)
+	if err != nil {
+		application.LogError(ctx, r.logger, "failed to search busTickets", err, map[string]interface{}{
+			"filter": filter,
+		})
+		return domain.BusTicketPage{}, err
+	}
+
+	result := domain.NewBusTicketPage(busTickets, limit)
+	application.LogInfo(ctx, r.logger, "busTickets searched", map[string]interface{}{
+		"filter": filter,
+		"count":  len(result.BusTickets),
+	})
+	return result, nil
+}
+
+func (r *redisBusTicketRepository) Count(ctx context.Context, filter domain.BusTicketFilter) (int, error) {
+	busTickets, err := r.search(ctx, filter, domain.Cursor{}, 0)
+	if err != nil {
+		application.LogError(ctx, r.logger, "failed to count busTickets", err, map[string]interface{}{
+			"filter": filter,
+		})
+		return 0, err
+	}
+	return len(busTickets), nil
+}
+
+// search returns up to limit sorted tickets after cursor, or all of them when
+// limit is 0. A passenger filter reads that passenger's index; otherwise the
+// departure index is walked in batches from the cursor or range start.
+func (r *redisBusTicketRepository) search(ctx context.Context, filter domain.BusTicketFilter, cursor domain.Cursor, limit int) ([]domain.BusTicket, error) {
+	tenantID := application.TenantID(ctx)
+
+	if filter.PassengerName != "" {
+		candidates, err := r.passengerTickets(ctx, tenantID, filter.PassengerName)
+		if err != nil {
+			return nil, err
+		}
+		var busTickets []domain.BusTicket
+		for _, busTicket := range candidates {
+			if filter.Matches(busTicket) && cursor.Precedes(busTicket) {
+				busTickets = append(busTickets, busTicket)
+			}
+		}
+		sort.Slice(busTickets, func(i, j int) bool {
+			return domain.LessBusTicket(busTickets[i], busTickets[j])
+		})
+		if limit > 0 && len(busTickets) > limit {
+			busTickets = busTickets[:limit]
+		}
+		return busTickets, nil
+	}
+
+	indexKey := r.departuresKey(tenantID)
+	min, max := "-", "+"
+	if !filter.DepartureFrom.IsZero() {
+		min = "[" + filter.DepartureFrom.UTC().Format(redisSortableTime)
+	}
+	if !cursor.IsZero() {
+		min = "(" + departureMember(cursor.DepartureTime, cursor.ID)
+	}
+	if !filter.DepartureTo.IsZero() {
+		max = "(" + filter.DepartureTo.UTC().Format(redisSortableTime)
+	}
+
+	var busTickets []domain.BusTicket
+	for limit == 0 || len(busTickets) < limit {
+		members, err := r.client.ZRangeByLex(ctx, indexKey, &redis.ZRangeBy{Min: min, Max: max, Count: redisScanBatch}).Result()
+		if err != nil {
+			return nil, err
+		}
+
+		ids := make([]string, len(members))
+		for i, member := range members {
+			_, ids[i], _ = strings.Cut(member, "|")
+		}
+		loaded, expired, err := r.load(ctx, tenantID, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, busTicket := range loaded {
+			if filter.Matches(busTicket) && (limit == 0 || len(busTickets) < limit) {
+				busTickets = append(busTickets, busTicket)
+			}
+		}
+		if len(expired) > 0 {
+			stale := make([]interface{}, 0, len(expired))
+			for i, id := range ids {
+				if _, found := expired[id]; found {
+					stale = append(stale, members[i])
+				}
+			}
+			r.prune(ctx, r.client.ZRem, indexKey, stale)
+		}
+
+		if len(members) < redisScanBatch {
+			break
+		}
+		min = "(" + members[len(members)-1]
+	}
+	return busTickets, nil
+}
+
+func (r *redisBusTicketRepository) Update(ctx context.Context, busTicket domain.BusTicket) error {
+	busTicket.TenantID = application.TenantID(ctx)
+	key := r.ticketKey(busTicket.TenantID, busTicket.ID)
+
+	update := func(tx *redis.Tx) error {
+		fields, err := tx.HGetAll(ctx, key).Result()
+		if err != nil {
+			return err
+		}
+		if len(fields) == 0 {
+			return domain.ErrBusTicketNotFound
+		}
+		previous, err := decodeBusTicket(fields)
+		if err != nil {
+			return err
+		}
+		if previous.Version != busTicket.Version {
+			return domain.ErrConcurrencyConflict
+		}
+		if err := r.claimSeat(ctx, tx, busTicket); err != nil {
+			return err
+		}
+
+		next := busTicket
+		next.Version++
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			r.unindex(ctx, pipe, fields, next)
+			r.write(ctx, pipe, next)
+			return nil
+		})
+		return err
+	}
+
+	err := r.watch(ctx, update, key)
+	if errors.Is(err, domain.ErrBusTicketNotFound) {
+		application.LogInfo(ctx, r.logger, "busTicket not found", map[string]interface{}{
+			"busTicket": busTicket,
+		})
+		return err
+	}
+	if err != nil {
+		application.LogError(ctx, r.logger, "failed to update busTicket", err, map[string]interface{}{
+			"busTicket": busTicket,
+		})
+		return err
+	}
+
+	application.LogInfo(ctx, r.logger, "busTicket updated", map[string]interface{}{
+		"busTicket": busTicket,
+	})
+	return nil
+}
+
+func (r *redisBusTicketRepository) Delete(ctx context.Context, id string) error {
+	tenantID := application.TenantID(ctx)
+	key := r.ticketKey(tenantID, id)
+
+	err := r.watch(ctx, func(tx *redis.Tx) error {
+		fields, err := tx.HGetAll(ctx, key).Result()
+		if err != nil {
+			return err
+		}
+		if len(fields) == 0 {
+			return domain.ErrBusTicketNotFound
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, key)
+			r.unindex(ctx, pipe, fields, domain.BusTicket{})
+			return nil
+		})
+		return err
+	}, key)
+	if errors.Is(err, domain.ErrBusTicketNotFound) {
+		return err
+	}
+	if err != nil {
+		application.LogError(ctx, r.logger, "failed to delete busTicket", err, map[string]interface{}{
+			"id": id,
+		})
+		return err
+	}
+
+	application.LogInfo(ctx, r.logger, "busTicket deleted", map[string]interface{}{
+		"id": id,
+	})
+	return nil
+}
+
+// watch runs fn in a WATCH transaction on keys, retrying when another
+// writer changes a watched key between the reads and the MULTI/EXEC. Once
+// the attempts run out it returns ErrConcurrencyConflict, so that callers
+// retry the command or answer 409.
+func (r *redisBusTicketRepository) watch(ctx context.Context, fn func(tx *redis.Tx) error, keys ...string) error {
+	var err error
+	for attempt := 0; attempt < redisUpdateAttempts; attempt++ {
+		if err = r.client.Watch(ctx, fn, keys...); !errors.Is(err, redis.TxFailedErr) {
+			return err
+		}
+	}
+	return fmt.Errorf("busTicket changed concurrently %d times: %w: %w", redisUpdateAttempts, domain.ErrConcurrencyConflict, err)
+}
+
+// claimSeat also watches the seat map of the ticket's trip and fails when
+// another ticket holds its seat, so that two writers cannot both take it.
+// Cancelled tickets claim no seat.
+func (r *redisBusTicketRepository) claimSeat(ctx context.Context, tx *redis.Tx, busTicket domain.BusTicket) error {
+	if !busTicket.HoldsSeat() {
+		return nil
+	}
+	seatsKey := r.seatsKey(busTicket.TenantID, busTicket.Trip())
+	if err := tx.Watch(ctx, seatsKey).Err(); err != nil {
+		return err
+	}
+	holder, err := tx.HGet(ctx, seatsKey, strconv.Itoa(busTicket.SeatNumber)).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if holder != busTicket.ID {
+		return &domain.SeatUnavailableError{Trip: busTicket.Trip(), SeatNumber: busTicket.SeatNumber}
+	}
+	return nil
+}
+
+func (r *redisBusTicketRepository) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
+// write queues the hash, its index entries, its seat unless cancelled and
+// their expiry on pipe. Tickets of a trip share its departure, so the seat map expires with
+// them.
+func (r *redisBusTicketRepository) write(ctx context.Context, pipe redis.Pipeliner, busTicket domain.BusTicket) {
+	key := r.ticketKey(busTicket.TenantID, busTicket.ID)
+	seatsKey := r.seatsKey(busTicket.TenantID, busTicket.Trip())
+	pipe.HSet(ctx, key, encodeBusTicket(busTicket))
+	pipe.SAdd(ctx, r.passengerKey(busTicket.TenantID, busTicket.PassengerName), busTicket.ID)
+	pipe.ZAdd(ctx, r.departuresKey(busTicket.TenantID), redis.Z{Member: departureMember(busTicket.DepartureTime, busTicket.ID)})
+	if busTicket.HoldsSeat() {
+		pipe.HSet(ctx, seatsKey, strconv.Itoa(busTicket.SeatNumber), busTicket.ID)
+	}
+	if r.config.Retention > 0 {
+		pipe.ExpireAt(ctx, key, busTicket.DepartureTime.Add(r.config.Retention))
+		pipe.ExpireAt(ctx, seatsKey, busTicket.DepartureTime.Add(r.config.Retention))
+	}
+}
+
+// unindex queues the removal of the index entries of the stored fields that
+// next no longer shares, including the seat a cancellation releases.
+func (r *redisBusTicketRepository) unindex(ctx context.Context, pipe redis.Pipeliner, fields map[string]string, next domain.BusTicket) {
+	previous, err := decodeBusTicket(fields)
+	if err != nil {
+		return
+	}
+	if previous.PassengerName != next.PassengerName {
+		pipe.SRem(ctx, r.passengerKey(previous.TenantID, previous.PassengerName), previous.ID)
+	}
+	if member := departureMember(previous.DepartureTime, previous.ID); member != departureMember(next.DepartureTime, next.ID) {
+		pipe.ZRem(ctx, r.departuresKey(previous.TenantID), member)
+	}
+	if previous.HoldsSeat() && (!next.HoldsSeat() || previous.SeatNumber != next.SeatNumber || !previous.Trip().Equal(next.Trip())) {
+		pipe.HDel(ctx, r.seatsKey(previous.TenantID, previous.Trip()), strconv.Itoa(previous.SeatNumber))
+	}
+}
+
+func (r *redisBusTicketRepository) passengerTickets(ctx context.Context, tenantID, passengerName string) ([]domain.BusTicket, error) {
+	indexKey := r.passengerKey(tenantID, passengerName)
+	ids, err := r.client.SMembers(ctx, indexKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	loaded, expired, err := r.load(ctx, tenantID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	// The index is only advisory: the hash may have been updated to
+	// another passenger after the IDs were read.
+	var busTickets []domain.BusTicket
+	for _, busTicket := range loaded {
+		if busTicket.PassengerName == passengerName {
+			busTickets = append(busTickets, busTicket)
+		}
+	}
+
+	stale := make([]interface{}, 0, len(expired))
+	for id := range expired {
+		stale = append(stale, id)
+	}
+	r.prune(ctx, r.client.SRem, indexKey, stale)
+	return busTickets, nil
+}
+
+// load reads the hashes of ids in one round trip, keeping their order, and
+// reports the IDs whose hash expired.
+func (r *redisBusTicketRepository) load(ctx context.Context, tenantID string, ids []string) ([]domain.BusTicket, map[string]struct{}, error) {
+	if len(ids) == 0 {
+		return nil, nil, nil
+	}
+
+	pipe := r.client.Pipeline()
+	results := make([]*redis.MapStringStringCmd, len(ids))
+	for i, id := range ids {
+		results[i] = pipe.HGetAll(ctx, r.ticketKey(tenantID, id))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	busTickets := make([]domain.BusTicket, 0, len(ids))
+	expired := make(map[string]struct{})
+	for i, result := range results {
+		fields := result.Val()
+		if len(fields) == 0 {
+			expired[ids[i]] = struct{}{}
+			continue
+		}
+		busTicket, err := decodeBusTicket(fields)
+		if err != nil {
+			return nil, nil, fmt.Errorf("busTicket %s: %w", ids[i], err)
+		}
+		busTickets = append(busTickets, busTicket)
+	}
+	return busTickets, expired, nil
+}
+
+// prune removes index members of expired tickets with remove, SREM or ZREM;
+// failing to is harmless.
+func (r *redisBusTicketRepository) prune(ctx context.Context, remove func(ctx context.Context, key string, members ...interface{}) *redis.IntCmd, indexKey string, members []interface{}) {
+	if len(members) == 0 {
+		return
+	}
+	if err := remove(ctx, indexKey, members...).Err(); err != nil {
+		application.LogError(ctx, r.logger, "failed to prune index", err, map[string]interface{}{
+			"index": indexKey,
+		})
+	}
+}
+
+func (r *redisBusTicketRepository) ticketKey(tenantID, id string) string {
+	return fmt.Sprintf("%s:%s:ticket:%s", r.config.KeyPrefix, tenantID, id)
+}
+
+func (r *redisBusTicketRepository) passengerKey(tenantID, passengerName string) string {
+	return fmt.Sprintf("%s:%s:passenger:%s", r.config.KeyPrefix, tenantID, passengerName)
+}
+
+func (r *redisBusTicketRepository) departuresKey(tenantID string) string {
+	return fmt.Sprintf("%s:%s:departures", r.config.KeyPrefix, tenantID)
+}
+
+func (r *redisBusTicketRepository) seatsKey(tenantID string, trip domain.TripKey) string {
+	return redisSeatsKey(r.config.KeyPrefix, tenantID, trip)
+}
+
+func redisSeatsKey(prefix, tenantID string, trip domain.TripKey) string {
+	return fmt.Sprintf("%s:%s:seats:%s", prefix, tenantID, trip)
+}
+
+func departureMember(departureTime time.Time, id string) string {
+	return departureTime.UTC().Format(redisSortableTime) + "|" + id
+}
+
+func encodeBusTicket(busTicket domain.BusTicket) map[string]interface{} {
+	return map[string]interface{}{
+		"id":             busTicket.ID,
+		"tenant_id":      busTicket.TenantID,
+		"trip_id":        busTicket.TripID,
+		"passenger_name": busTicket.PassengerName,
+		"departure_time": busTicket.DepartureTime.Format(time.RFC3339Nano),
+		"seat_number":    busTicket.SeatNumber,
+		"origin":         busTicket.Origin,
+		"destination":    busTicket.Destination,
+		"status":         string(busTicket.Status),
+		"price":          busTicket.Price,
+		"refund":         busTicket.Refund,
+		"version":        busTicket.Version,
+	}
+}
+
+func decodeBusTicket(fields map[string]string) (domain.BusTicket, error) {
+	departureTime, err := time.Parse(time.RFC3339Nano, fields["departure_time"])
+	if err != nil {
+		return domain.BusTicket{}, fmt.Errorf("departure_time: %w", err)
+	}
+	seatNumber, err := strconv.Atoi(fields["seat_number"])
+	if err != nil {
+		return domain.BusTicket{}, fmt.Errorf("seat_number: %w", err)
+	}
+	// Tickets written before versioning count as version 1.
+	version := int64(1)
+	if raw, ok := fields["version"]; ok {
+		if version, err = strconv.ParseInt(raw, 10, 64); err != nil {
+			return domain.BusTicket{}, fmt.Errorf("version: %w", err)
+		}
+	}
+	// Tickets written before cancellations are reserved and have no price.
+	status := domain.BusTicketReserved
+	if raw, ok := fields["status"]; ok {
+		status = domain.BusTicketStatus(raw)
+	}
+	var price, refund int64
+	if raw, ok := fields["price"]; ok {
+		if price, err = strconv.ParseInt(raw, 10, 64); err != nil {
+			return domain.BusTicket{}, fmt.Errorf("price: %w", err)
+		}
+	}
+	if raw, ok := fields["refund"]; ok {
+		if refund, err = strconv.ParseInt(raw, 10, 64); err != nil {
+			return domain.BusTicket{}, fmt.Errorf("refund: %w", err)
+		}
+	}
+	return domain.BusTicket{
+		ID:            fields["id"],
+		TenantID:      fields["tenant_id"],
+		TripID:        fields["trip_id"],
+		PassengerName: fields["passenger_name"],
+		DepartureTime: departureTime,
+		SeatNumber:    seatNumber,
+		Origin:        fields["origin"],
+		Destination:   fields["destination"],
+		Status:        status,
+		Price:         price,
+		Refund:        refund,
+		Version:       version,
+	}, nil
+}
@@ -0,0 +1,106 @@
+package infrastructure_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/mateusmacedo/go-bff/internal/busticket/domain"
+	"github.com/mateusmacedo/go-bff/internal/busticket/infrastructure"
+	"github.com/mateusmacedo/go-bff/internal/busticket/infrastructure/repositorytest"
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	pkgDomain "github.com/mateusmacedo/go-bff/pkg/domain"
+	"github.com/mateusmacedo/go-bff/pkg/testkit"
+)
+
+func TestRedisBusTicketRepository(t *testing.T) {
+	repositorytest.TestBusTicketRepository(t, func(t *testing.T) domain.BusTicketRepository {
+		client, _ := newRedisClient(t)
+		return infrastructure.NewRedisBusTicketRepository(client, infrastructure.RedisBusTicketRepositoryConfig{}, time.Now, testkit.NewLogger(t))
+	})
+
+	t.Run("reports a concurrency conflict once the watch attempts run out", func(t *testing.T) {
+		client, server := newRedisClient(t)
+		repository := infrastructure.NewRedisBusTicketRepository(client, infrastructure.RedisBusTicketRepositoryConfig{}, time.Now, testkit.NewLogger(t))
+		ctx := application.WithTenant(context.Background(), "tenant-a")
+
+		busTicket := domain.BusTicket{ID: "b1", PassengerName: "Ana", DepartureTime: time.Now().Add(24 * time.Hour), SeatNumber: 1}
+		if err := repository.Save(ctx, busTicket); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		busTicket, err := repository.FindByID(ctx, "b1")
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		// Every read of the ticket inside the transaction is followed by a
+		// write from another client, which fails the transaction's WATCH.
+		client.AddHook(touchAfterRead{server: server})
+
+		busTicket.PassengerName = "Bia"
+		err = repository.Update(ctx, busTicket)
+		if !errors.Is(err, domain.ErrConcurrencyConflict) || !errors.Is(err, pkgDomain.ErrConcurrencyConflict) {
+			t.Fatalf("Update() error = %v, want %v", err, domain.ErrConcurrencyConflict)
+		}
+	})
+}
+
+func TestRedisTripRepository(t *testing.T) {
+	repositorytest.TestTripRepository(t, func(t *testing.T) (domain.RouteRepository, domain.TripRepository) {
+		client, _ := newRedisClient(t)
+		logger := testkit.NewLogger(t)
+		config := infrastructure.RedisBusTicketRepositoryConfig{}
+		return infrastructure.NewRedisRouteRepository(client, config, logger), infrastructure.NewRedisTripRepository(client, config, logger)
+	})
+}
+
+func TestRedisSeatHoldRepository(t *testing.T) {
+	repositorytest.TestSeatHoldRepository(t, func(t *testing.T, clock pkgDomain.Clock) domain.SeatHoldRepository {
+		_, holds := newRedisSeatRepositories(t, clock)
+		return holds
+	})
+	repositorytest.TestSeatClaims(t, newRedisSeatRepositories)
+}
+
+func newRedisSeatRepositories(t *testing.T, clock pkgDomain.Clock) (domain.BusTicketRepository, domain.SeatHoldRepository) {
+	client, _ := newRedisClient(t)
+	logger := testkit.NewLogger(t)
+	config := infrastructure.RedisBusTicketRepositoryConfig{}
+	return infrastructure.NewRedisBusTicketRepository(client, config, clock, logger), infrastructure.NewRedisSeatHoldRepository(client, config, clock, logger)
+}
+
+// newRedisClient connects to an in-process Redis server.
+func newRedisClient(t *testing.T) (*redis.Client, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return client, server
+}
+
+// touchAfterRead changes every hash read with HGETALL behind the back of
+// the client that read it.
+type touchAfterRead struct {
+	server *miniredis.Miniredis
+}
+
+func (h touchAfterRead) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h touchAfterRead) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		err := next(ctx, cmd)
+		if cmd.Name() == "hgetall" {
+			h.server.HSet(cmd.Args()[1].(string), "touched", time.Now().String())
+		}
+		return err
+	}
+}
+
+func (h touchAfterRead) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
@@ -0,0 +1,336 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/mateusmacedo/go-bff/internal/busticket/domain"
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	pkgDomain "github.com/mateusmacedo/go-bff/pkg/domain"
+)
+
+// redisSeatHoldRepository keeps each hold as JSON under its own key and, per
+// tenant, a set of hold IDs for every trip and a key per held seat, holding
+// the hold's ID, that Redis expires with the hold. A sorted set of hold keys
+// scored by expiry, shared by every tenant, tells releases which holds to
+// delete; removing a hold's key from it decides which caller deletes the
+// hold.
+type redisSeatHoldRepository struct {
+	client redis.UniversalClient
+	config RedisBusTicketRepositoryConfig
+	clock  pkgDomain.Clock
+	logger application.AppLogger
+}
+
+// NewRedisSeatHoldRepository shares the key prefix of the ticket repository
+// built with config, keeps released holds' keys at most Retention past their
+// expiry and tells expired holds apart with clock.
+func NewRedisSeatHoldRepository(client redis.UniversalClient, config RedisBusTicketRepositoryConfig, clock pkgDomain.Clock, logger application.AppLogger) domain.SeatHoldRepository {
+	if config.KeyPrefix == "" {
+		config.KeyPrefix = defaultRedisKeyPrefix
+	}
+	return &redisSeatHoldRepository{
+		client: client,
+		config: config,
+		clock:  clock,
+		logger: logger,
+	}
+}
+
+// Save watches the seat key and the seat map of the trip's tickets, so that
+// two writers cannot both take the seat. The seat of an expired hold is
+// free, and Redis expires its key, before the hold is released.
+func (r *redisSeatHoldRepository) Save(ctx context.Context, hold domain.SeatHold) error {
+	hold.TenantID = application.TenantID(ctx)
+	hold.ExpiresAt = hold.ExpiresAt.UTC()
+	key := r.holdKey(hold.TenantID, hold.ID)
+	seatKey := r.seatKey(hold.TenantID, hold.TripID, hold.SeatNumber)
+	trip := domain.TripKey{TripID: hold.TripID}
+	seatsKey := redisSeatsKey(r.config.KeyPrefix, hold.TenantID, trip)
+
+	err := r.client.Watch(ctx, func(tx *redis.Tx) error {
+		exists, err := tx.Exists(ctx, key).Result()
+		if err != nil {
+			return err
+		}
+		if exists > 0 {
+			return errors.New("seat hold already exists")
+		}
+		seat := domain.BusTicket{ID: hold.ID, TenantID: hold.TenantID, TripID: hold.TripID, SeatNumber: hold.SeatNumber}
+		if err := redisSeatHeld(ctx, tx, r.config.KeyPrefix, seat, r.clock()); err != nil {
+			return err
+		}
+		booked, err := tx.HExists(ctx, seatsKey, strconv.Itoa(hold.SeatNumber)).Result()
+		if err != nil {
+			return err
+		}
+		if booked {
+			return &domain.SeatUnavailableError{Trip: trip, SeatNumber: hold.SeatNumber}
+		}
+
+		value, err := json.Marshal(hold)
+		if err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, value, 0)
+			if r.config.Retention > 0 {
+				pipe.ExpireAt(ctx, key, hold.ExpiresAt.Add(r.config.Retention))
+			}
+			pipe.SetArgs(ctx, seatKey, hold.ID, redis.SetArgs{ExpireAt: hold.ExpiresAt})
+			pipe.SAdd(ctx, r.tripHoldsKey(hold.TenantID, hold.TripID), hold.ID)
+			pipe.ZAdd(ctx, r.expiriesKey(), redis.Z{Score: expiryScore(hold.ExpiresAt), Member: key})
+			return nil
+		})
+		return err
+	}, key, seatKey, seatsKey)
+	if errors.Is(err, redis.TxFailedErr) {
+		err = &domain.SeatUnavailableError{Trip: domain.TripKey{TripID: hold.TripID}, SeatNumber: hold.SeatNumber}
+	}
+	if err != nil {
+		application.LogError(ctx, r.logger, "failed to save seatHold", err, map[string]interface{}{
+			"seatHold": hold,
+		})
+		return err
+	}
+
+	application.LogInfo(ctx, r.logger, "seatHold saved", map[string]interface{}{
+		"seatHold": hold,
+	})
+	return nil
+}
+
+func (r *redisSeatHoldRepository) FindByID(ctx context.Context, id string) (domain.SeatHold, error) {
+	hold, err := getRedisSeatHold(ctx, r.client, r.holdKey(application.TenantID(ctx), id))
+	if err != nil && !errors.Is(err, domain.ErrSeatHoldNotFound) {
+		application.LogError(ctx, r.logger, "failed to find seatHold", err, map[string]interface{}{
+			"id": id,
+		})
+	}
+	return hold, err
+}
+
+// FindByTrip prunes the IDs of deleted holds from the trip index.
+func (r *redisSeatHoldRepository) FindByTrip(ctx context.Context, tripID string) ([]domain.SeatHold, error) {
+	tenantID := application.TenantID(ctx)
+	indexKey := r.tripHoldsKey(tenantID, tripID)
+	ids, err := r.client.SMembers(ctx, indexKey).Result()
+	if err != nil {
+		application.LogError(ctx, r.logger, "failed to find seatHolds of trip", err, map[string]interface{}{
+			"tripId": tripID,
+		})
+		return nil, err
+	}
+
+	var (
+		now   = r.clock()
+		holds []domain.SeatHold
+		stale []interface{}
+	)
+	for _, id := range ids {
+		hold, err := getRedisSeatHold(ctx, r.client, r.holdKey(tenantID, id))
+		if errors.Is(err, domain.ErrSeatHoldNotFound) {
+			stale = append(stale, id)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !hold.Expired(now) {
+			holds = append(holds, hold)
+		}
+	}
+	if len(stale) > 0 {
+		if err := r.client.SRem(ctx, indexKey, stale...).Err(); err != nil {
+			application.LogError(ctx, r.logger, "failed to prune index", err, map[string]interface{}{
+				"index": indexKey,
+			})
+		}
+	}
+	sortSeatHolds(holds)
+	return holds, nil
+}
+
+func (r *redisSeatHoldRepository) Delete(ctx context.Context, id string) error {
+	key := r.holdKey(application.TenantID(ctx), id)
+	hold, err := r.take(ctx, key)
+	if err != nil {
+		if !errors.Is(err, domain.ErrSeatHoldNotFound) {
+			application.LogError(ctx, r.logger, "failed to delete seatHold", err, map[string]interface{}{
+				"id": id,
+			})
+		}
+		return err
+	}
+
+	application.LogInfo(ctx, r.logger, "seatHold deleted", map[string]interface{}{
+		"seatHold": hold,
+	})
+	return nil
+}
+
+func (r *redisSeatHoldRepository) ReleaseExpired(ctx context.Context) ([]domain.SeatHold, error) {
+	keys, err := r.client.ZRangeByScore(ctx, r.expiriesKey(), &redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(r.clock().UnixMilli(), 10),
+	}).Result()
+	if err != nil {
+		application.LogError(ctx, r.logger, "failed to find expired seatHolds", err, nil)
+		return nil, err
+	}
+
+	var released []domain.SeatHold
+	for _, key := range keys {
+		hold, err := r.take(ctx, key)
+		if errors.Is(err, domain.ErrSeatHoldNotFound) {
+			continue
+		}
+		if err != nil {
+			application.LogError(ctx, r.logger, "failed to release seatHold", err, map[string]interface{}{
+				"key": key,
+			})
+			return released, err
+		}
+		released = append(released, hold)
+	}
+	sortSeatHolds(released)
+
+	if len(released) > 0 {
+		application.LogInfo(ctx, r.logger, "expired seatHolds released", map[string]interface{}{
+			"count": len(released),
+		})
+	}
+	return released, nil
+}
+
+// take claims the hold under key by removing it from the expiry index, then
+// deletes it, its trip index entry and its seat key unless Redis expired it
+// and another hold took the seat. It returns ErrSeatHoldNotFound when
+// another caller claimed the hold first.
+func (r *redisSeatHoldRepository) take(ctx context.Context, key string) (domain.SeatHold, error) {
+	removed, err := r.client.ZRem(ctx, r.expiriesKey(), key).Result()
+	if err != nil {
+		return domain.SeatHold{}, err
+	}
+	if removed == 0 {
+		return domain.SeatHold{}, domain.ErrSeatHoldNotFound
+	}
+	hold, err := getRedisSeatHold(ctx, r.client, key)
+	if err != nil {
+		return domain.SeatHold{}, err
+	}
+
+	seatKey := r.seatKey(hold.TenantID, hold.TripID, hold.SeatNumber)
+	for attempt := 0; attempt < redisUpdateAttempts; attempt++ {
+		if err = r.client.Watch(ctx, r.unindex(ctx, key, seatKey, hold), seatKey); !errors.Is(err, redis.TxFailedErr) {
+			return hold, err
+		}
+	}
+	return hold, fmt.Errorf("seat %d changed concurrently %d times: %w: %w", hold.SeatNumber, redisUpdateAttempts, pkgDomain.ErrConcurrencyConflict, err)
+}
+
+// unindex deletes the hold under key with its index entries and its seat
+// key, if the seat is still its own.
+func (r *redisSeatHoldRepository) unindex(ctx context.Context, key, seatKey string, hold domain.SeatHold) func(tx *redis.Tx) error {
+	return func(tx *redis.Tx) error {
+		holder, err := tx.Get(ctx, seatKey).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, key)
+			pipe.SRem(ctx, r.tripHoldsKey(hold.TenantID, hold.TripID), hold.ID)
+			if holder == hold.ID {
+				pipe.Del(ctx, seatKey)
+			}
+			return nil
+		})
+		return err
+	}
+}
+
+// redisSeatHeld watches the held seat key of busTicket's seat on tx and
+// fails while a hold unexpired at now, other than busTicket's own, takes it.
+func redisSeatHeld(ctx context.Context, tx *redis.Tx, prefix string, busTicket domain.BusTicket, now time.Time) error {
+	if busTicket.TripID == "" || !busTicket.HoldsSeat() {
+		return nil
+	}
+	seatKey := redisHeldSeatKey(prefix, busTicket.TenantID, busTicket.TripID, busTicket.SeatNumber)
+	if err := tx.Watch(ctx, seatKey).Err(); err != nil {
+		return err
+	}
+	holder, err := tx.Get(ctx, seatKey).Result()
+	if errors.Is(err, redis.Nil) || holder == busTicket.ID {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	hold, err := getRedisSeatHold(ctx, tx, redisSeatHoldKey(prefix, busTicket.TenantID, holder))
+	if errors.Is(err, domain.ErrSeatHoldNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if hold.Expired(now) {
+		return nil
+	}
+	return &domain.SeatUnavailableError{Trip: busTicket.Trip(), SeatNumber: busTicket.SeatNumber}
+}
+
+func getRedisSeatHold(ctx context.Context, client redis.Cmdable, key string) (domain.SeatHold, error) {
+	value, err := client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return domain.SeatHold{}, domain.ErrSeatHoldNotFound
+	}
+	if err != nil {
+		return domain.SeatHold{}, err
+	}
+
+	var hold domain.SeatHold
+	if err := json.Unmarshal(value, &hold); err != nil {
+		return domain.SeatHold{}, fmt.Errorf("seat hold %s: %w", key, err)
+	}
+	return hold, nil
+}
+
+func (r *redisSeatHoldRepository) holdKey(tenantID, id string) string {
+	return redisSeatHoldKey(r.config.KeyPrefix, tenantID, id)
+}
+
+func (r *redisSeatHoldRepository) tripHoldsKey(tenantID, tripID string) string {
+	return fmt.Sprintf("%s:%s:trip-seat-holds:%s", r.config.KeyPrefix, tenantID, tripID)
+}
+
+func (r *redisSeatHoldRepository) seatKey(tenantID, tripID string, seatNumber int) string {
+	return redisHeldSeatKey(r.config.KeyPrefix, tenantID, tripID, seatNumber)
+}
+
+func (r *redisSeatHoldRepository) expiriesKey() string {
+	return r.config.KeyPrefix + ":seat-hold-expiries"
+}
+
+// expiryScore rounds expiresAt up to the millisecond, so that a release
+// never picks a hold before it expires.
+func expiryScore(expiresAt time.Time) float64 {
+	ms := expiresAt.UnixMilli()
+	if expiresAt.After(time.UnixMilli(ms)) {
+		ms++
+	}
+	return float64(ms)
+}
+
+func redisSeatHoldKey(prefix, tenantID, id string) string {
+	return fmt.Sprintf("%s:%s:seat-hold:%s", prefix, tenantID, id)
+}
+
+func redisHeldSeatKey(prefix, tenantID, tripID string, seatNumber int) string {
+	return fmt.Sprintf("%s:%s:held-seat:%s:%d", prefix, tenantID, tripID, seatNumber)
+}
@@ -0,0 +1,255 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/mateusmacedo/go-bff/internal/busticket/domain"
+	"github.com/mateusmacedo/go-bff/pkg/application"
+)
+
+// redisRouteRepository keeps each route as JSON under its own key.
+type redisRouteRepository struct {
+	client redis.UniversalClient
+	config RedisBusTicketRepositoryConfig
+	logger application.AppLogger
+}
+
+// NewRedisRouteRepository shares the key prefix of the ticket repository
+// built with config.
+func NewRedisRouteRepository(client redis.UniversalClient, config RedisBusTicketRepositoryConfig, logger application.AppLogger) domain.RouteRepository {
+	if config.KeyPrefix == "" {
+		config.KeyPrefix = defaultRedisKeyPrefix
+	}
+	return &redisRouteRepository{
+		client: client,
+		config: config,
+		logger: logger,
+	}
+}
+
+func (r *redisRouteRepository) Save(ctx context.Context, route domain.Route) error {
+	route.TenantID = application.TenantID(ctx)
+	value, err := json.Marshal(route)
+	if err != nil {
+		return err
+	}
+
+	created, err := r.client.SetNX(ctx, r.routeKey(route.TenantID, route.ID), value, 0).Result()
+	if err == nil && !created {
+		err = errors.New("route already exists")
+	}
+	if err != nil {
+		application.LogError(ctx, r.logger, "failed to save route", err, map[string]interface{}{
+			"route": route,
+		})
+		return err
+	}
+
+	application.LogInfo(ctx, r.logger, "route saved", map[string]interface{}{
+		"route": route,
+	})
+	return nil
+}
+
+func (r *redisRouteRepository) FindByID(ctx context.Context, id string) (domain.Route, error) {
+	value, err := r.client.Get(ctx, r.routeKey(application.TenantID(ctx), id)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return domain.Route{}, domain.ErrRouteNotFound
+	}
+	if err != nil {
+		application.LogError(ctx, r.logger, "failed to find route", err, map[string]interface{}{
+			"id": id,
+		})
+		return domain.Route{}, err
+	}
+
+	var route domain.Route
+	if err := json.Unmarshal(value, &route); err != nil {
+		return domain.Route{}, fmt.Errorf("route %s: %w", id, err)
+	}
+	return route, nil
+}
+
+func (r *redisRouteRepository) routeKey(tenantID, id string) string {
+	return fmt.Sprintf("%s:%s:route:%s", r.config.KeyPrefix, tenantID, id)
+}
+
+// redisTripRepository keeps each trip as JSON under its own key and, per
+// route, a set of its trip IDs. Trips expire Retention after departure, as
+// their tickets do.
+type redisTripRepository struct {
+	client redis.UniversalClient
+	config RedisBusTicketRepositoryConfig
+	logger application.AppLogger
+}
+
+// NewRedisTripRepository shares the key prefix and retention of the ticket
+// repository built with config.
+func NewRedisTripRepository(client redis.UniversalClient, config RedisBusTicketRepositoryConfig, logger application.AppLogger) domain.TripRepository {
+	if config.KeyPrefix == "" {
+		config.KeyPrefix = defaultRedisKeyPrefix
+	}
+	return &redisTripRepository{
+		client: client,
+		config: config,
+		logger: logger,
+	}
+}
+
+func (r *redisTripRepository) Save(ctx context.Context, trip domain.Trip) error {
+	trip.TenantID = application.TenantID(ctx)
+	trip.Version = 1
+	key := r.tripKey(trip.TenantID, trip.ID)
+
+	err := r.client.Watch(ctx, func(tx *redis.Tx) error {
+		exists, err := tx.Exists(ctx, key).Result()
+		if err != nil {
+			return err
+		}
+		if exists > 0 {
+			return errors.New("trip already exists")
+		}
+		return r.write(ctx, tx, trip)
+	}, key)
+	if err != nil {
+		application.LogError(ctx, r.logger, "failed to save trip", err, map[string]interface{}{
+			"trip": trip,
+		})
+		return err
+	}
+
+	application.LogInfo(ctx, r.logger, "trip saved", map[string]interface{}{
+		"trip": trip,
+	})
+	return nil
+}
+
+func (r *redisTripRepository) FindByID(ctx context.Context, id string) (domain.Trip, error) {
+	trip, err := r.get(ctx, r.client, application.TenantID(ctx), id)
+	if err != nil && !errors.Is(err, domain.ErrTripNotFound) {
+		application.LogError(ctx, r.logger, "failed to find trip", err, map[string]interface{}{
+			"id": id,
+		})
+	}
+	return trip, err
+}
+
+// FindByRoute prunes the IDs of expired trips from the route index.
+func (r *redisTripRepository) FindByRoute(ctx context.Context, routeID string) ([]domain.Trip, error) {
+	tenantID := application.TenantID(ctx)
+	indexKey := r.routeTripsKey(tenantID, routeID)
+	ids, err := r.client.SMembers(ctx, indexKey).Result()
+	if err != nil {
+		application.LogError(ctx, r.logger, "failed to find trips of route", err, map[string]interface{}{
+			"routeId": routeID,
+		})
+		return nil, err
+	}
+
+	var (
+		trips []domain.Trip
+		stale []interface{}
+	)
+	for _, id := range ids {
+		trip, err := r.get(ctx, r.client, tenantID, id)
+		if errors.Is(err, domain.ErrTripNotFound) {
+			stale = append(stale, id)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		trips = append(trips, trip)
+	}
+	if len(stale) > 0 {
+		if err := r.client.SRem(ctx, indexKey, stale...).Err(); err != nil {
+			application.LogError(ctx, r.logger, "failed to prune index", err, map[string]interface{}{
+				"index": indexKey,
+			})
+		}
+	}
+	sortTrips(trips)
+	return trips, nil
+}
+
+// Update watches the trip key, so a concurrent writer makes it fail with
+// ErrTripConcurrencyConflict as a stale version does.
+func (r *redisTripRepository) Update(ctx context.Context, trip domain.Trip) error {
+	trip.TenantID = application.TenantID(ctx)
+	key := r.tripKey(trip.TenantID, trip.ID)
+
+	err := r.client.Watch(ctx, func(tx *redis.Tx) error {
+		stored, err := r.get(ctx, tx, trip.TenantID, trip.ID)
+		if err != nil {
+			return err
+		}
+		if stored.Version != trip.Version {
+			return domain.ErrTripConcurrencyConflict
+		}
+		next := trip
+		next.Version++
+		return r.write(ctx, tx, next)
+	}, key)
+	if errors.Is(err, redis.TxFailedErr) {
+		err = domain.ErrTripConcurrencyConflict
+	}
+	if err != nil {
+		application.LogError(ctx, r.logger, "failed to update trip", err, map[string]interface{}{
+			"trip": trip,
+		})
+		return err
+	}
+
+	application.LogInfo(ctx, r.logger, "trip updated", map[string]interface{}{
+		"trip": trip,
+	})
+	return nil
+}
+
+func (r *redisTripRepository) get(ctx context.Context, client redis.Cmdable, tenantID, id string) (domain.Trip, error) {
+	value, err := client.Get(ctx, r.tripKey(tenantID, id)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return domain.Trip{}, domain.ErrTripNotFound
+	}
+	if err != nil {
+		return domain.Trip{}, err
+	}
+
+	var trip domain.Trip
+	if err := json.Unmarshal(value, &trip); err != nil {
+		return domain.Trip{}, fmt.Errorf("trip %s: %w", id, err)
+	}
+	return trip, nil
+}
+
+// write stores trip and indexes it under its route in one MULTI/EXEC.
+func (r *redisTripRepository) write(ctx context.Context, tx *redis.Tx, trip domain.Trip) error {
+	value, err := json.Marshal(trip)
+	if err != nil {
+		return err
+	}
+
+	key := r.tripKey(trip.TenantID, trip.ID)
+	_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, key, value, 0)
+		pipe.SAdd(ctx, r.routeTripsKey(trip.TenantID, trip.RouteID), trip.ID)
+		if r.config.Retention > 0 {
+			pipe.ExpireAt(ctx, key, trip.DepartureTime.Add(r.config.Retention))
+		}
+		return nil
+	})
+	return err
+}
+
+func (r *redisTripRepository) tripKey(tenantID, id string) string {
+	return fmt.Sprintf("%s:%s:trip:%s", r.config.KeyPrefix, tenantID, id)
+}
+
+func (r *redisTripRepository) routeTripsKey(tenantID, routeID string) string {
+	return fmt.Sprintf("%s:%s:route-trips:%s", r.config.KeyPrefix, tenantID, routeID)
+}
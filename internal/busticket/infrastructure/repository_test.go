@@ -0,0 +1,111 @@
+package infrastructure_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mateusmacedo/go-bff/internal/busticket/domain"
+	"github.com/mateusmacedo/go-bff/internal/busticket/infrastructure"
+	"github.com/mateusmacedo/go-bff/internal/busticket/infrastructure/repositorytest"
+	pkgDomain "github.com/mateusmacedo/go-bff/pkg/domain"
+	sqlAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/sql/adapter"
+	"github.com/mateusmacedo/go-bff/pkg/testkit"
+)
+
+func TestInMemoryBusTicketRepository(t *testing.T) {
+	repositorytest.TestBusTicketRepository(t, func(t *testing.T) domain.BusTicketRepository {
+		return infrastructure.NewInMemoryBusTicketRepository(testkit.NewLogger(t))
+	})
+}
+
+func TestGormBusTicketRepository(t *testing.T) {
+	repositorytest.TestBusTicketRepository(t, func(t *testing.T) domain.BusTicketRepository {
+		repository, err := infrastructure.NewGormBusTicketRepository(newSQLiteDB(t), sqlAdapter.SQLiteDialect{}, time.Now, testkit.NewLogger(t))
+		if err != nil {
+			t.Fatalf("NewGormBusTicketRepository() error = %v", err)
+		}
+		return repository
+	})
+}
+
+func TestInMemoryTripRepository(t *testing.T) {
+	repositorytest.TestTripRepository(t, func(t *testing.T) (domain.RouteRepository, domain.TripRepository) {
+		logger := testkit.NewLogger(t)
+		return infrastructure.NewInMemoryRouteRepository(logger), infrastructure.NewInMemoryTripRepository(logger)
+	})
+}
+
+func TestGormTripRepository(t *testing.T) {
+	repositorytest.TestTripRepository(t, func(t *testing.T) (domain.RouteRepository, domain.TripRepository) {
+		db := newSQLiteDB(t)
+		logger := testkit.NewLogger(t)
+		routes, err := infrastructure.NewGormRouteRepository(db, sqlAdapter.SQLiteDialect{}, logger)
+		if err != nil {
+			t.Fatalf("NewGormRouteRepository() error = %v", err)
+		}
+		trips, err := infrastructure.NewGormTripRepository(db, sqlAdapter.SQLiteDialect{}, logger)
+		if err != nil {
+			t.Fatalf("NewGormTripRepository() error = %v", err)
+		}
+		return routes, trips
+	})
+}
+
+func TestInMemorySeatHoldRepository(t *testing.T) {
+	repositorytest.TestSeatHoldRepository(t, func(t *testing.T, clock pkgDomain.Clock) domain.SeatHoldRepository {
+		_, holds := newInMemorySeatRepositories(t, clock)
+		return holds
+	})
+	repositorytest.TestSeatClaims(t, newInMemorySeatRepositories)
+}
+
+func TestGormSeatHoldRepository(t *testing.T) {
+	repositorytest.TestSeatHoldRepository(t, func(t *testing.T, clock pkgDomain.Clock) domain.SeatHoldRepository {
+		_, holds := newGormSeatRepositories(t, clock)
+		return holds
+	})
+	repositorytest.TestSeatClaims(t, newGormSeatRepositories)
+}
+
+func newInMemorySeatRepositories(t *testing.T, clock pkgDomain.Clock) (domain.BusTicketRepository, domain.SeatHoldRepository) {
+	logger := testkit.NewLogger(t)
+	busTickets := infrastructure.NewInMemoryBusTicketRepository(logger)
+	return busTickets, infrastructure.NewInMemorySeatHoldRepository(busTickets, clock, logger)
+}
+
+func newGormSeatRepositories(t *testing.T, clock pkgDomain.Clock) (domain.BusTicketRepository, domain.SeatHoldRepository) {
+	db := newSQLiteDB(t)
+	logger := testkit.NewLogger(t)
+	busTickets, err := infrastructure.NewGormBusTicketRepository(db, sqlAdapter.SQLiteDialect{}, clock, logger)
+	if err != nil {
+		t.Fatalf("NewGormBusTicketRepository() error = %v", err)
+	}
+	holds, err := infrastructure.NewGormSeatHoldRepository(db, sqlAdapter.SQLiteDialect{}, clock, logger)
+	if err != nil {
+		t.Fatalf("NewGormSeatHoldRepository() error = %v", err)
+	}
+	return busTickets, holds
+}
+
+// newSQLiteDB opens a migrated SQLite database in a file, so that parallel
+// writers get connections of their own.
+func newSQLiteDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sqlAdapter.NewSQLiteDB(filepath.Join(t.TempDir(), "bff.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteDB() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrations, err := infrastructure.BusTicketMigrations(sqlAdapter.SQLiteDialect{})
+	if err != nil {
+		t.Fatalf("BusTicketMigrations() error = %v", err)
+	}
+	if _, err := sqlAdapter.NewMigrator(db, sqlAdapter.SQLiteDialect{}, migrations, testkit.NewLogger(t)).Up(context.Background()); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	return db
+}
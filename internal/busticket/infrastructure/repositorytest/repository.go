@@ -0,0 +1,567 @@
+// Package repositorytest holds the contracts every implementation of the
+// bus ticket, route, trip and seat hold repositories must satisfy.
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mateusmacedo/go-bff/internal/busticket/domain"
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	pkgDomain "github.com/mateusmacedo/go-bff/pkg/domain"
+)
+
+// TestBusTicketRepository runs the repository contract against repositories
+// built by newRepository; every subtest gets a fresh repository.
+func TestBusTicketRepository(t *testing.T, newRepository func(t *testing.T) domain.BusTicketRepository) {
+	t.Run("finds saved tickets by passenger name", func(t *testing.T) {
+		repository := newRepository(t)
+		ctx := application.WithTenant(context.Background(), "tenant-a")
+
+		save(t, ctx, repository, ticket("1", "Ana"), ticket("2", "Ana"), ticket("3", "Bruno"))
+
+		found := find(t, ctx, repository, "Ana")
+		if len(found) != 2 || found[0].ID != "1" || found[1].ID != "2" {
+			t.Fatalf("FindByPassengerName() = %+v, want tickets 1 and 2", found)
+		}
+		if want := ticket("1", "Ana"); !sameTicket(found[0], want) || found[0].TenantID != "tenant-a" {
+			t.Fatalf("FindByPassengerName() = %+v, want %+v in tenant-a", found[0], want)
+		}
+		if found := find(t, ctx, repository, "Carla"); len(found) != 0 {
+			t.Fatalf("FindByPassengerName() = %+v, want none", found)
+		}
+	})
+
+	t.Run("isolates tenants", func(t *testing.T) {
+		repository := newRepository(t)
+		tenantA := application.WithTenant(context.Background(), "tenant-a")
+		tenantB := application.WithTenant(context.Background(), "tenant-b")
+
+		save(t, tenantA, repository, ticket("1", "Ana"))
+
+		if found := find(t, tenantB, repository, "Ana"); len(found) != 0 {
+			t.Fatalf("tenant-b found %+v", found)
+		}
+		if err := repository.Update(tenantB, ticket("1", "Ana")); err == nil {
+			t.Fatalf("tenant-b updated a ticket of tenant-a")
+		}
+	})
+
+	t.Run("rejects duplicate IDs", func(t *testing.T) {
+		repository := newRepository(t)
+		ctx := application.WithTenant(context.Background(), "tenant-a")
+
+		save(t, ctx, repository, ticket("1", "Ana"))
+		if err := repository.Save(ctx, ticket("1", "Bruno")); err == nil {
+			t.Fatalf("Save() of a duplicate ID succeeded")
+		}
+		if found := find(t, ctx, repository, "Bruno"); len(found) != 0 {
+			t.Fatalf("duplicate was stored: %+v", found)
+		}
+	})
+
+	t.Run("updates tickets", func(t *testing.T) {
+		repository := newRepository(t)
+		ctx := application.WithTenant(context.Background(), "tenant-a")
+
+		save(t, ctx, repository, ticket("1", "Ana"))
+		updated := ticket("1", "Bruno")
+		updated.SeatNumber = 12
+		if err := repository.Update(ctx, updated); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+
+		if found := find(t, ctx, repository, "Ana"); len(found) != 0 {
+			t.Fatalf("ticket still found under its previous passenger: %+v", found)
+		}
+		found := find(t, ctx, repository, "Bruno")
+		if len(found) != 1 || !sameTicket(found[0], updated) {
+			t.Fatalf("FindByPassengerName() = %+v, want %+v", found, updated)
+		}
+		if found[0].Version != 2 {
+			t.Fatalf("updated ticket at version %d, want 2", found[0].Version)
+		}
+	})
+
+	t.Run("saves tickets at version 1", func(t *testing.T) {
+		repository := newRepository(t)
+		ctx := application.WithTenant(context.Background(), "tenant-a")
+
+		saved := ticket("1", "Ana")
+		saved.Version = 7
+		save(t, ctx, repository, saved)
+		if found, err := repository.FindByID(ctx, "1"); err != nil || found.Version != 1 {
+			t.Fatalf("FindByID() = %+v, %v, want version 1", found, err)
+		}
+	})
+
+	t.Run("rejects stale versions", func(t *testing.T) {
+		repository := newRepository(t)
+		ctx := application.WithTenant(context.Background(), "tenant-a")
+
+		save(t, ctx, repository, ticket("1", "Ana"))
+		first := ticket("1", "Bruno")
+		second := ticket("1", "Carla")
+		if err := repository.Update(ctx, first); err != nil {
+			t.Fatalf("first Update() error = %v", err)
+		}
+		err := repository.Update(ctx, second)
+		if !errors.Is(err, domain.ErrConcurrencyConflict) || !errors.Is(err, pkgDomain.ErrConcurrencyConflict) {
+			t.Fatalf("stale Update() error = %v, want %v", err, domain.ErrConcurrencyConflict)
+		}
+
+		found, err := repository.FindByID(ctx, "1")
+		if err != nil || found.PassengerName != "Bruno" || found.Version != 2 {
+			t.Fatalf("FindByID() = %+v, %v, want Bruno at version 2", found, err)
+		}
+		second.Version = found.Version
+		if err := repository.Update(ctx, second); err != nil {
+			t.Fatalf("Update() at the current version error = %v", err)
+		}
+	})
+
+	t.Run("lets one of parallel updates win", func(t *testing.T) {
+		repository := newRepository(t)
+		ctx := application.WithTenant(context.Background(), "tenant-a")
+		save(t, ctx, repository, ticket("1", "Ana"))
+
+		const n = 20
+		var (
+			wg      sync.WaitGroup
+			mu      sync.Mutex
+			winners []string
+		)
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				busTicket := ticket("1", fmt.Sprint("Passenger ", i))
+				err := repository.Update(ctx, busTicket)
+				switch {
+				case err == nil:
+					mu.Lock()
+					winners = append(winners, busTicket.PassengerName)
+					mu.Unlock()
+				case !errors.Is(err, domain.ErrConcurrencyConflict):
+					t.Errorf("Update(%s) error = %v, want nil or %v", busTicket.PassengerName, err, domain.ErrConcurrencyConflict)
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		if len(winners) != 1 {
+			t.Fatalf("updates applied: %v, want exactly one", winners)
+		}
+		found, err := repository.FindByID(ctx, "1")
+		if err != nil || found.PassengerName != winners[0] || found.Version != 2 {
+			t.Fatalf("FindByID() = %+v, %v, want %s at version 2", found, err, winners[0])
+		}
+	})
+
+	t.Run("fails to update missing tickets", func(t *testing.T) {
+		repository := newRepository(t)
+		ctx := application.WithTenant(context.Background(), "tenant-a")
+
+		if err := repository.Update(ctx, ticket("1", "Ana")); !errors.Is(err, domain.ErrBusTicketNotFound) {
+			t.Fatalf("Update() error = %v, want %v", err, domain.ErrBusTicketNotFound)
+		}
+	})
+
+	t.Run("finds tickets by ID", func(t *testing.T) {
+		repository := newRepository(t)
+		tenantA := application.WithTenant(context.Background(), "tenant-a")
+		tenantB := application.WithTenant(context.Background(), "tenant-b")
+
+		save(t, tenantA, repository, ticket("1", "Ana"))
+
+		found, err := repository.FindByID(tenantA, "1")
+		if err != nil || !sameTicket(found, ticket("1", "Ana")) {
+			t.Fatalf("FindByID() = %+v, %v", found, err)
+		}
+		if _, err := repository.FindByID(tenantA, "2"); !errors.Is(err, domain.ErrBusTicketNotFound) {
+			t.Fatalf("FindByID() of a missing ticket error = %v, want %v", err, domain.ErrBusTicketNotFound)
+		}
+		if _, err := repository.FindByID(tenantB, "1"); !errors.Is(err, domain.ErrBusTicketNotFound) {
+			t.Fatalf("tenant-b FindByID() error = %v, want %v", err, domain.ErrBusTicketNotFound)
+		}
+	})
+
+	t.Run("searches and counts with filters", func(t *testing.T) {
+		repository := newRepository(t)
+		ctx := application.WithTenant(context.Background(), "tenant-a")
+
+		save(t, ctx, repository,
+			trip("1", "Ana", "São Paulo", "Curitiba", 0),
+			trip("2", "Bruno", "São Paulo", "Curitiba", time.Hour),
+			trip("3", "Ana", "São Paulo", "Santos", 2*time.Hour),
+			trip("4", "Ana", "Curitiba", "São Paulo", 3*time.Hour),
+		)
+		save(t, application.WithTenant(context.Background(), "tenant-b"), repository, trip("5", "Ana", "São Paulo", "Curitiba", 0))
+
+		tests := []struct {
+			name   string
+			filter domain.BusTicketFilter
+			want   []string
+		}{
+			{"everything", domain.BusTicketFilter{}, []string{"1", "2", "3", "4"}},
+			{"passenger", domain.BusTicketFilter{PassengerName: "Ana"}, []string{"1", "3", "4"}},
+			{"route", domain.BusTicketFilter{Origin: "São Paulo", Destination: "Curitiba"}, []string{"1", "2"}},
+			{"departure range", domain.BusTicketFilter{DepartureFrom: departure.Add(time.Hour), DepartureTo: departure.Add(3 * time.Hour)}, []string{"2", "3"}},
+			{"passenger and range", domain.BusTicketFilter{PassengerName: "Ana", DepartureFrom: departure.Add(time.Hour)}, []string{"3", "4"}},
+			{"nothing", domain.BusTicketFilter{Origin: "Santos"}, nil},
+		}
+		for _, tt := range tests {
+			page, err := repository.Search(ctx, tt.filter, domain.PageRequest{})
+			if err != nil {
+				t.Fatalf("%s: Search() error = %v", tt.name, err)
+			}
+			if got := ids(page.BusTickets); !equal(got, tt.want) || page.NextCursor != "" {
+				t.Fatalf("%s: Search() = %v, next %q, want %v on one page", tt.name, got, page.NextCursor, tt.want)
+			}
+			count, err := repository.Count(ctx, tt.filter)
+			if err != nil || count != len(tt.want) {
+				t.Fatalf("%s: Count() = %d, %v, want %d", tt.name, count, err, len(tt.want))
+			}
+		}
+	})
+
+	t.Run("pages in stable order", func(t *testing.T) {
+		repository := newRepository(t)
+		ctx := application.WithTenant(context.Background(), "tenant-a")
+
+		// Tickets 1 to 3 share a departure, so IDs break the tie.
+		save(t, ctx, repository,
+			trip("5", "Ana", "São Paulo", "Curitiba", 2*time.Hour),
+			trip("3", "Ana", "São Paulo", "Curitiba", time.Hour),
+			trip("1", "Ana", "São Paulo", "Curitiba", time.Hour),
+			trip("2", "Ana", "São Paulo", "Curitiba", time.Hour),
+			trip("4", "Ana", "São Paulo", "Curitiba", 0),
+		)
+
+		for _, filter := range []domain.BusTicketFilter{{}, {PassengerName: "Ana"}} {
+			first, err := repository.Search(ctx, filter, domain.PageRequest{Limit: 2})
+			if err != nil || !equal(ids(first.BusTickets), []string{"4", "1"}) || first.NextCursor == "" {
+				t.Fatalf("first page = %v, next %q, %v", ids(first.BusTickets), first.NextCursor, err)
+			}
+
+			// Tickets added before the cursor do not shift later pages.
+			if filter == (domain.BusTicketFilter{}) {
+				save(t, ctx, repository, trip("0", "Bruno", "São Paulo", "Curitiba", 0))
+			}
+
+			second, err := repository.Search(ctx, filter, domain.PageRequest{Cursor: first.NextCursor, Limit: 2})
+			if err != nil || !equal(ids(second.BusTickets), []string{"2", "3"}) || second.NextCursor == "" {
+				t.Fatalf("second page = %v, next %q, %v", ids(second.BusTickets), second.NextCursor, err)
+			}
+			last, err := repository.Search(ctx, filter, domain.PageRequest{Cursor: second.NextCursor, Limit: 2})
+			if err != nil || !equal(ids(last.BusTickets), []string{"5"}) || last.NextCursor != "" {
+				t.Fatalf("last page = %v, next %q, %v", ids(last.BusTickets), last.NextCursor, err)
+			}
+		}
+
+		if _, err := repository.Search(ctx, domain.BusTicketFilter{}, domain.PageRequest{Cursor: "not a cursor"}); !errors.Is(err, domain.ErrInvalidCursor) {
+			t.Fatalf("Search() with a bad cursor error = %v, want %v", err, domain.ErrInvalidCursor)
+		}
+	})
+
+	t.Run("deletes tickets", func(t *testing.T) {
+		repository := newRepository(t)
+		ctx := application.WithTenant(context.Background(), "tenant-a")
+
+		save(t, ctx, repository, ticket("1", "Ana"), ticket("2", "Ana"))
+		if err := repository.Delete(application.WithTenant(context.Background(), "tenant-b"), "1"); !errors.Is(err, domain.ErrBusTicketNotFound) {
+			t.Fatalf("tenant-b Delete() error = %v, want %v", err, domain.ErrBusTicketNotFound)
+		}
+		if err := repository.Delete(ctx, "1"); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		if err := repository.Delete(ctx, "1"); !errors.Is(err, domain.ErrBusTicketNotFound) {
+			t.Fatalf("second Delete() error = %v, want %v", err, domain.ErrBusTicketNotFound)
+		}
+
+		if _, err := repository.FindByID(ctx, "1"); !errors.Is(err, domain.ErrBusTicketNotFound) {
+			t.Fatalf("FindByID() after Delete() error = %v", err)
+		}
+		if found := find(t, ctx, repository, "Ana"); !equal(ids(found), []string{"2"}) {
+			t.Fatalf("FindByPassengerName() after Delete() = %v", ids(found))
+		}
+		if page, err := repository.Search(ctx, domain.BusTicketFilter{}, domain.PageRequest{}); err != nil || !equal(ids(page.BusTickets), []string{"2"}) {
+			t.Fatalf("Search() after Delete() = %v, %v", ids(page.BusTickets), err)
+		}
+	})
+
+	t.Run("finds tickets by trip", func(t *testing.T) {
+		repository := newRepository(t)
+		ctx := application.WithTenant(context.Background(), "tenant-a")
+
+		save(t, ctx, repository,
+			ticket("2", "Ana"),
+			ticket("1", "Bruno"),
+			trip("3", "Ana", "São Paulo", "Curitiba", time.Hour),
+			trip("4", "Ana", "São Paulo", "Santos", 0),
+		)
+		save(t, application.WithTenant(context.Background(), "tenant-b"), repository, ticket("5", "Ana"))
+
+		found, err := repository.FindByTrip(ctx, ticket("1", "").Trip())
+		if err != nil {
+			t.Fatalf("FindByTrip() error = %v", err)
+		}
+		sort.Slice(found, func(i, j int) bool { return found[i].ID < found[j].ID })
+		if !equal(ids(found), []string{"1", "2"}) {
+			t.Fatalf("FindByTrip() = %v, want tickets 1 and 2", ids(found))
+		}
+	})
+
+	t.Run("keys the seats of trip tickets by trip ID", func(t *testing.T) {
+		repository := newRepository(t)
+		ctx := application.WithTenant(context.Background(), "tenant-a")
+
+		// Two buses leave together; tickets of the same trip keep their
+		// seats whatever departure they copied.
+		first := onTrip(ticket("1", "Ana"), "t1", 0)
+		other := onTrip(ticket("2", "Bruno"), "t2", 0)
+		other.SeatNumber = first.SeatNumber
+		legacy := ticket("3", "Carla")
+		legacy.SeatNumber = first.SeatNumber
+		delayed := onTrip(ticket("4", "Davi"), "t1", time.Hour)
+		save(t, ctx, repository, first, other, legacy, delayed)
+
+		double := onTrip(ticket("5", "Eva"), "t1", time.Hour)
+		double.SeatNumber = first.SeatNumber
+		if err := repository.Save(ctx, double); !errors.Is(err, domain.ErrSeatUnavailable) {
+			t.Fatalf("Save() of a taken trip seat error = %v, want %v", err, domain.ErrSeatUnavailable)
+		}
+
+		found, err := repository.FindByTrip(ctx, domain.TripKey{TripID: "t1"})
+		if err != nil {
+			t.Fatalf("FindByTrip() error = %v", err)
+		}
+		sort.Slice(found, func(i, j int) bool { return found[i].ID < found[j].ID })
+		if !equal(ids(found), []string{"1", "4"}) || found[0].TripID != "t1" {
+			t.Fatalf("FindByTrip() = %+v, want tickets 1 and 4 of t1", found)
+		}
+		found, err = repository.FindByTrip(ctx, legacy.Trip())
+		if err != nil || !equal(ids(found), []string{"3"}) {
+			t.Fatalf("FindByTrip() of tickets without trip = %v, %v, want ticket 3", ids(found), err)
+		}
+	})
+
+	t.Run("rejects taken seats", func(t *testing.T) {
+		repository := newRepository(t)
+		ctx := application.WithTenant(context.Background(), "tenant-a")
+
+		taken := ticket("1", "Ana")
+		save(t, ctx, repository, taken)
+
+		double := ticket("2", "Bruno")
+		double.SeatNumber = taken.SeatNumber
+		err := repository.Save(ctx, double)
+		if !errors.Is(err, domain.ErrSeatUnavailable) || !errors.Is(err, pkgDomain.ErrConflict) {
+			t.Fatalf("Save() of a taken seat error = %v, want %v", err, domain.ErrSeatUnavailable)
+		}
+		if _, err := repository.FindByID(ctx, "2"); !errors.Is(err, domain.ErrBusTicketNotFound) {
+			t.Fatalf("double booking was stored: %v", err)
+		}
+
+		// The same seat is free on another departure and for another tenant.
+		later := trip("3", "Bruno", "São Paulo", "Curitiba", time.Hour)
+		later.SeatNumber = taken.SeatNumber
+		save(t, ctx, repository, later)
+		save(t, application.WithTenant(context.Background(), "tenant-b"), repository, double)
+
+		// Updates cannot move onto a taken seat, and moving away frees it.
+		other := ticket("4", "Carla")
+		save(t, ctx, repository, other)
+		other.SeatNumber = taken.SeatNumber
+		if err := repository.Update(ctx, other); !errors.Is(err, domain.ErrSeatUnavailable) {
+			t.Fatalf("Update() onto a taken seat error = %v, want %v", err, domain.ErrSeatUnavailable)
+		}
+		if err := repository.Update(ctx, taken); err != nil {
+			t.Fatalf("Update() keeping the seat error = %v", err)
+		}
+		moved := taken
+		moved.SeatNumber = 30
+		moved.Version = 2
+		if err := repository.Update(ctx, moved); err != nil {
+			t.Fatalf("Update() to a free seat error = %v", err)
+		}
+		if err := repository.Update(ctx, other); err != nil {
+			t.Fatalf("Update() onto a freed seat error = %v", err)
+		}
+
+		// Deleting a ticket frees its seat.
+		if err := repository.Delete(ctx, moved.ID); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		freed := ticket("5", "Ana")
+		freed.SeatNumber = moved.SeatNumber
+		save(t, ctx, repository, freed)
+	})
+
+	t.Run("releases the seats of cancelled tickets", func(t *testing.T) {
+		repository := newRepository(t)
+		ctx := application.WithTenant(context.Background(), "tenant-a")
+
+		onTheTrip := onTrip(ticket("1", "Ana"), "t1", 0)
+		legacy := ticket("2", "Bruno")
+		save(t, ctx, repository, onTheTrip, legacy)
+
+		for _, busTicket := range []domain.BusTicket{onTheTrip, legacy} {
+			cancelled := busTicket
+			if err := cancelled.Cancel(busTicket.Price / 2); err != nil {
+				t.Fatalf("Cancel() error = %v", err)
+			}
+			if err := repository.Update(ctx, cancelled); err != nil {
+				t.Fatalf("Update() of a cancelled ticket error = %v", err)
+			}
+			got, err := repository.FindByID(ctx, busTicket.ID)
+			if err != nil || !sameTicket(got, cancelled) || got.Version != 2 {
+				t.Fatalf("FindByID() = %+v, %v, want %+v at version 2", got, err, cancelled)
+			}
+			if found, err := repository.FindByTrip(ctx, busTicket.Trip()); err != nil || len(found) != 0 {
+				t.Fatalf("FindByTrip() = %v, %v, want no ticket holding a seat", ids(found), err)
+			}
+
+			rebooked := busTicket
+			rebooked.ID += "0"
+			save(t, ctx, repository, rebooked)
+			if found, err := repository.FindByTrip(ctx, busTicket.Trip()); err != nil || !equal(ids(found), []string{rebooked.ID}) {
+				t.Fatalf("FindByTrip() = %v, %v, want the rebooked ticket", ids(found), err)
+			}
+		}
+	})
+
+	t.Run("prevents double booking under parallel saves", func(t *testing.T) {
+		repository := newRepository(t)
+		ctx := application.WithTenant(context.Background(), "tenant-a")
+
+		const n = 20
+		var (
+			wg     sync.WaitGroup
+			mu     sync.Mutex
+			booked []string
+		)
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				busTicket := ticket(fmt.Sprint(i), fmt.Sprint("Passenger ", i))
+				busTicket.SeatNumber = 9
+				err := repository.Save(ctx, busTicket)
+				switch {
+				case err == nil:
+					mu.Lock()
+					booked = append(booked, busTicket.ID)
+					mu.Unlock()
+				case !errors.Is(err, domain.ErrSeatUnavailable):
+					t.Errorf("Save(%s) error = %v, want nil or %v", busTicket.ID, err, domain.ErrSeatUnavailable)
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		if len(booked) != 1 {
+			t.Fatalf("seat booked by %v, want exactly one ticket", booked)
+		}
+		found, err := repository.FindByTrip(ctx, ticket("0", "").Trip())
+		if err != nil || !equal(ids(found), booked) {
+			t.Fatalf("FindByTrip() = %v, %v, want %v", ids(found), err, booked)
+		}
+	})
+}
+
+var departure = time.Date(2030, time.January, 2, 15, 4, 5, 0, time.UTC)
+
+// ticket is a ticket on the reference trip, at the version Save stores;
+// numeric IDs take seat ID+1 so that tickets with distinct IDs do not
+// collide.
+func ticket(id, passengerName string) domain.BusTicket {
+	seatNumber, _ := strconv.Atoi(id)
+	return domain.BusTicket{
+		ID:            id,
+		PassengerName: passengerName,
+		DepartureTime: departure,
+		SeatNumber:    seatNumber + 1,
+		Origin:        "São Paulo",
+		Destination:   "Curitiba",
+		Status:        domain.BusTicketReserved,
+		Price:         12990,
+		Version:       1,
+	}
+}
+
+// trip is a ticket departing offset after departure.
+func trip(id, passengerName, origin, destination string, offset time.Duration) domain.BusTicket {
+	busTicket := ticket(id, passengerName)
+	busTicket.Origin = origin
+	busTicket.Destination = destination
+	busTicket.DepartureTime = departure.Add(offset)
+	return busTicket
+}
+
+// onTrip puts busTicket on tripID, departing offset after departure.
+func onTrip(busTicket domain.BusTicket, tripID string, offset time.Duration) domain.BusTicket {
+	busTicket.TripID = tripID
+	busTicket.DepartureTime = departure.Add(offset)
+	return busTicket
+}
+
+func ids(busTickets []domain.BusTicket) []string {
+	var ids []string
+	for _, busTicket := range busTickets {
+		ids = append(ids, busTicket.ID)
+	}
+	return ids
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func save(t *testing.T, ctx context.Context, repository domain.BusTicketRepository, busTickets ...domain.BusTicket) {
+	t.Helper()
+	for _, busTicket := range busTickets {
+		if err := repository.Save(ctx, busTicket); err != nil {
+			t.Fatalf("Save(%s) error = %v", busTicket.ID, err)
+		}
+	}
+}
+
+func find(t *testing.T, ctx context.Context, repository domain.BusTicketRepository, passengerName string) []domain.BusTicket {
+	t.Helper()
+	found, err := repository.FindByPassengerName(ctx, passengerName)
+	if err != nil {
+		t.Fatalf("FindByPassengerName(%s) error = %v", passengerName, err)
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].ID < found[j].ID })
+	return found
+}
+
+// sameTicket compares the stored fields; tenants are assigned by the
+// repository and times may come back in another location.
+func sameTicket(got, want domain.BusTicket) bool {
+	return got.ID == want.ID &&
+		got.TripID == want.TripID &&
+		got.PassengerName == want.PassengerName &&
+		got.DepartureTime.Equal(want.DepartureTime) &&
+		got.SeatNumber == want.SeatNumber &&
+		got.Origin == want.Origin &&
+		got.Destination == want.Destination &&
+		got.Status == want.Status &&
+		got.Price == want.Price &&
+		got.Refund == want.Refund
+}
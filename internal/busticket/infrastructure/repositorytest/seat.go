@@ -0,0 +1,116 @@
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mateusmacedo/go-bff/internal/busticket/domain"
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	pkgDomain "github.com/mateusmacedo/go-bff/pkg/domain"
+	"github.com/mateusmacedo/go-bff/pkg/testkit"
+)
+
+// TestSeatClaims runs the contract between the tickets and the holds of the
+// same storage, built by newRepositories with clock: a seat of a trip goes
+// to one ticket or one unexpired hold, however the two are saved.
+func TestSeatClaims(t *testing.T, newRepositories func(t *testing.T, clock pkgDomain.Clock) (domain.BusTicketRepository, domain.SeatHoldRepository)) {
+	newClock := func() *testkit.ManualClock {
+		return testkit.NewManualClock(time.Now().UTC().Truncate(time.Second))
+	}
+
+	t.Run("refuses tickets on held seats but the hold's own", func(t *testing.T) {
+		clock := newClock()
+		busTickets, holds := newRepositories(t, clock.Clock())
+		ctx := application.WithTenant(context.Background(), "tenant-a")
+
+		saveHolds(t, ctx, holds, hold("h1", "t1", 4, clock.Now()))
+		err := busTickets.Save(ctx, onTrip(ticket("3", "Alice"), "t1", 0))
+		var unavailable *domain.SeatUnavailableError
+		if !errors.As(err, &unavailable) || !errors.Is(err, pkgDomain.ErrConflict) || unavailable.SeatNumber != 4 {
+			t.Fatalf("Save() of a held seat error = %v, want a *SeatUnavailableError for seat 4", err)
+		}
+
+		confirmed := onTrip(ticket("3", "Passenger h1"), "t1", 0)
+		confirmed.ID = "h1"
+		save(t, ctx, busTickets, confirmed)
+		save(t, application.WithTenant(context.Background(), "tenant-b"), busTickets, onTrip(ticket("3", "Bob"), "t1", 0))
+	})
+
+	t.Run("refuses holds on booked seats until cancelled", func(t *testing.T) {
+		clock := newClock()
+		busTickets, holds := newRepositories(t, clock.Clock())
+		ctx := application.WithTenant(context.Background(), "tenant-a")
+
+		booked := onTrip(ticket("4", "Alice"), "t1", 0)
+		save(t, ctx, busTickets, booked)
+		if err := holds.Save(ctx, hold("h1", "t1", 5, clock.Now())); !errors.Is(err, domain.ErrSeatUnavailable) {
+			t.Fatalf("Save() of a hold on a booked seat error = %v, want %v", err, domain.ErrSeatUnavailable)
+		}
+
+		booked.Status = domain.BusTicketCancelled
+		if err := busTickets.Update(ctx, booked); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		saveHolds(t, ctx, holds, hold("h1", "t1", 5, clock.Now()))
+	})
+
+	t.Run("books the seats of expired holds before releasing them", func(t *testing.T) {
+		clock := newClock()
+		busTickets, holds := newRepositories(t, clock.Clock())
+		ctx := application.WithTenant(context.Background(), "tenant-a")
+
+		saveHolds(t, ctx, holds, hold("h1", "t1", 7, clock.Now()))
+		clock.Advance(10 * time.Minute)
+		save(t, ctx, busTickets, onTrip(ticket("6", "Alice"), "t1", 0))
+
+		released, err := holds.ReleaseExpired(ctx)
+		if err != nil || len(released) != 1 || released[0].ID != "h1" {
+			t.Fatalf("ReleaseExpired() = %+v, %v, want h1", released, err)
+		}
+	})
+
+	t.Run("gives a seat to one of parallel holds and tickets", func(t *testing.T) {
+		clock := newClock()
+		busTickets, holds := newRepositories(t, clock.Clock())
+		ctx := application.WithTenant(context.Background(), "tenant-a")
+
+		const n = 20
+		var (
+			wg    sync.WaitGroup
+			mu    sync.Mutex
+			taken []string
+		)
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				id := fmt.Sprint("c", i)
+				var err error
+				if i%2 == 0 {
+					err = holds.Save(ctx, hold(id, "t1", 9, clock.Now()))
+				} else {
+					busTicket := onTrip(ticket("8", "Passenger "+id), "t1", 0)
+					busTicket.ID = id
+					err = busTickets.Save(ctx, busTicket)
+				}
+				switch {
+				case err == nil:
+					mu.Lock()
+					taken = append(taken, id)
+					mu.Unlock()
+				case !errors.Is(err, pkgDomain.ErrConflict) && !errors.Is(err, pkgDomain.ErrConcurrencyConflict):
+					t.Errorf("Save(%s) error = %v, want nil or a conflict", id, err)
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		if len(taken) != 1 {
+			t.Fatalf("seat taken by %v, want exactly one hold or ticket", taken)
+		}
+	})
+}
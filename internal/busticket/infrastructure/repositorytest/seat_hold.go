@@ -0,0 +1,263 @@
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mateusmacedo/go-bff/internal/busticket/domain"
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	pkgDomain "github.com/mateusmacedo/go-bff/pkg/domain"
+	"github.com/mateusmacedo/go-bff/pkg/testkit"
+)
+
+// TestSeatHoldRepository runs the seat hold repository contract against
+// repositories built by newRepository with clock; every subtest gets a fresh
+// repository and a clock starting at the current time, which it advances
+// past the expiry of holds instead of waiting for them.
+func TestSeatHoldRepository(t *testing.T, newRepository func(t *testing.T, clock pkgDomain.Clock) domain.SeatHoldRepository) {
+	newClock := func() *testkit.ManualClock {
+		return testkit.NewManualClock(time.Now().UTC().Truncate(time.Second))
+	}
+
+	t.Run("finds saved holds by ID and trip", func(t *testing.T) {
+		clock := newClock()
+		repository := newRepository(t, clock.Clock())
+		ctx := application.WithTenant(context.Background(), "tenant-a")
+
+		h1, h2, h3 := hold("h1", "t1", 2, clock.Now()), hold("h2", "t1", 1, clock.Now()), hold("h3", "t2", 1, clock.Now())
+		saveHolds(t, ctx, repository, h1, h2, h3)
+		if err := repository.Save(ctx, hold("h1", "t1", 5, clock.Now())); err == nil {
+			t.Fatalf("Save() of a duplicate ID succeeded")
+		}
+
+		got, err := repository.FindByID(ctx, "h1")
+		if err != nil || !sameHold(got, h1) || got.TenantID != "tenant-a" {
+			t.Fatalf("FindByID() = %+v, %v, want %+v in tenant-a", got, err, h1)
+		}
+		if _, err := repository.FindByID(ctx, "missing"); !errors.Is(err, domain.ErrSeatHoldNotFound) || !errors.Is(err, pkgDomain.ErrNotFound) {
+			t.Fatalf("FindByID() of a missing hold error = %v, want %v", err, domain.ErrSeatHoldNotFound)
+		}
+
+		found, err := repository.FindByTrip(ctx, "t1")
+		if err != nil || len(found) != 2 || !sameHold(found[0], h2) || !sameHold(found[1], h1) {
+			t.Fatalf("FindByTrip() = %+v, %v, want h2 and h1 by seat", found, err)
+		}
+
+		otherTenant := application.WithTenant(context.Background(), "tenant-b")
+		if _, err := repository.FindByID(otherTenant, "h1"); !errors.Is(err, domain.ErrSeatHoldNotFound) {
+			t.Fatalf("FindByID() in tenant-b error = %v, want %v", err, domain.ErrSeatHoldNotFound)
+		}
+		if found, err := repository.FindByTrip(otherTenant, "t1"); err != nil || len(found) != 0 {
+			t.Fatalf("FindByTrip() in tenant-b = %+v, %v, want none", found, err)
+		}
+	})
+
+	t.Run("keeps held seats taken until deleted", func(t *testing.T) {
+		clock := newClock()
+		repository := newRepository(t, clock.Clock())
+		ctx := application.WithTenant(context.Background(), "tenant-a")
+
+		saveHolds(t, ctx, repository, hold("h1", "t1", 3, clock.Now()))
+		err := repository.Save(ctx, hold("h2", "t1", 3, clock.Now()))
+		var unavailable *domain.SeatUnavailableError
+		if !errors.As(err, &unavailable) || !errors.Is(err, pkgDomain.ErrConflict) || unavailable.SeatNumber != 3 {
+			t.Fatalf("Save() of a held seat error = %v, want a *SeatUnavailableError for seat 3", err)
+		}
+		saveHolds(t, ctx, repository, hold("h3", "t2", 3, clock.Now()))
+		saveHolds(t, application.WithTenant(context.Background(), "tenant-b"), repository, hold("h4", "t1", 3, clock.Now()))
+
+		if err := repository.Delete(ctx, "h1"); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		if err := repository.Delete(ctx, "h1"); !errors.Is(err, domain.ErrSeatHoldNotFound) {
+			t.Fatalf("Delete() of a deleted hold error = %v, want %v", err, domain.ErrSeatHoldNotFound)
+		}
+		if _, err := repository.FindByID(ctx, "h1"); !errors.Is(err, domain.ErrSeatHoldNotFound) {
+			t.Fatalf("FindByID() of a deleted hold error = %v, want %v", err, domain.ErrSeatHoldNotFound)
+		}
+		saveHolds(t, ctx, repository, hold("h2", "t1", 3, clock.Now()))
+	})
+
+	t.Run("frees the seats of expired holds before releasing them", func(t *testing.T) {
+		clock := newClock()
+		repository := newRepository(t, clock.Clock())
+		ctx := application.WithTenant(context.Background(), "tenant-a")
+
+		saveHolds(t, ctx, repository, hold("h1", "t1", 3, clock.Now()))
+		clock.Advance(10 * time.Minute)
+		saveHolds(t, ctx, repository, hold("h2", "t1", 3, clock.Now()))
+
+		released, err := repository.ReleaseExpired(ctx)
+		if err != nil || len(released) != 1 || released[0].ID != "h1" {
+			t.Fatalf("ReleaseExpired() = %+v, %v, want h1", released, err)
+		}
+		found, err := repository.FindByTrip(ctx, "t1")
+		if err != nil || len(found) != 1 || found[0].ID != "h2" {
+			t.Fatalf("FindByTrip() after the release = %+v, %v, want h2", found, err)
+		}
+		if err := repository.Save(ctx, hold("h3", "t1", 3, clock.Now())); !errors.Is(err, domain.ErrSeatUnavailable) {
+			t.Fatalf("Save() of the seat of h2 error = %v, want %v", err, domain.ErrSeatUnavailable)
+		}
+	})
+
+	t.Run("releases expired holds of every tenant once", func(t *testing.T) {
+		clock := newClock()
+		repository := newRepository(t, clock.Clock())
+		ctx := application.WithTenant(context.Background(), "tenant-a")
+		otherTenant := application.WithTenant(context.Background(), "tenant-b")
+
+		expiring := hold("h1", "t1", 1, clock.Now())
+		expiring.ExpiresAt = clock.Now().Add(time.Minute)
+		saveHolds(t, ctx, repository, expiring, hold("h2", "t1", 2, clock.Now()))
+		saveHolds(t, otherTenant, repository, hold("h3", "t1", 1, clock.Now().Add(-9*time.Minute)))
+
+		if released, err := repository.ReleaseExpired(ctx); err != nil || len(released) != 0 {
+			t.Fatalf("ReleaseExpired() before expiry = %+v, %v, want none", released, err)
+		}
+		clock.Advance(5 * time.Minute)
+
+		found, err := repository.FindByTrip(ctx, "t1")
+		if err != nil || len(found) != 1 || found[0].ID != "h2" {
+			t.Fatalf("FindByTrip() after expiry = %+v, %v, want only h2", found, err)
+		}
+		if _, err := repository.FindByID(ctx, "h1"); err != nil {
+			t.Fatalf("FindByID() of an unreleased expired hold error = %v", err)
+		}
+
+		released, err := repository.ReleaseExpired(ctx)
+		if err != nil || len(released) != 2 {
+			t.Fatalf("ReleaseExpired() = %+v, %v, want h1 and h3", released, err)
+		}
+		tenants := map[string]string{}
+		for _, hold := range released {
+			tenants[hold.ID] = hold.TenantID
+		}
+		if tenants["h1"] != "tenant-a" || tenants["h3"] != "tenant-b" {
+			t.Fatalf("ReleaseExpired() released %v, want h1 of tenant-a and h3 of tenant-b", tenants)
+		}
+		if released, err := repository.ReleaseExpired(ctx); err != nil || len(released) != 0 {
+			t.Fatalf("ReleaseExpired() again = %+v, %v, want none", released, err)
+		}
+
+		if _, err := repository.FindByID(ctx, "h1"); !errors.Is(err, domain.ErrSeatHoldNotFound) {
+			t.Fatalf("FindByID() of a released hold error = %v, want %v", err, domain.ErrSeatHoldNotFound)
+		}
+		if err := repository.Delete(otherTenant, "h3"); !errors.Is(err, domain.ErrSeatHoldNotFound) {
+			t.Fatalf("Delete() of a released hold error = %v, want %v", err, domain.ErrSeatHoldNotFound)
+		}
+		saveHolds(t, ctx, repository, hold("h4", "t1", 1, clock.Now()))
+	})
+
+	t.Run("releases each hold once under parallel releases", func(t *testing.T) {
+		clock := newClock()
+		repository := newRepository(t, clock.Clock())
+		ctx := application.WithTenant(context.Background(), "tenant-a")
+
+		const n = 10
+		for i := 0; i < n; i++ {
+			saveHolds(t, ctx, repository, hold(fmt.Sprint("h", i), "t1", i+1, clock.Now()))
+		}
+		clock.Advance(time.Hour)
+
+		var (
+			wg       sync.WaitGroup
+			mu       sync.Mutex
+			released = map[string]int{}
+		)
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				holds, err := repository.ReleaseExpired(ctx)
+				if err != nil {
+					t.Errorf("ReleaseExpired() error = %v", err)
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				for _, hold := range holds {
+					released[hold.ID]++
+				}
+			}()
+		}
+		wg.Wait()
+
+		if len(released) != n {
+			t.Fatalf("released %v, want the %d holds", released, n)
+		}
+		for id, count := range released {
+			if count != 1 {
+				t.Fatalf("hold %s released %d times, want once", id, count)
+			}
+		}
+	})
+
+	t.Run("prevents double holds under parallel saves", func(t *testing.T) {
+		clock := newClock()
+		repository := newRepository(t, clock.Clock())
+		ctx := application.WithTenant(context.Background(), "tenant-a")
+
+		const n = 20
+		var (
+			wg   sync.WaitGroup
+			mu   sync.Mutex
+			held []string
+		)
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				seatHold := hold(fmt.Sprint("h", i), "t1", 9, clock.Now())
+				err := repository.Save(ctx, seatHold)
+				switch {
+				case err == nil:
+					mu.Lock()
+					held = append(held, seatHold.ID)
+					mu.Unlock()
+				case !errors.Is(err, domain.ErrSeatUnavailable):
+					t.Errorf("Save(%s) error = %v, want nil or %v", seatHold.ID, err, domain.ErrSeatUnavailable)
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		if len(held) != 1 {
+			t.Fatalf("seat held by %v, want exactly one hold", held)
+		}
+		found, err := repository.FindByTrip(ctx, "t1")
+		if err != nil || len(found) != 1 || found[0].ID != held[0] {
+			t.Fatalf("FindByTrip() = %+v, %v, want %v", found, err, held)
+		}
+	})
+}
+
+// hold holds seatNumber of tripID for ten minutes from now.
+func hold(id, tripID string, seatNumber int, now time.Time) domain.SeatHold {
+	return domain.SeatHold{
+		ID:            id,
+		TripID:        tripID,
+		SeatNumber:    seatNumber,
+		PassengerName: "Passenger " + id,
+		ExpiresAt:     now.Add(10 * time.Minute),
+	}
+}
+
+func saveHolds(t *testing.T, ctx context.Context, repository domain.SeatHoldRepository, holds ...domain.SeatHold) {
+	t.Helper()
+	for _, hold := range holds {
+		if err := repository.Save(ctx, hold); err != nil {
+			t.Fatalf("Save(%s) error = %v", hold.ID, err)
+		}
+	}
+}
+
+func sameHold(got, want domain.SeatHold) bool {
+	return got.ID == want.ID &&
+		got.TripID == want.TripID &&
+		got.SeatNumber == want.SeatNumber &&
+		got.PassengerName == want.PassengerName &&
+		got.ExpiresAt.Equal(want.ExpiresAt)
+}
@@ -0,0 +1,129 @@
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mateusmacedo/go-bff/internal/busticket/domain"
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	pkgDomain "github.com/mateusmacedo/go-bff/pkg/domain"
+)
+
+// TestTripRepository runs the route and trip repository contract against
+// repositories built by newRepositories; every subtest gets fresh ones.
+func TestTripRepository(t *testing.T, newRepositories func(t *testing.T) (domain.RouteRepository, domain.TripRepository)) {
+	t.Run("finds saved routes by ID", func(t *testing.T) {
+		routes, _ := newRepositories(t)
+		ctx := application.WithTenant(context.Background(), "tenant-a")
+
+		want := domain.Route{ID: "r1", Origin: "São Paulo", Destination: "Curitiba"}
+		if err := routes.Save(ctx, want); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		if err := routes.Save(ctx, want); err == nil {
+			t.Fatalf("Save() of a duplicate ID succeeded")
+		}
+
+		got, err := routes.FindByID(ctx, "r1")
+		if err != nil || got.Origin != want.Origin || got.Destination != want.Destination || got.TenantID != "tenant-a" {
+			t.Fatalf("FindByID() = %+v, %v, want %+v in tenant-a", got, err, want)
+		}
+		_, err = routes.FindByID(application.WithTenant(context.Background(), "tenant-b"), "r1")
+		if !errors.Is(err, domain.ErrRouteNotFound) || !errors.Is(err, pkgDomain.ErrNotFound) {
+			t.Fatalf("FindByID() in tenant-b error = %v, want %v", err, domain.ErrRouteNotFound)
+		}
+	})
+
+	t.Run("finds saved trips by ID and route", func(t *testing.T) {
+		_, trips := newRepositories(t)
+		ctx := application.WithTenant(context.Background(), "tenant-a")
+
+		saveTrips(t, ctx, trips, scheduled("t2", "r1", 2*time.Hour), scheduled("t1", "r1", time.Hour), scheduled("t3", "r2", time.Hour))
+		if err := trips.Save(ctx, scheduled("t1", "r1", time.Hour)); err == nil {
+			t.Fatalf("Save() of a duplicate ID succeeded")
+		}
+
+		got, err := trips.FindByID(ctx, "t1")
+		if want := scheduled("t1", "r1", time.Hour); err != nil || !sameTrip(got, want) || got.Version != 1 || got.TenantID != "tenant-a" {
+			t.Fatalf("FindByID() = %+v, %v, want %+v at version 1 in tenant-a", got, err, want)
+		}
+		if _, err := trips.FindByID(ctx, "missing"); !errors.Is(err, domain.ErrTripNotFound) {
+			t.Fatalf("FindByID() of a missing trip error = %v, want %v", err, domain.ErrTripNotFound)
+		}
+
+		found, err := trips.FindByRoute(ctx, "r1")
+		if err != nil || len(found) != 2 || found[0].ID != "t1" || found[1].ID != "t2" {
+			t.Fatalf("FindByRoute() = %+v, %v, want t1 and t2", found, err)
+		}
+		found, err = trips.FindByRoute(application.WithTenant(context.Background(), "tenant-b"), "r1")
+		if err != nil || len(found) != 0 {
+			t.Fatalf("FindByRoute() in tenant-b = %+v, %v, want none", found, err)
+		}
+	})
+
+	t.Run("updates trips with versions", func(t *testing.T) {
+		_, trips := newRepositories(t)
+		ctx := application.WithTenant(context.Background(), "tenant-a")
+		saveTrips(t, ctx, trips, scheduled("t1", "r1", time.Hour))
+
+		delayed := scheduled("t1", "r1", 3*time.Hour)
+		delayed.Status = domain.TripDelayed
+		if err := trips.Update(ctx, delayed); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		stale := scheduled("t1", "r1", time.Hour)
+		stale.Status = domain.TripCancelled
+		err := trips.Update(ctx, stale)
+		if !errors.Is(err, domain.ErrTripConcurrencyConflict) || !errors.Is(err, pkgDomain.ErrConcurrencyConflict) {
+			t.Fatalf("stale Update() error = %v, want %v", err, domain.ErrTripConcurrencyConflict)
+		}
+
+		got, err := trips.FindByID(ctx, "t1")
+		if err != nil || !sameTrip(got, delayed) || got.Version != 2 {
+			t.Fatalf("FindByID() = %+v, %v, want %+v at version 2", got, err, delayed)
+		}
+		if found, err := trips.FindByRoute(ctx, "r1"); err != nil || len(found) != 1 || !sameTrip(found[0], delayed) {
+			t.Fatalf("FindByRoute() = %+v, %v, want the delayed trip", found, err)
+		}
+
+		if err := trips.Update(ctx, scheduled("missing", "r1", time.Hour)); !errors.Is(err, domain.ErrTripNotFound) {
+			t.Fatalf("Update() of a missing trip error = %v, want %v", err, domain.ErrTripNotFound)
+		}
+	})
+}
+
+// scheduled is a trip of routeID departing offset after departure, at the
+// version Save stores.
+func scheduled(id, routeID string, offset time.Duration) domain.Trip {
+	return domain.Trip{
+		ID:            id,
+		RouteID:       routeID,
+		DepartureTime: departure.Add(offset),
+		Vehicle:       "BUS-" + id,
+		Capacity:      40,
+		Fare:          12990,
+		Status:        domain.TripScheduled,
+		Version:       1,
+	}
+}
+
+func saveTrips(t *testing.T, ctx context.Context, repository domain.TripRepository, trips ...domain.Trip) {
+	t.Helper()
+	for _, trip := range trips {
+		if err := repository.Save(ctx, trip); err != nil {
+			t.Fatalf("Save(%s) error = %v", trip.ID, err)
+		}
+	}
+}
+
+func sameTrip(got, want domain.Trip) bool {
+	return got.ID == want.ID &&
+		got.RouteID == want.RouteID &&
+		got.DepartureTime.Equal(want.DepartureTime) &&
+		got.Vehicle == want.Vehicle &&
+		got.Capacity == want.Capacity &&
+		got.Fare == want.Fare &&
+		got.Status == want.Status
+}
@@ -0,0 +1,250 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/mateusmacedo/go-bff/internal/busticket/application"
+	pkgApp "github.com/mateusmacedo/go-bff/pkg/application"
+	pkgDomain "github.com/mateusmacedo/go-bff/pkg/domain"
+)
+
+// TripBuses are the buses the route, trip and seat hold endpoints dispatch
+// on.
+type TripBuses struct {
+	CreateRoute        pkgApp.CommandBus[pkgDomain.Command[application.CreateRouteData], application.CreateRouteData]
+	CreateTrip         pkgApp.CommandBus[pkgDomain.Command[application.CreateTripData], application.CreateTripData]
+	CancelTrip         pkgApp.CommandBus[pkgDomain.Command[application.CancelTripData], application.CancelTripData]
+	DelayTrip          pkgApp.CommandBus[pkgDomain.Command[application.DelayTripData], application.DelayTripData]
+	GetRouteByID       pkgApp.QueryBus[pkgDomain.Query[application.GetRouteByIDData], application.GetRouteByIDData, application.RouteDetails]
+	GetTripByID        pkgApp.QueryBus[pkgDomain.Query[application.GetTripByIDData], application.GetTripByIDData, application.TripDetails]
+	HoldSeat           pkgApp.CommandBus[pkgDomain.Command[application.HoldSeatData], application.HoldSeatData]
+	ConfirmReservation pkgApp.CommandBus[pkgDomain.Command[application.ConfirmReservationData], application.ConfirmReservationData]
+}
+
+// TripHTTPHandler generates the IDs of new routes, trips and seat holds, so
+// that it can answer with them although commands return no result.
+type TripHTTPHandler struct {
+	buses       TripBuses
+	idGenerator pkgDomain.IDGenerator[string]
+}
+
+func NewTripHTTPHandler(buses TripBuses, idGenerator pkgDomain.IDGenerator[string]) *TripHTTPHandler {
+	return &TripHTTPHandler{
+		buses:       buses,
+		idGenerator: idGenerator,
+	}
+}
+
+func (h *TripHTTPHandler) HandleCreateRoute(w http.ResponseWriter, r *http.Request) {
+	var data application.CreateRouteData
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		handleError(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+	data.ID = h.idGenerator()
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	if err := h.buses.CreateRoute.Dispatch(ctx, application.NewCreateRouteCommand(data)); err != nil {
+		handleError(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	writeCreated(w, "/routes/"+data.ID, "Route created", data)
+}
+
+func (h *TripHTTPHandler) HandleGetRouteByID(w http.ResponseWriter, r *http.Request) {
+	query := application.NewGetRouteByIDQuery(application.GetRouteByIDData{
+		ID: chi.URLParam(r, "routeID"),
+	})
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	route, err := h.buses.GetRouteByID.Dispatch(ctx, query)
+	if err != nil {
+		handleError(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(route); err != nil {
+		handleError(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h *TripHTTPHandler) HandleCreateTrip(w http.ResponseWriter, r *http.Request) {
+	var data application.CreateTripData
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		handleError(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+	data.ID = h.idGenerator()
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	if err := h.buses.CreateTrip.Dispatch(ctx, application.NewCreateTripCommand(data)); err != nil {
+		handleError(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	writeCreated(w, "/trips/"+data.ID, "Trip created", data)
+}
+
+func (h *TripHTTPHandler) HandleGetTripByID(w http.ResponseWriter, r *http.Request) {
+	query := application.NewGetTripByIDQuery(application.GetTripByIDData{
+		ID: chi.URLParam(r, "tripID"),
+	})
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	trip, err := h.buses.GetTripByID.Dispatch(ctx, query)
+	if err != nil {
+		handleError(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag(trip.Version))
+	if err := json.NewEncoder(w).Encode(trip); err != nil {
+		handleError(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h *TripHTTPHandler) HandleCancelTrip(w http.ResponseWriter, r *http.Request) {
+	data := application.CancelTripData{ID: chi.URLParam(r, "tripID")}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	if err := h.buses.CancelTrip.Dispatch(ctx, application.NewCancelTripCommand(data)); err != nil {
+		handleError(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	writeOK(w, "Trip cancelled", data)
+}
+
+// HandleDelayTrip takes the new departure in the body.
+func (h *TripHTTPHandler) HandleDelayTrip(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		DepartureTime time.Time
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		handleError(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+	data := application.DelayTripData{ID: chi.URLParam(r, "tripID"), DepartureTime: body.DepartureTime}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	if err := h.buses.DelayTrip.Dispatch(ctx, application.NewDelayTripCommand(data)); err != nil {
+		handleError(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	writeOK(w, "Trip delayed", data)
+}
+
+// HandleHoldSeat takes the passenger and seat in the body. The hold has no
+// endpoint of its own: the response carries its ID, to confirm it with.
+func (h *TripHTTPHandler) HandleHoldSeat(w http.ResponseWriter, r *http.Request) {
+	var data application.HoldSeatData
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		handleError(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+	data.ID = h.idGenerator()
+	data.TripID = chi.URLParam(r, "tripID")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	if err := h.buses.HoldSeat.Dispatch(ctx, application.NewHoldSeatCommand(data)); err != nil {
+		handleError(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{"message": "Seat held", "data": data}); err != nil {
+		handleError(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// HandleConfirmReservation answers with the location of the ticket, which
+// takes the ID of the hold.
+func (h *TripHTTPHandler) HandleConfirmReservation(w http.ResponseWriter, r *http.Request) {
+	data := application.ConfirmReservationData{HoldID: chi.URLParam(r, "holdID")}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	if err := h.buses.ConfirmReservation.Dispatch(ctx, application.NewConfirmReservationCommand(data)); err != nil {
+		handleError(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	writeCreated(w, "/bustickets/"+data.HoldID, "Reservation confirmed", data)
+}
+
+const (
+	CreateRouteRoute  = "CreateRoute"
+	GetRouteByIDRoute = "GetRouteByID"
+	CreateTripRoute   = "CreateTrip"
+	GetTripByIDRoute  = "GetTripByID"
+	CancelTripRoute   = "CancelTrip"
+	DelayTripRoute    = "DelayTrip"
+
+	HoldSeatRoute           = "HoldSeat"
+	ConfirmReservationRoute = "ConfirmReservation"
+)
+
+func (h *TripHTTPHandler) RegisterRoutes(router chi.Router, options ...RouteOption) {
+	requirements := make(map[string][]func(http.Handler) http.Handler)
+	for _, option := range options {
+		option(requirements)
+	}
+
+	router.With(requirements[CreateRouteRoute]...).Post("/routes", h.HandleCreateRoute)
+	router.With(requirements[GetRouteByIDRoute]...).Get("/routes/{routeID}", h.HandleGetRouteByID)
+	router.With(requirements[CreateTripRoute]...).Post("/trips", h.HandleCreateTrip)
+	router.With(requirements[GetTripByIDRoute]...).Get("/trips/{tripID}", h.HandleGetTripByID)
+	router.With(requirements[CancelTripRoute]...).Post("/trips/{tripID}/cancel", h.HandleCancelTrip)
+	router.With(requirements[DelayTripRoute]...).Post("/trips/{tripID}/delay", h.HandleDelayTrip)
+	router.With(requirements[HoldSeatRoute]...).Post("/trips/{tripID}/holds", h.HandleHoldSeat)
+	router.With(requirements[ConfirmReservationRoute]...).Post("/seatholds/{holdID}/confirm", h.HandleConfirmReservation)
+}
+
+func writeCreated(w http.ResponseWriter, location, message string, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", location)
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{"message": message, "data": data}); err != nil {
+		handleError(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func writeAccepted(w http.ResponseWriter, message string, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{"message": message, "data": data}); err != nil {
+		handleError(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func writeOK(w http.ResponseWriter, message string, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{"message": message, "data": data}); err != nil {
+		handleError(w, err.Error(), http.StatusInternalServerError)
+	}
+}
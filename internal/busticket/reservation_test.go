@@ -0,0 +1,130 @@
+package busticket_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/mateusmacedo/go-bff/internal/busticket"
+	"github.com/mateusmacedo/go-bff/internal/busticket/application"
+	"github.com/mateusmacedo/go-bff/internal/busticket/domain"
+	"github.com/mateusmacedo/go-bff/internal/busticket/infrastructure"
+	bustickettestkit "github.com/mateusmacedo/go-bff/internal/busticket/testkit"
+	pkgApp "github.com/mateusmacedo/go-bff/pkg/application"
+	pkgDomain "github.com/mateusmacedo/go-bff/pkg/domain"
+	sqlAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/sql/adapter"
+	"github.com/mateusmacedo/go-bff/pkg/testkit"
+)
+
+func TestReserveBusTicketConcurrently(t *testing.T) {
+	repositories := []struct {
+		name string
+		new  func(t *testing.T) bustickettestkit.NewRepositories
+	}{
+		{"memory", func(*testing.T) bustickettestkit.NewRepositories { return bustickettestkit.InMemoryRepositories }},
+		{"gorm", gormRepositories},
+		{"redis", redisRepositories},
+	}
+	for _, repository := range repositories {
+		t.Run(repository.name, func(t *testing.T) {
+			h := bustickettestkit.NewHarness(t, bustickettestkit.WithRepositories(repository.new(t)))
+			trip := scheduleTrip(t, h)
+
+			const n = 10
+			var (
+				wg       sync.WaitGroup
+				statuses = make([]int, n)
+			)
+			for i := 0; i < n; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					passengerName := fmt.Sprint("Passenger ", i)
+					resp, _ := h.ReserveBusTicket(application.ReserveBusTicketData{
+						TripID:        trip.ID,
+						PassengerName: passengerName,
+						SeatNumber:    7,
+					}, bustickettestkit.AsPassenger(passengerName))
+					statuses[i] = resp.StatusCode
+				}(i)
+			}
+			wg.Wait()
+
+			counts := map[int]int{}
+			for _, status := range statuses {
+				counts[status]++
+			}
+			if counts[http.StatusCreated] != 1 || counts[http.StatusConflict] != n-1 {
+				t.Fatalf("reservations answered %v, want one success and %d conflicts", counts, n-1)
+			}
+
+			booked, err := h.Repository.FindByTrip(context.Background(), domain.TripKey{TripID: trip.ID})
+			if err != nil || len(booked) != 1 || booked[0].SeatNumber != 7 {
+				t.Fatalf("FindByTrip() = %+v, %v, want one ticket for seat 7", booked, err)
+			}
+		})
+	}
+}
+
+func gormRepositories(t *testing.T) bustickettestkit.NewRepositories {
+	db := newSQLiteDB(t)
+	return func(clock pkgDomain.Clock, logger pkgApp.AppLogger) busticket.Repositories {
+		dialect := sqlAdapter.SQLiteDialect{}
+		var (
+			repositories busticket.Repositories
+			errs         [4]error
+		)
+		repositories.BusTickets, errs[0] = infrastructure.NewGormBusTicketRepository(db, dialect, clock, logger)
+		repositories.Routes, errs[1] = infrastructure.NewGormRouteRepository(db, dialect, logger)
+		repositories.Trips, errs[2] = infrastructure.NewGormTripRepository(db, dialect, logger)
+		repositories.SeatHolds, errs[3] = infrastructure.NewGormSeatHoldRepository(db, dialect, clock, logger)
+		for _, err := range errs {
+			if err != nil {
+				t.Fatalf("creating GORM repositories: %v", err)
+			}
+		}
+		return repositories
+	}
+}
+
+func redisRepositories(t *testing.T) bustickettestkit.NewRepositories {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return func(clock pkgDomain.Clock, logger pkgApp.AppLogger) busticket.Repositories {
+		config := infrastructure.RedisBusTicketRepositoryConfig{}
+		return busticket.Repositories{
+			BusTickets: infrastructure.NewRedisBusTicketRepository(client, config, clock, logger),
+			Routes:     infrastructure.NewRedisRouteRepository(client, config, logger),
+			Trips:      infrastructure.NewRedisTripRepository(client, config, logger),
+			SeatHolds:  infrastructure.NewRedisSeatHoldRepository(client, config, clock, logger),
+		}
+	}
+}
+
+// newSQLiteDB opens a migrated SQLite database in a file, so that parallel
+// requests get connections of their own.
+func newSQLiteDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sqlAdapter.NewSQLiteDB(filepath.Join(t.TempDir(), "bff.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteDB() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrations, err := infrastructure.BusTicketMigrations(sqlAdapter.SQLiteDialect{})
+	if err != nil {
+		t.Fatalf("BusTicketMigrations() error = %v", err)
+	}
+	if _, err := sqlAdapter.NewMigrator(db, sqlAdapter.SQLiteDialect{}, migrations, testkit.NewLogger(t)).Up(context.Background()); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	return db
+}
@@ -0,0 +1,182 @@
+package busticket_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mateusmacedo/go-bff/internal/busticket"
+	"github.com/mateusmacedo/go-bff/internal/busticket/application"
+	"github.com/mateusmacedo/go-bff/internal/busticket/domain"
+	bustickettestkit "github.com/mateusmacedo/go-bff/internal/busticket/testkit"
+)
+
+func TestSeatHolds(t *testing.T) {
+	h := bustickettestkit.NewHarness(t)
+	trip := scheduleTrip(t, h)
+
+	resp, body := h.Do(http.MethodPost, "/trips/"+trip.ID+"/holds", application.HoldSeatData{PassengerName: "Ana", SeatNumber: 3}, bustickettestkit.AsPassenger("Ana"))
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("holding seat 3 answered %d: %s", resp.StatusCode, body)
+	}
+
+	reserveSeat := application.ReserveBusTicketData{TripID: trip.ID, PassengerName: "Bia", SeatNumber: 3}
+	if resp, body := h.ReserveBusTicket(reserveSeat, bustickettestkit.AsPassenger("Bia")); resp.StatusCode != http.StatusConflict {
+		t.Fatalf("reserving the held seat answered %d: %s, want 409", resp.StatusCode, body)
+	}
+
+	h.Clock.Advance(time.Hour)
+	h.ReleaseExpiredSeatHolds()
+	h.SeatHoldBuses.Expired.ExpectPublished("SeatHoldExpired").
+		WithPayload(func(payload application.SeatHoldExpiredData) bool { return payload.SeatNumber == 3 }).
+		Once(t)
+
+	if resp, body := h.ReserveBusTicket(reserveSeat, bustickettestkit.AsPassenger("Bia")); resp.StatusCode != http.StatusCreated {
+		t.Fatalf("reserving the released seat answered %d: %s", resp.StatusCode, body)
+	}
+	h.EventBus.ExpectPublished("BusTicketBooked").Once(t)
+}
+
+func TestReserveBusTicketResponse(t *testing.T) {
+	tests := []struct {
+		name         string
+		options      []busticket.SliceOption
+		wantStatus   int
+		wantLocation string
+		wantETag     string
+	}{
+		{name: "synchronous dispatch", wantStatus: http.StatusCreated, wantLocation: "/bustickets/busticket-1", wantETag: `"1"`},
+		{name: "queued dispatch", options: []busticket.SliceOption{busticket.WithAsynchronousReservations()}, wantStatus: http.StatusAccepted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := bustickettestkit.NewHarness(t, bustickettestkit.WithSliceOptions(tt.options...))
+			trip := scheduleTrip(t, h)
+
+			resp, body := h.ReserveBusTicket(application.ReserveBusTicketData{TripID: trip.ID, PassengerName: "Ana", SeatNumber: 1}, bustickettestkit.AsPassenger("Ana"))
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("reserving answered %d: %s, want %d", resp.StatusCode, body, tt.wantStatus)
+			}
+			if got := resp.Header.Get("Location"); got != tt.wantLocation {
+				t.Errorf("Location = %q, want %q", got, tt.wantLocation)
+			}
+			if got := resp.Header.Get("ETag"); got != tt.wantETag {
+				t.Errorf("ETag = %q, want %q", got, tt.wantETag)
+			}
+
+			var answer struct {
+				Data application.ReserveBusTicketData `json:"data"`
+			}
+			if err := json.Unmarshal(body, &answer); err != nil {
+				t.Fatalf("decoding %s: %v", body, err)
+			}
+			if answer.Data.ID != "busticket-1" {
+				t.Errorf("answered ID %q, want busticket-1", answer.Data.ID)
+			}
+		})
+	}
+}
+
+func TestReserveBusTicketRejections(t *testing.T) {
+	tests := []struct {
+		name       string
+		prepare    func(t *testing.T, h *bustickettestkit.Harness) string
+		wantStatus int
+		wantError  string
+	}{
+		{
+			name: "full trip",
+			prepare: func(t *testing.T, h *bustickettestkit.Harness) string {
+				trip := saveTrip(t, h, 1)
+				if resp, body := h.ReserveBusTicket(application.ReserveBusTicketData{TripID: trip.ID, PassengerName: "Bia", SeatNumber: 1}, bustickettestkit.AsPassenger("Bia")); resp.StatusCode != http.StatusCreated {
+					t.Fatalf("reserving the last seat answered %d: %s", resp.StatusCode, body)
+				}
+				h.EventBus.Reset()
+				return trip.ID
+			},
+			wantStatus: http.StatusConflict,
+			wantError:  domain.ErrTripFull.Error(),
+		},
+		{
+			name: "cancelled trip",
+			prepare: func(t *testing.T, h *bustickettestkit.Harness) string {
+				trip, err := h.Trips.FindByID(context.Background(), scheduleTrip(t, h).ID)
+				if err != nil {
+					t.Fatalf("finding trip: %v", err)
+				}
+				if err := trip.Cancel(h.Clock.Now()); err != nil {
+					t.Fatalf("Cancel() error = %v", err)
+				}
+				if err := h.Trips.Update(context.Background(), trip); err != nil {
+					t.Fatalf("updating trip: %v", err)
+				}
+				return trip.ID
+			},
+			wantStatus: http.StatusConflict,
+			wantError:  domain.ErrTripCancelled.Error(),
+		},
+		{
+			name: "departed trip",
+			prepare: func(t *testing.T, h *bustickettestkit.Harness) string {
+				trip := scheduleTrip(t, h)
+				h.Clock.Advance(25 * time.Hour)
+				return trip.ID
+			},
+			wantStatus: http.StatusConflict,
+			wantError:  domain.ErrTripDeparted.Error(),
+		},
+		{
+			name: "unknown trip",
+			prepare: func(*testing.T, *bustickettestkit.Harness) string {
+				return "missing"
+			},
+			wantStatus: http.StatusNotFound,
+			wantError:  domain.ErrTripNotFound.Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := bustickettestkit.NewHarness(t)
+			tripID := tt.prepare(t, h)
+
+			resp, body := h.ReserveBusTicket(application.ReserveBusTicketData{TripID: tripID, PassengerName: "Ana", SeatNumber: 1}, bustickettestkit.AsPassenger("Ana"))
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("reserving answered %d: %s, want %d", resp.StatusCode, body, tt.wantStatus)
+			}
+			if !strings.Contains(string(body), tt.wantError) {
+				t.Errorf("reserving answered %q, want it to mention %q", body, tt.wantError)
+			}
+			h.EventBus.ExpectPublished("BusTicketBooked").Never(t)
+		})
+	}
+}
+
+// scheduleTrip saves a route and a trip departing in a day.
+func scheduleTrip(t *testing.T, h *bustickettestkit.Harness) domain.Trip {
+	t.Helper()
+	return saveTrip(t, h, 40)
+}
+
+// saveTrip saves a route and a trip with capacity seats departing in a day.
+func saveTrip(t *testing.T, h *bustickettestkit.Harness, capacity int) domain.Trip {
+	t.Helper()
+	ctx := context.Background()
+	route, err := domain.NewRoute("r1", "Recife", "Natal")
+	if err != nil {
+		t.Fatalf("NewRoute() error = %v", err)
+	}
+	if err := h.Routes.Save(ctx, route); err != nil {
+		t.Fatalf("saving route: %v", err)
+	}
+	trip, err := domain.NewTrip("t1", route, h.Clock.Now().Add(24*time.Hour), "bus-1", capacity, 12000, h.Clock.Now())
+	if err != nil {
+		t.Fatalf("NewTrip() error = %v", err)
+	}
+	if err := h.Trips.Save(ctx, trip); err != nil {
+		t.Fatalf("saving trip: %v", err)
+	}
+	return trip
+}
@@ -0,0 +1,253 @@
+package testkit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/mateusmacedo/go-bff/internal/busticket"
+	"github.com/mateusmacedo/go-bff/internal/busticket/application"
+	"github.com/mateusmacedo/go-bff/internal/busticket/domain"
+	"github.com/mateusmacedo/go-bff/internal/busticket/infrastructure"
+	pkgApp "github.com/mateusmacedo/go-bff/pkg/application"
+	pkgDomain "github.com/mateusmacedo/go-bff/pkg/domain"
+	chiAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/chi/adapter"
+	"github.com/mateusmacedo/go-bff/pkg/testkit"
+)
+
+// TripBuses record the route and trip messages.
+type TripBuses struct {
+	CreateRoute  *testkit.RecordingCommandBus[pkgDomain.Command[application.CreateRouteData], application.CreateRouteData]
+	CreateTrip   *testkit.RecordingCommandBus[pkgDomain.Command[application.CreateTripData], application.CreateTripData]
+	CancelTrip   *testkit.RecordingCommandBus[pkgDomain.Command[application.CancelTripData], application.CancelTripData]
+	DelayTrip    *testkit.RecordingCommandBus[pkgDomain.Command[application.DelayTripData], application.DelayTripData]
+	GetRouteByID *testkit.RecordingQueryBus[pkgDomain.Query[application.GetRouteByIDData], application.GetRouteByIDData, application.RouteDetails]
+	GetTripByID  *testkit.RecordingQueryBus[pkgDomain.Query[application.GetTripByIDData], application.GetTripByIDData, application.TripDetails]
+}
+
+// SeatHoldBuses record the seat hold messages.
+type SeatHoldBuses struct {
+	HoldSeat           *testkit.RecordingCommandBus[pkgDomain.Command[application.HoldSeatData], application.HoldSeatData]
+	ConfirmReservation *testkit.RecordingCommandBus[pkgDomain.Command[application.ConfirmReservationData], application.ConfirmReservationData]
+	ReleaseExpired     *testkit.RecordingCommandBus[pkgDomain.Command[application.ReleaseExpiredSeatHoldsData], application.ReleaseExpiredSeatHoldsData]
+	Expired            *testkit.RecordingEventBus[pkgDomain.Event[application.SeatHoldExpiredData], application.SeatHoldExpiredData]
+}
+
+type Harness struct {
+	Server            *httptest.Server
+	Repository        domain.BusTicketRepository
+	Routes            domain.RouteRepository
+	Trips             domain.TripRepository
+	SeatHolds         domain.SeatHoldRepository
+	Clock             *testkit.ManualClock
+	CommandBus        *testkit.RecordingCommandBus[pkgDomain.Command[application.ReserveBusTicketData], application.ReserveBusTicketData]
+	CancelBus         *testkit.RecordingCommandBus[pkgDomain.Command[application.CancelBusTicketData], application.CancelBusTicketData]
+	GetBus            *testkit.RecordingQueryBus[pkgDomain.Query[application.GetBusTicketByIDData], application.GetBusTicketByIDData, domain.BusTicket]
+	SearchBus         *testkit.RecordingQueryBus[pkgDomain.Query[application.SearchBusTicketsData], application.SearchBusTicketsData, application.SearchBusTicketsResult]
+	EventBus          *testkit.RecordingEventBus[pkgDomain.Event[string], string]
+	CancelledEventBus *testkit.RecordingEventBus[pkgDomain.Event[application.BusTicketCancelledData], application.BusTicketCancelledData]
+	TripBuses         TripBuses
+	SeatHoldBuses     SeatHoldBuses
+	Logger            *testkit.Logger
+
+	tb testing.TB
+}
+
+// NewRepositories builds the repositories of the slice on clock.
+type NewRepositories func(clock pkgDomain.Clock, logger pkgApp.AppLogger) busticket.Repositories
+
+type HarnessOption func(*harnessOptions)
+
+type harnessOptions struct {
+	newRepositories NewRepositories
+	sliceOptions    []busticket.SliceOption
+}
+
+// WithRepositories runs the slice on the repositories of newRepositories
+// instead of in-memory ones.
+func WithRepositories(newRepositories NewRepositories) HarnessOption {
+	return func(o *harnessOptions) {
+		o.newRepositories = newRepositories
+	}
+}
+
+// WithSliceOptions adds options to the slice, which always reads the time
+// from the harness Clock.
+func WithSliceOptions(options ...busticket.SliceOption) HarnessOption {
+	return func(o *harnessOptions) {
+		o.sliceOptions = append(o.sliceOptions, options...)
+	}
+}
+
+// InMemoryRepositories builds the in-memory repositories of the slice.
+func InMemoryRepositories(clock pkgDomain.Clock, logger pkgApp.AppLogger) busticket.Repositories {
+	busTickets := infrastructure.NewInMemoryBusTicketRepository(logger)
+	return busticket.Repositories{
+		BusTickets: busTickets,
+		Routes:     infrastructure.NewInMemoryRouteRepository(logger),
+		Trips:      infrastructure.NewInMemoryTripRepository(logger),
+		SeatHolds:  infrastructure.NewInMemorySeatHoldRepository(busTickets, clock, logger),
+	}
+}
+
+// NewHarness runs a BusTicketSlice on in-memory infrastructure, or on the
+// repositories of WithRepositories, behind an httptest server; identity and tenant are taken from request headers, and
+// handlers read the time from Clock, which starts at the current time.
+func NewHarness(tb testing.TB, options ...HarnessOption) *Harness {
+	tb.Helper()
+
+	o := harnessOptions{newRepositories: InMemoryRepositories}
+	for _, option := range options {
+		option(&o)
+	}
+
+	logger := testkit.NewLogger(nil)
+	clock := testkit.NewManualClock(time.Now().UTC())
+	repositories := o.newRepositories(clock.Clock(), logger)
+	h := &Harness{
+		Repository:        repositories.BusTickets,
+		Routes:            repositories.Routes,
+		Trips:             repositories.Trips,
+		SeatHolds:         repositories.SeatHolds,
+		Clock:             clock,
+		CommandBus:        testkit.NewRecordingCommandBus[pkgDomain.Command[application.ReserveBusTicketData], application.ReserveBusTicketData](),
+		CancelBus:         testkit.NewRecordingCommandBus[pkgDomain.Command[application.CancelBusTicketData], application.CancelBusTicketData](),
+		GetBus:            testkit.NewRecordingQueryBus[pkgDomain.Query[application.GetBusTicketByIDData], application.GetBusTicketByIDData, domain.BusTicket](),
+		SearchBus:         testkit.NewRecordingQueryBus[pkgDomain.Query[application.SearchBusTicketsData], application.SearchBusTicketsData, application.SearchBusTicketsResult](),
+		EventBus:          testkit.NewRecordingEventBus[pkgDomain.Event[string], string](),
+		CancelledEventBus: testkit.NewRecordingEventBus[pkgDomain.Event[application.BusTicketCancelledData], application.BusTicketCancelledData](),
+		TripBuses: TripBuses{
+			CreateRoute:  testkit.NewRecordingCommandBus[pkgDomain.Command[application.CreateRouteData], application.CreateRouteData](),
+			CreateTrip:   testkit.NewRecordingCommandBus[pkgDomain.Command[application.CreateTripData], application.CreateTripData](),
+			CancelTrip:   testkit.NewRecordingCommandBus[pkgDomain.Command[application.CancelTripData], application.CancelTripData](),
+			DelayTrip:    testkit.NewRecordingCommandBus[pkgDomain.Command[application.DelayTripData], application.DelayTripData](),
+			GetRouteByID: testkit.NewRecordingQueryBus[pkgDomain.Query[application.GetRouteByIDData], application.GetRouteByIDData, application.RouteDetails](),
+			GetTripByID:  testkit.NewRecordingQueryBus[pkgDomain.Query[application.GetTripByIDData], application.GetTripByIDData, application.TripDetails](),
+		},
+		SeatHoldBuses: SeatHoldBuses{
+			HoldSeat:           testkit.NewRecordingCommandBus[pkgDomain.Command[application.HoldSeatData], application.HoldSeatData](),
+			ConfirmReservation: testkit.NewRecordingCommandBus[pkgDomain.Command[application.ConfirmReservationData], application.ConfirmReservationData](),
+			ReleaseExpired:     testkit.NewRecordingCommandBus[pkgDomain.Command[application.ReleaseExpiredSeatHoldsData], application.ReleaseExpiredSeatHoldsData](),
+			Expired:            testkit.NewRecordingEventBus[pkgDomain.Event[application.SeatHoldExpiredData], application.SeatHoldExpiredData](),
+		},
+		Logger: logger,
+		tb:     tb,
+	}
+
+	buses := busticket.Buses{
+		ReserveBusTicket:        h.CommandBus,
+		CancelBusTicket:         h.CancelBus,
+		GetBusTicketByID:        h.GetBus,
+		SearchBusTickets:        h.SearchBus,
+		BusTicketBooked:         h.EventBus,
+		BusTicketCancelled:      h.CancelledEventBus,
+		CreateRoute:             h.TripBuses.CreateRoute,
+		CreateTrip:              h.TripBuses.CreateTrip,
+		CancelTrip:              h.TripBuses.CancelTrip,
+		DelayTrip:               h.TripBuses.DelayTrip,
+		GetRouteByID:            h.TripBuses.GetRouteByID,
+		GetTripByID:             h.TripBuses.GetTripByID,
+		HoldSeat:                h.SeatHoldBuses.HoldSeat,
+		ConfirmReservation:      h.SeatHoldBuses.ConfirmReservation,
+		ReleaseExpiredSeatHolds: h.SeatHoldBuses.ReleaseExpired,
+		SeatHoldExpired:         h.SeatHoldBuses.Expired,
+	}
+	sliceOptions := append([]busticket.SliceOption{busticket.WithClock(h.Clock.Clock())}, o.sliceOptions...)
+	slice, err := busticket.NewBusTicketSlice(buses, testkit.SequentialIDs("busticket"), logger, repositories, sliceOptions...)
+	if err != nil {
+		tb.Fatalf("NewBusTicketSlice() error = %v", err)
+	}
+
+	router := chi.NewRouter()
+	router.Use(chiAdapter.PrincipalFromHeaders)
+	router.Use(chiAdapter.ResolveTenant([]string{application.RoleAdmin}, chiAdapter.TenantFromPrincipal(), chiAdapter.TenantFromHeader(chiAdapter.TenantIDHeader)))
+	slice.RegisterRoutes(router)
+
+	h.Server = httptest.NewServer(router)
+	tb.Cleanup(h.Server.Close)
+
+	return h
+}
+
+type RequestOption func(r *http.Request)
+
+func AsPrincipal(principal pkgApp.Principal) RequestOption {
+	return func(r *http.Request) {
+		r.Header.Set(chiAdapter.PrincipalIDHeader, principal.ID)
+		r.Header.Set(chiAdapter.PrincipalRolesHeader, strings.Join(principal.Roles, ","))
+		for key, value := range principal.Attributes {
+			r.Header.Set(chiAdapter.PrincipalAttributeHeaderPrefix+strings.ReplaceAll(key, "_", "-"), value)
+		}
+	}
+}
+
+func AsPassenger(passengerName string) RequestOption {
+	return AsPrincipal(pkgApp.Principal{
+		ID:         passengerName,
+		Roles:      []string{application.RolePassenger},
+		Attributes: map[string]string{application.PassengerNameAttribute: passengerName},
+	})
+}
+
+func ForTenant(tenantID string) RequestOption {
+	return func(r *http.Request) {
+		r.Header.Set(chiAdapter.TenantIDHeader, tenantID)
+	}
+}
+
+// Do sends body as JSON and returns the response with its body already read.
+func (h *Harness) Do(method, path string, body interface{}, options ...RequestOption) (*http.Response, []byte) {
+	h.tb.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			h.tb.Fatalf("marshalling request body: %v", err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequest(method, h.Server.URL+path, reader)
+	if err != nil {
+		h.tb.Fatalf("building request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for _, option := range options {
+		option(req)
+	}
+
+	resp, err := h.Server.Client().Do(req)
+	if err != nil {
+		h.tb.Fatalf("sending request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		h.tb.Fatalf("reading response body: %v", err)
+	}
+	return resp, responseBody
+}
+
+// ReleaseExpiredSeatHolds dispatches what serve dispatches periodically,
+// releasing the holds expired at Clock's time.
+func (h *Harness) ReleaseExpiredSeatHolds() {
+	h.tb.Helper()
+	if err := h.SeatHoldBuses.ReleaseExpired.Dispatch(context.Background(), application.NewReleaseExpiredSeatHoldsCommand()); err != nil {
+		h.tb.Fatalf("releasing expired seat holds: %v", err)
+	}
+}
+
+func (h *Harness) ReserveBusTicket(data application.ReserveBusTicketData, options ...RequestOption) (*http.Response, []byte) {
+	h.tb.Helper()
+	return h.Do(http.MethodPost, "/bustickets", data, options...)
+}
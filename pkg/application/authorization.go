@@ -0,0 +1,156 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+var ErrForbidden = errors.New("forbidden")
+
+type ForbiddenError struct {
+	Name        string
+	PrincipalID string
+	Reason      string
+}
+
+func (e *ForbiddenError) Error() string {
+	if e.PrincipalID == "" {
+		return fmt.Sprintf("forbidden: %s: %s", e.Name, e.Reason)
+	}
+	return fmt.Sprintf("forbidden: principal %s cannot %s: %s", e.PrincipalID, e.Name, e.Reason)
+}
+
+func (e *ForbiddenError) Is(target error) bool {
+	return target == ErrForbidden
+}
+
+type Policy[T any] interface {
+	Evaluate(ctx context.Context, principal Principal, payload T) error
+}
+
+type PolicyFunc[T any] func(ctx context.Context, principal Principal, payload T) error
+
+func (f PolicyFunc[T]) Evaluate(ctx context.Context, principal Principal, payload T) error {
+	return f(ctx, principal, payload)
+}
+
+func Deny(reason string) error {
+	return &ForbiddenError{Reason: reason}
+}
+
+func AllowAll[T any]() Policy[T] {
+	return PolicyFunc[T](func(context.Context, Principal, T) error {
+		return nil
+	})
+}
+
+func RequireAnyRole[T any](roles ...string) Policy[T] {
+	return PolicyFunc[T](func(_ context.Context, principal Principal, _ T) error {
+		for _, role := range roles {
+			if principal.HasRole(role) {
+				return nil
+			}
+		}
+		return Deny(fmt.Sprintf("requires one of roles %v", roles))
+	})
+}
+
+func RequireAttribute[T any](key string, value func(payload T) string) Policy[T] {
+	return PolicyFunc[T](func(_ context.Context, principal Principal, payload T) error {
+		actual, expected := principal.Attribute(key), value(payload)
+		if actual == "" || actual != expected {
+			return Deny(fmt.Sprintf("attribute %s does not match", key))
+		}
+		return nil
+	})
+}
+
+func AllOf[T any](policies ...Policy[T]) Policy[T] {
+	return PolicyFunc[T](func(ctx context.Context, principal Principal, payload T) error {
+		for _, policy := range policies {
+			if err := policy.Evaluate(ctx, principal, payload); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func AnyOf[T any](policies ...Policy[T]) Policy[T] {
+	return PolicyFunc[T](func(ctx context.Context, principal Principal, payload T) error {
+		reasons := make([]string, 0, len(policies))
+		for _, policy := range policies {
+			err := policy.Evaluate(ctx, principal, payload)
+			if err == nil {
+				return nil
+			}
+			reasons = append(reasons, denyReason(err))
+		}
+		if len(reasons) == 0 {
+			return Deny("no policy allowed the request")
+		}
+		return Deny(strings.Join(reasons, "; "))
+	})
+}
+
+type Authorizer[T any] struct {
+	policies  map[string][]Policy[T]
+	anonymous map[string]bool
+	mu        sync.RWMutex
+}
+
+func NewAuthorizer[T any]() *Authorizer[T] {
+	return &Authorizer[T]{
+		policies:  make(map[string][]Policy[T]),
+		anonymous: make(map[string]bool),
+	}
+}
+
+func (a *Authorizer[T]) Register(name string, policies ...Policy[T]) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.policies[name] = append(a.policies[name], policies...)
+}
+
+func (a *Authorizer[T]) AllowAnonymous(name string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.anonymous[name] = true
+}
+
+func (a *Authorizer[T]) Authorize(ctx context.Context, name string, payload T) error {
+	a.mu.RLock()
+	policies, found := a.policies[name]
+	anonymous := a.anonymous[name]
+	a.mu.RUnlock()
+
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		if anonymous {
+			return nil
+		}
+		return &ForbiddenError{Name: name, Reason: "no principal in context"}
+	}
+
+	if !found {
+		return &ForbiddenError{Name: name, PrincipalID: principal.ID, Reason: "no policy registered"}
+	}
+
+	for _, policy := range policies {
+		if err := policy.Evaluate(ctx, principal, payload); err != nil {
+			return &ForbiddenError{Name: name, PrincipalID: principal.ID, Reason: denyReason(err)}
+		}
+	}
+	return nil
+}
+
+func denyReason(err error) string {
+	var forbidden *ForbiddenError
+	if errors.As(err, &forbidden) {
+		return forbidden.Reason
+	}
+	return err.Error()
+}
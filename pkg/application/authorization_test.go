@@ -0,0 +1,117 @@
+package application_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+)
+
+type ticket struct {
+	Passenger string
+}
+
+func passengerOf(payload ticket) string {
+	return payload.Passenger
+}
+
+func TestPolicies(t *testing.T) {
+	admin := application.Principal{ID: "root", Roles: []string{"admin"}}
+	ana := application.Principal{ID: "ana", Roles: []string{"passenger"}, Attributes: map[string]string{"passenger_name": "Ana"}}
+	nameless := application.Principal{ID: "bot", Roles: []string{"passenger"}}
+
+	isAdmin := application.RequireAnyRole[ticket]("admin")
+	ownsTicket := application.RequireAttribute("passenger_name", passengerOf)
+	denied := application.PolicyFunc[ticket](func(context.Context, application.Principal, ticket) error {
+		return errors.New("closed for maintenance")
+	})
+
+	tests := []struct {
+		name      string
+		policy    application.Policy[ticket]
+		principal application.Principal
+		payload   ticket
+		reason    string
+	}{
+		{name: "any role with the role", policy: application.RequireAnyRole[ticket]("agent", "admin"), principal: admin},
+		{name: "any role without it", policy: application.RequireAnyRole[ticket]("agent", "admin"), principal: ana, reason: "requires one of roles [agent admin]"},
+		{name: "any role of none", policy: application.RequireAnyRole[ticket](), principal: admin, reason: "requires one of roles []"},
+		{name: "matching attribute", policy: ownsTicket, principal: ana, payload: ticket{Passenger: "Ana"}},
+		{name: "other attribute", policy: ownsTicket, principal: ana, payload: ticket{Passenger: "Bia"}, reason: "attribute passenger_name does not match"},
+		{name: "missing attribute", policy: ownsTicket, principal: nameless, payload: ticket{}, reason: "attribute passenger_name does not match"},
+		{name: "all of when every policy allows", policy: application.AllOf(application.RequireAnyRole[ticket]("passenger"), ownsTicket), principal: ana, payload: ticket{Passenger: "Ana"}},
+		{name: "all of stops at the first denial", policy: application.AllOf(ownsTicket, isAdmin), principal: ana, payload: ticket{Passenger: "Bia"}, reason: "attribute passenger_name does not match"},
+		{name: "all of nothing", policy: application.AllOf[ticket](), principal: ana},
+		{name: "any of when one policy allows", policy: application.AnyOf(isAdmin, ownsTicket), principal: ana, payload: ticket{Passenger: "Ana"}},
+		{
+			name:      "any of joins every reason",
+			policy:    application.AnyOf(isAdmin, ownsTicket, denied),
+			principal: ana,
+			payload:   ticket{Passenger: "Bia"},
+			reason:    "requires one of roles [admin]; attribute passenger_name does not match; closed for maintenance",
+		},
+		{name: "any of nothing", policy: application.AnyOf[ticket](), principal: admin, reason: "no policy allowed the request"},
+		{name: "allow all", policy: application.AllowAll[ticket](), principal: nameless},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Evaluate(context.Background(), tt.principal, tt.payload)
+			if tt.reason == "" {
+				if err != nil {
+					t.Fatalf("Evaluate() error = %v", err)
+				}
+				return
+			}
+			var forbidden *application.ForbiddenError
+			if !errors.As(err, &forbidden) || forbidden.Reason != tt.reason {
+				t.Fatalf("Evaluate() error = %v, want a denial because %q", err, tt.reason)
+			}
+		})
+	}
+}
+
+func TestAuthorizer(t *testing.T) {
+	authorizer := application.NewAuthorizer[ticket]()
+	authorizer.Register("Cancel", application.RequireAnyRole[ticket]("passenger", "admin"))
+	authorizer.Register("Cancel", application.AnyOf(application.RequireAnyRole[ticket]("admin"), application.RequireAttribute("passenger_name", passengerOf)))
+	authorizer.Register("ListTrips", application.AllowAll[ticket]())
+	authorizer.AllowAnonymous("ListTrips")
+
+	ana := application.Principal{ID: "ana", Roles: []string{"passenger"}, Attributes: map[string]string{"passenger_name": "Ana"}}
+	as := func(principal application.Principal) context.Context {
+		return application.WithPrincipal(context.Background(), principal)
+	}
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		message string
+		payload ticket
+		reason  string
+	}{
+		{name: "every registered policy allows", ctx: as(ana), message: "Cancel", payload: ticket{Passenger: "Ana"}},
+		{name: "a later policy denies", ctx: as(ana), message: "Cancel", payload: ticket{Passenger: "Bia"}, reason: "principal ana cannot Cancel"},
+		{name: "an earlier policy denies", ctx: as(application.Principal{ID: "bob", Roles: []string{"agent"}}), message: "Cancel", reason: "requires one of roles [passenger admin]"},
+		{name: "no principal", ctx: context.Background(), message: "Cancel", payload: ticket{Passenger: "Ana"}, reason: "no principal in context"},
+		{name: "unknown message", ctx: as(ana), message: "Refund", reason: "principal ana cannot Refund: no policy registered"},
+		{name: "unknown message without a principal", ctx: context.Background(), message: "Refund", reason: "no principal in context"},
+		{name: "anonymous message", ctx: context.Background(), message: "ListTrips"},
+		{name: "anonymous message with a principal", ctx: as(ana), message: "ListTrips"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := authorizer.Authorize(tt.ctx, tt.message, tt.payload)
+			if tt.reason == "" {
+				if err != nil {
+					t.Fatalf("Authorize() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, application.ErrForbidden) || !strings.Contains(err.Error(), tt.reason) {
+				t.Fatalf("Authorize() error = %v, want %v because %q", err, application.ErrForbidden, tt.reason)
+			}
+		})
+	}
+}
@@ -11,6 +11,6 @@ type CommandHandler[C domain.Command[T], T any] interface {
 }
 
 type CommandBus[C domain.Command[T], T any] interface {
-	RegisterHandler(commandName string, handler CommandHandler[C, T])
+	RegisterHandler(commandName string, handler CommandHandler[C, T]) error
 	Dispatch(ctx context.Context, command C) error
 }
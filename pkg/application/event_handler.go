@@ -11,6 +11,6 @@ type EventHandler[E domain.Event[T], T any] interface {
 }
 
 type EventBus[E domain.Event[D], D any] interface {
-	RegisterHandler(eventName string, handler EventHandler[E, D])
+	RegisterHandler(eventName string, handler EventHandler[E, D]) error
 	Publish(ctx context.Context, event E) error
 }
@@ -0,0 +1,15 @@
+package application
+
+import "context"
+
+// HealthCheck probes one dependency; Check returns nil while it is usable.
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// Pinger is implemented by repositories and clients that can verify their
+// connection.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
@@ -11,6 +11,6 @@ type QueryHandler[Q domain.Query[T], T any, R any] interface {
 }
 
 type QueryBus[Q domain.Query[D], D any, R any] interface {
-	RegisterHandler(queryName string, handler QueryHandler[Q, D, R])
+	RegisterHandler(queryName string, handler QueryHandler[Q, D, R]) error
 	Dispatch(ctx context.Context, query Q) (R, error)
 }
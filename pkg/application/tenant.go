@@ -0,0 +1,46 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+const TenantAttribute = "tenant"
+
+var (
+	ErrTenantMismatch   = errors.New("tenant does not match principal")
+	ErrTenantNotAllowed = errors.New("principal cannot choose a tenant")
+	ErrInvalidTenant    = errors.New("invalid tenant")
+)
+
+// tenantIDPattern keeps tenant IDs safe to embed in keys and topic names,
+// whose separators they must not contain.
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9-]{1,64}$`)
+
+// ValidateTenantID returns ErrInvalidTenant unless tenantID is 1 to 64
+// lowercase letters, digits or hyphens.
+func ValidateTenantID(tenantID string) error {
+	if !tenantIDPattern.MatchString(tenantID) {
+		return fmt.Errorf("%w %q", ErrInvalidTenant, tenantID)
+	}
+	return nil
+}
+
+type tenantContextKey struct{}
+
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenantID)
+}
+
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenantID, ok := ctx.Value(tenantContextKey{}).(string)
+	return tenantID, ok && tenantID != ""
+}
+
+// TenantID returns the tenant in ctx, or the empty tenant for single-tenant callers.
+func TenantID(ctx context.Context) string {
+	tenantID, _ := TenantFromContext(ctx)
+	return tenantID
+}
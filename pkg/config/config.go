@@ -0,0 +1,471 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Secret holds a credential. It prints masked so that it never ends up in
+// logs or config dumps by accident; use Value to read it.
+type Secret string
+
+const secretMask = "******"
+
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return secretMask
+}
+
+func (s Secret) Value() string {
+	return string(s)
+}
+
+var (
+	Transports   = []string{"memory", "channels", "kafka", "redis", "nats", "sql", "bolt", "grpc", "routed"}
+	Repositories = []string{"postgres", "sqlite", "redis", "memory"}
+	Roles        = []string{"all", "api"}
+	Bridges      = []string{"kafka", "redis", "nats"}
+)
+
+type Config struct {
+	Transport  string `config:"transport" usage:"message transport: memory, channels, kafka, redis, nats, sql, bolt, grpc or routed"`
+	Repository string `config:"repository" usage:"bus ticket repository: postgres, sqlite, redis or memory"`
+	Role       string `config:"role" usage:"role of serve: all also handles messages, api only dispatches them"`
+
+	HTTP         HTTPConfig         `config:"http"`
+	Health       HealthConfig       `config:"health"`
+	Database     DatabaseConfig     `config:"database"`
+	Auth         AuthConfig         `config:"auth"`
+	Kafka        KafkaConfig        `config:"kafka"`
+	Redis        RedisConfig        `config:"redis"`
+	NATS         NATSConfig         `config:"nats"`
+	SQL          SQLConfig          `config:"sql"`
+	Bolt         BoltConfig         `config:"bolt"`
+	GRPC         GRPCConfig         `config:"grpc"`
+	Routing      RoutingConfig      `config:"routing"`
+	Commands     CommandsConfig     `config:"commands"`
+	Cancellation CancellationConfig `config:"cancellation"`
+	SeatHolds    SeatHoldsConfig    `config:"seat_holds"`
+	Bridge       BridgeConfig       `config:"bridge"`
+}
+
+type HTTPConfig struct {
+	Address         string        `config:"address" usage:"HTTP listen address"`
+	ShutdownTimeout time.Duration `config:"shutdown_timeout" usage:"time allowed for in-flight requests on shutdown"`
+}
+
+// HealthConfig configures readiness checks. Workers, which serve no API,
+// expose /healthz and /readyz on Address.
+type HealthConfig struct {
+	Address string        `config:"address" usage:"health endpoint listen address of the worker"`
+	Timeout time.Duration `config:"timeout" usage:"time allowed for the readiness checks"`
+}
+
+// DatabaseConfig describes the Postgres connection. DSN, when set, is used
+// as is and the individual fields are ignored. SQLitePath is used by the
+// sqlite repository instead.
+type DatabaseConfig struct {
+	DSN        Secret `config:"dsn" usage:"full Postgres connection string"`
+	Host       string `config:"host" usage:"Postgres host"`
+	Port       int    `config:"port" usage:"Postgres port"`
+	User       string `config:"user" usage:"Postgres user"`
+	Password   Secret `config:"password" usage:"Postgres password"`
+	Name       string `config:"name" usage:"Postgres database name"`
+	SSLMode    string `config:"ssl_mode" usage:"Postgres sslmode"`
+	TimeZone   string `config:"time_zone" usage:"Postgres session time zone"`
+	SQLitePath string `config:"sqlite_path" usage:"SQLite database file of the sqlite repository, or :memory:"`
+	// AutoMigrate applies pending migrations on startup. It is meant for
+	// local development; deployments run "bff migrate up" once instead.
+	AutoMigrate bool `config:"auto_migrate" usage:"apply pending migrations when serve or worker starts"`
+}
+
+func (c DatabaseConfig) ConnectionString() string {
+	if c.DSN != "" {
+		return c.DSN.Value()
+	}
+	params := []string{
+		"host=" + quoteParam(c.Host),
+		"user=" + quoteParam(c.User),
+	}
+	if c.Password != "" {
+		params = append(params, "password="+quoteParam(c.Password.Value()))
+	}
+	params = append(params,
+		"dbname="+quoteParam(c.Name),
+		fmt.Sprintf("port=%d", c.Port),
+		"sslmode="+quoteParam(c.SSLMode),
+		"TimeZone="+quoteParam(c.TimeZone),
+	)
+	return strings.Join(params, " ")
+}
+
+func quoteParam(value string) string {
+	if value != "" && !strings.ContainsAny(value, ` '\`) {
+		return value
+	}
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
+
+type AuthConfig struct {
+	JWKSURL     string        `config:"jwks_url" usage:"URL of the JWKS used to verify tokens"`
+	JWKSFile    string        `config:"jwks_file" usage:"file with the JWKS used to verify tokens"`
+	JWKSRefresh time.Duration `config:"jwks_refresh" usage:"JWKS refresh interval"`
+	Issuer      string        `config:"issuer" usage:"expected token issuer"`
+	Audience    string        `config:"audience" usage:"expected token audience"`
+	// TrustHeaders takes the principal from the X-Principal-* request
+	// headers, as sent by the client. It is meant for local development only.
+	TrustHeaders bool `config:"trust_headers" usage:"DEVELOPMENT ONLY: trust client-sent X-Principal-* headers instead of verifying tokens"`
+}
+
+type KafkaConfig struct {
+	Brokers       []string `config:"brokers" usage:"comma separated Kafka brokers"`
+	ConsumerGroup string   `config:"consumer_group" usage:"Kafka consumer group"`
+	ClientID      string   `config:"client_id" usage:"Kafka client ID"`
+}
+
+type RedisConfig struct {
+	Address       string `config:"address" usage:"Redis address"`
+	Password      Secret `config:"password" usage:"Redis password"`
+	DB            int    `config:"db" usage:"Redis database number"`
+	ConsumerGroup string `config:"consumer_group" usage:"Redis streams consumer group"`
+	Consumer      string `config:"consumer" usage:"Redis streams consumer name"`
+	// TicketRetention is how long after departure the redis repository keeps
+	// a ticket before it expires.
+	TicketRetention time.Duration `config:"ticket_retention" usage:"time the redis repository keeps tickets after departure, 0 keeps them forever"`
+}
+
+type NATSConfig struct {
+	URL        string        `config:"url" usage:"NATS server URL"`
+	QueueGroup string        `config:"queue_group" usage:"NATS queue group prefix"`
+	AckWait    time.Duration `config:"ack_wait" usage:"JetStream ack wait"`
+	NakDelay   time.Duration `config:"nak_delay" usage:"JetStream redelivery delay"`
+	MaxDeliver int           `config:"max_deliver" usage:"JetStream max deliveries"`
+}
+
+type SQLConfig struct {
+	ConsumerGroup string        `config:"consumer_group" usage:"SQL transport consumer group"`
+	PollInterval  time.Duration `config:"poll_interval" usage:"SQL transport poll interval"`
+	ClaimTimeout  time.Duration `config:"claim_timeout" usage:"how long a consumer may keep a message before others of its group redeliver it"`
+}
+
+type BoltConfig struct {
+	Path            string        `config:"path" usage:"bbolt database file"`
+	ConsumerGroup   string        `config:"consumer_group" usage:"bbolt consumer group"`
+	SyncInterval    time.Duration `config:"sync_interval" usage:"bbolt fsync interval, 0 syncs every write"`
+	Retention       time.Duration `config:"retention" usage:"bbolt message retention, 0 keeps messages forever"`
+	CompactInterval time.Duration `config:"compact_interval" usage:"bbolt compaction interval, 0 disables compaction"`
+}
+
+// GRPCConfig connects the BFF to the worker. The worker trusts the principal
+// and tenant of every call, so it only accepts callers that present Token or
+// a client certificate signed by TLSCA. Both processes read the same keys:
+// the worker serves TLSCert and the BFF presents it for mutual TLS.
+type GRPCConfig struct {
+	Address       string `config:"address" usage:"gRPC listen address of the worker"`
+	Target        string `config:"target" usage:"gRPC target the BFF dials"`
+	Token         Secret `config:"token" usage:"shared secret the BFF sends and the worker requires on every call"`
+	TLSCert       string `config:"tls_cert" usage:"PEM certificate the worker serves, or the BFF presents for mutual TLS"`
+	TLSKey        string `config:"tls_key" usage:"PEM key of grpc.tls_cert"`
+	TLSCA         string `config:"tls_ca" usage:"PEM CA the BFF verifies the worker with, and the worker requires client certificates from"`
+	TLSServerName string `config:"tls_server_name" usage:"name the BFF expects in the worker certificate"`
+}
+
+// TLS reports whether the gRPC connection uses TLS.
+func (c GRPCConfig) TLS() bool {
+	return c.TLSCert != "" || c.TLSCA != ""
+}
+
+type RoutingConfig struct {
+	TableFile string `config:"table_file" usage:"JSON routing table file"`
+}
+
+// CommandsConfig bounds the retries of commands that fail on a concurrent
+// update; ConflictRetries 0 disables them.
+type CommandsConfig struct {
+	ConflictRetries int           `config:"conflict_retries" usage:"retries of a command after a concurrency conflict"`
+	ConflictBackoff time.Duration `config:"conflict_backoff" usage:"wait before the first conflict retry, doubled before each later one"`
+}
+
+// CancellationConfig is the refund policy of cancelled tickets: a full
+// refund with at least FullRefundNotice before departure and, on shorter
+// notice, the price less the fee of the tier with the longest notice still
+// given. Each fee tier is written "<notice>:<fee percent>", as in "6h:25",
+// and the tiers are listed from the longest notice to the shortest.
+type CancellationConfig struct {
+	FullRefundNotice time.Duration `config:"full_refund_notice" usage:"least notice before departure refunded in full"`
+	FeeTiers         []string      `config:"fee_tiers" usage:"comma separated <notice>:<fee percent> tiers charged on shorter notice"`
+}
+
+// SeatHoldsConfig sets how long a seat stays held before its reservation is
+// confirmed and how often expired holds are released.
+type SeatHoldsConfig struct {
+	TTL             time.Duration `config:"ttl" usage:"time a held seat waits for its reservation to be confirmed"`
+	ReleaseInterval time.Duration `config:"release_interval" usage:"interval between releases of expired seat holds"`
+}
+
+// BridgeConfig connects the in-process buses of the memory transport to
+// Broker: the Outbound events published in process are forwarded to it and
+// the Inbound events other replicas forward are published in process. Each
+// replica needs a consumer group of its own on Broker to receive every
+// event. The events of TenantTopics go to topics prefixed with their tenant.
+type BridgeConfig struct {
+	Broker       string   `config:"broker" usage:"broker the memory transport bridges events through: kafka, redis or nats, empty disables the bridge"`
+	Outbound     []string `config:"outbound" usage:"comma separated events forwarded to the broker"`
+	Inbound      []string `config:"inbound" usage:"comma separated events received from the broker"`
+	TenantTopics []string `config:"tenant_topics" usage:"comma separated tenants whose bridged events use tenant-prefixed topics"`
+}
+
+type FeeTier struct {
+	Notice     time.Duration
+	FeePercent int
+}
+
+func (c CancellationConfig) ParseFeeTiers() ([]FeeTier, error) {
+	tiers := make([]FeeTier, 0, len(c.FeeTiers))
+	for _, raw := range c.FeeTiers {
+		notice, percent, found := strings.Cut(strings.TrimSpace(raw), ":")
+		if !found {
+			return nil, fmt.Errorf("fee tier %q is not <notice>:<fee percent>", raw)
+		}
+		var (
+			tier FeeTier
+			err  error
+		)
+		if tier.Notice, err = time.ParseDuration(notice); err != nil || tier.Notice < 0 {
+			return nil, fmt.Errorf("fee tier %q has an invalid notice", raw)
+		}
+		if tier.FeePercent, err = strconv.Atoi(percent); err != nil || tier.FeePercent < 0 || tier.FeePercent > 100 {
+			return nil, fmt.Errorf("fee tier %q needs a fee percent from 0 to 100", raw)
+		}
+		if tier.Notice >= c.FullRefundNotice {
+			return nil, fmt.Errorf("fee tier %q has no less notice than the full refund", raw)
+		}
+		if len(tiers) > 0 && tier.Notice >= tiers[len(tiers)-1].Notice {
+			return nil, fmt.Errorf("fee tier %q has no less notice than the tier before it", raw)
+		}
+		tiers = append(tiers, tier)
+	}
+	return tiers, nil
+}
+
+func Default() *Config {
+	return &Config{
+		Transport:  "memory",
+		Repository: "postgres",
+		Role:       "all",
+		HTTP: HTTPConfig{
+			Address:         ":8080",
+			ShutdownTimeout: 5 * time.Second,
+		},
+		Health: HealthConfig{
+			Address: ":8081",
+			Timeout: 2 * time.Second,
+		},
+		Database: DatabaseConfig{
+			Host:       "localhost",
+			Port:       5432,
+			User:       "myuser",
+			Name:       "mydb",
+			SSLMode:    "disable",
+			TimeZone:   "UTC",
+			SQLitePath: "bff.sqlite",
+		},
+		Auth: AuthConfig{
+			JWKSRefresh: time.Hour,
+		},
+		Kafka: KafkaConfig{
+			Brokers:       []string{"localhost:9092"},
+			ConsumerGroup: "example_consumer_group",
+			ClientID:      "watermill",
+		},
+		Redis: RedisConfig{
+			Address:         "localhost:6379",
+			ConsumerGroup:   "my_group",
+			Consumer:        "my_consumer",
+			TicketRetention: 30 * 24 * time.Hour,
+		},
+		NATS: NATSConfig{
+			URL:        "nats://127.0.0.1:4222",
+			QueueGroup: "bff",
+			AckWait:    30 * time.Second,
+			NakDelay:   time.Second,
+			MaxDeliver: 5,
+		},
+		SQL: SQLConfig{
+			ConsumerGroup: "bff",
+			PollInterval:  time.Second,
+			ClaimTimeout:  30 * time.Second,
+		},
+		Bolt: BoltConfig{
+			Path:            "bff.db",
+			ConsumerGroup:   "bff",
+			SyncInterval:    100 * time.Millisecond,
+			Retention:       24 * time.Hour,
+			CompactInterval: 6 * time.Hour,
+		},
+		GRPC: GRPCConfig{
+			Address: ":9090",
+			Target:  "localhost:9090",
+		},
+		Commands: CommandsConfig{
+			ConflictRetries: 3,
+			ConflictBackoff: 10 * time.Millisecond,
+		},
+		Cancellation: CancellationConfig{
+			FullRefundNotice: 24 * time.Hour,
+			FeeTiers:         []string{"6h:25", "0s:50"},
+		},
+		SeatHolds: SeatHoldsConfig{
+			TTL:             10 * time.Minute,
+			ReleaseInterval: 30 * time.Second,
+		},
+	}
+}
+
+func (c *Config) Validate() error {
+	var errs []error
+	require := func(key, value string) {
+		if strings.TrimSpace(value) == "" {
+			errs = append(errs, fmt.Errorf("%s is required", key))
+		}
+	}
+	positive := func(key string, value time.Duration) {
+		if value <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive", key))
+		}
+	}
+	notNegative := func(key string, value time.Duration) {
+		if value < 0 {
+			errs = append(errs, fmt.Errorf("%s must not be negative", key))
+		}
+	}
+
+	oneOf := func(key, value string, allowed []string) {
+		for _, candidate := range allowed {
+			if value == candidate {
+				return
+			}
+		}
+		errs = append(errs, fmt.Errorf("%s %q must be one of %s", key, value, strings.Join(allowed, ", ")))
+	}
+
+	oneOf("transport", c.Transport, Transports)
+	oneOf("repository", c.Repository, Repositories)
+	oneOf("role", c.Role, Roles)
+	if c.Role == "api" && (c.Transport == "memory" || c.Transport == "channels") {
+		errs = append(errs, fmt.Errorf("role api needs a transport that reaches a worker, %q stays in process", c.Transport))
+	}
+
+	require("http.address", c.HTTP.Address)
+	positive("http.shutdown_timeout", c.HTTP.ShutdownTimeout)
+
+	require("health.address", c.Health.Address)
+	positive("health.timeout", c.Health.Timeout)
+
+	if c.Repository == "sqlite" {
+		require("database.sqlite_path", c.Database.SQLitePath)
+	}
+	if c.Transport == "sql" && c.Repository != "postgres" && c.Repository != "sqlite" {
+		errs = append(errs, fmt.Errorf("transport sql shares the database of the repository, which must be postgres or sqlite, not %q", c.Repository))
+	}
+	if c.Repository == "postgres" && c.Database.DSN == "" {
+		require("database.host", c.Database.Host)
+		require("database.user", c.Database.User)
+		require("database.name", c.Database.Name)
+		if c.Database.Port <= 0 || c.Database.Port > 65535 {
+			errs = append(errs, fmt.Errorf("database.port %d is out of range", c.Database.Port))
+		}
+	}
+
+	if c.Auth.JWKSURL != "" && c.Auth.JWKSFile != "" {
+		errs = append(errs, errors.New("auth.jwks_url and auth.jwks_file are mutually exclusive"))
+	}
+	if c.Auth.TrustHeaders && (c.Auth.JWKSURL != "" || c.Auth.JWKSFile != "") {
+		errs = append(errs, errors.New("auth.trust_headers cannot be combined with a JWKS"))
+	}
+	positive("auth.jwks_refresh", c.Auth.JWKSRefresh)
+
+	if c.Commands.ConflictRetries < 0 {
+		errs = append(errs, errors.New("commands.conflict_retries must not be negative"))
+	}
+	notNegative("commands.conflict_backoff", c.Commands.ConflictBackoff)
+	notNegative("cancellation.full_refund_notice", c.Cancellation.FullRefundNotice)
+	if _, err := c.Cancellation.ParseFeeTiers(); err != nil {
+		errs = append(errs, fmt.Errorf("cancellation.fee_tiers: %w", err))
+	}
+	positive("seat_holds.ttl", c.SeatHolds.TTL)
+	positive("seat_holds.release_interval", c.SeatHolds.ReleaseInterval)
+
+	if c.Bridge.Broker != "" {
+		oneOf("bridge.broker", c.Bridge.Broker, Bridges)
+		if c.Transport != "memory" {
+			errs = append(errs, fmt.Errorf("bridge.broker needs the memory transport, not %q", c.Transport))
+		}
+		if len(c.Bridge.Outbound) == 0 && len(c.Bridge.Inbound) == 0 {
+			errs = append(errs, errors.New("bridge.outbound or bridge.inbound is required"))
+		}
+	}
+
+	// Only the brokers the transport or the bridge use, and Redis for the
+	// Redis repository, need settings.
+	usesKafka := c.Transport == "kafka" || c.Transport == "routed" || c.Bridge.Broker == "kafka"
+	usesRedis := c.Transport == "redis" || c.Transport == "routed" || c.Bridge.Broker == "redis"
+	usesNATS := c.Transport == "nats" || c.Bridge.Broker == "nats"
+	if usesKafka {
+		if len(c.Kafka.Brokers) == 0 {
+			errs = append(errs, errors.New("kafka.brokers is required"))
+		}
+		require("kafka.consumer_group", c.Kafka.ConsumerGroup)
+	}
+	if usesRedis || c.Repository == "redis" {
+		require("redis.address", c.Redis.Address)
+		notNegative("redis.ticket_retention", c.Redis.TicketRetention)
+		if c.Redis.DB < 0 {
+			errs = append(errs, errors.New("redis.db must not be negative"))
+		}
+	}
+	if usesRedis {
+		require("redis.consumer_group", c.Redis.ConsumerGroup)
+		require("redis.consumer", c.Redis.Consumer)
+	}
+	if usesNATS {
+		require("nats.url", c.NATS.URL)
+		require("nats.queue_group", c.NATS.QueueGroup)
+		positive("nats.ack_wait", c.NATS.AckWait)
+		notNegative("nats.nak_delay", c.NATS.NakDelay)
+		if c.NATS.MaxDeliver < 0 {
+			errs = append(errs, errors.New("nats.max_deliver must not be negative"))
+		}
+	}
+	switch c.Transport {
+	case "sql":
+		require("sql.consumer_group", c.SQL.ConsumerGroup)
+		positive("sql.poll_interval", c.SQL.PollInterval)
+		positive("sql.claim_timeout", c.SQL.ClaimTimeout)
+	case "bolt":
+		require("bolt.path", c.Bolt.Path)
+		require("bolt.consumer_group", c.Bolt.ConsumerGroup)
+		notNegative("bolt.sync_interval", c.Bolt.SyncInterval)
+		notNegative("bolt.retention", c.Bolt.Retention)
+		notNegative("bolt.compact_interval", c.Bolt.CompactInterval)
+	case "grpc":
+		require("grpc.address", c.GRPC.Address)
+		require("grpc.target", c.GRPC.Target)
+		if (c.GRPC.TLSCert == "") != (c.GRPC.TLSKey == "") {
+			errs = append(errs, errors.New("grpc.tls_cert and grpc.tls_key must be set together"))
+		}
+		if c.GRPC.Token == "" && (c.GRPC.TLSCA == "" || c.GRPC.TLSCert == "") {
+			errs = append(errs, errors.New("grpc.token or mutual TLS (grpc.tls_ca, grpc.tls_cert and grpc.tls_key) is required to authenticate the BFF to the worker"))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration: %w", errors.Join(errs...))
+	}
+	return nil
+}
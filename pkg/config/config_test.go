@@ -0,0 +1,88 @@
+package config_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mateusmacedo/go-bff/pkg/config"
+)
+
+func TestValidateGRPC(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(*config.GRPCConfig)
+		want   string
+	}{
+		{"refuses unauthenticated callers", func(*config.GRPCConfig) {}, "grpc.token or mutual TLS"},
+		{"refuses TLS without client certificates", func(c *config.GRPCConfig) {
+			c.TLSCert, c.TLSKey = "worker.pem", "worker-key.pem"
+		}, "grpc.token or mutual TLS"},
+		{"refuses a certificate without its key", func(c *config.GRPCConfig) {
+			c.Token, c.TLSCert = "s3cret", "worker.pem"
+		}, "grpc.tls_cert and grpc.tls_key"},
+		{"accepts a token", func(c *config.GRPCConfig) { c.Token = "s3cret" }, ""},
+		{"accepts mutual TLS", func(c *config.GRPCConfig) {
+			c.TLSCert, c.TLSKey, c.TLSCA = "worker.pem", "worker-key.pem", "ca.pem"
+		}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.Transport = "grpc"
+			tt.change(&cfg.GRPC)
+
+			err := cfg.Validate()
+			if tt.want == "" && err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)) {
+				t.Fatalf("Validate() error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFeeTiers(t *testing.T) {
+	tests := []struct {
+		name  string
+		tiers []string
+		want  []config.FeeTier
+		err   string
+	}{
+		{name: "no tiers", want: []config.FeeTier{}},
+		{
+			name:  "tiers from the longest notice",
+			tiers: []string{"6h:25", " 1h:40 ", "0s:50"},
+			want:  []config.FeeTier{{Notice: 6 * time.Hour, FeePercent: 25}, {Notice: time.Hour, FeePercent: 40}, {Notice: 0, FeePercent: 50}},
+		},
+		{name: "unordered tiers", tiers: []string{"0s:50", "6h:25"}, err: `fee tier "6h:25" has no less notice than the tier before it`},
+		{name: "repeated notice", tiers: []string{"6h:25", "6h:30"}, err: `fee tier "6h:30" has no less notice than the tier before it`},
+		{name: "negative percent", tiers: []string{"6h:-1"}, err: "fee percent from 0 to 100"},
+		{name: "percent over 100", tiers: []string{"6h:101"}, err: "fee percent from 0 to 100"},
+		{name: "percent bounds", tiers: []string{"6h:0", "0s:100"}, want: []config.FeeTier{{Notice: 6 * time.Hour, FeePercent: 0}, {Notice: 0, FeePercent: 100}}},
+		{name: "missing percent", tiers: []string{"6h"}, err: "is not <notice>:<fee percent>"},
+		{name: "negative notice", tiers: []string{"-1h:25"}, err: "invalid notice"},
+		{name: "notice of the full refund", tiers: []string{"24h:10"}, err: "no less notice than the full refund"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cancellation := config.CancellationConfig{FullRefundNotice: 24 * time.Hour, FeeTiers: tt.tiers}
+
+			tiers, err := cancellation.ParseFeeTiers()
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("ParseFeeTiers() error = %v, want it to mention %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseFeeTiers() error = %v", err)
+			}
+			if !reflect.DeepEqual(tiers, tt.want) {
+				t.Fatalf("ParseFeeTiers() = %+v, want %+v", tiers, tt.want)
+			}
+		})
+	}
+}
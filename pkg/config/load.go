@@ -0,0 +1,352 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	EnvPrefix     = "BFF_"
+	FileEnv       = EnvPrefix + "CONFIG_FILE"
+	secretFileKey = "_file"
+)
+
+// ErrPrinted is returned by Load after it wrote the effective configuration
+// requested with -print-config.
+var ErrPrinted = errors.New("configuration printed")
+
+type field struct {
+	key    string
+	usage  string
+	value  reflect.Value
+	secret bool
+}
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	secretType   = reflect.TypeOf(Secret(""))
+)
+
+func fields(cfg *Config) []field {
+	var result []field
+	var walk func(prefix string, value reflect.Value)
+	walk = func(prefix string, value reflect.Value) {
+		for i := 0; i < value.NumField(); i++ {
+			structField := value.Type().Field(i)
+			key := structField.Tag.Get("config")
+			if key == "" || key == "-" {
+				continue
+			}
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+			if structField.Type.Kind() == reflect.Struct && structField.Type != durationType {
+				walk(key, value.Field(i))
+				continue
+			}
+			result = append(result, field{
+				key:    key,
+				usage:  structField.Tag.Get("usage"),
+				value:  value.Field(i),
+				secret: structField.Type == secretType,
+			})
+		}
+	}
+	walk("", reflect.ValueOf(cfg).Elem())
+	return result
+}
+
+func envName(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.NewReplacer(".", "_").Replace(key))
+}
+
+func flagName(key string) string {
+	return strings.ReplaceAll(key, "_", "-")
+}
+
+// Load builds the configuration from, in increasing precedence, the defaults,
+// the file given by -config or BFF_CONFIG_FILE (YAML or TOML), BFF_*
+// environment variables and command line flags. Every secret key also
+// accepts a "_file" variant naming a file to read the value from, so
+// database.password can come from database.password_file,
+// BFF_DATABASE_PASSWORD_FILE or -database.password-file.
+func Load(name string, args []string) (*Config, error) {
+	cfg := Default()
+	fieldList := fields(cfg)
+	secretFiles := make(map[string]string)
+
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	configFile := flags.String("config", os.Getenv(FileEnv), "configuration file (YAML or TOML)")
+	printConfig := flags.Bool("print-config", false, "print the effective configuration and exit")
+
+	type flagValue struct {
+		field  field
+		value  string
+		isFile bool
+	}
+	var flagValues []flagValue
+	for _, f := range fieldList {
+		f := f
+		flags.Func(flagName(f.key), fmt.Sprintf("%s (env %s, default %q)", f.usage, envName(f.key), formatValue(f.value, true)), func(value string) error {
+			flagValues = append(flagValues, flagValue{field: f, value: value})
+			return nil
+		})
+		if f.secret {
+			flags.Func(flagName(f.key+secretFileKey), fmt.Sprintf("file to read %s from", f.key), func(value string) error {
+				flagValues = append(flagValues, flagValue{field: f, value: value, isFile: true})
+				return nil
+			})
+		}
+	}
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if flags.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected argument %q", flags.Arg(0))
+	}
+
+	if *configFile != "" {
+		if err := loadFile(*configFile, fieldList, secretFiles); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, f := range fieldList {
+		if value, found := os.LookupEnv(envName(f.key)); found {
+			if err := setValue(f, value, secretFiles); err != nil {
+				return nil, fmt.Errorf("%s: %w", envName(f.key), err)
+			}
+		}
+		if !f.secret {
+			continue
+		}
+		if path, found := os.LookupEnv(envName(f.key + secretFileKey)); found {
+			secretFiles[f.key] = path
+		}
+	}
+
+	for _, v := range flagValues {
+		if v.isFile {
+			secretFiles[v.field.key] = v.value
+			continue
+		}
+		if err := setValue(v.field, v.value, secretFiles); err != nil {
+			return nil, fmt.Errorf("-%s: %w", flagName(v.field.key), err)
+		}
+	}
+
+	if err := readSecretFiles(fieldList, secretFiles); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	if *printConfig {
+		if err := cfg.Dump(os.Stdout); err != nil {
+			return nil, err
+		}
+		return nil, ErrPrinted
+	}
+	return cfg, nil
+}
+
+// MustLoad calls Load and exits the process when the configuration was only
+// printed, help was requested or the configuration is invalid.
+func MustLoad(name string, args []string) *Config {
+	cfg, err := Load(name, args)
+	switch {
+	case err == nil:
+		return cfg
+	case errors.Is(err, ErrPrinted), errors.Is(err, flag.ErrHelp):
+		os.Exit(0)
+	default:
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	return nil
+}
+
+func loadFile(path string, fieldList []field, secretFiles map[string]string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading configuration file: %w", err)
+	}
+
+	raw := make(map[string]interface{})
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	case ".toml":
+		err = toml.Unmarshal(data, &raw)
+	default:
+		return fmt.Errorf("unsupported configuration file format %q", filepath.Ext(path))
+	}
+	if err != nil {
+		return fmt.Errorf("parsing configuration file %s: %w", path, err)
+	}
+
+	values := make(map[string]interface{})
+	flatten("", raw, values)
+
+	byKey := make(map[string]field, len(fieldList))
+	for _, f := range fieldList {
+		byKey[f.key] = f
+	}
+
+	for key, value := range values {
+		if f, found := byKey[key]; found {
+			if err := setValue(f, value, secretFiles); err != nil {
+				return fmt.Errorf("%s: %s: %w", path, key, err)
+			}
+			continue
+		}
+		secretKey := strings.TrimSuffix(key, secretFileKey)
+		if f, found := byKey[secretKey]; found && f.secret && secretKey != key {
+			secretFiles[secretKey] = fmt.Sprint(value)
+			continue
+		}
+		return fmt.Errorf("%s: unknown configuration key %q", path, key)
+	}
+	return nil
+}
+
+func flatten(prefix string, raw map[string]interface{}, values map[string]interface{}) {
+	for key, value := range raw {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			flatten(key, nested, values)
+			continue
+		}
+		values[key] = value
+	}
+}
+
+// setValue assigns a value given by a source. A value set directly discards
+// any secret file named by a lower precedence source.
+func setValue(f field, raw interface{}, secretFiles map[string]string) error {
+	delete(secretFiles, f.key)
+
+	if f.value.Kind() == reflect.Slice {
+		var items []string
+		switch value := raw.(type) {
+		case []interface{}:
+			for _, item := range value {
+				items = append(items, fmt.Sprint(item))
+			}
+		default:
+			for _, item := range strings.Split(fmt.Sprint(value), ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+		}
+		f.value.Set(reflect.ValueOf(items))
+		return nil
+	}
+
+	value := fmt.Sprint(raw)
+	switch {
+	case f.value.Type() == durationType:
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		f.value.SetInt(int64(duration))
+	case f.value.Kind() == reflect.String:
+		f.value.SetString(value)
+	case f.value.Kind() == reflect.Int:
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		f.value.SetInt(int64(number))
+	case f.value.Kind() == reflect.Bool:
+		boolean, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		f.value.SetBool(boolean)
+	default:
+		return fmt.Errorf("unsupported configuration type %s", f.value.Type())
+	}
+	return nil
+}
+
+func readSecretFiles(fieldList []field, secretFiles map[string]string) error {
+	for _, f := range fieldList {
+		path, found := secretFiles[f.key]
+		if !found {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("reading %s from file: %w", f.key, err)
+		}
+		f.value.SetString(strings.TrimRight(string(data), "\r\n"))
+	}
+	return nil
+}
+
+func formatValue(value reflect.Value, mask bool) string {
+	switch {
+	case value.Type() == secretType && mask:
+		return Secret(value.String()).String()
+	case value.Kind() == reflect.Slice:
+		return strings.Join(value.Interface().([]string), ",")
+	default:
+		return fmt.Sprint(value.Interface())
+	}
+}
+
+// Dump writes the configuration as YAML with secrets masked.
+func (c *Config) Dump(w io.Writer) error {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	sections := make(map[string]*yaml.Node)
+	for _, f := range fields(c) {
+		node, key := root, f.key
+		if section, sectionKey, nested := strings.Cut(f.key, "."); nested {
+			node, key = sections[section], sectionKey
+			if node == nil {
+				node = &yaml.Node{Kind: yaml.MappingNode}
+				sections[section] = node
+				root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: section}, node)
+			}
+		}
+
+		var value *yaml.Node
+		if f.value.Kind() == reflect.Slice {
+			value = &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+			for _, item := range f.value.Interface().([]string) {
+				value.Content = append(value.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: item})
+			}
+		} else {
+			value = &yaml.Node{Kind: yaml.ScalarNode, Value: formatValue(f.value, true)}
+			if f.value.Kind() == reflect.String {
+				value.Style = yaml.DoubleQuotedStyle
+			}
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(root); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
@@ -0,0 +1,249 @@
+package config_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mateusmacedo/go-bff/pkg/config"
+)
+
+func TestLoadPrecedence(t *testing.T) {
+	tests := []struct {
+		name  string
+		file  string
+		env   string
+		flags []string
+		want  string
+	}{
+		{name: "defaults", want: ":8080"},
+		{name: "file over defaults", file: ":8001", want: ":8001"},
+		{name: "env over file", file: ":8001", env: ":8002", want: ":8002"},
+		{name: "flags over env", file: ":8001", env: ":8002", flags: []string{"-http.address=:8003"}, want: ":8003"},
+		{name: "flags over defaults", flags: []string{"-http.address", ":8003"}, want: ":8003"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := tt.flags
+			if tt.file != "" {
+				args = append([]string{"-config", writeFile(t, "bff.yaml", "http:\n  address: \""+tt.file+"\"\n")}, args...)
+			}
+			if tt.env != "" {
+				t.Setenv("BFF_HTTP_ADDRESS", tt.env)
+			}
+
+			cfg, err := config.Load("bff", args)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if cfg.HTTP.Address != tt.want {
+				t.Fatalf("http.address = %q, want %q", cfg.HTTP.Address, tt.want)
+			}
+		})
+	}
+
+	t.Run("reads the file named by BFF_CONFIG_FILE as TOML", func(t *testing.T) {
+		t.Setenv(config.FileEnv, writeFile(t, "bff.toml", "transport = \"channels\"\n[http]\naddress = \":8001\"\n"))
+		cfg, err := config.Load("bff", nil)
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if cfg.Transport != "channels" || cfg.HTTP.Address != ":8001" {
+			t.Fatalf("transport = %q, http.address = %q, want channels and :8001", cfg.Transport, cfg.HTTP.Address)
+		}
+	})
+}
+
+func TestLoadTrustHeaders(t *testing.T) {
+	tests := []struct {
+		name  string
+		file  string
+		env   string
+		flags []string
+		want  bool
+	}{
+		{name: "off by default"},
+		{name: "off in the file", file: "auth:\n  trust_headers: false\n"},
+		{name: "off in the environment", env: "false"},
+		{name: "off in a flag", flags: []string{"-auth.trust-headers=false"}},
+		{name: "flag turning the file off", file: "auth:\n  trust_headers: true\n", flags: []string{"-auth.trust-headers=false"}},
+		{name: "on in the file", file: "auth:\n  trust_headers: true\n", want: true},
+		{name: "on in the environment", env: "true", want: true},
+		{name: "on in a flag", flags: []string{"-auth.trust-headers=true"}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := tt.flags
+			if tt.file != "" {
+				args = append([]string{"-config", writeFile(t, "bff.yaml", tt.file)}, args...)
+			}
+			if tt.env != "" {
+				t.Setenv("BFF_AUTH_TRUST_HEADERS", tt.env)
+			}
+
+			cfg, err := config.Load("bff", args)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if cfg.Auth.TrustHeaders != tt.want {
+				t.Fatalf("auth.trust_headers = %v, want %v", cfg.Auth.TrustHeaders, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadSecretFiles(t *testing.T) {
+	fromFile := writeFile(t, "password", "from-file\n")
+
+	tests := []struct {
+		name  string
+		file  string
+		env   map[string]string
+		flags []string
+		want  string
+	}{
+		{name: "file key", file: "database:\n  password_file: " + fromFile + "\n", want: "from-file"},
+		{name: "env variable", env: map[string]string{"BFF_DATABASE_PASSWORD_FILE": fromFile}, want: "from-file"},
+		{name: "flag", flags: []string{"-database.password-file=" + fromFile}, want: "from-file"},
+		{
+			name:  "value in a higher source discards a lower file",
+			env:   map[string]string{"BFF_DATABASE_PASSWORD_FILE": fromFile},
+			flags: []string{"-database.password=from-flag"},
+			want:  "from-flag",
+		},
+		{
+			name: "file in a higher source replaces a lower value",
+			file: "database:\n  password: from-config\n",
+			env:  map[string]string{"BFF_DATABASE_PASSWORD_FILE": fromFile},
+			want: "from-file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := tt.flags
+			if tt.file != "" {
+				args = append([]string{"-config", writeFile(t, "bff.yaml", tt.file)}, args...)
+			}
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := config.Load("bff", args)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if got := cfg.Database.Password.Value(); got != tt.want {
+				t.Fatalf("database.password = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("fails on a missing file", func(t *testing.T) {
+		_, err := config.Load("bff", []string{"-database.password-file=" + filepath.Join(t.TempDir(), "missing")})
+		if err == nil || !strings.Contains(err.Error(), "reading database.password from file") {
+			t.Fatalf("Load() error = %v, want a read error", err)
+		}
+	})
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		env  map[string]string
+		args []string
+		want []string
+	}{
+		{name: "unknown transport", args: []string{"-transport=pigeon"}, want: []string{`transport "pigeon" must be one of`}},
+		{
+			name: "every invalid setting at once",
+			args: []string{"-role=api", "-http.shutdown-timeout=0s", "-health.address="},
+			want: []string{"invalid configuration", "role api needs a transport", "http.shutdown_timeout must be positive", "health.address is required"},
+		},
+		{name: "malformed duration in the environment", env: map[string]string{"BFF_HTTP_SHUTDOWN_TIMEOUT": "soon"}, want: []string{"BFF_HTTP_SHUTDOWN_TIMEOUT"}},
+		{name: "malformed number in a flag", args: []string{"-database.port=many"}, want: []string{"-database.port"}},
+		{name: "unknown file key", file: "http:\n  adress: \":8001\"\n", want: []string{`unknown configuration key "http.adress"`}},
+		{name: "unsupported file format", file: "-", want: []string{"unsupported configuration file format"}},
+		{name: "stray argument", args: []string{"serve"}, want: []string{`unexpected argument "serve"`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := tt.args
+			switch tt.file {
+			case "":
+			case "-":
+				args = append([]string{"-config", writeFile(t, "bff.ini", "")}, args...)
+			default:
+				args = append([]string{"-config", writeFile(t, "bff.yaml", tt.file)}, args...)
+			}
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			_, err := config.Load("bff", args)
+			if err == nil {
+				t.Fatalf("Load() succeeded, want an error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Load() error = %v, want it to mention %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDumpMasksSecrets(t *testing.T) {
+	cfg := config.Default()
+	cfg.Database.Password = "hunter2"
+	cfg.Redis.Password = "hunter3"
+
+	var out bytes.Buffer
+	if err := cfg.Dump(&out); err != nil {
+		t.Fatalf("Dump() error = %v", err)
+	}
+	dump := out.String()
+	for _, secret := range []string{"hunter2", "hunter3"} {
+		if strings.Contains(dump, secret) {
+			t.Fatalf("Dump() revealed %q:\n%s", secret, dump)
+		}
+	}
+	if !strings.Contains(dump, `password: "******"`) {
+		t.Fatalf("Dump() did not mask the passwords:\n%s", dump)
+	}
+
+	t.Run("print-config", func(t *testing.T) {
+		stdout := os.Stdout
+		printed, err := os.Create(filepath.Join(t.TempDir(), "stdout"))
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		os.Stdout = printed
+		_, err = config.Load("bff", []string{"-print-config", "-database.password=hunter2"})
+		os.Stdout = stdout
+		printed.Close()
+
+		if !errors.Is(err, config.ErrPrinted) {
+			t.Fatalf("Load() error = %v, want %v", err, config.ErrPrinted)
+		}
+		data, err := os.ReadFile(printed.Name())
+		if err != nil {
+			t.Fatalf("ReadFile() error = %v", err)
+		}
+		if strings.Contains(string(data), "hunter2") || !strings.Contains(string(data), `password: "******"`) {
+			t.Fatalf("-print-config did not mask the password:\n%s", data)
+		}
+	})
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	return path
+}
@@ -0,0 +1,24 @@
+package domain
+
+import "errors"
+
+// ErrNotFound, ErrInvalid and ErrConflict classify slice errors wrapping
+// them, so that transports and HTTP handlers can map failures without
+// knowing each slice.
+var (
+	ErrNotFound = errors.New("not found")
+	ErrInvalid  = errors.New("invalid")
+	ErrConflict = errors.New("conflict")
+)
+
+// ErrConcurrencyConflict classifies writes that lost a compare-and-swap on an
+// aggregate version. Unlike the classes above it is transient: running the
+// message again against fresh state may succeed.
+var ErrConcurrencyConflict = errors.New("concurrency conflict")
+
+// Rejected reports whether err belongs to ErrNotFound, ErrInvalid or
+// ErrConflict. Such errors come from the message itself, so redelivering it
+// cannot succeed.
+func Rejected(err error) bool {
+	return errors.Is(err, ErrNotFound) || errors.Is(err, ErrInvalid) || errors.Is(err, ErrConflict)
+}
@@ -1,3 +1,7 @@
 package domain
 
+import "time"
+
 type IDGenerator[T any] func() T
+
+type Clock func() time.Time
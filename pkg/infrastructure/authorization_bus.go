@@ -0,0 +1,67 @@
+package infrastructure
+
+import (
+	"context"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	"github.com/mateusmacedo/go-bff/pkg/domain"
+)
+
+type authorizedCommandBus[C domain.Command[D], D any] struct {
+	next       application.CommandBus[C, D]
+	authorizer *application.Authorizer[D]
+	logger     application.AppLogger
+}
+
+func NewAuthorizedCommandBus[C domain.Command[D], D any](next application.CommandBus[C, D], authorizer *application.Authorizer[D], logger application.AppLogger) application.CommandBus[C, D] {
+	return &authorizedCommandBus[C, D]{
+		next:       next,
+		authorizer: authorizer,
+		logger:     logger,
+	}
+}
+
+func (bus *authorizedCommandBus[C, D]) RegisterHandler(commandName string, handler application.CommandHandler[C, D]) error {
+	return bus.next.RegisterHandler(commandName, handler)
+}
+
+func (bus *authorizedCommandBus[C, D]) Dispatch(ctx context.Context, command C) error {
+	if err := bus.authorizer.Authorize(ctx, command.CommandName(), command.Payload()); err != nil {
+		application.LogError(ctx, bus.logger, "command not authorized", err, map[string]interface{}{
+			"command_name": command.CommandName(),
+		})
+		return err
+	}
+
+	return bus.next.Dispatch(ctx, command)
+}
+
+type authorizedQueryBus[Q domain.Query[D], D any, R any] struct {
+	next       application.QueryBus[Q, D, R]
+	authorizer *application.Authorizer[D]
+	logger     application.AppLogger
+}
+
+func NewAuthorizedQueryBus[Q domain.Query[D], D any, R any](next application.QueryBus[Q, D, R], authorizer *application.Authorizer[D], logger application.AppLogger) application.QueryBus[Q, D, R] {
+	return &authorizedQueryBus[Q, D, R]{
+		next:       next,
+		authorizer: authorizer,
+		logger:     logger,
+	}
+}
+
+func (bus *authorizedQueryBus[Q, D, R]) RegisterHandler(queryName string, handler application.QueryHandler[Q, D, R]) error {
+	return bus.next.RegisterHandler(queryName, handler)
+}
+
+func (bus *authorizedQueryBus[Q, D, R]) Dispatch(ctx context.Context, query Q) (R, error) {
+	if err := bus.authorizer.Authorize(ctx, query.QueryName(), query.Payload()); err != nil {
+		application.LogError(ctx, bus.logger, "query not authorized", err, map[string]interface{}{
+			"query_name": query.QueryName(),
+		})
+		var zero R
+		return zero, err
+	}
+
+	return bus.next.Dispatch(ctx, query)
+}
@@ -0,0 +1,76 @@
+package infrastructure_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pkgApp "github.com/mateusmacedo/go-bff/pkg/application"
+	pkgInfra "github.com/mateusmacedo/go-bff/pkg/infrastructure"
+	"github.com/mateusmacedo/go-bff/pkg/testkit"
+	"github.com/mateusmacedo/go-bff/pkg/testkit/conformance"
+)
+
+func newAuthorizer() *pkgApp.Authorizer[conformance.Payload] {
+	authorizer := pkgApp.NewAuthorizer[conformance.Payload]()
+	authorizer.Register("Reserve", pkgApp.RequireAnyRole[conformance.Payload]("passenger"))
+	return authorizer
+}
+
+var authorizationCases = []struct {
+	name    string
+	ctx     context.Context
+	message string
+	allowed bool
+}{
+	{name: "allowed role", ctx: pkgApp.WithPrincipal(context.Background(), pkgApp.Principal{ID: "ana", Roles: []string{"passenger"}}), message: "Reserve", allowed: true},
+	{name: "other role", ctx: pkgApp.WithPrincipal(context.Background(), pkgApp.Principal{ID: "bob", Roles: []string{"agent"}}), message: "Reserve"},
+	{name: "no principal", ctx: context.Background(), message: "Reserve"},
+	{name: "unknown message", ctx: pkgApp.WithPrincipal(context.Background(), pkgApp.Principal{ID: "ana", Roles: []string{"passenger"}}), message: "Refund"},
+}
+
+func TestAuthorizedCommandBus(t *testing.T) {
+	for _, tt := range authorizationCases {
+		t.Run(tt.name, func(t *testing.T) {
+			next := testkit.NewRecordingCommandBus[conformance.Command, conformance.Payload]()
+			bus := pkgInfra.NewAuthorizedCommandBus[conformance.Command](next, newAuthorizer(), testkit.NewLogger(t))
+
+			err := bus.Dispatch(tt.ctx, conformance.NewCommand(tt.message, conformance.Payload{ID: "1"}))
+			if tt.allowed {
+				if err != nil {
+					t.Fatalf("Dispatch() error = %v", err)
+				}
+				next.ExpectDispatched(tt.message).Once(t)
+				return
+			}
+			if !errors.Is(err, pkgApp.ErrForbidden) {
+				t.Fatalf("Dispatch() error = %v, want %v", err, pkgApp.ErrForbidden)
+			}
+			next.ExpectDispatched(tt.message).Never(t)
+		})
+	}
+}
+
+func TestAuthorizedQueryBus(t *testing.T) {
+	for _, tt := range authorizationCases {
+		t.Run(tt.name, func(t *testing.T) {
+			next := testkit.NewRecordingQueryBus[conformance.Query, conformance.Payload, conformance.Payload]()
+			next.Respond(tt.message, conformance.Payload{ID: "1", Value: 7}, nil)
+			bus := pkgInfra.NewAuthorizedQueryBus[conformance.Query](next, newAuthorizer(), testkit.NewLogger(t))
+
+			result, err := bus.Dispatch(tt.ctx, conformance.NewQuery(tt.message, conformance.Payload{ID: "1"}))
+			if tt.allowed {
+				if err != nil || result.Value != 7 {
+					t.Fatalf("Dispatch() = %+v, %v, want the next bus's result", result, err)
+				}
+				return
+			}
+			if !errors.Is(err, pkgApp.ErrForbidden) || result != (conformance.Payload{}) {
+				t.Fatalf("Dispatch() = %+v, %v, want a zero result and %v", result, err, pkgApp.ErrForbidden)
+			}
+			if dispatched := next.Dispatched(); len(dispatched) != 0 {
+				t.Fatalf("next bus saw %d queries, want none", len(dispatched))
+			}
+		})
+	}
+}
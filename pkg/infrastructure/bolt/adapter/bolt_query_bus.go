@@ -0,0 +1,17 @@
+package adapter
+
+import (
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	"github.com/mateusmacedo/go-bff/pkg/domain"
+	watermillAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/watermill/adapter"
+)
+
+type BoltQueryBus[Q domain.Query[D], D any, R any] struct {
+	*watermillAdapter.WatermillQueryBus[Q, D, R]
+}
+
+func NewBoltQueryBus[Q domain.Query[D], D any, R any](publisher *Publisher, subscriber *Subscriber, logger application.AppLogger, options ...watermillAdapter.Option) *BoltQueryBus[Q, D, R] {
+	return &BoltQueryBus[Q, D, R]{
+		WatermillQueryBus: watermillAdapter.NewWatermillQueryBus[Q, D, R](publisher, subscriber, logger, options...),
+	}
+}
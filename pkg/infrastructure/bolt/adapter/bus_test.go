@@ -0,0 +1,61 @@
+package adapter_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	boltAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/bolt/adapter"
+	watermillAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/watermill/adapter"
+	"github.com/mateusmacedo/go-bff/pkg/testkit"
+	"github.com/mateusmacedo/go-bff/pkg/testkit/conformance"
+)
+
+func TestBoltBuses(t *testing.T) {
+	config := conformance.Config{Delivery: conformance.Asynchronous}
+
+	t.Run("command bus", func(t *testing.T) {
+		conformance.TestCommandBus(t, config, func(t *testing.T) conformance.CommandBus {
+			publisher, subscriber := newPubSub(t)
+			bus := boltAdapter.NewBoltCommandBus[conformance.Command, conformance.Payload](publisher, subscriber, testkit.NewLogger(t))
+			t.Cleanup(func() { bus.Close() })
+			return bus
+		})
+	})
+	t.Run("query bus", func(t *testing.T) {
+		conformance.TestQueryBus(t, config, func(t *testing.T) conformance.QueryBus {
+			publisher, subscriber := newPubSub(t)
+			bus := boltAdapter.NewBoltQueryBus[conformance.Query, conformance.Payload, conformance.Payload](publisher, subscriber, testkit.NewLogger(t))
+			t.Cleanup(func() { bus.Close() })
+			return bus
+		})
+	})
+	t.Run("event bus", func(t *testing.T) {
+		conformance.TestEventBus(t, config, func(t *testing.T) conformance.EventBus {
+			publisher, subscriber := newPubSub(t)
+			bus := boltAdapter.NewBoltEventBus[conformance.Event, conformance.Payload](publisher, subscriber, testkit.NewLogger(t))
+			t.Cleanup(func() { bus.Close() })
+			return bus
+		})
+	})
+}
+
+// newPubSub opens the bolt transport on a fresh file.
+func newPubSub(t *testing.T) (*boltAdapter.Publisher, *boltAdapter.Subscriber) {
+	t.Helper()
+	store, err := boltAdapter.OpenStore(boltAdapter.StoreConfig{
+		Path: filepath.Join(t.TempDir(), "messages.db"),
+	}, watermillAdapter.NewWatermillLoggerAdapter(testkit.NewLogger(t)))
+	if err != nil {
+		t.Fatalf("OpenStore() error = %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	subscriber := boltAdapter.NewSubscriber(store, boltAdapter.SubscriberConfig{
+		ConsumerGroup: "bff",
+		PollInterval:  10 * time.Millisecond,
+		NakDelay:      10 * time.Millisecond,
+	})
+	t.Cleanup(func() { subscriber.Close() })
+	return boltAdapter.NewPublisher(store), subscriber
+}
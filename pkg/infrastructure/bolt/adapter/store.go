@@ -0,0 +1,406 @@
+package adapter
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	ErrStoreClosed = errors.New("bolt store closed")
+
+	topicsBucket  = []byte("topics")
+	offsetsBucket = []byte("offsets")
+)
+
+type SyncPolicy int
+
+const (
+	// SyncAlways fsyncs every commit: nothing acknowledged is lost.
+	SyncAlways SyncPolicy = iota
+	// SyncInterval fsyncs every SyncInterval: a crash loses at most that window.
+	SyncInterval
+)
+
+type StoreConfig struct {
+	Path         string
+	Sync         SyncPolicy
+	SyncInterval time.Duration
+	// Retention keeps messages acked by every consumer group for this long
+	// before removing them; zero keeps them forever.
+	Retention         time.Duration
+	RetentionInterval time.Duration
+	// CompactInterval rewrites the file to give space freed by retention back
+	// to the filesystem; zero disables it.
+	CompactInterval time.Duration
+}
+
+func (c StoreConfig) withDefaults() StoreConfig {
+	if c.SyncInterval <= 0 {
+		c.SyncInterval = time.Second
+	}
+	if c.RetentionInterval <= 0 {
+		c.RetentionInterval = time.Minute
+	}
+	return c
+}
+
+type envelope struct {
+	UUID        string            `json:"uuid"`
+	Payload     []byte            `json:"payload"`
+	Metadata    map[string]string `json:"metadata"`
+	PublishedAt time.Time         `json:"published_at"`
+}
+
+// Store is an append-only log per topic kept in a single bbolt file, shared
+// by the publisher and the subscribers of a process.
+type Store struct {
+	db      *bolt.DB
+	config  StoreConfig
+	logger  watermill.LoggerAdapter
+	mu      sync.RWMutex
+	signals map[string]chan struct{}
+	groups  map[string]*sync.Mutex
+	stateMu sync.Mutex
+	closing chan struct{}
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+func OpenStore(config StoreConfig, logger watermill.LoggerAdapter) (*Store, error) {
+	if config.Path == "" {
+		return nil, errors.New("bolt store: path is required")
+	}
+	if logger == nil {
+		logger = watermill.NopLogger{}
+	}
+	config = config.withDefaults()
+
+	db, err := openDB(config)
+	if err != nil {
+		return nil, err
+	}
+
+	store := &Store{
+		db:      db,
+		config:  config,
+		logger:  logger,
+		signals: make(map[string]chan struct{}),
+		groups:  make(map[string]*sync.Mutex),
+		closing: make(chan struct{}),
+	}
+	if config.Sync == SyncInterval {
+		store.every(config.SyncInterval, store.sync)
+	}
+	if config.Retention > 0 {
+		store.every(config.RetentionInterval, store.applyRetention)
+	}
+	if config.CompactInterval > 0 {
+		store.every(config.CompactInterval, store.Compact)
+	}
+	return store, nil
+}
+
+func openDB(config StoreConfig) (*bolt.DB, error) {
+	db, err := bolt.Open(config.Path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+	db.NoSync = config.Sync != SyncAlways
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{topicsBucket, offsetsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+func (s *Store) Close() error {
+	s.stateMu.Lock()
+	if s.closed {
+		s.stateMu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.closing)
+	s.stateMu.Unlock()
+
+	s.wg.Wait()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.config.Sync != SyncAlways {
+		_ = s.db.Sync()
+	}
+	return s.db.Close()
+}
+
+// Compact rewrites the store into a fresh file and swaps it in; publishers
+// and subscribers wait while it runs. The compacted file stays open across
+// the swap, so the store only ever holds a handle known to work: if the swap
+// fails the original file is reopened.
+func (s *Store) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	compactPath := s.config.Path + ".compact"
+	_ = os.Remove(compactPath)
+	dst, err := bolt.Open(compactPath, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return err
+	}
+	discard := func() {
+		_ = dst.Close()
+		_ = os.Remove(compactPath)
+	}
+	if err := bolt.Compact(dst, s.db, 64*1024*1024); err != nil {
+		discard()
+		return err
+	}
+	dst.NoSync = s.config.Sync != SyncAlways
+
+	if err := s.db.Close(); err != nil {
+		discard()
+		return s.reopen(err)
+	}
+	if err := os.Rename(compactPath, s.config.Path); err != nil {
+		discard()
+		return s.reopen(err)
+	}
+	s.db = dst
+	s.logger.Debug("Store compacted", watermill.LogFields{"path": s.config.Path})
+	return nil
+}
+
+// reopen opens the store's file again after a failed Compact and returns
+// cause.
+func (s *Store) reopen(cause error) error {
+	db, err := openDB(s.config)
+	if err != nil {
+		return errors.Join(cause, err)
+	}
+	s.db = db
+	return cause
+}
+
+func (s *Store) append(topic string, envelopes []envelope) error {
+	s.mu.RLock()
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.Bucket(topicsBucket).CreateBucketIfNotExists([]byte(topic))
+		if err != nil {
+			return err
+		}
+		for _, env := range envelopes {
+			value, err := json.Marshal(env)
+			if err != nil {
+				return err
+			}
+			offset, err := bucket.NextSequence()
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put(encodeOffset(offset), value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	s.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	s.stateMu.Lock()
+	if signal, found := s.signals[topic]; found {
+		close(signal)
+		delete(s.signals, topic)
+	}
+	s.stateMu.Unlock()
+	return nil
+}
+
+// next returns the first message of topic after the group's acked offset.
+func (s *Store) next(group, topic string) (uint64, uint64, *envelope, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var (
+		acked  uint64
+		offset uint64
+		env    *envelope
+	)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		if value := tx.Bucket(offsetsBucket).Get(offsetKey(group, topic)); value != nil {
+			acked = binary.BigEndian.Uint64(value)
+		}
+		bucket := tx.Bucket(topicsBucket).Bucket([]byte(topic))
+		if bucket == nil {
+			return nil
+		}
+		key, value := bucket.Cursor().Seek(encodeOffset(acked + 1))
+		if key == nil {
+			return nil
+		}
+		offset = binary.BigEndian.Uint64(key)
+		env = &envelope{}
+		return json.Unmarshal(value, env)
+	})
+	return acked, offset, env, err
+}
+
+func (s *Store) ack(group, topic string, offset uint64) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(offsetsBucket).Put(offsetKey(group, topic), encodeOffset(offset))
+	})
+}
+
+// register makes group count for retention from the start of topic.
+func (s *Store) register(group, topic string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.db.Update(func(tx *bolt.Tx) error {
+		offsets := tx.Bucket(offsetsBucket)
+		if offsets.Get(offsetKey(group, topic)) != nil {
+			return nil
+		}
+		return offsets.Put(offsetKey(group, topic), encodeOffset(0))
+	})
+}
+
+// signal returns a channel closed on the next append to topic.
+func (s *Store) signal(topic string) <-chan struct{} {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+	signal, found := s.signals[topic]
+	if !found {
+		signal = make(chan struct{})
+		s.signals[topic] = signal
+	}
+	return signal
+}
+
+// groupLock keeps subscribers of one group in this process from delivering
+// the same offset twice.
+func (s *Store) groupLock(group, topic string) *sync.Mutex {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+	key := string(offsetKey(group, topic))
+	lock, found := s.groups[key]
+	if !found {
+		lock = &sync.Mutex{}
+		s.groups[key] = lock
+	}
+	return lock
+}
+
+func (s *Store) applyRetention() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	cutoff := time.Now().Add(-s.config.Retention)
+	return s.db.Update(func(tx *bolt.Tx) error {
+		minAcked := make(map[string]uint64)
+		err := tx.Bucket(offsetsBucket).ForEach(func(key, value []byte) error {
+			_, topic := splitOffsetKey(key)
+			acked := binary.BigEndian.Uint64(value)
+			if current, found := minAcked[topic]; !found || acked < current {
+				minAcked[topic] = acked
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for topic, acked := range minAcked {
+			bucket := tx.Bucket(topicsBucket).Bucket([]byte(topic))
+			if bucket == nil {
+				continue
+			}
+			var expired [][]byte
+			cursor := bucket.Cursor()
+			for key, value := cursor.First(); key != nil && binary.BigEndian.Uint64(key) <= acked; key, value = cursor.Next() {
+				var env envelope
+				if err := json.Unmarshal(value, &env); err != nil {
+					return err
+				}
+				if env.PublishedAt.After(cutoff) {
+					break
+				}
+				expired = append(expired, key)
+			}
+			for _, key := range expired {
+				if err := bucket.Delete(key); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func (s *Store) sync() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.db.Sync()
+}
+
+func (s *Store) every(interval time.Duration, task func() error) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.closing:
+				return
+			case <-ticker.C:
+				if err := task(); err != nil {
+					s.logger.Error("Store maintenance failed", err, watermill.LogFields{"path": s.config.Path})
+				}
+			}
+		}
+	}()
+}
+
+func encodeOffset(offset uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, offset)
+	return key
+}
+
+func offsetKey(group, topic string) []byte {
+	return []byte(group + "\x00" + topic)
+}
+
+func splitOffsetKey(key []byte) (string, string) {
+	for i, char := range key {
+		if char == 0 {
+			return string(key[:i]), string(key[i+1:])
+		}
+	}
+	return "", string(key)
+}
+
+func (s *Store) isClosed() bool {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+	return s.closed
+}
@@ -0,0 +1,203 @@
+package adapter_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+
+	boltAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/bolt/adapter"
+)
+
+func TestStoreSurvivesRestarts(t *testing.T) {
+	t.Run("redelivers a message left unacked", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "messages.db")
+		store := openStore(t, boltAdapter.StoreConfig{Path: path})
+		publish(t, store, "m1")
+		if held := receive(t, subscribe(t, store, "group")); held.UUID != "m1" {
+			t.Fatalf("delivered %s, want m1", held.UUID)
+		}
+		closeStore(t, store)
+
+		redelivered := receive(t, subscribe(t, openStore(t, boltAdapter.StoreConfig{Path: path}), "group"))
+		if redelivered.UUID != "m1" {
+			t.Fatalf("redelivered %s after a restart, want m1", redelivered.UUID)
+		}
+		redelivered.Ack()
+	})
+
+	t.Run("resumes after the last acked message", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "messages.db")
+		store := openStore(t, boltAdapter.StoreConfig{Path: path, Sync: boltAdapter.SyncInterval, SyncInterval: 10 * time.Millisecond})
+		publish(t, store, "m1", "m2")
+		messages := subscribe(t, store, "group")
+		receive(t, messages).Ack()
+		// m2 is only delivered once the ack of m1 is stored.
+		receive(t, messages)
+		closeStore(t, store)
+
+		if next := receive(t, subscribe(t, openStore(t, boltAdapter.StoreConfig{Path: path}), "group")); next.UUID != "m2" {
+			t.Fatalf("delivered %s after a restart, want m2", next.UUID)
+		}
+	})
+}
+
+func TestStoreRetention(t *testing.T) {
+	store := openStore(t, boltAdapter.StoreConfig{
+		Path:              filepath.Join(t.TempDir(), "messages.db"),
+		Retention:         time.Millisecond,
+		RetentionInterval: 10 * time.Millisecond,
+	})
+	fast := subscribe(t, store, "fast")
+	slow := subscribe(t, store, "slow")
+	publish(t, store, "m1", "m2", "m3")
+
+	// Each group acks up to the message before the one it receives last.
+	receive(t, fast).Ack()
+	receive(t, fast).Ack()
+	receive(t, fast)
+	receive(t, slow).Ack()
+	receive(t, slow)
+	waitForRetention()
+
+	// A group joining now starts at the oldest message retention kept: m1,
+	// acked by every group, is gone; m2, still held by "slow", is not.
+	if oldest := receive(t, subscribe(t, store, "late")); oldest.UUID != "m2" {
+		t.Fatalf("oldest kept message is %s, want m2", oldest.UUID)
+	}
+}
+
+func TestStoreCompact(t *testing.T) {
+	t.Run("gives space back and keeps serving", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "messages.db")
+		store := openStore(t, boltAdapter.StoreConfig{Path: path, Retention: time.Millisecond, RetentionInterval: 10 * time.Millisecond})
+		messages := subscribe(t, store, "group")
+		payload := make([]byte, 64*1024)
+		for i := range 64 {
+			publishPayload(t, store, fmt.Sprintf("bulk-%d", i), payload)
+		}
+		for range 64 {
+			receive(t, messages).Ack()
+		}
+		publish(t, store, "tail")
+		receive(t, messages).Ack()
+		waitForRetention()
+
+		before := fileSize(t, path)
+		if err := store.Compact(); err != nil {
+			t.Fatalf("Compact() error = %v", err)
+		}
+		if after := fileSize(t, path); after >= before {
+			t.Fatalf("file has %d bytes after compaction, want fewer than %d", after, before)
+		}
+
+		publish(t, store, "m1")
+		if next := receive(t, messages); next.UUID != "m1" {
+			t.Fatalf("delivered %s after compaction, want m1", next.UUID)
+		}
+	})
+
+	t.Run("keeps the original file when compaction fails", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "messages.db")
+		store := openStore(t, boltAdapter.StoreConfig{Path: path})
+		publish(t, store, "m1")
+
+		// A non-empty directory where the compacted file goes cannot be
+		// removed nor opened as a database.
+		if err := os.MkdirAll(filepath.Join(path+".compact", "busy"), 0o700); err != nil {
+			t.Fatalf("MkdirAll() error = %v", err)
+		}
+		if err := store.Compact(); err == nil {
+			t.Fatalf("Compact() succeeded over a directory")
+		}
+
+		publish(t, store, "m2")
+		messages := subscribe(t, store, "group")
+		for _, want := range []string{"m1", "m2"} {
+			msg := receive(t, messages)
+			if msg.UUID != want {
+				t.Fatalf("delivered %s after a failed compaction, want %s", msg.UUID, want)
+			}
+			msg.Ack()
+		}
+	})
+}
+
+const topic = "BusTicketBooked"
+
+func openStore(t *testing.T, config boltAdapter.StoreConfig) *boltAdapter.Store {
+	t.Helper()
+	store, err := boltAdapter.OpenStore(config, nil)
+	if err != nil {
+		t.Fatalf("OpenStore() error = %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func closeStore(t *testing.T, store *boltAdapter.Store) {
+	t.Helper()
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+}
+
+func subscribe(t *testing.T, store *boltAdapter.Store, group string) <-chan *message.Message {
+	t.Helper()
+	subscriber := boltAdapter.NewSubscriber(store, boltAdapter.SubscriberConfig{
+		ConsumerGroup: group,
+		PollInterval:  10 * time.Millisecond,
+		NakDelay:      10 * time.Millisecond,
+	})
+	t.Cleanup(func() { subscriber.Close() })
+
+	messages, err := subscriber.Subscribe(context.Background(), topic)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	return messages
+}
+
+func publish(t *testing.T, store *boltAdapter.Store, uuids ...string) {
+	t.Helper()
+	for _, uuid := range uuids {
+		publishPayload(t, store, uuid, []byte(uuid))
+	}
+}
+
+func publishPayload(t *testing.T, store *boltAdapter.Store, uuid string, payload []byte) {
+	t.Helper()
+	if err := boltAdapter.NewPublisher(store).Publish(topic, message.NewMessage(uuid, payload)); err != nil {
+		t.Fatalf("Publish(%s) error = %v", uuid, err)
+	}
+}
+
+func receive(t *testing.T, messages <-chan *message.Message) *message.Message {
+	t.Helper()
+	select {
+	case msg := <-messages:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no message delivered")
+		return nil
+	}
+}
+
+// waitForRetention gives a store with a 10ms RetentionInterval time to run
+// it several times.
+func waitForRetention() {
+	time.Sleep(200 * time.Millisecond)
+}
+
+func fileSize(t *testing.T, path string) int64 {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	return info.Size()
+}
@@ -0,0 +1,169 @@
+package adapter
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+)
+
+type SubscriberConfig struct {
+	ConsumerGroup string
+	NakDelay      time.Duration
+	PollInterval  time.Duration
+}
+
+func (c SubscriberConfig) withDefaults() SubscriberConfig {
+	if c.ConsumerGroup == "" {
+		c.ConsumerGroup = "default"
+	}
+	if c.NakDelay <= 0 {
+		c.NakDelay = time.Second
+	}
+	if c.PollInterval <= 0 {
+		c.PollInterval = 5 * time.Second
+	}
+	return c
+}
+
+type Subscriber struct {
+	store   *Store
+	config  SubscriberConfig
+	logger  watermill.LoggerAdapter
+	closing chan struct{}
+	closed  bool
+	mu      sync.Mutex
+	wg      sync.WaitGroup
+}
+
+func NewSubscriber(store *Store, config SubscriberConfig) *Subscriber {
+	return &Subscriber{
+		store:   store,
+		config:  config.withDefaults(),
+		logger:  store.logger,
+		closing: make(chan struct{}),
+	}
+}
+
+func (s *Subscriber) Subscribe(ctx context.Context, topic string) (<-chan *message.Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed || s.store.isClosed() {
+		return nil, ErrStoreClosed
+	}
+	if err := s.store.register(s.config.ConsumerGroup, topic); err != nil {
+		return nil, err
+	}
+
+	output := make(chan *message.Message)
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer close(output)
+		s.consume(ctx, topic, output)
+	}()
+	return output, nil
+}
+
+func (s *Subscriber) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.closing)
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return nil
+}
+
+func (s *Subscriber) consume(ctx context.Context, topic string, output chan *message.Message) {
+	logFields := watermill.LogFields{"topic": topic, "consumer_group": s.config.ConsumerGroup}
+	lock := s.store.groupLock(s.config.ConsumerGroup, topic)
+
+	for {
+		// Taken before reading the offset so a publish between the read and
+		// the wait still wakes us up.
+		signal := s.store.signal(topic)
+
+		lock.Lock()
+		delivered, err := s.consumeNext(ctx, topic, output)
+		lock.Unlock()
+		if err != nil && !s.done(ctx) {
+			s.logger.Error("Consuming message failed", err, logFields)
+		}
+		if delivered {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.closing:
+			return
+		case <-s.store.closing:
+			return
+		case <-signal:
+		case <-time.After(s.config.PollInterval):
+		}
+	}
+}
+
+func (s *Subscriber) consumeNext(ctx context.Context, topic string, output chan *message.Message) (bool, error) {
+	if s.done(ctx) {
+		return false, nil
+	}
+
+	_, offset, env, err := s.store.next(s.config.ConsumerGroup, topic)
+	if err != nil || env == nil {
+		return false, err
+	}
+
+	msg := message.NewMessage(env.UUID, env.Payload)
+	for key, value := range env.Metadata {
+		msg.Metadata.Set(key, value)
+	}
+	msgCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	msg.SetContext(msgCtx)
+
+	select {
+	case output <- msg:
+	case <-ctx.Done():
+		return false, nil
+	case <-s.closing:
+		return false, nil
+	}
+
+	select {
+	case <-msg.Acked():
+		return true, s.store.ack(s.config.ConsumerGroup, topic, offset)
+	case <-msg.Nacked():
+		select {
+		case <-time.After(s.config.NakDelay):
+			return true, nil
+		case <-ctx.Done():
+		case <-s.closing:
+		}
+	case <-ctx.Done():
+	case <-s.closing:
+	}
+	return false, nil
+}
+
+func (s *Subscriber) done(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return true
+	case <-s.closing:
+		return true
+	case <-s.store.closing:
+		return true
+	default:
+		return false
+	}
+}
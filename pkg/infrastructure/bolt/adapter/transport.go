@@ -0,0 +1,15 @@
+package adapter
+
+import (
+	"github.com/ThreeDotsLabs/watermill/message"
+
+	watermillAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/watermill/adapter"
+)
+
+func BoltSubscriberFactory(store *Store, config SubscriberConfig) watermillAdapter.SubscriberFactory {
+	return func(consumerGroup string) (message.Subscriber, error) {
+		groupConfig := config
+		groupConfig.ConsumerGroup = consumerGroup
+		return NewSubscriber(store, groupConfig), nil
+	}
+}
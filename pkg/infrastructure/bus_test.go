@@ -0,0 +1,29 @@
+package infrastructure_test
+
+import (
+	"testing"
+
+	pkgInfra "github.com/mateusmacedo/go-bff/pkg/infrastructure"
+	"github.com/mateusmacedo/go-bff/pkg/testkit"
+	"github.com/mateusmacedo/go-bff/pkg/testkit/conformance"
+)
+
+func TestSimpleBuses(t *testing.T) {
+	config := conformance.Config{Delivery: conformance.Synchronous}
+
+	t.Run("command bus", func(t *testing.T) {
+		conformance.TestCommandBus(t, config, func(t *testing.T) conformance.CommandBus {
+			return pkgInfra.NewSimpleCommandBus[conformance.Command, conformance.Payload](testkit.NewLogger(t))
+		})
+	})
+	t.Run("query bus", func(t *testing.T) {
+		conformance.TestQueryBus(t, config, func(t *testing.T) conformance.QueryBus {
+			return pkgInfra.NewSimpleQueryBus[conformance.Query, conformance.Payload, conformance.Payload](testkit.NewLogger(t))
+		})
+	})
+	t.Run("event bus", func(t *testing.T) {
+		conformance.TestEventBus(t, config, func(t *testing.T) conformance.EventBus {
+			return pkgInfra.NewSimpleEventBus[conformance.Event, conformance.Payload](testkit.NewLogger(t))
+		})
+	})
+}
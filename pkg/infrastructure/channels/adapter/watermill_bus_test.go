@@ -0,0 +1,47 @@
+package adapter_test
+
+import (
+	"testing"
+
+	"github.com/ThreeDotsLabs/watermill/pubsub/gochannel"
+
+	channelsAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/channels/adapter"
+	watermillAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/watermill/adapter"
+	"github.com/mateusmacedo/go-bff/pkg/testkit"
+	"github.com/mateusmacedo/go-bff/pkg/testkit/conformance"
+)
+
+func TestWatermillBuses(t *testing.T) {
+	config := conformance.Config{Delivery: conformance.Asynchronous}
+
+	t.Run("command bus", func(t *testing.T) {
+		conformance.TestCommandBus(t, config, func(t *testing.T) conformance.CommandBus {
+			pubSub := newGoChannel(t)
+			bus := channelsAdapter.NewWatermillCommandBus[conformance.Command, conformance.Payload](pubSub, pubSub, testkit.NewLogger(t))
+			t.Cleanup(func() { bus.Close() })
+			return bus
+		})
+	})
+	t.Run("query bus", func(t *testing.T) {
+		conformance.TestQueryBus(t, config, func(t *testing.T) conformance.QueryBus {
+			pubSub := newGoChannel(t)
+			bus := channelsAdapter.NewWatermillQueryBus[conformance.Query, conformance.Payload, conformance.Payload](pubSub, pubSub, testkit.NewLogger(t))
+			t.Cleanup(func() { bus.Close() })
+			return bus
+		})
+	})
+	t.Run("event bus", func(t *testing.T) {
+		conformance.TestEventBus(t, config, func(t *testing.T) conformance.EventBus {
+			pubSub := newGoChannel(t)
+			bus := channelsAdapter.NewWatermillEventBus[conformance.Event, conformance.Payload](pubSub, pubSub, testkit.NewLogger(t))
+			t.Cleanup(func() { bus.Close() })
+			return bus
+		})
+	})
+}
+
+func newGoChannel(t *testing.T) *gochannel.GoChannel {
+	pubSub := gochannel.NewGoChannel(gochannel.Config{}, watermillAdapter.NewWatermillLoggerAdapter(testkit.NewLogger(t)))
+	t.Cleanup(func() { pubSub.Close() })
+	return pubSub
+}
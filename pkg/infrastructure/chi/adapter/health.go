@@ -0,0 +1,81 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+)
+
+const (
+	LivenessRoute  = "/healthz"
+	ReadinessRoute = "/readyz"
+)
+
+type healthReport struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// RegisterHealthRoutes serves liveness, which only tells the process is
+// running, and readiness, which runs every check within timeout.
+func RegisterHealthRoutes(router chi.Router, checks []application.HealthCheck, timeout time.Duration, logger application.AppLogger) {
+	router.Get(LivenessRoute, LivenessHandler())
+	router.Get(ReadinessRoute, ReadinessHandler(checks, timeout, logger))
+}
+
+func LivenessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeHealthReport(w, http.StatusOK, healthReport{Status: "ok"})
+	}
+}
+
+func ReadinessHandler(checks []application.HealthCheck, timeout time.Duration, logger application.AppLogger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		report := healthReport{Status: "ok", Checks: make(map[string]string, len(checks))}
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		for _, check := range checks {
+			wg.Add(1)
+			go func(check application.HealthCheck) {
+				defer wg.Done()
+				err := check.Check(ctx)
+
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					application.LogError(ctx, logger, "health check failed", err, map[string]interface{}{
+						"check": check.Name,
+					})
+					report.Status = "unavailable"
+					report.Checks[check.Name] = err.Error()
+					return
+				}
+				report.Checks[check.Name] = "ok"
+			}(check)
+		}
+		wg.Wait()
+
+		status := http.StatusOK
+		if report.Status != "ok" {
+			status = http.StatusServiceUnavailable
+		}
+		writeHealthReport(w, status, report)
+	}
+}
+
+func writeHealthReport(w http.ResponseWriter, status int, report healthReport) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,44 @@
+package adapter
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+)
+
+const (
+	PrincipalIDHeader              = "X-Principal-Id"
+	PrincipalRolesHeader           = "X-Principal-Roles"
+	PrincipalAttributeHeaderPrefix = "X-Principal-Attr-"
+)
+
+// PrincipalFromHeaders trusts identity headers as sent by the client, so
+// anyone can claim any identity. It is meant for tests and local
+// development only; servers verify identity with a JWT authenticator.
+func PrincipalFromHeaders(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principalID := r.Header.Get(PrincipalIDHeader)
+		if principalID == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		principal := application.Principal{
+			ID:         principalID,
+			Attributes: make(map[string]string),
+		}
+		for _, role := range strings.Split(r.Header.Get(PrincipalRolesHeader), ",") {
+			if role = strings.TrimSpace(role); role != "" {
+				principal.Roles = append(principal.Roles, role)
+			}
+		}
+		for header, values := range r.Header {
+			if key, found := strings.CutPrefix(header, PrincipalAttributeHeaderPrefix); found && len(values) > 0 {
+				principal.Attributes[strings.ToLower(strings.ReplaceAll(key, "-", "_"))] = values[0]
+			}
+		}
+
+		next.ServeHTTP(w, r.WithContext(application.WithPrincipal(r.Context(), principal)))
+	})
+}
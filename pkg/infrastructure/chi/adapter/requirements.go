@@ -0,0 +1,33 @@
+package adapter
+
+import (
+	"net/http"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+)
+
+func RequireAuthenticated(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := application.PrincipalFromContext(r.Context()); !ok {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "authentication required", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func RequireAnyRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return RequireAuthenticated(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, _ := application.PrincipalFromContext(r.Context())
+			for _, role := range roles {
+				if principal.HasRole(role) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+			http.Error(w, "forbidden", http.StatusForbidden)
+		}))
+	}
+}
@@ -0,0 +1,19 @@
+package adapter
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/mateusmacedo/go-bff/pkg/infrastructure"
+)
+
+// RoutingTableHandler shows which backend each message currently goes
+// through.
+func RoutingTableHandler(table infrastructure.RoutingTable) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(table); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package adapter
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+)
+
+const TenantIDHeader = "X-Tenant-Id"
+
+type TenantResolver func(r *http.Request) string
+
+func TenantFromHeader(header string) TenantResolver {
+	return func(r *http.Request) string {
+		return strings.TrimSpace(r.Header.Get(header))
+	}
+}
+
+func TenantFromSubdomain(baseDomain string) TenantResolver {
+	suffix := "." + strings.TrimPrefix(baseDomain, ".")
+	return func(r *http.Request) string {
+		host := r.Host
+		if i := strings.LastIndex(host, ":"); i > strings.LastIndex(host, "]") {
+			host = host[:i]
+		}
+		subdomain, found := strings.CutSuffix(host, suffix)
+		if !found || strings.Contains(subdomain, ".") {
+			return ""
+		}
+		return subdomain
+	}
+}
+
+func TenantFromPrincipal() TenantResolver {
+	return func(r *http.Request) string {
+		principal, _ := application.PrincipalFromContext(r.Context())
+		return principal.Attribute(application.TenantAttribute)
+	}
+}
+
+// ResolveTenant uses the first resolver that yields a tenant. A tenant bound
+// to the principal must match it; otherwise only principals with one of
+// operatorRoles may pick a tenant, and anyone else gets 403. Tenant IDs that
+// fail application.ValidateTenantID get 400.
+func ResolveTenant(operatorRoles []string, resolvers ...TenantResolver) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var tenantID string
+			for _, resolve := range resolvers {
+				if tenantID = resolve(r); tenantID != "" {
+					break
+				}
+			}
+			if tenantID == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if err := application.ValidateTenantID(tenantID); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			principal, _ := application.PrincipalFromContext(r.Context())
+			switch bound := principal.Attribute(application.TenantAttribute); {
+			case bound != "" && bound != tenantID:
+				http.Error(w, application.ErrTenantMismatch.Error(), http.StatusForbidden)
+				return
+			case bound == "" && !hasAnyRole(principal, operatorRoles):
+				http.Error(w, application.ErrTenantNotAllowed.Error(), http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(application.WithTenant(r.Context(), tenantID)))
+		})
+	}
+}
+
+func hasAnyRole(principal application.Principal, roles []string) bool {
+	for _, role := range roles {
+		if principal.HasRole(role) {
+			return true
+		}
+	}
+	return false
+}
+
+func RequireTenant(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := application.TenantFromContext(r.Context()); !ok {
+			http.Error(w, "tenant required", http.StatusBadRequest)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
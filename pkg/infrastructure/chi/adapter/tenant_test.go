@@ -0,0 +1,126 @@
+package adapter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	chiAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/chi/adapter"
+)
+
+func TestResolveTenant(t *testing.T) {
+	admin := &application.Principal{ID: "root", Roles: []string{"admin"}}
+	passenger := &application.Principal{ID: "ana", Roles: []string{"passenger"}}
+	boundPassenger := &application.Principal{ID: "bia", Roles: []string{"passenger"}, Attributes: map[string]string{application.TenantAttribute: "tenant-a"}}
+	boundAdmin := &application.Principal{ID: "ops", Roles: []string{"admin"}, Attributes: map[string]string{application.TenantAttribute: "tenant-a"}}
+	principalFirst := []chiAdapter.TenantResolver{chiAdapter.TenantFromPrincipal(), chiAdapter.TenantFromHeader(chiAdapter.TenantIDHeader)}
+	headerFirst := []chiAdapter.TenantResolver{chiAdapter.TenantFromHeader(chiAdapter.TenantIDHeader), chiAdapter.TenantFromPrincipal()}
+
+	tests := []struct {
+		name      string
+		resolvers []chiAdapter.TenantResolver
+		principal *application.Principal
+		header    string
+		status    int
+		tenant    string
+		body      string
+	}{
+		{name: "no tenant anywhere", resolvers: principalFirst, principal: passenger, status: http.StatusOK},
+		{name: "no tenant nor principal", resolvers: principalFirst, status: http.StatusOK},
+		{name: "tenant bound to the principal", resolvers: principalFirst, principal: boundPassenger, status: http.StatusOK, tenant: "tenant-a"},
+		{name: "bound tenant wins over the header", resolvers: principalFirst, principal: boundPassenger, header: "tenant-b", status: http.StatusOK, tenant: "tenant-a"},
+		{name: "header matching the bound tenant", resolvers: headerFirst, principal: boundPassenger, header: "tenant-a", status: http.StatusOK, tenant: "tenant-a"},
+		{name: "header against the bound tenant", resolvers: headerFirst, principal: boundPassenger, header: "tenant-b", status: http.StatusForbidden, body: application.ErrTenantMismatch.Error()},
+		{name: "operator against their bound tenant", resolvers: headerFirst, principal: boundAdmin, header: "tenant-b", status: http.StatusForbidden, body: application.ErrTenantMismatch.Error()},
+		{name: "operator override", resolvers: principalFirst, principal: admin, header: "tenant-b", status: http.StatusOK, tenant: "tenant-b"},
+		{name: "non-operator picking a tenant", resolvers: principalFirst, principal: passenger, header: "tenant-b", status: http.StatusForbidden, body: application.ErrTenantNotAllowed.Error()},
+		{name: "anonymous picking a tenant", resolvers: principalFirst, header: "tenant-b", status: http.StatusForbidden, body: application.ErrTenantNotAllowed.Error()},
+		{name: "invalid tenant", resolvers: principalFirst, principal: admin, header: "Tenant.B", status: http.StatusBadRequest, body: application.ErrInvalidTenant.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reached bool
+			var tenantID string
+			handler := chiAdapter.ResolveTenant([]string{"admin"}, tt.resolvers...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				reached = true
+				tenantID = application.TenantID(r.Context())
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.principal != nil {
+				r = r.WithContext(application.WithPrincipal(r.Context(), *tt.principal))
+			}
+			if tt.header != "" {
+				r.Header.Set(chiAdapter.TenantIDHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.status || !strings.Contains(w.Body.String(), tt.body) {
+				t.Fatalf("ResolveTenant() = %d %q, want %d %q", w.Code, w.Body.String(), tt.status, tt.body)
+			}
+			if reached != (tt.status == http.StatusOK) {
+				t.Fatalf("next handler reached = %v, want %v", reached, tt.status == http.StatusOK)
+			}
+			if tenantID != tt.tenant {
+				t.Fatalf("tenant = %q, want %q", tenantID, tt.tenant)
+			}
+		})
+	}
+}
+
+func TestRequireTenant(t *testing.T) {
+	handler := chiAdapter.ResolveTenant([]string{"admin"}, chiAdapter.TenantFromHeader(chiAdapter.TenantIDHeader))(
+		chiAdapter.RequireTenant(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})),
+	)
+	admin := application.Principal{ID: "root", Roles: []string{"admin"}}
+
+	tests := []struct {
+		name   string
+		header string
+		status int
+	}{
+		{name: "missing tenant", status: http.StatusBadRequest},
+		{name: "resolved tenant", header: "tenant-a", status: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r = r.WithContext(application.WithPrincipal(r.Context(), admin))
+			if tt.header != "" {
+				r.Header.Set(chiAdapter.TenantIDHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.status {
+				t.Fatalf("RequireTenant() = %d %q, want %d", w.Code, w.Body.String(), tt.status)
+			}
+		})
+	}
+}
+
+func TestTenantFromSubdomain(t *testing.T) {
+	resolve := chiAdapter.TenantFromSubdomain("bff.example.com")
+	tests := []struct {
+		host string
+		want string
+	}{
+		{host: "tenant-a.bff.example.com", want: "tenant-a"},
+		{host: "tenant-a.bff.example.com:8080", want: "tenant-a"},
+		{host: "bff.example.com"},
+		{host: "a.b.bff.example.com"},
+		{host: "tenant-a.other.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Host = tt.host
+			if got := resolve(r); got != tt.want {
+				t.Fatalf("TenantFromSubdomain(%q) = %q, want %q", tt.host, got, tt.want)
+			}
+		})
+	}
+}
@@ -22,13 +22,21 @@ func NewSimpleCommandBus[C domain.Command[D], D any](logger application.AppLogge
 	}
 }
 
-func (bus *simpleCommandBus[C, D]) RegisterHandler(commandName string, handler application.CommandHandler[C, D]) {
+func (bus *simpleCommandBus[C, D]) RegisterHandler(commandName string, handler application.CommandHandler[C, D]) error {
 	bus.mu.Lock()
 	defer bus.mu.Unlock()
 	bus.handlers[commandName] = handler
+	return nil
 }
 
 func (bus *simpleCommandBus[C, D]) Dispatch(ctx context.Context, command C) error {
+	if err := ctx.Err(); err != nil {
+		application.LogError(ctx, bus.logger, "context done", err, map[string]interface{}{
+			"command_name": command.CommandName(),
+		})
+		return err
+	}
+
 	bus.mu.RLock()
 	handler, found := bus.handlers[command.CommandName()]
 	bus.mu.RUnlock()
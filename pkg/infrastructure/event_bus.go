@@ -2,6 +2,7 @@ package infrastructure
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
 	"sync"
 
@@ -22,13 +23,21 @@ func NewSimpleEventBus[E domain.Event[T], T any](logger application.AppLogger) a
 	}
 }
 
-func (bus *simpleEventBus[E, T]) RegisterHandler(eventName string, handler application.EventHandler[E, T]) {
+func (bus *simpleEventBus[E, T]) RegisterHandler(eventName string, handler application.EventHandler[E, T]) error {
 	bus.mu.Lock()
 	defer bus.mu.Unlock()
 	bus.handlers[eventName] = append(bus.handlers[eventName], handler)
+	return nil
 }
 
 func (bus *simpleEventBus[E, T]) Publish(ctx context.Context, event E) error {
+	if err := ctx.Err(); err != nil {
+		application.LogError(ctx, bus.logger, "context done", err, map[string]interface{}{
+			"event_name": event.EventName(),
+		})
+		return err
+	}
+
 	bus.mu.RLock()
 	handlers, found := bus.handlers[event.EventName()]
 	bus.mu.RUnlock()
@@ -47,16 +56,6 @@ func (bus *simpleEventBus[E, T]) Publish(ctx context.Context, event E) error {
 	application.LogInfo(ctx, bus.logger, "publishing event", map[string]interface{}{
 		"event_name": event.EventName(),
 	})
-	go func() {
-		bus.logger.Info(ctx, "waiting for goroutines to finish", nil)
-		wg.Wait()
-		application.LogInfo(ctx, bus.logger, "all goroutines finished", nil)
-		close(errChan)
-		application.LogInfo(ctx, bus.logger, "error channel closed", nil)
-		close(done)
-		application.LogInfo(ctx, bus.logger, "done channel closed", nil)
-	}()
-
 	for _, handler := range handlers {
 		wg.Add(1)
 		go func(h application.EventHandler[E, T]) {
@@ -78,6 +77,16 @@ func (bus *simpleEventBus[E, T]) Publish(ctx context.Context, event E) error {
 		}(handler)
 	}
 
+	go func() {
+		bus.logger.Info(ctx, "waiting for goroutines to finish", nil)
+		wg.Wait()
+		application.LogInfo(ctx, bus.logger, "all goroutines finished", nil)
+		close(errChan)
+		application.LogInfo(ctx, bus.logger, "error channel closed", nil)
+		close(done)
+		application.LogInfo(ctx, bus.logger, "done channel closed", nil)
+	}()
+
 	select {
 	case <-ctx.Done():
 		application.LogError(ctx, bus.logger, "context done", ctx.Err(), nil)
@@ -99,7 +108,7 @@ func (bus *simpleEventBus[E, T]) collectErrors(ctx context.Context, errChan <-ch
 		application.LogError(ctx, bus.logger, "errors publishing event", nil, map[string]interface{}{
 			"errors": errors,
 		})
-		return fmt.Errorf("errors: %v", errors)
+		return fmt.Errorf("errors publishing event: %w", stderrors.Join(errors...))
 	}
 	return nil
 }
@@ -0,0 +1,192 @@
+package adapter_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	grpcAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/grpc/adapter"
+	"github.com/mateusmacedo/go-bff/pkg/testkit"
+	"github.com/mateusmacedo/go-bff/pkg/testkit/conformance"
+)
+
+func TestBusServerToken(t *testing.T) {
+	r := newSecuredRemote(t, grpcAdapter.ServerConfig{Token: "s3cret"}, grpcAdapter.ClientConfig{Token: "s3cret"})
+	handled := make(chan conformance.Payload, 1)
+	r.serveCommand(t, "Reserve", func(_ context.Context, command conformance.Command) error {
+		handled <- command.Payload()
+		return nil
+	})
+	r.serveEvents(t, "Booked")
+
+	t.Run("serves callers with the token", func(t *testing.T) {
+		if err := r.commandBus(t).Dispatch(context.Background(), conformance.NewCommand("Reserve", conformance.Payload{ID: "1"})); err != nil {
+			t.Fatalf("Dispatch() error = %v", err)
+		}
+		<-handled
+	})
+
+	for name, token := range map[string]string{"without a token": "", "with another token": "guess"} {
+		t.Run("refuses calls "+name, func(t *testing.T) {
+			conn := r.dial(t, grpcAdapter.ClientConfig{Token: token})
+			commands := grpcAdapter.NewGRPCCommandBus[conformance.Command, conformance.Payload](conn, testkit.NewLogger(t))
+			if err := commands.Dispatch(context.Background(), conformance.NewCommand("Reserve", conformance.Payload{ID: "1"})); !errors.Is(err, grpcAdapter.ErrUnauthenticated) {
+				t.Fatalf("Dispatch() error = %v, want %v", err, grpcAdapter.ErrUnauthenticated)
+			}
+
+			// Subscriptions are refused too, so no event reaches the handler.
+			events := grpcAdapter.NewGRPCEventBus[conformance.Event, conformance.Payload](conn, grpcAdapter.EventBusConfig{ReadyTimeout: 100 * time.Millisecond}, testkit.NewLogger(t))
+			t.Cleanup(func() { events.Close() })
+			received := make(chan struct{}, 1)
+			if err := events.RegisterHandler("Booked", eventHandlerFunc(func(context.Context, conformance.Event) error {
+				received <- struct{}{}
+				return nil
+			})); err != nil {
+				t.Fatalf("RegisterHandler() error = %v", err)
+			}
+			if err := r.events.Publish(context.Background(), conformance.NewEvent("Booked", conformance.Payload{ID: "1"})); err != nil {
+				t.Fatalf("Publish() error = %v", err)
+			}
+			select {
+			case <-received:
+				t.Fatalf("event streamed to an unauthenticated subscriber")
+			case <-time.After(200 * time.Millisecond):
+			}
+			if len(handled) != 0 {
+				t.Fatalf("handler ran for an unauthenticated call")
+			}
+		})
+	}
+}
+
+func TestBusServerMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := newCertificateAuthority(t)
+	serverCert, serverKey := ca.issue(t, dir, "server", "bus.test")
+	clientCert, clientKey := ca.issue(t, dir, "client", "")
+	caFile := filepath.Join(dir, "ca.pem")
+	writePEM(t, caFile, "CERTIFICATE", ca.certificate.Raw)
+
+	serverTLS, err := grpcAdapter.NewServerTLS(grpcAdapter.TLSConfig{CertFile: serverCert, KeyFile: serverKey, CAFile: caFile})
+	if err != nil {
+		t.Fatalf("NewServerTLS() error = %v", err)
+	}
+	clientTLS, err := grpcAdapter.NewClientTLS(grpcAdapter.TLSConfig{CertFile: clientCert, KeyFile: clientKey, CAFile: caFile, ServerName: "bus.test"})
+	if err != nil {
+		t.Fatalf("NewClientTLS() error = %v", err)
+	}
+	r := newSecuredRemote(t, grpcAdapter.ServerConfig{Credentials: serverTLS}, grpcAdapter.ClientConfig{Credentials: clientTLS})
+	r.serveCommand(t, "Reserve", func(context.Context, conformance.Command) error { return nil })
+
+	t.Run("serves clients with a certificate of the CA", func(t *testing.T) {
+		if err := r.commandBus(t).Dispatch(context.Background(), conformance.NewCommand("Reserve", conformance.Payload{})); err != nil {
+			t.Fatalf("Dispatch() error = %v", err)
+		}
+	})
+
+	t.Run("refuses clients without a certificate", func(t *testing.T) {
+		anonymousTLS, err := grpcAdapter.NewClientTLS(grpcAdapter.TLSConfig{CAFile: caFile, ServerName: "bus.test"})
+		if err != nil {
+			t.Fatalf("NewClientTLS() error = %v", err)
+		}
+		conn := r.dial(t, grpcAdapter.ClientConfig{Credentials: anonymousTLS})
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		commands := grpcAdapter.NewGRPCCommandBus[conformance.Command, conformance.Payload](conn, testkit.NewLogger(t))
+		if err := commands.Dispatch(ctx, conformance.NewCommand("Reserve", conformance.Payload{})); err == nil {
+			t.Fatalf("Dispatch() without a client certificate succeeded")
+		}
+	})
+
+	t.Run("needs a certificate to serve", func(t *testing.T) {
+		if _, err := grpcAdapter.NewServerTLS(grpcAdapter.TLSConfig{CAFile: caFile}); err == nil {
+			t.Fatalf("NewServerTLS() without a certificate succeeded")
+		}
+	})
+}
+
+type certificateAuthority struct {
+	certificate *x509.Certificate
+	key         *ecdsa.PrivateKey
+}
+
+func newCertificateAuthority(t *testing.T) *certificateAuthority {
+	t.Helper()
+	key := newKey(t)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "bus test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatalf("CreateCertificate() error = %v", err)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("ParseCertificate() error = %v", err)
+	}
+	return &certificateAuthority{certificate: certificate, key: key}
+}
+
+// issue writes a certificate signed by ca and its key to dir. Certificates
+// with a dnsName are for servers, the others for clients.
+func (ca *certificateAuthority) issue(t *testing.T, dir, name, dnsName string) (certFile, keyFile string) {
+	t.Helper()
+	key := newKey(t)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if dnsName != "" {
+		template.DNSNames = []string{dnsName}
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.certificate, key.Public(), ca.key)
+	if err != nil {
+		t.Fatalf("CreateCertificate() error = %v", err)
+	}
+	encodedKey, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalECPrivateKey() error = %v", err)
+	}
+
+	certFile, keyFile = filepath.Join(dir, name+".pem"), filepath.Join(dir, name+"-key.pem")
+	writePEM(t, certFile, "CERTIFICATE", der)
+	writePEM(t, keyFile, "EC PRIVATE KEY", encodedKey)
+	return certFile, keyFile
+}
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	return key
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatalf("WriteFile(%s) error = %v", path, err)
+	}
+}
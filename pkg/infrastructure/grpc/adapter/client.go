@@ -0,0 +1,74 @@
+package adapter
+
+import (
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+)
+
+type ClientConfig struct {
+	Target string
+	// Credentials defaults to an insecure transport.
+	Credentials credentials.TransportCredentials
+	// Token, when set, is sent with every call to servers that require it.
+	Token string
+	// MaxAttempts bounds retries of calls that never reached the server
+	// (UNAVAILABLE); handlers are never run twice by a retry.
+	MaxAttempts      int
+	KeepAliveTime    time.Duration
+	KeepAliveTimeout time.Duration
+	DialOptions      []grpc.DialOption
+}
+
+func (c ClientConfig) withDefaults() ClientConfig {
+	if c.Credentials == nil {
+		c.Credentials = insecure.NewCredentials()
+	}
+	if c.MaxAttempts < 2 {
+		c.MaxAttempts = 3
+	}
+	if c.KeepAliveTime <= 0 {
+		c.KeepAliveTime = 30 * time.Second
+	}
+	if c.KeepAliveTimeout <= 0 {
+		c.KeepAliveTimeout = 10 * time.Second
+	}
+	return c
+}
+
+// NewClientConn connects lazily: the connection is established on the first
+// call and re-established in the background whenever it drops.
+func NewClientConn(config ClientConfig) (*grpc.ClientConn, error) {
+	config = config.withDefaults()
+	serviceConfig := fmt.Sprintf(`{
+		"methodConfig": [{
+			"name": [{"service": %q}],
+			"retryPolicy": {
+				"maxAttempts": %d,
+				"initialBackoff": "0.1s",
+				"maxBackoff": "2s",
+				"backoffMultiplier": 2,
+				"retryableStatusCodes": ["UNAVAILABLE"]
+			}
+		}]
+	}`, busServiceName, config.MaxAttempts)
+
+	options := append([]grpc.DialOption{
+		grpc.WithTransportCredentials(config.Credentials),
+		grpc.WithDefaultServiceConfig(serviceConfig),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(jsonCodec{})),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                config.KeepAliveTime,
+			Timeout:             config.KeepAliveTimeout,
+			PermitWithoutStream: true,
+		}),
+	}, config.DialOptions...)
+	if config.Token != "" {
+		options = append(options, grpc.WithPerRPCCredentials(tokenCredentials{token: config.Token}))
+	}
+	return grpc.NewClient(config.Target, options...)
+}
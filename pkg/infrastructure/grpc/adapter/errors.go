@@ -0,0 +1,70 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	"github.com/mateusmacedo/go-bff/pkg/domain"
+)
+
+var ErrNoRemoteHandler = errors.New("no handler registered on the bus server")
+
+func toStatus(err error) error {
+	code := codes.Unknown
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case errors.Is(err, application.ErrForbidden), errors.Is(err, application.ErrTenantMismatch):
+		code = codes.PermissionDenied
+	case errors.Is(err, domain.ErrNotFound):
+		code = codes.NotFound
+	case errors.Is(err, domain.ErrInvalid):
+		code = codes.InvalidArgument
+	case errors.Is(err, domain.ErrConflict):
+		code = codes.AlreadyExists
+	case errors.Is(err, domain.ErrConcurrencyConflict):
+		code = codes.Aborted
+	case errors.Is(err, ErrNoRemoteHandler):
+		code = codes.Unimplemented
+	case errors.Is(err, ErrUnauthenticated):
+		code = codes.Unauthenticated
+	}
+	return status.Error(code, err.Error())
+}
+
+// fromStatus turns the codes set by toStatus back into the sentinel errors
+// callers of the bus match on.
+func fromStatus(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	switch st.Code() {
+	case codes.DeadlineExceeded:
+		return fmt.Errorf("%s: %w", st.Message(), context.DeadlineExceeded)
+	case codes.Canceled:
+		return fmt.Errorf("%s: %w", st.Message(), context.Canceled)
+	case codes.PermissionDenied:
+		return fmt.Errorf("%s: %w", st.Message(), application.ErrForbidden)
+	case codes.NotFound:
+		return fmt.Errorf("%s: %w", st.Message(), domain.ErrNotFound)
+	case codes.InvalidArgument:
+		return fmt.Errorf("%s: %w", st.Message(), domain.ErrInvalid)
+	case codes.AlreadyExists:
+		return fmt.Errorf("%s: %w", st.Message(), domain.ErrConflict)
+	case codes.Aborted:
+		return fmt.Errorf("%s: %w", st.Message(), domain.ErrConcurrencyConflict)
+	case codes.Unimplemented:
+		return fmt.Errorf("%s: %w", st.Message(), ErrNoRemoteHandler)
+	case codes.Unauthenticated:
+		return fmt.Errorf("%s: %w", st.Message(), ErrUnauthenticated)
+	}
+	return errors.New(st.Message())
+}
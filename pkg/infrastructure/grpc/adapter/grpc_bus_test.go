@@ -0,0 +1,336 @@
+package adapter_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	"github.com/mateusmacedo/go-bff/pkg/domain"
+	pkgInfra "github.com/mateusmacedo/go-bff/pkg/infrastructure"
+	grpcAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/grpc/adapter"
+	"github.com/mateusmacedo/go-bff/pkg/testkit"
+	"github.com/mateusmacedo/go-bff/pkg/testkit/conformance"
+)
+
+func TestGRPCCommandBus(t *testing.T) {
+	t.Run("dispatches to the served bus with the caller's principal and tenant", func(t *testing.T) {
+		r := newRemote(t)
+		var received call
+		r.serveCommand(t, "Reserve", func(ctx context.Context, command conformance.Command) error {
+			received.record(ctx, command.Payload())
+			return nil
+		})
+
+		ctx := application.WithTenant(application.WithPrincipal(context.Background(), application.Principal{ID: "ana"}), "tenant-a")
+		if err := r.commandBus(t).Dispatch(ctx, conformance.NewCommand("Reserve", conformance.Payload{ID: "1", Value: 42})); err != nil {
+			t.Fatalf("Dispatch() error = %v", err)
+		}
+		received.expect(t, conformance.Payload{ID: "1", Value: 42}, "ana", "tenant-a")
+	})
+
+	t.Run("refuses commands the server does not serve", func(t *testing.T) {
+		r := newRemote(t)
+		if err := r.commandBus(t).Dispatch(context.Background(), conformance.NewCommand("Unknown", conformance.Payload{})); !errors.Is(err, grpcAdapter.ErrNoRemoteHandler) {
+			t.Fatalf("Dispatch() error = %v, want %v", err, grpcAdapter.ErrNoRemoteHandler)
+		}
+	})
+
+	t.Run("propagates the deadline of the caller", func(t *testing.T) {
+		r := newRemote(t)
+		deadlines := make(chan time.Time, 1)
+		r.serveCommand(t, "Slow", func(ctx context.Context, _ conformance.Command) error {
+			deadline, _ := ctx.Deadline()
+			deadlines <- deadline
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		want, _ := ctx.Deadline()
+		if err := r.commandBus(t).Dispatch(ctx, conformance.NewCommand("Slow", conformance.Payload{})); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Dispatch() error = %v, want %v", err, context.DeadlineExceeded)
+		}
+		// The deadline travels as a timeout, so it is only kept approximately.
+		if got := <-deadlines; got.IsZero() || got.Sub(want).Abs() > 50*time.Millisecond {
+			t.Fatalf("handler deadline = %v, want about %v", got, want)
+		}
+	})
+
+	t.Run("refuses a cancelled context without calling the server", func(t *testing.T) {
+		r := newRemote(t)
+		var calls int
+		r.serveCommand(t, "Reserve", func(context.Context, conformance.Command) error {
+			calls++
+			return nil
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if err := r.commandBus(t).Dispatch(ctx, conformance.NewCommand("Reserve", conformance.Payload{})); !errors.Is(err, context.Canceled) {
+			t.Fatalf("Dispatch() error = %v, want %v", err, context.Canceled)
+		}
+		if calls != 0 {
+			t.Fatalf("handler called %d times", calls)
+		}
+	})
+}
+
+func TestGRPCQueryBus(t *testing.T) {
+	t.Run("returns the result of the served bus", func(t *testing.T) {
+		r := newRemote(t)
+		var received call
+		r.serveQuery(t, "Find", func(ctx context.Context, query conformance.Query) (conformance.Payload, error) {
+			received.record(ctx, query.Payload())
+			return conformance.Payload{ID: query.Payload().ID, Value: 7}, nil
+		})
+
+		ctx := application.WithTenant(application.WithPrincipal(context.Background(), application.Principal{ID: "ana"}), "tenant-a")
+		result, err := r.queryBus(t).Dispatch(ctx, conformance.NewQuery("Find", conformance.Payload{ID: "1"}))
+		if err != nil || result != (conformance.Payload{ID: "1", Value: 7}) {
+			t.Fatalf("Dispatch() = %+v, %v, want {1 7}", result, err)
+		}
+		received.expect(t, conformance.Payload{ID: "1"}, "ana", "tenant-a")
+	})
+
+	t.Run("refuses queries the server does not serve", func(t *testing.T) {
+		r := newRemote(t)
+		if _, err := r.queryBus(t).Dispatch(context.Background(), conformance.NewQuery("Unknown", conformance.Payload{})); !errors.Is(err, grpcAdapter.ErrNoRemoteHandler) {
+			t.Fatalf("Dispatch() error = %v, want %v", err, grpcAdapter.ErrNoRemoteHandler)
+		}
+	})
+
+	t.Run("propagates the deadline of the caller", func(t *testing.T) {
+		r := newRemote(t)
+		r.serveQuery(t, "Slow", func(ctx context.Context, _ conformance.Query) (conformance.Payload, error) {
+			if _, ok := ctx.Deadline(); !ok {
+				return conformance.Payload{}, errors.New("no deadline")
+			}
+			<-ctx.Done()
+			return conformance.Payload{}, ctx.Err()
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		if _, err := r.queryBus(t).Dispatch(ctx, conformance.NewQuery("Slow", conformance.Payload{})); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Dispatch() error = %v, want %v", err, context.DeadlineExceeded)
+		}
+	})
+}
+
+func TestGRPCEventBus(t *testing.T) {
+	conformance.TestEventBus(t, conformance.Config{Delivery: conformance.Asynchronous}, func(t *testing.T) conformance.EventBus {
+		r := newRemote(t)
+		r.serveEvents(t, "ConformanceFanOut", "ConformanceNobody", "ConformanceFail", "ConformanceConcurrent", "ConformanceCancelled")
+		return r.eventBus(t)
+	})
+
+	t.Run("streams the events published on the server", func(t *testing.T) {
+		r := newRemote(t)
+		r.serveEvents(t, "Booked")
+		received := make(chan conformance.Payload, 1)
+		if err := r.eventBus(t).RegisterHandler("Booked", eventHandlerFunc(func(_ context.Context, event conformance.Event) error {
+			received <- event.Payload()
+			return nil
+		})); err != nil {
+			t.Fatalf("RegisterHandler() error = %v", err)
+		}
+
+		if err := r.events.Publish(context.Background(), conformance.NewEvent("Booked", conformance.Payload{ID: "1"})); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+		select {
+		case payload := <-received:
+			if payload.ID != "1" {
+				t.Fatalf("handler received %+v, want event 1", payload)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("event not streamed")
+		}
+	})
+
+	t.Run("refuses events the server does not serve", func(t *testing.T) {
+		r := newRemote(t)
+		if err := r.eventBus(t).Publish(context.Background(), conformance.NewEvent("Unknown", conformance.Payload{})); !errors.Is(err, grpcAdapter.ErrNoRemoteHandler) {
+			t.Fatalf("Publish() error = %v, want %v", err, grpcAdapter.ErrNoRemoteHandler)
+		}
+	})
+}
+
+func TestStatusMapping(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"not found", fmt.Errorf("ticket t1: %w", domain.ErrNotFound), domain.ErrNotFound},
+		{"invalid", fmt.Errorf("seat 0: %w", domain.ErrInvalid), domain.ErrInvalid},
+		{"conflict", fmt.Errorf("seat 7: %w", domain.ErrConflict), domain.ErrConflict},
+		{"concurrency conflict", domain.ErrConcurrencyConflict, domain.ErrConcurrencyConflict},
+		{"forbidden", application.ErrForbidden, application.ErrForbidden},
+		{"tenant mismatch", application.ErrTenantMismatch, application.ErrForbidden},
+		{"deadline exceeded", context.DeadlineExceeded, context.DeadlineExceeded},
+		{"canceled", context.Canceled, context.Canceled},
+	}
+
+	r := newRemote(t)
+	for _, tt := range tests {
+		err := tt.err
+		r.serveCommand(t, tt.name, func(context.Context, conformance.Command) error { return err })
+	}
+	bus := r.commandBus(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := bus.Dispatch(context.Background(), conformance.NewCommand(tt.name, conformance.Payload{}))
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Dispatch() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	t.Run("keeps the message of other errors", func(t *testing.T) {
+		r.serveCommand(t, "Broken", func(context.Context, conformance.Command) error { return errors.New("disk full") })
+		err := bus.Dispatch(context.Background(), conformance.NewCommand("Broken", conformance.Payload{}))
+		if err == nil || err.Error() != "disk full" {
+			t.Fatalf("Dispatch() error = %v, want disk full", err)
+		}
+	})
+}
+
+// remote is a BusServer over in-process buses, reached through bufconn.
+type remote struct {
+	server   *grpcAdapter.BusServer
+	commands conformance.CommandBus
+	queries  conformance.QueryBus
+	events   conformance.EventBus
+	listener *bufconn.Listener
+	conn     *grpc.ClientConn
+}
+
+func newRemote(t *testing.T) *remote {
+	return newSecuredRemote(t, grpcAdapter.ServerConfig{}, grpcAdapter.ClientConfig{})
+}
+
+// newSecuredRemote serves with serverConfig and connects r.conn with
+// clientConfig.
+func newSecuredRemote(t *testing.T, serverConfig grpcAdapter.ServerConfig, clientConfig grpcAdapter.ClientConfig) *remote {
+	t.Helper()
+	logger := testkit.NewLogger(t)
+	r := &remote{
+		server:   grpcAdapter.NewBusServer(logger),
+		commands: pkgInfra.NewSimpleCommandBus[conformance.Command, conformance.Payload](logger),
+		queries:  pkgInfra.NewSimpleQueryBus[conformance.Query, conformance.Payload, conformance.Payload](logger),
+		events:   pkgInfra.NewSimpleEventBus[conformance.Event, conformance.Payload](logger),
+		listener: bufconn.Listen(1 << 20),
+	}
+
+	server := grpc.NewServer(grpcAdapter.ServerOptions(serverConfig)...)
+	r.server.Register(server)
+	go server.Serve(r.listener)
+	t.Cleanup(server.Stop)
+
+	r.conn = r.dial(t, clientConfig)
+	return r
+}
+
+func (r *remote) dial(t *testing.T, config grpcAdapter.ClientConfig) *grpc.ClientConn {
+	t.Helper()
+	config.Target = "passthrough:///bufconn"
+	config.DialOptions = append(config.DialOptions, grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return r.listener.DialContext(ctx)
+	}))
+	conn, err := grpcAdapter.NewClientConn(config)
+	if err != nil {
+		t.Fatalf("NewClientConn() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func (r *remote) serveCommand(t *testing.T, name string, handle func(context.Context, conformance.Command) error) {
+	t.Helper()
+	if err := r.commands.RegisterHandler(name, commandHandlerFunc(handle)); err != nil {
+		t.Fatalf("RegisterHandler() error = %v", err)
+	}
+	grpcAdapter.ServeCommands(r.server, r.commands, name)
+}
+
+func (r *remote) serveQuery(t *testing.T, name string, handle func(context.Context, conformance.Query) (conformance.Payload, error)) {
+	t.Helper()
+	if err := r.queries.RegisterHandler(name, queryHandlerFunc(handle)); err != nil {
+		t.Fatalf("RegisterHandler() error = %v", err)
+	}
+	grpcAdapter.ServeQueries(r.server, r.queries, name)
+}
+
+func (r *remote) serveEvents(t *testing.T, names ...string) {
+	t.Helper()
+	if err := grpcAdapter.ServeEvents(r.server, r.events, names...); err != nil {
+		t.Fatalf("ServeEvents() error = %v", err)
+	}
+}
+
+func (r *remote) commandBus(t *testing.T) conformance.CommandBus {
+	return grpcAdapter.NewGRPCCommandBus[conformance.Command, conformance.Payload](r.conn, testkit.NewLogger(t))
+}
+
+func (r *remote) queryBus(t *testing.T) conformance.QueryBus {
+	return grpcAdapter.NewGRPCQueryBus[conformance.Query, conformance.Payload, conformance.Payload](r.conn, testkit.NewLogger(t))
+}
+
+func (r *remote) eventBus(t *testing.T) conformance.EventBus {
+	bus := grpcAdapter.NewGRPCEventBus[conformance.Event, conformance.Payload](r.conn, grpcAdapter.EventBusConfig{}, testkit.NewLogger(t))
+	t.Cleanup(func() { bus.Close() })
+	return bus
+}
+
+// call records what a served handler received.
+type call struct {
+	mu        sync.Mutex
+	payload   conformance.Payload
+	principal string
+	tenant    string
+}
+
+func (c *call) record(ctx context.Context, payload conformance.Payload) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	principal, _ := application.PrincipalFromContext(ctx)
+	c.payload, c.principal, c.tenant = payload, principal.ID, application.TenantID(ctx)
+}
+
+func (c *call) expect(t *testing.T, payload conformance.Payload, principal, tenant string) {
+	t.Helper()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.payload != payload || c.principal != principal || c.tenant != tenant {
+		t.Fatalf("handler received %+v as %q in tenant %q, want %+v as %q in tenant %q", c.payload, c.principal, c.tenant, payload, principal, tenant)
+	}
+}
+
+type commandHandlerFunc func(ctx context.Context, command conformance.Command) error
+
+func (f commandHandlerFunc) Handle(ctx context.Context, command conformance.Command) error {
+	return f(ctx, command)
+}
+
+type queryHandlerFunc func(ctx context.Context, query conformance.Query) (conformance.Payload, error)
+
+func (f queryHandlerFunc) Handle(ctx context.Context, query conformance.Query) (conformance.Payload, error) {
+	return f(ctx, query)
+}
+
+type eventHandlerFunc func(ctx context.Context, event conformance.Event) error
+
+func (f eventHandlerFunc) Handle(ctx context.Context, event conformance.Event) error {
+	return f(ctx, event)
+}
@@ -0,0 +1,75 @@
+package adapter
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	"github.com/mateusmacedo/go-bff/pkg/domain"
+	watermillAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/watermill/adapter"
+)
+
+// GRPCCommandBus forwards commands to a BusServer; the deadline of ctx
+// travels with the call and Dispatch returns the remote handler's error.
+type GRPCCommandBus[C domain.Command[T], T any] struct {
+	conn   grpc.ClientConnInterface
+	logger application.AppLogger
+}
+
+func NewGRPCCommandBus[C domain.Command[T], T any](conn grpc.ClientConnInterface, logger application.AppLogger) *GRPCCommandBus[C, T] {
+	return &GRPCCommandBus[C, T]{
+		conn:   conn,
+		logger: logger,
+	}
+}
+
+// RegisterHandler only logs: commands are handled where the BusServer runs.
+func (bus *GRPCCommandBus[C, T]) RegisterHandler(commandName string, _ application.CommandHandler[C, T]) error {
+	application.LogInfo(context.Background(), bus.logger, "command handled by remote bus server", map[string]interface{}{
+		"command_name": commandName,
+	})
+	return nil
+}
+
+func (bus *GRPCCommandBus[C, T]) Dispatch(ctx context.Context, command C) error {
+	if err := ctx.Err(); err != nil {
+		application.LogError(ctx, bus.logger, "context done", err, map[string]interface{}{
+			"command_name": command.CommandName(),
+		})
+		return err
+	}
+
+	envelope, err := newEnvelope(ctx, command.CommandName(), command.Payload())
+	if err != nil {
+		application.LogError(ctx, bus.logger, "error encoding command", err, map[string]interface{}{
+			"command_name": command.CommandName(),
+		})
+		return err
+	}
+
+	if err := bus.conn.Invoke(ctx, dispatchMethod, envelope, &Ack{}, grpc.ForceCodec(jsonCodec{})); err != nil {
+		err = fromStatus(err)
+		application.LogError(ctx, bus.logger, "error dispatching remote command", err, map[string]interface{}{
+			"command_name": command.CommandName(),
+		})
+		return err
+	}
+
+	application.LogInfo(ctx, bus.logger, "command dispatched", map[string]interface{}{
+		"command_name": command.CommandName(),
+	})
+	return nil
+}
+
+func newEnvelope(ctx context.Context, name string, payload interface{}) (*Envelope, error) {
+	encoded, err := (watermillAdapter.JSONMarshaler{}).Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	envelope := &Envelope{Name: name, Metadata: map[string]string{}, Payload: encoded}
+	if err := watermillAdapter.InjectMetadata(ctx, envelope.Metadata); err != nil {
+		return nil, err
+	}
+	return envelope, nil
+}
@@ -0,0 +1,238 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	"github.com/mateusmacedo/go-bff/pkg/domain"
+	watermillAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/watermill/adapter"
+)
+
+type EventBusConfig struct {
+	// HandlerAttempts is how many times a failing local handler is retried
+	// for one streamed event; the stream has no acks to redeliver with.
+	HandlerAttempts int
+	RetryDelay      time.Duration
+	// ReadyTimeout bounds how long RegisterHandler waits for the first
+	// subscription before returning and retrying in the background.
+	ReadyTimeout time.Duration
+}
+
+func (c EventBusConfig) withDefaults() EventBusConfig {
+	if c.HandlerAttempts <= 0 {
+		c.HandlerAttempts = 3
+	}
+	if c.RetryDelay <= 0 {
+		c.RetryDelay = 100 * time.Millisecond
+	}
+	if c.ReadyTimeout <= 0 {
+		c.ReadyTimeout = 5 * time.Second
+	}
+	return c
+}
+
+// GRPCEventBus publishes through a BusServer and runs local handlers for the
+// events the server streams back. Events published while no stream is open
+// are not replayed.
+type GRPCEventBus[E domain.Event[D], D any] struct {
+	conn     grpc.ClientConnInterface
+	config   EventBusConfig
+	handlers map[string][]application.EventHandler[E, D]
+	mu       sync.RWMutex
+	logger   application.AppLogger
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+func NewGRPCEventBus[E domain.Event[D], D any](conn grpc.ClientConnInterface, config EventBusConfig, logger application.AppLogger) *GRPCEventBus[E, D] {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &GRPCEventBus[E, D]{
+		conn:     conn,
+		config:   config.withDefaults(),
+		handlers: make(map[string][]application.EventHandler[E, D]),
+		logger:   logger,
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+func (bus *GRPCEventBus[E, D]) RegisterHandler(eventName string, handler application.EventHandler[E, D]) error {
+	bus.mu.Lock()
+	_, subscribed := bus.handlers[eventName]
+	bus.handlers[eventName] = append(bus.handlers[eventName], handler)
+	bus.mu.Unlock()
+	if subscribed {
+		return nil
+	}
+
+	ready := make(chan struct{})
+	bus.wg.Add(1)
+	go func() {
+		defer bus.wg.Done()
+		bus.subscribe(eventName, ready)
+	}()
+
+	select {
+	case <-ready:
+	case <-time.After(bus.config.ReadyTimeout):
+		application.LogError(bus.ctx, bus.logger, "event subscription not ready, retrying in background", nil, map[string]interface{}{
+			"event_name": eventName,
+		})
+	}
+	return nil
+}
+
+func (bus *GRPCEventBus[E, D]) Publish(ctx context.Context, event E) error {
+	if err := ctx.Err(); err != nil {
+		application.LogError(ctx, bus.logger, "context done", err, map[string]interface{}{
+			"event_name": event.EventName(),
+		})
+		return err
+	}
+
+	envelope, err := newEnvelope(ctx, event.EventName(), event.Payload())
+	if err != nil {
+		application.LogError(ctx, bus.logger, "error encoding event", err, map[string]interface{}{
+			"event_name": event.EventName(),
+		})
+		return err
+	}
+
+	if err := bus.conn.Invoke(ctx, publishMethod, envelope, &Ack{}, grpc.ForceCodec(jsonCodec{})); err != nil {
+		err = fromStatus(err)
+		application.LogError(ctx, bus.logger, "error publishing remote event", err, map[string]interface{}{
+			"event_name": event.EventName(),
+		})
+		return err
+	}
+
+	application.LogInfo(ctx, bus.logger, "event published", map[string]interface{}{
+		"event_name": event.EventName(),
+	})
+	return nil
+}
+
+func (bus *GRPCEventBus[E, D]) Close() error {
+	bus.cancel()
+	bus.wg.Wait()
+	return nil
+}
+
+// subscribe keeps a stream open for eventName until the bus is closed,
+// reconnecting with backoff; ready is closed on the first confirmation.
+func (bus *GRPCEventBus[E, D]) subscribe(eventName string, ready chan struct{}) {
+	var once sync.Once
+	backoff := 100 * time.Millisecond
+	for {
+		err := bus.stream(eventName, func() {
+			once.Do(func() { close(ready) })
+			backoff = 100 * time.Millisecond
+		})
+		if bus.ctx.Err() != nil {
+			return
+		}
+		application.LogError(bus.ctx, bus.logger, "event subscription lost", err, map[string]interface{}{
+			"event_name": eventName,
+		})
+
+		select {
+		case <-bus.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > 30*time.Second {
+			backoff = 30 * time.Second
+		}
+	}
+}
+
+func (bus *GRPCEventBus[E, D]) stream(eventName string, confirmed func()) error {
+	stream, err := bus.conn.NewStream(bus.ctx, &busServiceDesc.Streams[0], subscribeMethod, grpc.ForceCodec(jsonCodec{}))
+	if err != nil {
+		return fromStatus(err)
+	}
+	if err := stream.SendMsg(&SubscribeRequest{Names: []string{eventName}}); err != nil {
+		return fromStatus(err)
+	}
+	if err := stream.CloseSend(); err != nil {
+		return fromStatus(err)
+	}
+
+	for {
+		envelope := new(Envelope)
+		if err := stream.RecvMsg(envelope); err != nil {
+			return fromStatus(err)
+		}
+		if envelope.Name == "" {
+			confirmed()
+			continue
+		}
+		bus.wg.Add(1)
+		go func() {
+			defer bus.wg.Done()
+			bus.handle(envelope)
+		}()
+	}
+}
+
+func (bus *GRPCEventBus[E, D]) handle(envelope *Envelope) {
+	ctx, err := watermillAdapter.ExtractMetadata(bus.ctx, envelope.Metadata)
+	if err != nil {
+		application.LogError(ctx, bus.logger, "error extracting event metadata", err, map[string]interface{}{
+			"event_name": envelope.Name,
+		})
+		return
+	}
+
+	var payload D
+	if err := (watermillAdapter.JSONMarshaler{}).Unmarshal(envelope.Payload, &payload); err != nil {
+		application.LogError(ctx, bus.logger, "error unmarshalling event payload", err, map[string]interface{}{
+			"event_name": envelope.Name,
+		})
+		return
+	}
+
+	event, ok := interface{}(&dynamicEvent[D]{eventName: envelope.Name, payload: payload}).(E)
+	if !ok {
+		application.LogError(ctx, bus.logger, "error asserting event type", nil, map[string]interface{}{
+			"event_name": envelope.Name,
+		})
+		return
+	}
+
+	bus.mu.RLock()
+	handlers := bus.handlers[envelope.Name]
+	bus.mu.RUnlock()
+
+	for _, handler := range handlers {
+		if err := bus.handleWithRetry(ctx, handler, event); err != nil {
+			application.LogError(ctx, bus.logger, "error handling event", err, map[string]interface{}{
+				"event_name": envelope.Name,
+			})
+		}
+	}
+}
+
+func (bus *GRPCEventBus[E, D]) handleWithRetry(ctx context.Context, handler application.EventHandler[E, D], event E) error {
+	var errs []error
+	for attempt := 0; attempt < bus.config.HandlerAttempts; attempt++ {
+		err := handler.Handle(ctx, event)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(bus.config.RetryDelay):
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,67 @@
+package adapter
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	"github.com/mateusmacedo/go-bff/pkg/domain"
+	watermillAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/watermill/adapter"
+)
+
+type GRPCQueryBus[Q domain.Query[D], D any, R any] struct {
+	conn   grpc.ClientConnInterface
+	logger application.AppLogger
+}
+
+func NewGRPCQueryBus[Q domain.Query[D], D any, R any](conn grpc.ClientConnInterface, logger application.AppLogger) *GRPCQueryBus[Q, D, R] {
+	return &GRPCQueryBus[Q, D, R]{
+		conn:   conn,
+		logger: logger,
+	}
+}
+
+// RegisterHandler only logs: queries are handled where the BusServer runs.
+func (bus *GRPCQueryBus[Q, D, R]) RegisterHandler(queryName string, _ application.QueryHandler[Q, D, R]) error {
+	application.LogInfo(context.Background(), bus.logger, "query handled by remote bus server", map[string]interface{}{
+		"query_name": queryName,
+	})
+	return nil
+}
+
+func (bus *GRPCQueryBus[Q, D, R]) Dispatch(ctx context.Context, query Q) (R, error) {
+	var zero R
+	if err := ctx.Err(); err != nil {
+		application.LogError(ctx, bus.logger, "context done", err, map[string]interface{}{
+			"query_name": query.QueryName(),
+		})
+		return zero, err
+	}
+
+	envelope, err := newEnvelope(ctx, query.QueryName(), query.Payload())
+	if err != nil {
+		application.LogError(ctx, bus.logger, "error encoding query", err, map[string]interface{}{
+			"query_name": query.QueryName(),
+		})
+		return zero, err
+	}
+
+	reply := new(Envelope)
+	if err := bus.conn.Invoke(ctx, queryMethod, envelope, reply, grpc.ForceCodec(jsonCodec{})); err != nil {
+		err = fromStatus(err)
+		application.LogError(ctx, bus.logger, "error dispatching remote query", err, map[string]interface{}{
+			"query_name": query.QueryName(),
+		})
+		return zero, err
+	}
+
+	var result R
+	if err := (watermillAdapter.JSONMarshaler{}).Unmarshal(reply.Payload, &result); err != nil {
+		application.LogError(ctx, bus.logger, "error unmarshalling query response", err, map[string]interface{}{
+			"query_name": query.QueryName(),
+		})
+		return zero, err
+	}
+	return result, nil
+}
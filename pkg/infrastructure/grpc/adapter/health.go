@@ -0,0 +1,34 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+)
+
+// NewConnHealthCheck waits, within the check deadline, for the connection to
+// the remote bus to become ready.
+func NewConnHealthCheck(conn *grpc.ClientConn) application.HealthCheck {
+	return application.HealthCheck{
+		Name: "grpc",
+		Check: func(ctx context.Context) error {
+			conn.Connect()
+			for {
+				state := conn.GetState()
+				if state == connectivity.Ready {
+					return nil
+				}
+				if state == connectivity.Shutdown {
+					return fmt.Errorf("grpc connection is %s", state)
+				}
+				if !conn.WaitForStateChange(ctx, state) {
+					return fmt.Errorf("grpc connection is %s: %w", state, ctx.Err())
+				}
+			}
+		},
+	}
+}
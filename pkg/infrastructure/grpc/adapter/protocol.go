@@ -0,0 +1,98 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+
+	"google.golang.org/grpc"
+)
+
+// The bus protocol is small enough to describe by hand: every message is an
+// Envelope encoded as JSON, so no generated code is needed on either side.
+const busServiceName = "gobff.bus.v1.Bus"
+
+const (
+	dispatchMethod  = "/" + busServiceName + "/Dispatch"
+	queryMethod     = "/" + busServiceName + "/Query"
+	publishMethod   = "/" + busServiceName + "/Publish"
+	subscribeMethod = "/" + busServiceName + "/Subscribe"
+)
+
+type Envelope struct {
+	Name     string            `json:"name"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Payload  []byte            `json:"payload,omitempty"`
+}
+
+type Ack struct{}
+
+type SubscribeRequest struct {
+	Names []string `json:"names"`
+}
+
+type busService interface {
+	dispatch(ctx context.Context, envelope *Envelope) (*Ack, error)
+	query(ctx context.Context, envelope *Envelope) (*Envelope, error)
+	publish(ctx context.Context, envelope *Envelope) (*Ack, error)
+	subscribe(request *SubscribeRequest, stream grpc.ServerStream) error
+}
+
+var busServiceDesc = grpc.ServiceDesc{
+	ServiceName: busServiceName,
+	HandlerType: (*busService)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Dispatch", Handler: unaryHandler(dispatchMethod, func(s busService, ctx context.Context, envelope *Envelope) (interface{}, error) {
+			return s.dispatch(ctx, envelope)
+		})},
+		{MethodName: "Query", Handler: unaryHandler(queryMethod, func(s busService, ctx context.Context, envelope *Envelope) (interface{}, error) {
+			return s.query(ctx, envelope)
+		})},
+		{MethodName: "Publish", Handler: unaryHandler(publishMethod, func(s busService, ctx context.Context, envelope *Envelope) (interface{}, error) {
+			return s.publish(ctx, envelope)
+		})},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Subscribe",
+			ServerStreams: true,
+			Handler: func(srv interface{}, stream grpc.ServerStream) error {
+				request := new(SubscribeRequest)
+				if err := stream.RecvMsg(request); err != nil {
+					return err
+				}
+				return srv.(busService).subscribe(request, stream)
+			},
+		},
+	},
+	Metadata: "bus.proto",
+}
+
+func unaryHandler(method string, call func(busService, context.Context, *Envelope) (interface{}, error)) func(interface{}, context.Context, func(interface{}) error, grpc.UnaryServerInterceptor) (interface{}, error) {
+	return func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+		envelope := new(Envelope)
+		if err := dec(envelope); err != nil {
+			return nil, err
+		}
+		if interceptor == nil {
+			return call(srv.(busService), ctx, envelope)
+		}
+		info := &grpc.UnaryServerInfo{Server: srv, FullMethod: method}
+		return interceptor(ctx, envelope, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return call(srv.(busService), ctx, req.(*Envelope))
+		})
+	}
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) Name() string {
+	return "json"
+}
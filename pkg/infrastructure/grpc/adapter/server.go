@@ -0,0 +1,310 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	"github.com/mateusmacedo/go-bff/pkg/domain"
+	watermillAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/watermill/adapter"
+)
+
+// BusServer exposes in-process buses to remote GRPC*Bus clients. Messages
+// are served once registered with ServeCommands, ServeQueries or ServeEvents.
+type BusServer struct {
+	commands    map[string]func(ctx context.Context, payload []byte) error
+	queries     map[string]func(ctx context.Context, payload []byte) ([]byte, error)
+	events      map[string]func(ctx context.Context, payload []byte) error
+	subscribers map[*subscription]struct{}
+	mu          sync.RWMutex
+	logger      application.AppLogger
+}
+
+type subscription struct {
+	names     map[string]bool
+	envelopes chan *Envelope
+	done      <-chan struct{}
+}
+
+func NewBusServer(logger application.AppLogger) *BusServer {
+	return &BusServer{
+		commands:    make(map[string]func(ctx context.Context, payload []byte) error),
+		queries:     make(map[string]func(ctx context.Context, payload []byte) ([]byte, error)),
+		events:      make(map[string]func(ctx context.Context, payload []byte) error),
+		subscribers: make(map[*subscription]struct{}),
+		logger:      logger,
+	}
+}
+
+// ServerConfig secures a BusServer. The server trusts the principal and
+// tenant each call carries, so callers must be authenticated with Token,
+// client certificates or both.
+type ServerConfig struct {
+	// Credentials defaults to an insecure transport.
+	Credentials credentials.TransportCredentials
+	// Token, when set, is required of every call.
+	Token string
+}
+
+// ServerOptions must be passed to grpc.NewServer for the bus protocol codec.
+func ServerOptions(config ServerConfig) []grpc.ServerOption {
+	options := []grpc.ServerOption{grpc.ForceServerCodec(jsonCodec{})}
+	if config.Credentials != nil {
+		options = append(options, grpc.Creds(config.Credentials))
+	}
+	if config.Token != "" {
+		options = append(options, tokenInterceptors(config.Token)...)
+	}
+	return options
+}
+
+func (s *BusServer) Register(server *grpc.Server) {
+	server.RegisterService(&busServiceDesc, s)
+}
+
+func ServeCommands[C domain.Command[T], T any](server *BusServer, bus application.CommandBus[C, T], commandNames ...string) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	for _, commandName := range commandNames {
+		commandName := commandName
+		server.commands[commandName] = func(ctx context.Context, payload []byte) error {
+			var data T
+			if err := (watermillAdapter.JSONMarshaler{}).Unmarshal(payload, &data); err != nil {
+				return err
+			}
+			command, ok := interface{}(&dynamicCommand[T]{commandName: commandName, payload: data}).(C)
+			if !ok {
+				return fmt.Errorf("error asserting command type for %s", commandName)
+			}
+			return bus.Dispatch(ctx, command)
+		}
+	}
+}
+
+func ServeQueries[Q domain.Query[D], D any, R any](server *BusServer, bus application.QueryBus[Q, D, R], queryNames ...string) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	for _, queryName := range queryNames {
+		queryName := queryName
+		server.queries[queryName] = func(ctx context.Context, payload []byte) ([]byte, error) {
+			var data D
+			if err := (watermillAdapter.JSONMarshaler{}).Unmarshal(payload, &data); err != nil {
+				return nil, err
+			}
+			query, ok := interface{}(&dynamicQuery[D]{queryName: queryName, payload: data}).(Q)
+			if !ok {
+				return nil, fmt.Errorf("error asserting query type for %s", queryName)
+			}
+			result, err := bus.Dispatch(ctx, query)
+			if err != nil {
+				return nil, err
+			}
+			return (watermillAdapter.JSONMarshaler{}).Marshal(result)
+		}
+	}
+}
+
+// ServeEvents lets clients publish to bus and streams every event it
+// publishes under eventNames to the clients subscribed to them.
+func ServeEvents[E domain.Event[D], D any](server *BusServer, bus application.EventBus[E, D], eventNames ...string) error {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	for _, eventName := range eventNames {
+		eventName := eventName
+		server.events[eventName] = func(ctx context.Context, payload []byte) error {
+			var data D
+			if err := (watermillAdapter.JSONMarshaler{}).Unmarshal(payload, &data); err != nil {
+				return err
+			}
+			event, ok := interface{}(&dynamicEvent[D]{eventName: eventName, payload: data}).(E)
+			if !ok {
+				return fmt.Errorf("error asserting event type for %s", eventName)
+			}
+			return bus.Publish(ctx, event)
+		}
+		if err := bus.RegisterHandler(eventName, &forwardingHandler[E, D]{server: server}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *BusServer) dispatch(ctx context.Context, envelope *Envelope) (*Ack, error) {
+	s.mu.RLock()
+	handle, found := s.commands[envelope.Name]
+	s.mu.RUnlock()
+	if !found {
+		return nil, toStatus(fmt.Errorf("command %s: %w", envelope.Name, ErrNoRemoteHandler))
+	}
+
+	ctx, err := watermillAdapter.ExtractMetadata(ctx, envelope.Metadata)
+	if err == nil {
+		err = handle(ctx, envelope.Payload)
+	}
+	if err != nil {
+		application.LogError(ctx, s.logger, "error serving command", err, map[string]interface{}{
+			"command_name": envelope.Name,
+		})
+		return nil, toStatus(err)
+	}
+	return &Ack{}, nil
+}
+
+func (s *BusServer) query(ctx context.Context, envelope *Envelope) (*Envelope, error) {
+	s.mu.RLock()
+	handle, found := s.queries[envelope.Name]
+	s.mu.RUnlock()
+	if !found {
+		return nil, toStatus(fmt.Errorf("query %s: %w", envelope.Name, ErrNoRemoteHandler))
+	}
+
+	ctx, err := watermillAdapter.ExtractMetadata(ctx, envelope.Metadata)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	result, err := handle(ctx, envelope.Payload)
+	if err != nil {
+		application.LogError(ctx, s.logger, "error serving query", err, map[string]interface{}{
+			"query_name": envelope.Name,
+		})
+		return nil, toStatus(err)
+	}
+	return &Envelope{Name: envelope.Name, Payload: result}, nil
+}
+
+func (s *BusServer) publish(ctx context.Context, envelope *Envelope) (*Ack, error) {
+	s.mu.RLock()
+	handle, found := s.events[envelope.Name]
+	s.mu.RUnlock()
+	if !found {
+		return nil, toStatus(fmt.Errorf("event %s: %w", envelope.Name, ErrNoRemoteHandler))
+	}
+
+	ctx, err := watermillAdapter.ExtractMetadata(ctx, envelope.Metadata)
+	if err == nil {
+		err = handle(ctx, envelope.Payload)
+	}
+	if err != nil {
+		application.LogError(ctx, s.logger, "error serving event", err, map[string]interface{}{
+			"event_name": envelope.Name,
+		})
+		return nil, toStatus(err)
+	}
+	return &Ack{}, nil
+}
+
+// subscribe sends an empty envelope once the subscription is registered, so
+// clients know that later events will reach them.
+func (s *BusServer) subscribe(request *SubscribeRequest, stream grpc.ServerStream) error {
+	sub := &subscription{
+		names:     make(map[string]bool, len(request.Names)),
+		envelopes: make(chan *Envelope, 64),
+		done:      stream.Context().Done(),
+	}
+	for _, name := range request.Names {
+		sub.names[name] = true
+	}
+
+	s.mu.Lock()
+	s.subscribers[sub] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.subscribers, sub)
+		s.mu.Unlock()
+	}()
+
+	if err := stream.SendMsg(&Envelope{}); err != nil {
+		return err
+	}
+	for {
+		select {
+		case envelope := <-sub.envelopes:
+			if err := stream.SendMsg(envelope); err != nil {
+				return err
+			}
+		case <-sub.done:
+			return nil
+		}
+	}
+}
+
+func (s *BusServer) broadcast(ctx context.Context, envelope *Envelope) error {
+	s.mu.RLock()
+	var targets []*subscription
+	for sub := range s.subscribers {
+		if sub.names[envelope.Name] {
+			targets = append(targets, sub)
+		}
+	}
+	s.mu.RUnlock()
+
+	for _, sub := range targets {
+		select {
+		case sub.envelopes <- envelope:
+		case <-sub.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+type forwardingHandler[E domain.Event[D], D any] struct {
+	server *BusServer
+}
+
+func (h *forwardingHandler[E, D]) Handle(ctx context.Context, event E) error {
+	payload, err := (watermillAdapter.JSONMarshaler{}).Marshal(event.Payload())
+	if err != nil {
+		return err
+	}
+	envelope := &Envelope{Name: event.EventName(), Metadata: map[string]string{}, Payload: payload}
+	if err := watermillAdapter.InjectMetadata(ctx, envelope.Metadata); err != nil {
+		return err
+	}
+	return h.server.broadcast(ctx, envelope)
+}
+
+type dynamicCommand[T any] struct {
+	commandName string
+	payload     T
+}
+
+func (c *dynamicCommand[T]) CommandName() string {
+	return c.commandName
+}
+
+func (c *dynamicCommand[T]) Payload() T {
+	return c.payload
+}
+
+type dynamicQuery[D any] struct {
+	queryName string
+	payload   D
+}
+
+func (q *dynamicQuery[D]) QueryName() string {
+	return q.queryName
+}
+
+func (q *dynamicQuery[D]) Payload() D {
+	return q.payload
+}
+
+type dynamicEvent[D any] struct {
+	eventName string
+	payload   D
+}
+
+func (e *dynamicEvent[D]) EventName() string {
+	return e.eventName
+}
+
+func (e *dynamicEvent[D]) Payload() D {
+	return e.payload
+}
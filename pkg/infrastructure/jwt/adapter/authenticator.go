@@ -0,0 +1,115 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+)
+
+var ErrInvalidToken = errors.New("invalid token")
+
+type ClaimsMapping struct {
+	SubjectClaim    string
+	RolesClaim      string
+	AttributeClaims map[string]string
+}
+
+func DefaultClaimsMapping() ClaimsMapping {
+	return ClaimsMapping{
+		SubjectClaim:    "sub",
+		RolesClaim:      "roles",
+		AttributeClaims: map[string]string{},
+	}
+}
+
+type AuthenticatorConfig struct {
+	Issuer        string
+	Audience      string
+	Algorithms    []string
+	Leeway        time.Duration
+	ClaimsMapping ClaimsMapping
+}
+
+type Authenticator struct {
+	keys   KeyProvider
+	config AuthenticatorConfig
+	parser *jwt.Parser
+}
+
+func NewAuthenticator(keys KeyProvider, config AuthenticatorConfig) *Authenticator {
+	if len(config.Algorithms) == 0 {
+		config.Algorithms = []string{"RS256", "ES256", "HS256"}
+	}
+	if config.ClaimsMapping.SubjectClaim == "" {
+		config.ClaimsMapping = DefaultClaimsMapping()
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(config.Algorithms),
+		jwt.WithLeeway(config.Leeway),
+		jwt.WithExpirationRequired(),
+	}
+	if config.Issuer != "" {
+		options = append(options, jwt.WithIssuer(config.Issuer))
+	}
+	if config.Audience != "" {
+		options = append(options, jwt.WithAudience(config.Audience))
+	}
+
+	return &Authenticator{
+		keys:   keys,
+		config: config,
+		parser: jwt.NewParser(options...),
+	}
+}
+
+func (a *Authenticator) Authenticate(ctx context.Context, tokenString string) (application.Principal, error) {
+	claims := jwt.MapClaims{}
+	_, err := a.parser.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return a.keys.Key(ctx, kid)
+	})
+	if err != nil {
+		return application.Principal{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	return a.mapClaims(claims)
+}
+
+func (a *Authenticator) mapClaims(claims jwt.MapClaims) (application.Principal, error) {
+	mapping := a.config.ClaimsMapping
+
+	subject, _ := claims[mapping.SubjectClaim].(string)
+	if subject == "" {
+		return application.Principal{}, fmt.Errorf("%w: missing %s claim", ErrInvalidToken, mapping.SubjectClaim)
+	}
+
+	principal := application.Principal{
+		ID:         subject,
+		Attributes: make(map[string]string),
+	}
+
+	switch roles := claims[mapping.RolesClaim].(type) {
+	case []interface{}:
+		for _, role := range roles {
+			if value, ok := role.(string); ok {
+				principal.Roles = append(principal.Roles, value)
+			}
+		}
+	case string:
+		principal.Roles = append(principal.Roles, roles)
+	}
+
+	for claim, attribute := range mapping.AttributeClaims {
+		if value, ok := claims[claim]; ok {
+			principal.Attributes[attribute] = fmt.Sprint(value)
+		}
+	}
+
+	return principal, nil
+}
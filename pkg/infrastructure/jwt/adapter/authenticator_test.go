@@ -0,0 +1,163 @@
+package adapter_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	jwtAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/jwt/adapter"
+	"github.com/mateusmacedo/go-bff/pkg/testkit"
+)
+
+func TestAuthenticator(t *testing.T) {
+	rsaKey := newRSAKey(t)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	keys := jwtAdapter.NewStaticKeyProvider(map[string]interface{}{
+		"rsa": rsaKey.Public(),
+		"ec":  ecKey.Public(),
+	})
+	authenticator := jwtAdapter.NewAuthenticator(keys, jwtAdapter.AuthenticatorConfig{
+		Issuer:   "https://issuer.example",
+		Audience: "bff",
+		ClaimsMapping: jwtAdapter.ClaimsMapping{
+			SubjectClaim:    "sub",
+			RolesClaim:      "roles",
+			AttributeClaims: map[string]string{"name": "passenger_name"},
+		},
+	})
+
+	t.Run("maps the claims of tokens signed with RSA and ECDSA keys", func(t *testing.T) {
+		for _, token := range []string{
+			sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, validClaims()),
+			sign(t, jwt.SigningMethodES256, "ec", ecKey, validClaims()),
+		} {
+			principal, err := authenticator.Authenticate(context.Background(), token)
+			if err != nil {
+				t.Fatalf("Authenticate() error = %v", err)
+			}
+			if principal.ID != "ana" || !principal.HasRole("passenger") || principal.Attribute("passenger_name") != "Ana" {
+				t.Fatalf("Authenticate() = %+v, want ana, a passenger named Ana", principal)
+			}
+		}
+	})
+
+	claims := func(change func(jwt.MapClaims)) jwt.MapClaims {
+		c := validClaims()
+		change(c)
+		return c
+	}
+	invalid := map[string]string{
+		"bad signature":          sign(t, jwt.SigningMethodRS256, "rsa", newRSAKey(t), validClaims()),
+		"expired token":          sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, claims(func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() })),
+		"missing expiry":         sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, claims(func(c jwt.MapClaims) { delete(c, "exp") })),
+		"wrong issuer":           sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, claims(func(c jwt.MapClaims) { c["iss"] = "https://other.example" })),
+		"wrong audience":         sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, claims(func(c jwt.MapClaims) { c["aud"] = "other" })),
+		"missing subject":        sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, claims(func(c jwt.MapClaims) { delete(c, "sub") })),
+		"unknown kid":            sign(t, jwt.SigningMethodRS256, "other", rsaKey, validClaims()),
+		"unsigned token":         sign(t, jwt.SigningMethodNone, "rsa", jwt.UnsafeAllowNoneSignatureType, validClaims()),
+		"not accepted algorithm": sign(t, jwt.SigningMethodPS256, "rsa", rsaKey, validClaims()),
+	}
+	for name, token := range invalid {
+		t.Run("rejects "+name, func(t *testing.T) {
+			if _, err := authenticator.Authenticate(context.Background(), token); !errors.Is(err, jwtAdapter.ErrInvalidToken) {
+				t.Fatalf("Authenticate() error = %v, want %v", err, jwtAdapter.ErrInvalidToken)
+			}
+		})
+	}
+
+	t.Run("accepts only the configured algorithms", func(t *testing.T) {
+		rsaOnly := jwtAdapter.NewAuthenticator(keys, jwtAdapter.AuthenticatorConfig{Algorithms: []string{"RS256"}})
+		if _, err := rsaOnly.Authenticate(context.Background(), sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, validClaims())); err != nil {
+			t.Fatalf("Authenticate() of an RS256 token error = %v", err)
+		}
+		if _, err := rsaOnly.Authenticate(context.Background(), sign(t, jwt.SigningMethodES256, "ec", ecKey, validClaims())); !errors.Is(err, jwtAdapter.ErrInvalidToken) {
+			t.Fatalf("Authenticate() of an ES256 token error = %v, want %v", err, jwtAdapter.ErrInvalidToken)
+		}
+	})
+}
+
+func TestAuthenticate(t *testing.T) {
+	rsaKey := newRSAKey(t)
+	authenticator := jwtAdapter.NewAuthenticator(jwtAdapter.NewStaticKeyProvider(map[string]interface{}{"rsa": rsaKey.Public()}), jwtAdapter.AuthenticatorConfig{})
+	handler := jwtAdapter.Authenticate(authenticator, testkit.NewLogger(t))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, _ := application.PrincipalFromContext(r.Context())
+		w.Write([]byte(principal.ID))
+	}))
+
+	tests := []struct {
+		name, header string
+		status       int
+		body         string
+	}{
+		{"passes anonymous requests on", "", http.StatusOK, ""},
+		{"authenticates bearer tokens", "Bearer " + sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, validClaims()), http.StatusOK, "ana"},
+		{"refuses other schemes", "Basic YW5hOnNlY3JldA==", http.StatusUnauthorized, ""},
+		{"refuses invalid tokens", "Bearer " + sign(t, jwt.SigningMethodRS256, "rsa", newRSAKey(t), validClaims()), http.StatusUnauthorized, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/bustickets", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if tt.status == http.StatusOK && rec.Body.String() != tt.body {
+				t.Fatalf("principal = %q, want %q", rec.Body.String(), tt.body)
+			}
+			if tt.status == http.StatusUnauthorized && !strings.HasPrefix(rec.Header().Get("WWW-Authenticate"), "Bearer") {
+				t.Fatalf("WWW-Authenticate = %q, want a Bearer challenge", rec.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":   "ana",
+		"roles": []string{"passenger"},
+		"name":  "Ana",
+		"iss":   "https://issuer.example",
+		"aud":   "bff",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func newRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	return key
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key crypto.PrivateKey, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("SignedString() error = %v", err)
+	}
+	return signed
+}
@@ -0,0 +1,242 @@
+package adapter
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+)
+
+var ErrKeyNotFound = errors.New("signing key not found")
+
+type KeyProvider interface {
+	Key(ctx context.Context, kid string) (interface{}, error)
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	K   string `json:"k"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+func ParseJWKS(data []byte) (map[string]interface{}, error) {
+	var set jsonWebKeySet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("jwk %q: %w", jwk.Kid, err)
+		}
+		keys[jwk.Kid] = key
+	}
+	return keys, nil
+}
+
+func (jwk jsonWebKey) publicKey() (interface{}, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "oct":
+		return base64.RawURLEncoding.DecodeString(jwk.K)
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", jwk.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(decoded), nil
+}
+
+type StaticKeyProvider struct {
+	keys map[string]interface{}
+}
+
+func NewStaticKeyProvider(keys map[string]interface{}) *StaticKeyProvider {
+	return &StaticKeyProvider{keys: keys}
+}
+
+func (p *StaticKeyProvider) Key(_ context.Context, kid string) (interface{}, error) {
+	key, found := p.keys[kid]
+	if !found {
+		return nil, ErrKeyNotFound
+	}
+	return key, nil
+}
+
+type JWKSProvider struct {
+	fetch              func(ctx context.Context) ([]byte, error)
+	ttl                time.Duration
+	minRefreshInterval time.Duration
+	keys               map[string]interface{}
+	fetchedAt          time.Time
+	mu                 sync.RWMutex
+	refreshMu          sync.Mutex
+	logger             application.AppLogger
+}
+
+func NewFileJWKSProvider(path string, ttl time.Duration, logger application.AppLogger) *JWKSProvider {
+	return newJWKSProvider(func(context.Context) ([]byte, error) {
+		return os.ReadFile(path)
+	}, ttl, logger)
+}
+
+func NewURLJWKSProvider(url string, client *http.Client, ttl time.Duration, logger application.AppLogger) *JWKSProvider {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return newJWKSProvider(func(ctx context.Context) ([]byte, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status fetching jwks: %s", resp.Status)
+		}
+		return io.ReadAll(resp.Body)
+	}, ttl, logger)
+}
+
+func newJWKSProvider(fetch func(ctx context.Context) ([]byte, error), ttl time.Duration, logger application.AppLogger) *JWKSProvider {
+	return &JWKSProvider{
+		fetch:              fetch,
+		ttl:                ttl,
+		minRefreshInterval: 10 * time.Second,
+		keys:               make(map[string]interface{}),
+		logger:             logger,
+	}
+}
+
+func (p *JWKSProvider) Key(ctx context.Context, kid string) (interface{}, error) {
+	p.mu.RLock()
+	key, found := p.keys[kid]
+	stale := p.fetchedAt.IsZero() || (p.ttl > 0 && time.Since(p.fetchedAt) > p.ttl)
+	p.mu.RUnlock()
+
+	if found && !stale {
+		return key, nil
+	}
+
+	// Unknown kids usually mean the issuer rotated its keys.
+	if err := p.refresh(ctx, true); err != nil {
+		if found {
+			application.LogError(ctx, p.logger, "error refreshing jwks, using cached key", err, map[string]interface{}{
+				"kid": kid,
+			})
+			return key, nil
+		}
+		return nil, err
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if key, found = p.keys[kid]; !found {
+		return nil, ErrKeyNotFound
+	}
+	return key, nil
+}
+
+func (p *JWKSProvider) Refresh(ctx context.Context) error {
+	return p.refresh(ctx, false)
+}
+
+func (p *JWKSProvider) refresh(ctx context.Context, rateLimited bool) error {
+	p.refreshMu.Lock()
+	defer p.refreshMu.Unlock()
+
+	p.mu.RLock()
+	fetchedAt := p.fetchedAt
+	p.mu.RUnlock()
+	if rateLimited && !fetchedAt.IsZero() && time.Since(fetchedAt) < p.minRefreshInterval {
+		return nil
+	}
+
+	data, err := p.fetch(ctx)
+	if err != nil {
+		return err
+	}
+	keys, err := ParseJWKS(data)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	p.keys = keys
+	p.fetchedAt = time.Now()
+	p.mu.Unlock()
+
+	application.LogInfo(ctx, p.logger, "jwks refreshed", map[string]interface{}{
+		"keys": len(keys),
+	})
+	return nil
+}
@@ -0,0 +1,139 @@
+package adapter
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mateusmacedo/go-bff/pkg/testkit"
+)
+
+func TestJWKSProvider(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	t.Run("parses RSA and EC keys", func(t *testing.T) {
+		server := newJWKSServer(t, rsaJWK("rsa", &rsaKey.PublicKey), ecJWK("ec", &ecKey.PublicKey))
+		provider := NewURLJWKSProvider(server.URL, nil, time.Hour, testkit.NewLogger(t))
+
+		key, err := provider.Key(context.Background(), "rsa")
+		if public, ok := key.(*rsa.PublicKey); err != nil || !ok || !public.Equal(&rsaKey.PublicKey) {
+			t.Fatalf("Key(rsa) = %v, %v, want the RSA public key", key, err)
+		}
+		key, err = provider.Key(context.Background(), "ec")
+		if public, ok := key.(*ecdsa.PublicKey); err != nil || !ok || !public.Equal(&ecKey.PublicKey) {
+			t.Fatalf("Key(ec) = %v, %v, want the EC public key", key, err)
+		}
+		if n := server.fetches.Load(); n != 1 {
+			t.Fatalf("fetched the JWKS %d times, want once", n)
+		}
+	})
+
+	t.Run("refreshes on an unknown kid", func(t *testing.T) {
+		server := newJWKSServer(t, rsaJWK("old", &rsaKey.PublicKey))
+		provider := NewURLJWKSProvider(server.URL, nil, time.Hour, testkit.NewLogger(t))
+		provider.minRefreshInterval = 50 * time.Millisecond
+		if _, err := provider.Key(context.Background(), "old"); err != nil {
+			t.Fatalf("Key(old) error = %v", err)
+		}
+
+		// The issuer rotates its keys; a refresh within the minimum interval
+		// is skipped, so that unknown kids cannot hammer the issuer.
+		server.serve(ecJWK("new", &ecKey.PublicKey))
+		if _, err := provider.Key(context.Background(), "new"); !errors.Is(err, ErrKeyNotFound) {
+			t.Fatalf("Key(new) right after a fetch error = %v, want %v", err, ErrKeyNotFound)
+		}
+		time.Sleep(60 * time.Millisecond)
+		if key, err := provider.Key(context.Background(), "new"); err != nil || !key.(*ecdsa.PublicKey).Equal(&ecKey.PublicKey) {
+			t.Fatalf("Key(new) = %v, %v, want the rotated EC key", key, err)
+		}
+		if n := server.fetches.Load(); n != 2 {
+			t.Fatalf("fetched the JWKS %d times, want twice", n)
+		}
+	})
+
+	t.Run("keeps cached keys when a refresh fails", func(t *testing.T) {
+		server := newJWKSServer(t, rsaJWK("rsa", &rsaKey.PublicKey))
+		provider := NewURLJWKSProvider(server.URL, nil, time.Millisecond, testkit.NewLogger(t))
+		provider.minRefreshInterval = 0
+		if _, err := provider.Key(context.Background(), "rsa"); err != nil {
+			t.Fatalf("Key(rsa) error = %v", err)
+		}
+
+		server.fail()
+		time.Sleep(5 * time.Millisecond)
+		if key, err := provider.Key(context.Background(), "rsa"); err != nil || key == nil {
+			t.Fatalf("Key(rsa) with a stale JWKS = %v, %v, want the cached key", key, err)
+		}
+		if _, err := provider.Key(context.Background(), "other"); err == nil {
+			t.Fatalf("Key(other) succeeded while the JWKS is unavailable")
+		}
+	})
+}
+
+type jwksServer struct {
+	*httptest.Server
+	fetches atomic.Int32
+
+	mu      sync.Mutex
+	keys    []jsonWebKey
+	failing bool
+}
+
+func newJWKSServer(t *testing.T, keys ...jsonWebKey) *jwksServer {
+	s := &jwksServer{keys: keys}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.fetches.Add(1)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.failing {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(jsonWebKeySet{Keys: s.keys})
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *jwksServer) serve(keys ...jsonWebKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = keys
+}
+
+func (s *jwksServer) fail() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failing = true
+}
+
+func rsaJWK(kid string, key *rsa.PublicKey) jsonWebKey {
+	return jsonWebKey{Kty: "RSA", Kid: kid, Use: "sig", Alg: "RS256", N: encodeBigInt(key.N), E: encodeBigInt(big.NewInt(int64(key.E)))}
+}
+
+func ecJWK(kid string, key *ecdsa.PublicKey) jsonWebKey {
+	return jsonWebKey{Kty: "EC", Kid: kid, Use: "sig", Alg: "ES256", Crv: "P-256", X: encodeBigInt(key.X), Y: encodeBigInt(key.Y)}
+}
+
+func encodeBigInt(value *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(value.Bytes())
+}
@@ -0,0 +1,42 @@
+package adapter
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+)
+
+func Authenticate(authenticator *Authenticator, logger application.AppLogger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Authorization")
+			if header == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			tokenString, found := strings.CutPrefix(header, "Bearer ")
+			if !found {
+				unauthorized(w, "invalid authorization header")
+				return
+			}
+
+			principal, err := authenticator.Authenticate(r.Context(), strings.TrimSpace(tokenString))
+			if err != nil {
+				application.LogError(r.Context(), logger, "error authenticating request", err, map[string]interface{}{
+					"path": r.URL.Path,
+				})
+				unauthorized(w, "invalid token")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(application.WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
+
+func unauthorized(w http.ResponseWriter, message string) {
+	w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+	http.Error(w, message, http.StatusUnauthorized)
+}
@@ -0,0 +1,30 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+)
+
+// NewKafkaHealthCheck reports healthy while at least one broker accepts
+// connections.
+func NewKafkaHealthCheck(config KafkaConfig) application.HealthCheck {
+	return application.HealthCheck{
+		Name: "kafka",
+		Check: func(ctx context.Context) error {
+			var dialer net.Dialer
+			var errs []error
+			for _, broker := range config.Brokers {
+				conn, err := dialer.DialContext(ctx, "tcp", broker)
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				return conn.Close()
+			}
+			return errors.Join(errs...)
+		},
+	}
+}
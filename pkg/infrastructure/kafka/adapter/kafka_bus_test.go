@@ -0,0 +1,78 @@
+package adapter_test
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill-kafka/v2/pkg/kafka"
+
+	kafkaAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/kafka/adapter"
+	watermillAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/watermill/adapter"
+	"github.com/mateusmacedo/go-bff/pkg/testkit"
+	"github.com/mateusmacedo/go-bff/pkg/testkit/conformance"
+)
+
+// brokersEnv names the brokers the suite runs against. Kafka has no embedded
+// server for tests, so the suite is skipped without one.
+const brokersEnv = "BFF_TEST_KAFKA_BROKERS"
+
+func TestKafkaBuses(t *testing.T) {
+	brokers := os.Getenv(brokersEnv)
+	if brokers == "" {
+		t.Skipf("%s not set", brokersEnv)
+	}
+	config := conformance.Config{Delivery: conformance.Asynchronous}
+
+	t.Run("command bus", func(t *testing.T) {
+		conformance.TestCommandBus(t, config, func(t *testing.T) conformance.CommandBus {
+			publisher, subscriber, prefix := newPubSub(t, brokers)
+			bus := kafkaAdapter.NewKafkaCommandBus[conformance.Command, conformance.Payload](publisher, subscriber, testkit.NewLogger(t), prefix)
+			t.Cleanup(func() { bus.Close() })
+			return bus
+		})
+	})
+	t.Run("query bus", func(t *testing.T) {
+		conformance.TestQueryBus(t, config, func(t *testing.T) conformance.QueryBus {
+			publisher, subscriber, prefix := newPubSub(t, brokers)
+			bus := kafkaAdapter.NewKafkaQueryBus[conformance.Query, conformance.Payload, conformance.Payload](publisher, subscriber, testkit.NewLogger(t), prefix)
+			t.Cleanup(func() { bus.Close() })
+			return bus
+		})
+	})
+	t.Run("event bus", func(t *testing.T) {
+		conformance.TestEventBus(t, config, func(t *testing.T) conformance.EventBus {
+			publisher, subscriber, prefix := newPubSub(t, brokers)
+			bus := kafkaAdapter.NewKafkaEventBus[conformance.Event, conformance.Payload](publisher, subscriber, testkit.NewLogger(t), prefix)
+			t.Cleanup(func() { bus.Close() })
+			return bus
+		})
+	})
+}
+
+// newPubSub connects to brokers with a consumer group and a topic prefix of
+// its own, so runs sharing a cluster never see each other's messages.
+func newPubSub(t *testing.T, brokers string) (*kafka.Publisher, *kafka.Subscriber, watermillAdapter.Option) {
+	t.Helper()
+	run := fmt.Sprintf("conformance-%d", time.Now().UnixNano())
+	config := kafkaAdapter.KafkaConfig{
+		Brokers:       strings.Split(brokers, ","),
+		ConsumerGroup: run,
+		ClientID:      run,
+	}
+	logger := watermillAdapter.NewWatermillLoggerAdapter(testkit.NewLogger(t))
+
+	publisher, err := kafkaAdapter.NewKafkaPublisher(config, logger)
+	if err != nil {
+		t.Fatalf("NewKafkaPublisher() error = %v", err)
+	}
+	t.Cleanup(func() { publisher.Close() })
+	subscriber, err := kafkaAdapter.NewKafkaSubscriber(config, logger)
+	if err != nil {
+		t.Fatalf("NewKafkaSubscriber() error = %v", err)
+	}
+	t.Cleanup(func() { subscriber.Close() })
+	return publisher, subscriber, watermillAdapter.WithTopicPrefix(run + ".")
+}
@@ -0,0 +1,50 @@
+package adapter
+
+import (
+	"github.com/Shopify/sarama"
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill-kafka/v2/pkg/kafka"
+	"github.com/ThreeDotsLabs/watermill/message"
+
+	watermillAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/watermill/adapter"
+)
+
+type KafkaConfig struct {
+	Brokers       []string
+	ConsumerGroup string
+	ClientID      string
+}
+
+func NewKafkaPublisher(config KafkaConfig, logger watermill.LoggerAdapter) (*kafka.Publisher, error) {
+	return kafka.NewPublisher(kafka.PublisherConfig{
+		Brokers:   config.Brokers,
+		Marshaler: kafka.DefaultMarshaler{},
+	}, logger)
+}
+
+func NewKafkaSubscriber(config KafkaConfig, logger watermill.LoggerAdapter) (*kafka.Subscriber, error) {
+	saramaConfig := sarama.NewConfig()
+	saramaConfig.Version = sarama.V1_0_0_0
+	saramaConfig.Consumer.Offsets.Initial = sarama.OffsetOldest
+	saramaConfig.Consumer.Return.Errors = true
+	saramaConfig.ClientID = config.ClientID
+
+	return kafka.NewSubscriber(kafka.SubscriberConfig{
+		Brokers:               config.Brokers,
+		Unmarshaler:           kafka.DefaultMarshaler{},
+		ConsumerGroup:         config.ConsumerGroup,
+		OverwriteSaramaConfig: saramaConfig,
+		InitializeTopicDetails: &sarama.TopicDetail{
+			NumPartitions:     1,
+			ReplicationFactor: 1,
+		},
+	}, logger)
+}
+
+func KafkaSubscriberFactory(config KafkaConfig, logger watermill.LoggerAdapter) watermillAdapter.SubscriberFactory {
+	return func(consumerGroup string) (message.Subscriber, error) {
+		groupConfig := config
+		groupConfig.ConsumerGroup = consumerGroup
+		return NewKafkaSubscriber(groupConfig, logger)
+	}
+}
@@ -0,0 +1,24 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+)
+
+// NewNatsHealthCheck round-trips to the server so that a connection that is
+// still reconnecting reports unhealthy.
+func NewNatsHealthCheck(conn *nats.Conn) application.HealthCheck {
+	return application.HealthCheck{
+		Name: "nats",
+		Check: func(ctx context.Context) error {
+			if status := conn.Status(); status != nats.CONNECTED {
+				return fmt.Errorf("nats connection is %s", status)
+			}
+			return conn.FlushWithContext(ctx)
+		},
+	}
+}
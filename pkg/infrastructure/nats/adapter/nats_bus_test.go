@@ -0,0 +1,101 @@
+package adapter_test
+
+import (
+	"testing"
+	"time"
+
+	wmnats "github.com/ThreeDotsLabs/watermill-nats/v2/pkg/nats"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+
+	natsAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/nats/adapter"
+	watermillAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/watermill/adapter"
+	"github.com/mateusmacedo/go-bff/pkg/testkit"
+	"github.com/mateusmacedo/go-bff/pkg/testkit/conformance"
+)
+
+func TestNatsBuses(t *testing.T) {
+	config := conformance.Config{Delivery: conformance.Asynchronous}
+
+	t.Run("command bus", func(t *testing.T) {
+		conformance.TestCommandBus(t, config, func(t *testing.T) conformance.CommandBus {
+			conn, natsConfig := connect(t)
+			publisher, subscriber := newPubSub(t, conn, natsConfig)
+			bus := natsAdapter.NewNatsCommandBus[conformance.Command, conformance.Payload](publisher, subscriber, testkit.NewLogger(t))
+			t.Cleanup(func() { bus.Close() })
+			return bus
+		})
+	})
+	t.Run("query bus", func(t *testing.T) {
+		conformance.TestQueryBus(t, config, func(t *testing.T) conformance.QueryBus {
+			conn, natsConfig := connect(t)
+			bus := natsAdapter.NewNatsQueryBus[conformance.Query, conformance.Payload, conformance.Payload](conn, natsConfig.QueueGroup, testkit.NewLogger(t))
+			t.Cleanup(func() { bus.Close() })
+			return bus
+		})
+	})
+	t.Run("event bus", func(t *testing.T) {
+		conformance.TestEventBus(t, config, func(t *testing.T) conformance.EventBus {
+			conn, natsConfig := connect(t)
+			publisher, subscriber := newPubSub(t, conn, natsConfig)
+			bus := natsAdapter.NewNatsEventBus[conformance.Event, conformance.Payload](publisher, subscriber, testkit.NewLogger(t))
+			t.Cleanup(func() { bus.Close() })
+			return bus
+		})
+	})
+}
+
+// connect starts an embedded JetStream server for t and connects to it.
+func connect(t *testing.T) (*nats.Conn, natsAdapter.NatsConfig) {
+	t.Helper()
+	srv, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      server.RANDOM_PORT,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		NoLog:     true,
+		NoSigs:    true,
+	})
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	go srv.Start()
+	t.Cleanup(srv.Shutdown)
+	if !srv.ReadyForConnections(10 * time.Second) {
+		t.Fatalf("NATS server not ready")
+	}
+
+	config := natsAdapter.NatsConfig{
+		URL:        srv.ClientURL(),
+		QueueGroup: "bff",
+		AckWait:    time.Second,
+		NakDelay:   10 * time.Millisecond,
+	}
+	return dial(t, config), config
+}
+
+func dial(t *testing.T, config natsAdapter.NatsConfig) *nats.Conn {
+	t.Helper()
+	conn, err := natsAdapter.NewNatsConnection(config)
+	if err != nil {
+		t.Fatalf("NewNatsConnection() error = %v", err)
+	}
+	t.Cleanup(conn.Close)
+	return conn
+}
+
+func newPubSub(t *testing.T, conn *nats.Conn, config natsAdapter.NatsConfig) (*wmnats.Publisher, *wmnats.Subscriber) {
+	t.Helper()
+	logger := watermillAdapter.NewWatermillLoggerAdapter(testkit.NewLogger(t))
+	publisher, err := natsAdapter.NewNatsPublisher(conn, config, logger)
+	if err != nil {
+		t.Fatalf("NewNatsPublisher() error = %v", err)
+	}
+	t.Cleanup(func() { publisher.Close() })
+	subscriber, err := natsAdapter.NewNatsSubscriber(conn, config, logger)
+	if err != nil {
+		t.Fatalf("NewNatsSubscriber() error = %v", err)
+	}
+	t.Cleanup(func() { subscriber.Close() })
+	return publisher, subscriber
+}
@@ -0,0 +1,19 @@
+package adapter
+
+import (
+	wmnats "github.com/ThreeDotsLabs/watermill-nats/v2/pkg/nats"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	"github.com/mateusmacedo/go-bff/pkg/domain"
+	watermillAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/watermill/adapter"
+)
+
+type NatsCommandBus[C domain.Command[T], T any] struct {
+	*watermillAdapter.WatermillCommandBus[C, T]
+}
+
+func NewNatsCommandBus[C domain.Command[T], T any](publisher *wmnats.Publisher, subscriber *wmnats.Subscriber, logger application.AppLogger, options ...watermillAdapter.Option) *NatsCommandBus[C, T] {
+	return &NatsCommandBus[C, T]{
+		WatermillCommandBus: watermillAdapter.NewWatermillCommandBus[C, T](publisher, subscriber, logger, options...),
+	}
+}
@@ -0,0 +1,19 @@
+package adapter
+
+import (
+	wmnats "github.com/ThreeDotsLabs/watermill-nats/v2/pkg/nats"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	"github.com/mateusmacedo/go-bff/pkg/domain"
+	watermillAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/watermill/adapter"
+)
+
+type NatsEventBus[E domain.Event[D], D any] struct {
+	*watermillAdapter.WatermillEventBus[E, D]
+}
+
+func NewNatsEventBus[E domain.Event[D], D any](publisher *wmnats.Publisher, subscriber *wmnats.Subscriber, logger application.AppLogger, options ...watermillAdapter.Option) *NatsEventBus[E, D] {
+	return &NatsEventBus[E, D]{
+		WatermillEventBus: watermillAdapter.NewWatermillEventBus[E, D](publisher, subscriber, logger, options...),
+	}
+}
@@ -0,0 +1,255 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/ThreeDotsLabs/watermill"
+	wmnats "github.com/ThreeDotsLabs/watermill-nats/v2/pkg/nats"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/nats-io/nats.go"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	"github.com/mateusmacedo/go-bff/pkg/domain"
+	watermillAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/watermill/adapter"
+)
+
+// NatsQueryBus answers queries with core NATS request/reply instead of a
+// reply topic, so no response stream or correlation bookkeeping is needed.
+type NatsQueryBus[Q domain.Query[D], D any, R any] struct {
+	conn          *nats.Conn
+	queueGroup    string
+	marshaler     wmnats.NATSMarshaler
+	payloads      watermillAdapter.PayloadMarshaler
+	handlers      map[string]application.QueryHandler[Q, D, R]
+	subscriptions map[string]*nats.Subscription
+	mu            sync.RWMutex
+	logger        application.AppLogger
+	ctx           context.Context
+	cancel        context.CancelFunc
+}
+
+func NewNatsQueryBus[Q domain.Query[D], D any, R any](conn *nats.Conn, queueGroup string, logger application.AppLogger) *NatsQueryBus[Q, D, R] {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &NatsQueryBus[Q, D, R]{
+		conn:          conn,
+		queueGroup:    queueGroup,
+		payloads:      watermillAdapter.JSONMarshaler{},
+		handlers:      make(map[string]application.QueryHandler[Q, D, R]),
+		subscriptions: make(map[string]*nats.Subscription),
+		logger:        logger,
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+}
+
+func (bus *NatsQueryBus[Q, D, R]) RegisterHandler(queryName string, handler application.QueryHandler[Q, D, R]) error {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+
+	if _, subscribed := bus.subscriptions[queryName]; subscribed {
+		bus.handlers[queryName] = handler
+		return nil
+	}
+
+	subscription, err := bus.conn.QueueSubscribe(queryName, bus.queueGroup, func(request *nats.Msg) {
+		go bus.processRequest(bus.ctx, queryName, request)
+	})
+	if err != nil {
+		application.LogError(bus.ctx, bus.logger, "error subscribing to query", err, map[string]interface{}{
+			"query_name": queryName,
+		})
+		return err
+	}
+	bus.handlers[queryName] = handler
+	bus.subscriptions[queryName] = subscription
+	return nil
+}
+
+func (bus *NatsQueryBus[Q, D, R]) Dispatch(ctx context.Context, query Q) (R, error) {
+	var zero R
+	if err := ctx.Err(); err != nil {
+		application.LogError(ctx, bus.logger, "context done", err, map[string]interface{}{
+			"query_name": query.QueryName(),
+		})
+		return zero, err
+	}
+
+	payload, err := bus.payloads.Marshal(query.Payload())
+	if err != nil {
+		application.LogError(ctx, bus.logger, "error marshalling query payload", err, map[string]interface{}{
+			"query_name": query.QueryName(),
+		})
+		return zero, err
+	}
+
+	msg := message.NewMessage(watermill.NewUUID(), payload)
+	if err := watermillAdapter.InjectContextMetadata(ctx, msg); err != nil {
+		application.LogError(ctx, bus.logger, "error injecting query metadata", err, map[string]interface{}{
+			"query_name": query.QueryName(),
+		})
+		return zero, err
+	}
+
+	request, err := bus.marshaler.Marshal(query.QueryName(), msg)
+	if err != nil {
+		application.LogError(ctx, bus.logger, "error marshalling query message", err, map[string]interface{}{
+			"query_name": query.QueryName(),
+		})
+		return zero, err
+	}
+
+	reply, err := bus.conn.RequestMsgWithContext(ctx, request)
+	if err != nil {
+		application.LogError(ctx, bus.logger, "error requesting query", err, map[string]interface{}{
+			"query_name": query.QueryName(),
+		})
+		return zero, err
+	}
+
+	responseMsg, err := bus.marshaler.Unmarshal(reply)
+	if err != nil {
+		application.LogError(ctx, bus.logger, "error unmarshalling query reply", err, map[string]interface{}{
+			"query_name": query.QueryName(),
+		})
+		return zero, err
+	}
+	if err := watermillAdapter.ErrorFromMetadata(responseMsg.Metadata); err != nil {
+		application.LogError(ctx, bus.logger, "error handling query", err, map[string]interface{}{
+			"query_name": query.QueryName(),
+		})
+		return zero, err
+	}
+
+	var result R
+	if err := bus.payloads.Unmarshal(responseMsg.Payload, &result); err != nil {
+		application.LogError(ctx, bus.logger, "error unmarshalling query response", err, map[string]interface{}{
+			"query_name": query.QueryName(),
+		})
+		return zero, err
+	}
+	return result, nil
+}
+
+func (bus *NatsQueryBus[Q, D, R]) Close() error {
+	bus.cancel()
+
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+
+	var firstErr error
+	for queryName, subscription := range bus.subscriptions {
+		if err := subscription.Unsubscribe(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(bus.subscriptions, queryName)
+	}
+	return firstErr
+}
+
+func (bus *NatsQueryBus[Q, D, R]) processRequest(ctx context.Context, queryName string, request *nats.Msg) {
+	msg, err := bus.marshaler.Unmarshal(request)
+	if err != nil {
+		application.LogError(ctx, bus.logger, "error unmarshalling query message", err, map[string]interface{}{
+			"query_name": queryName,
+		})
+		bus.respond(ctx, queryName, request, nil, err)
+		return
+	}
+
+	ctx, err = watermillAdapter.ExtractContextMetadata(ctx, msg)
+	if err != nil {
+		application.LogError(ctx, bus.logger, "error extracting query metadata", err, map[string]interface{}{
+			"query_name": queryName,
+		})
+		bus.respond(ctx, queryName, request, nil, err)
+		return
+	}
+
+	var payload D
+	if err := bus.payloads.Unmarshal(msg.Payload, &payload); err != nil {
+		application.LogError(ctx, bus.logger, "error unmarshalling query payload", err, map[string]interface{}{
+			"query_name": queryName,
+		})
+		bus.respond(ctx, queryName, request, nil, err)
+		return
+	}
+
+	query := &dynamicQuery[D]{
+		queryName: queryName,
+		payload:   payload,
+	}
+
+	typedQuery, ok := interface{}(query).(Q)
+	if !ok {
+		err := errors.New("error asserting query type")
+		application.LogError(ctx, bus.logger, "error asserting query type", nil, map[string]interface{}{
+			"query_name": queryName,
+		})
+		bus.respond(ctx, queryName, request, nil, err)
+		return
+	}
+
+	bus.mu.RLock()
+	handler := bus.handlers[queryName]
+	bus.mu.RUnlock()
+
+	result, err := handler.Handle(ctx, typedQuery)
+	if err != nil {
+		application.LogError(ctx, bus.logger, "error handling query", err, map[string]interface{}{
+			"query_name": queryName,
+		})
+		bus.respond(ctx, queryName, request, nil, err)
+		return
+	}
+
+	responsePayload, err := bus.payloads.Marshal(result)
+	if err != nil {
+		application.LogError(ctx, bus.logger, "error marshalling query response", err, map[string]interface{}{
+			"query_name": queryName,
+		})
+		bus.respond(ctx, queryName, request, nil, err)
+		return
+	}
+
+	bus.respond(ctx, queryName, request, responsePayload, nil)
+	application.LogInfo(ctx, bus.logger, "query handled", map[string]interface{}{
+		"query_name": queryName,
+	})
+}
+
+func (bus *NatsQueryBus[Q, D, R]) respond(ctx context.Context, queryName string, request *nats.Msg, payload []byte, handleErr error) {
+	responseMsg := message.NewMessage(watermill.NewUUID(), payload)
+	if handleErr != nil {
+		watermillAdapter.SetErrorMetadata(responseMsg.Metadata, handleErr)
+	}
+	if err := watermillAdapter.InjectContextMetadata(ctx, responseMsg); err != nil {
+		application.LogError(ctx, bus.logger, "error injecting query response metadata", err, map[string]interface{}{
+			"query_name": queryName,
+		})
+	}
+
+	reply, err := bus.marshaler.Marshal(request.Reply, responseMsg)
+	if err == nil {
+		err = request.RespondMsg(reply)
+	}
+	if err != nil {
+		application.LogError(ctx, bus.logger, "error publishing query response", err, map[string]interface{}{
+			"query_name": queryName,
+		})
+	}
+}
+
+type dynamicQuery[D any] struct {
+	queryName string
+	payload   D
+}
+
+func (q *dynamicQuery[D]) QueryName() string {
+	return q.queryName
+}
+
+func (q *dynamicQuery[D]) Payload() D {
+	return q.payload
+}
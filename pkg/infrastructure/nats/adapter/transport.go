@@ -0,0 +1,155 @@
+package adapter
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	wmnats "github.com/ThreeDotsLabs/watermill-nats/v2/pkg/nats"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/nats-io/nats.go"
+
+	watermillAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/watermill/adapter"
+)
+
+type NatsConfig struct {
+	URL string
+	// QueueGroup names the durable consumers; replicas sharing it split the
+	// work of each topic instead of all receiving every message.
+	QueueGroup string
+	// StreamName provisions one stream over StreamSubjects instead of one
+	// stream per topic. Query subjects must stay outside of it, otherwise
+	// the stream acknowledges requests before any handler replies.
+	StreamName       string
+	StreamSubjects   []string
+	AckWait          time.Duration
+	NakDelay         time.Duration
+	MaxDeliver       int
+	SubscribersCount int
+}
+
+func (c NatsConfig) withDefaults() NatsConfig {
+	if c.URL == "" {
+		c.URL = nats.DefaultURL
+	}
+	if c.AckWait <= 0 {
+		c.AckWait = 30 * time.Second
+	}
+	if c.NakDelay <= 0 {
+		c.NakDelay = time.Second
+	}
+	return c
+}
+
+func NewNatsConnection(config NatsConfig) (*nats.Conn, error) {
+	config = config.withDefaults()
+	return nats.Connect(config.URL,
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+		nats.ReconnectWait(time.Second),
+	)
+}
+
+// ProvisionStream creates the stream configured by StreamName, or updates
+// its subjects when it already exists.
+func ProvisionStream(conn *nats.Conn, config NatsConfig) error {
+	if config.StreamName == "" {
+		return nil
+	}
+	if len(config.StreamSubjects) == 0 {
+		return errors.New("nats: stream subjects are required")
+	}
+
+	js, err := conn.JetStream()
+	if err != nil {
+		return err
+	}
+
+	streamConfig := &nats.StreamConfig{
+		Name:      config.StreamName,
+		Subjects:  config.StreamSubjects,
+		Retention: nats.LimitsPolicy,
+		Storage:   nats.FileStorage,
+	}
+	if _, err := js.StreamInfo(config.StreamName); errors.Is(err, nats.ErrStreamNotFound) {
+		_, err = js.AddStream(streamConfig)
+		return err
+	} else if err != nil {
+		return err
+	}
+	_, err = js.UpdateStream(streamConfig)
+	return err
+}
+
+func NewNatsPublisher(conn *nats.Conn, config NatsConfig, logger watermill.LoggerAdapter) (*wmnats.Publisher, error) {
+	config = config.withDefaults()
+	return wmnats.NewPublisherWithNatsConn(conn, wmnats.PublisherPublishConfig{
+		Marshaler:         &wmnats.NATSMarshaler{},
+		SubjectCalculator: subjectCalculator,
+		JetStream:         jetStreamConfig(config),
+	}, logger)
+}
+
+func NewNatsSubscriber(conn *nats.Conn, config NatsConfig, logger watermill.LoggerAdapter) (*wmnats.Subscriber, error) {
+	config = config.withDefaults()
+	return wmnats.NewSubscriberWithNatsConn(conn, wmnats.SubscriberSubscriptionConfig{
+		Unmarshaler:       &wmnats.NATSMarshaler{},
+		SubscribersCount:  config.SubscribersCount,
+		AckWaitTimeout:    config.AckWait,
+		SubjectCalculator: subjectCalculator,
+		NakDelay:          nakDelay(config),
+		JetStream:         jetStreamConfig(config),
+		QueueGroupPrefix:  config.QueueGroup,
+	}, logger)
+}
+
+func NatsSubscriberFactory(conn *nats.Conn, config NatsConfig, logger watermill.LoggerAdapter) watermillAdapter.SubscriberFactory {
+	return func(consumerGroup string) (message.Subscriber, error) {
+		groupConfig := config
+		groupConfig.QueueGroup = consumerGroup
+		return NewNatsSubscriber(conn, groupConfig, logger)
+	}
+}
+
+func jetStreamConfig(config NatsConfig) wmnats.JetStreamConfig {
+	subscribeOptions := []nats.SubOpt{
+		nats.AckExplicit(),
+		nats.DeliverAll(),
+		nats.AckWait(config.AckWait),
+	}
+	if config.MaxDeliver > 0 {
+		subscribeOptions = append(subscribeOptions, nats.MaxDeliver(config.MaxDeliver))
+	}
+
+	return wmnats.JetStreamConfig{
+		AutoProvision:     config.StreamName == "",
+		SubscribeOptions:  subscribeOptions,
+		TrackMsgId:        true,
+		DurablePrefix:     config.QueueGroup,
+		DurableCalculator: durableName,
+	}
+}
+
+func nakDelay(config NatsConfig) wmnats.Delay {
+	if config.MaxDeliver > 0 {
+		return wmnats.NewMaxRetryDelay(config.NakDelay, uint64(config.MaxDeliver))
+	}
+	return wmnats.NewStaticDelay(config.NakDelay)
+}
+
+// subjectCalculator keeps the queue group equal to the durable name, which
+// JetStream requires when both are set on a queue subscription.
+func subjectCalculator(queueGroupPrefix, topic string) *wmnats.SubjectDetail {
+	return &wmnats.SubjectDetail{
+		Primary:    topic,
+		QueueGroup: durableName(queueGroupPrefix, topic),
+	}
+}
+
+func durableName(prefix, topic string) string {
+	if prefix == "" {
+		return ""
+	}
+	return prefix + "_" + strings.NewReplacer(".", "_", "*", "_", ">", "_", " ", "_").Replace(topic)
+}
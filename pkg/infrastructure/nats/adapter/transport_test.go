@@ -0,0 +1,141 @@
+package adapter_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+
+	natsAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/nats/adapter"
+)
+
+func TestNatsTransport(t *testing.T) {
+	t.Run("keeps messages for a durable consumer that is away", func(t *testing.T) {
+		conn, config := connect(t)
+		publisher, _ := newPubSub(t, conn, config)
+		// Closing a subscriber closes its connection, so each replica dials.
+		_, away := newPubSub(t, dial(t, config), config)
+		subscribe(t, away, "BusTicketBooked")
+		away.Close()
+
+		publishAll(t, publisher, "BusTicketBooked", "m1")
+
+		_, back := newPubSub(t, dial(t, config), config)
+		msg := receive(t, subscribe(t, back, "BusTicketBooked"))
+		if msg.UUID != "m1" {
+			t.Fatalf("delivered %s, want m1", msg.UUID)
+		}
+		msg.Ack()
+	})
+
+	t.Run("shares the messages of a topic within a queue group", func(t *testing.T) {
+		conn, config := connect(t)
+		publisher, first := newPubSub(t, conn, config)
+		_, second := newPubSub(t, dial(t, config), config)
+		messages := []<-chan *message.Message{subscribe(t, first, "ReserveBusTicket"), subscribe(t, second, "ReserveBusTicket")}
+
+		const n = 10
+		uuids := make([]string, n)
+		for i := range uuids {
+			uuids[i] = fmt.Sprint("m", i)
+		}
+		publishAll(t, publisher, "ReserveBusTicket", uuids...)
+
+		var delivered []string
+		for len(delivered) < n {
+			msg := receive(t, messages...)
+			delivered = append(delivered, msg.UUID)
+			msg.Ack()
+		}
+		slices.Sort(delivered)
+		slices.Sort(uuids)
+		if !slices.Equal(delivered, uuids) {
+			t.Fatalf("delivered %v, want each of %v once", delivered, uuids)
+		}
+	})
+
+	t.Run("redelivers nacked messages", func(t *testing.T) {
+		conn, config := connect(t)
+		publisher, subscriber := newPubSub(t, conn, config)
+		messages := subscribe(t, subscriber, "BusTicketBooked")
+		publishAll(t, publisher, "BusTicketBooked", "m1")
+
+		receive(t, messages).Nack()
+		msg := receive(t, messages)
+		if msg.UUID != "m1" {
+			t.Fatalf("redelivered %s, want m1", msg.UUID)
+		}
+		msg.Ack()
+	})
+
+	t.Run("provisions and updates the configured stream", func(t *testing.T) {
+		conn, config := connect(t)
+		config.StreamName = "BFF"
+		config.StreamSubjects = []string{"ReserveBusTicket"}
+		if err := natsAdapter.ProvisionStream(conn, config); err != nil {
+			t.Fatalf("ProvisionStream() error = %v", err)
+		}
+		config.StreamSubjects = append(config.StreamSubjects, "BusTicketBooked")
+		if err := natsAdapter.ProvisionStream(conn, config); err != nil {
+			t.Fatalf("ProvisionStream() of an existing stream error = %v", err)
+		}
+
+		js, err := conn.JetStream()
+		if err != nil {
+			t.Fatalf("JetStream() error = %v", err)
+		}
+		info, err := js.StreamInfo("BFF")
+		if err != nil || !slices.Equal(info.Config.Subjects, config.StreamSubjects) {
+			t.Fatalf("StreamInfo() = %+v, %v, want subjects %v", info, err, config.StreamSubjects)
+		}
+
+		config.StreamSubjects = nil
+		if err := natsAdapter.ProvisionStream(conn, config); err == nil {
+			t.Fatalf("ProvisionStream() without subjects succeeded")
+		}
+	})
+}
+
+func subscribe(t *testing.T, subscriber message.Subscriber, topic string) <-chan *message.Message {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	messages, err := subscriber.Subscribe(ctx, topic)
+	if err != nil {
+		t.Fatalf("Subscribe(%s) error = %v", topic, err)
+	}
+	return messages
+}
+
+func publishAll(t *testing.T, publisher message.Publisher, topic string, uuids ...string) {
+	t.Helper()
+	for _, uuid := range uuids {
+		if err := publisher.Publish(topic, message.NewMessage(uuid, []byte(uuid))); err != nil {
+			t.Fatalf("Publish(%s) error = %v", uuid, err)
+		}
+	}
+}
+
+// receive waits for a message from any of subscriptions.
+func receive(t *testing.T, subscriptions ...<-chan *message.Message) *message.Message {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		for _, messages := range subscriptions {
+			select {
+			case msg := <-messages:
+				return msg
+			default:
+			}
+		}
+		select {
+		case <-timeout:
+			t.Fatalf("no message delivered")
+			return nil
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
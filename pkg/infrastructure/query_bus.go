@@ -22,10 +22,11 @@ func NewSimpleQueryBus[Q domain.Query[D], D any, R any](logger application.AppLo
 	}
 }
 
-func (bus *simpleQueryBus[Q, D, R]) RegisterHandler(queryName string, handler application.QueryHandler[Q, D, R]) {
+func (bus *simpleQueryBus[Q, D, R]) RegisterHandler(queryName string, handler application.QueryHandler[Q, D, R]) error {
 	bus.mu.Lock()
 	defer bus.mu.Unlock()
 	bus.handlers[queryName] = handler
+	return nil
 }
 
 func (bus *simpleQueryBus[Q, D, R]) Dispatch(ctx context.Context, query Q) (R, error) {
@@ -34,6 +35,13 @@ func (bus *simpleQueryBus[Q, D, R]) Dispatch(ctx context.Context, query Q) (R, e
 	bus.mu.RUnlock()
 
 	var zero R
+	if err := ctx.Err(); err != nil {
+		application.LogError(ctx, bus.logger, "context done", err, map[string]interface{}{
+			"query_name": query.QueryName(),
+		})
+		return zero, err
+	}
+
 	if !found {
 		err := errors.New("no handler registered for query")
 		application.LogError(ctx, bus.logger, "no handler registered for query", err, map[string]interface{}{
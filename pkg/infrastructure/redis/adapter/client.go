@@ -6,11 +6,17 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
-func NewRedisClient() redis.UniversalClient {
+type RedisClientConfig struct {
+	Addr     string
+	Password string
+	DB       int
+}
+
+func NewRedisClient(config RedisClientConfig) redis.UniversalClient {
 	return redis.NewClient(&redis.Options{
-		Addr:     "localhost:6379",
-		Password: "",
-		DB:       0,
+		Addr:     config.Addr,
+		Password: config.Password,
+		DB:       config.DB,
 	})
 }
 
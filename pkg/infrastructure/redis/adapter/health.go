@@ -0,0 +1,18 @@
+package adapter
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+)
+
+func NewRedisHealthCheck(client redis.UniversalClient) application.HealthCheck {
+	return application.HealthCheck{
+		Name: "redis",
+		Check: func(ctx context.Context) error {
+			return client.Ping(ctx).Err()
+		},
+	}
+}
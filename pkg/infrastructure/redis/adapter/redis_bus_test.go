@@ -0,0 +1,62 @@
+package adapter_test
+
+import (
+	"testing"
+
+	"github.com/ThreeDotsLabs/watermill-redisstream/pkg/redisstream"
+	"github.com/alicebob/miniredis/v2"
+
+	redisAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/redis/adapter"
+	watermillAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/watermill/adapter"
+	"github.com/mateusmacedo/go-bff/pkg/testkit"
+	"github.com/mateusmacedo/go-bff/pkg/testkit/conformance"
+)
+
+func TestRedisBuses(t *testing.T) {
+	config := conformance.Config{Delivery: conformance.Asynchronous}
+
+	t.Run("command bus", func(t *testing.T) {
+		conformance.TestCommandBus(t, config, func(t *testing.T) conformance.CommandBus {
+			publisher, subscriber := newPubSub(t)
+			bus := redisAdapter.NewRedisCommandBus[conformance.Command, conformance.Payload](publisher, subscriber, testkit.NewLogger(t))
+			t.Cleanup(func() { bus.Close() })
+			return bus
+		})
+	})
+	t.Run("query bus", func(t *testing.T) {
+		conformance.TestQueryBus(t, config, func(t *testing.T) conformance.QueryBus {
+			publisher, subscriber := newPubSub(t)
+			bus := redisAdapter.NewRedisQueryBus[conformance.Query, conformance.Payload, conformance.Payload](publisher, subscriber, testkit.NewLogger(t))
+			t.Cleanup(func() { bus.Close() })
+			return bus
+		})
+	})
+	t.Run("event bus", func(t *testing.T) {
+		conformance.TestEventBus(t, config, func(t *testing.T) conformance.EventBus {
+			publisher, subscriber := newPubSub(t)
+			bus := redisAdapter.NewRedisEventBus[conformance.Event, conformance.Payload](publisher, subscriber, testkit.NewLogger(t))
+			t.Cleanup(func() { bus.Close() })
+			return bus
+		})
+	})
+}
+
+// newPubSub opens Redis Streams on a fresh miniredis server.
+func newPubSub(t *testing.T) (*redisstream.Publisher, *redisstream.Subscriber) {
+	t.Helper()
+	client := redisAdapter.NewRedisClient(redisAdapter.RedisClientConfig{Addr: miniredis.RunT(t).Addr()})
+	t.Cleanup(func() { client.Close() })
+	logger := watermillAdapter.NewWatermillLoggerAdapter(testkit.NewLogger(t))
+
+	publisher, err := redisAdapter.NewRedisPublisher(client, logger)
+	if err != nil {
+		t.Fatalf("NewRedisPublisher() error = %v", err)
+	}
+	t.Cleanup(func() { publisher.Close() })
+	subscriber, err := redisAdapter.NewRedisSubscriber(client, redisAdapter.RedisStreamConfig{ConsumerGroup: "bff", Consumer: "bff-1"}, logger)
+	if err != nil {
+		t.Fatalf("NewRedisSubscriber() error = %v", err)
+	}
+	t.Cleanup(func() { subscriber.Close() })
+	return publisher, subscriber
+}
@@ -0,0 +1,37 @@
+package adapter
+
+import (
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill-redisstream/pkg/redisstream"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/redis/go-redis/v9"
+
+	watermillAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/watermill/adapter"
+)
+
+type RedisStreamConfig struct {
+	ConsumerGroup string
+	Consumer      string
+}
+
+func NewRedisPublisher(client redis.UniversalClient, logger watermill.LoggerAdapter) (*redisstream.Publisher, error) {
+	return redisstream.NewPublisher(redisstream.PublisherConfig{
+		Client: client,
+	}, logger)
+}
+
+func NewRedisSubscriber(client redis.UniversalClient, config RedisStreamConfig, logger watermill.LoggerAdapter) (*redisstream.Subscriber, error) {
+	return redisstream.NewSubscriber(redisstream.SubscriberConfig{
+		Client:        client,
+		ConsumerGroup: config.ConsumerGroup,
+		Consumer:      config.Consumer,
+	}, logger)
+}
+
+func RedisSubscriberFactory(client redis.UniversalClient, config RedisStreamConfig, logger watermill.LoggerAdapter) watermillAdapter.SubscriberFactory {
+	return func(consumerGroup string) (message.Subscriber, error) {
+		groupConfig := config
+		groupConfig.ConsumerGroup = consumerGroup
+		return NewRedisSubscriber(client, groupConfig, logger)
+	}
+}
@@ -0,0 +1,88 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	"github.com/mateusmacedo/go-bff/pkg/domain"
+)
+
+// RetryPolicy bounds how often a retrying command bus runs a handler.
+type RetryPolicy struct {
+	// MaxAttempts counts the first run; values below 1 mean a single run.
+	MaxAttempts int
+	// Backoff is the wait before the second attempt, doubled before each
+	// later one.
+	Backoff time.Duration
+	// Retryable selects the errors worth another attempt; nil retries
+	// domain.ErrConcurrencyConflict.
+	Retryable func(error) bool
+}
+
+func (p RetryPolicy) retryable(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return errors.Is(err, domain.ErrConcurrencyConflict)
+}
+
+type retryingCommandBus[C domain.Command[D], D any] struct {
+	next   application.CommandBus[C, D]
+	policy RetryPolicy
+	logger application.AppLogger
+}
+
+// NewRetryingCommandBus retries the handlers registered through it, so the
+// retries happen wherever the handler runs, whatever the transport.
+func NewRetryingCommandBus[C domain.Command[D], D any](next application.CommandBus[C, D], policy RetryPolicy, logger application.AppLogger) application.CommandBus[C, D] {
+	return &retryingCommandBus[C, D]{
+		next:   next,
+		policy: policy,
+		logger: logger,
+	}
+}
+
+func (bus *retryingCommandBus[C, D]) RegisterHandler(commandName string, handler application.CommandHandler[C, D]) error {
+	return bus.next.RegisterHandler(commandName, &retryingCommandHandler[C, D]{
+		next:   handler,
+		policy: bus.policy,
+		logger: bus.logger,
+	})
+}
+
+func (bus *retryingCommandBus[C, D]) Dispatch(ctx context.Context, command C) error {
+	return bus.next.Dispatch(ctx, command)
+}
+
+type retryingCommandHandler[C domain.Command[D], D any] struct {
+	next   application.CommandHandler[C, D]
+	policy RetryPolicy
+	logger application.AppLogger
+}
+
+func (h *retryingCommandHandler[C, D]) Handle(ctx context.Context, command C) error {
+	backoff := h.policy.Backoff
+	for attempt := 1; ; attempt++ {
+		err := h.next.Handle(ctx, command)
+		if err == nil || attempt >= h.policy.MaxAttempts || !h.policy.retryable(err) {
+			return err
+		}
+
+		application.LogInfo(ctx, h.logger, "retrying command", map[string]interface{}{
+			"command_name": command.CommandName(),
+			"attempt":      attempt,
+			"error":        err.Error(),
+		})
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
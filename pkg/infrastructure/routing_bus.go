@@ -0,0 +1,173 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	"github.com/mateusmacedo/go-bff/pkg/domain"
+)
+
+type routingCommandBus[C domain.Command[T], T any] struct {
+	table    RoutingTable
+	backends map[string]application.CommandBus[C, T]
+	logger   application.AppLogger
+}
+
+func NewRoutingCommandBus[C domain.Command[T], T any](table RoutingTable, backends map[string]application.CommandBus[C, T], logger application.AppLogger) (application.CommandBus[C, T], error) {
+	if err := table.Validate(backendNames(backends)); err != nil {
+		return nil, err
+	}
+	return &routingCommandBus[C, T]{
+		table:    table,
+		backends: backends,
+		logger:   logger,
+	}, nil
+}
+
+func (bus *routingCommandBus[C, T]) RegisterHandler(commandName string, handler application.CommandHandler[C, T]) error {
+	backend, err := bus.backend(commandName)
+	if err != nil {
+		application.LogError(context.Background(), bus.logger, "error routing command handler", err, map[string]interface{}{
+			"command_name": commandName,
+		})
+		return err
+	}
+	return backend.RegisterHandler(commandName, handler)
+}
+
+func (bus *routingCommandBus[C, T]) Dispatch(ctx context.Context, command C) error {
+	backend, err := bus.backend(command.CommandName())
+	if err != nil {
+		application.LogError(ctx, bus.logger, "error routing command", err, map[string]interface{}{
+			"command_name": command.CommandName(),
+		})
+		return err
+	}
+	return backend.Dispatch(ctx, command)
+}
+
+func (bus *routingCommandBus[C, T]) backend(commandName string) (application.CommandBus[C, T], error) {
+	name := bus.table.CommandBackend(commandName)
+	backend, found := bus.backends[name]
+	if !found {
+		return nil, fmt.Errorf("command %s: %w", commandName, ErrNoRoute)
+	}
+	return backend, nil
+}
+
+type routingQueryBus[Q domain.Query[D], D any, R any] struct {
+	table    RoutingTable
+	backends map[string]application.QueryBus[Q, D, R]
+	logger   application.AppLogger
+}
+
+func NewRoutingQueryBus[Q domain.Query[D], D any, R any](table RoutingTable, backends map[string]application.QueryBus[Q, D, R], logger application.AppLogger) (application.QueryBus[Q, D, R], error) {
+	if err := table.Validate(backendNames(backends)); err != nil {
+		return nil, err
+	}
+	return &routingQueryBus[Q, D, R]{
+		table:    table,
+		backends: backends,
+		logger:   logger,
+	}, nil
+}
+
+func (bus *routingQueryBus[Q, D, R]) RegisterHandler(queryName string, handler application.QueryHandler[Q, D, R]) error {
+	backend, err := bus.backend(queryName)
+	if err != nil {
+		application.LogError(context.Background(), bus.logger, "error routing query handler", err, map[string]interface{}{
+			"query_name": queryName,
+		})
+		return err
+	}
+	return backend.RegisterHandler(queryName, handler)
+}
+
+func (bus *routingQueryBus[Q, D, R]) Dispatch(ctx context.Context, query Q) (R, error) {
+	backend, err := bus.backend(query.QueryName())
+	if err != nil {
+		application.LogError(ctx, bus.logger, "error routing query", err, map[string]interface{}{
+			"query_name": query.QueryName(),
+		})
+		var zero R
+		return zero, err
+	}
+	return backend.Dispatch(ctx, query)
+}
+
+func (bus *routingQueryBus[Q, D, R]) backend(queryName string) (application.QueryBus[Q, D, R], error) {
+	name := bus.table.QueryBackend(queryName)
+	backend, found := bus.backends[name]
+	if !found {
+		return nil, fmt.Errorf("query %s: %w", queryName, ErrNoRoute)
+	}
+	return backend, nil
+}
+
+type routingEventBus[E domain.Event[T], T any] struct {
+	table    RoutingTable
+	backends map[string]application.EventBus[E, T]
+	logger   application.AppLogger
+}
+
+func NewRoutingEventBus[E domain.Event[T], T any](table RoutingTable, backends map[string]application.EventBus[E, T], logger application.AppLogger) (application.EventBus[E, T], error) {
+	if err := table.Validate(backendNames(backends)); err != nil {
+		return nil, err
+	}
+	return &routingEventBus[E, T]{
+		table:    table,
+		backends: backends,
+		logger:   logger,
+	}, nil
+}
+
+// RegisterHandler subscribes on every subscribe backend and joins their
+// errors, as Publish does.
+func (bus *routingEventBus[E, T]) RegisterHandler(eventName string, handler application.EventHandler[E, T]) error {
+	var errs []error
+	for _, name := range bus.table.EventRoute(eventName).Subscribe {
+		backend, found := bus.backends[name]
+		if !found {
+			application.LogError(context.Background(), bus.logger, "error routing event handler", ErrNoRoute, map[string]interface{}{
+				"event_name": eventName,
+				"backend":    name,
+			})
+			errs = append(errs, fmt.Errorf("event %s on %s: %w", eventName, name, ErrNoRoute))
+			continue
+		}
+		if err := backend.RegisterHandler(eventName, handler); err != nil {
+			errs = append(errs, fmt.Errorf("event %s on %s: %w", eventName, name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Publish tries every publish backend and joins their errors, so one broken
+// transport does not keep the event from the others.
+func (bus *routingEventBus[E, T]) Publish(ctx context.Context, event E) error {
+	var errs []error
+	for _, name := range bus.table.EventRoute(event.EventName()).Publish {
+		backend, found := bus.backends[name]
+		if !found {
+			errs = append(errs, fmt.Errorf("event %s on %s: %w", event.EventName(), name, ErrNoRoute))
+			continue
+		}
+		if err := backend.Publish(ctx, event); err != nil {
+			errs = append(errs, fmt.Errorf("event %s on %s: %w", event.EventName(), name, err))
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		application.LogError(ctx, bus.logger, "error routing event", err, map[string]interface{}{
+			"event_name": event.EventName(),
+		})
+		return err
+	}
+	return nil
+}
+
+func backendNames[B any](backends map[string]B) []string {
+	return sortedKeys(backends)
+}
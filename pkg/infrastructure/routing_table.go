@@ -0,0 +1,126 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var ErrNoRoute = errors.New("no route for message")
+
+// RoutingTable names the backend each message goes through. Backend names
+// are the keys of the bus maps given to the routing buses; messages without
+// an entry use Default.
+type RoutingTable struct {
+	Default  string                `json:"default"`
+	Commands map[string]string     `json:"commands,omitempty"`
+	Queries  map[string]string     `json:"queries,omitempty"`
+	Events   map[string]EventRoute `json:"events,omitempty"`
+}
+
+// EventRoute publishes to every backend in Publish and registers handlers on
+// Subscribe, which defaults to the first publish backend so local handlers do
+// not run once per backend.
+type EventRoute struct {
+	Publish   []string `json:"publish"`
+	Subscribe []string `json:"subscribe,omitempty"`
+}
+
+func ParseRoutingTable(data []byte) (RoutingTable, error) {
+	var table RoutingTable
+	if err := json.Unmarshal(data, &table); err != nil {
+		return RoutingTable{}, err
+	}
+	return table, nil
+}
+
+func (t RoutingTable) CommandBackend(commandName string) string {
+	if backend, found := t.Commands[commandName]; found {
+		return backend
+	}
+	return t.Default
+}
+
+func (t RoutingTable) QueryBackend(queryName string) string {
+	if backend, found := t.Queries[queryName]; found {
+		return backend
+	}
+	return t.Default
+}
+
+func (t RoutingTable) EventRoute(eventName string) EventRoute {
+	route, found := t.Events[eventName]
+	if !found || len(route.Publish) == 0 {
+		route.Publish = []string{t.Default}
+	}
+	if len(route.Subscribe) == 0 {
+		route.Subscribe = route.Publish[:1]
+	}
+	return route
+}
+
+// Backends lists, sorted, every backend the table references.
+func (t RoutingTable) Backends() []string {
+	referenced := map[string]bool{t.Default: true}
+	for _, backend := range t.Commands {
+		referenced[backend] = true
+	}
+	for _, backend := range t.Queries {
+		referenced[backend] = true
+	}
+	for name := range t.Events {
+		route := t.EventRoute(name)
+		for _, backend := range append(route.Publish, route.Subscribe...) {
+			referenced[backend] = true
+		}
+	}
+	delete(referenced, "")
+	return sortedKeys(referenced)
+}
+
+// Validate reports a missing default and every backend the table references
+// that is not in backends.
+func (t RoutingTable) Validate(backends []string) error {
+	known := make(map[string]bool, len(backends))
+	for _, backend := range backends {
+		known[backend] = true
+	}
+
+	var errs []error
+	check := func(kind, name, backend string) {
+		if !known[backend] {
+			errs = append(errs, fmt.Errorf("%s %q routed to unknown backend %q", kind, name, backend))
+		}
+	}
+
+	if t.Default == "" {
+		errs = append(errs, errors.New("routing table has no default backend"))
+	} else {
+		check("default", "", t.Default)
+	}
+	for _, name := range sortedKeys(t.Commands) {
+		check("command", name, t.Commands[name])
+	}
+	for _, name := range sortedKeys(t.Queries) {
+		check("query", name, t.Queries[name])
+	}
+	for _, name := range sortedKeys(t.Events) {
+		route := t.EventRoute(name)
+		for _, backends := range [][]string{route.Publish, route.Subscribe} {
+			for _, backend := range backends {
+				check("event", name, backend)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
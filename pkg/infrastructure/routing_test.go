@@ -0,0 +1,226 @@
+package infrastructure_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	pkgApp "github.com/mateusmacedo/go-bff/pkg/application"
+	pkgInfra "github.com/mateusmacedo/go-bff/pkg/infrastructure"
+	"github.com/mateusmacedo/go-bff/pkg/testkit"
+	"github.com/mateusmacedo/go-bff/pkg/testkit/conformance"
+)
+
+var backends = []string{"local", "kafka", "redis"}
+
+func TestRoutingTableValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		table string
+		want  []string
+	}{
+		{name: "default only", table: `{"default": "local"}`},
+		{
+			name:  "every kind of route",
+			table: `{"default": "local", "commands": {"Reserve": "kafka"}, "queries": {"Find": "local"}, "events": {"Booked": {"publish": ["local", "redis"]}}}`,
+		},
+		{name: "missing default", table: `{"commands": {"Reserve": "kafka"}}`, want: []string{"no default backend"}},
+		{name: "unknown default", table: `{"default": "carrier"}`, want: []string{`default "" routed to unknown backend "carrier"`}},
+		{
+			name:  "unknown backends",
+			table: `{"default": "local", "commands": {"Reserve": "rabbit"}, "queries": {"Find": "sqs"}, "events": {"Booked": {"publish": ["local"], "subscribe": ["pulsar"]}}}`,
+			want: []string{
+				`command "Reserve" routed to unknown backend "rabbit"`,
+				`query "Find" routed to unknown backend "sqs"`,
+				`event "Booked" routed to unknown backend "pulsar"`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, err := pkgInfra.ParseRoutingTable([]byte(tt.table))
+			if err != nil {
+				t.Fatalf("ParseRoutingTable() error = %v", err)
+			}
+
+			err = table.Validate(backends)
+			if len(tt.want) == 0 && err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if len(tt.want) > 0 && err == nil {
+				t.Fatalf("Validate() succeeded, want %q", tt.want)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Validate() error = %v, want it to mention %q", err, want)
+				}
+			}
+		})
+	}
+
+	t.Run("malformed JSON", func(t *testing.T) {
+		if _, err := pkgInfra.ParseRoutingTable([]byte(`{"default": `)); err == nil {
+			t.Fatalf("ParseRoutingTable() succeeded on malformed JSON")
+		}
+	})
+}
+
+func TestRoutingTableRoutes(t *testing.T) {
+	table := pkgInfra.RoutingTable{
+		Default:  "local",
+		Commands: map[string]string{"Reserve": "kafka"},
+		Queries:  map[string]string{"Find": "redis"},
+		Events: map[string]pkgInfra.EventRoute{
+			"Booked":    {Publish: []string{"local", "redis"}},
+			"Cancelled": {Publish: []string{"kafka"}, Subscribe: []string{"local", "kafka"}},
+			"Delayed":   {},
+		},
+	}
+
+	if got := table.CommandBackend("Reserve"); got != "kafka" {
+		t.Errorf("CommandBackend(Reserve) = %q, want kafka", got)
+	}
+	if got := table.CommandBackend("Cancel"); got != "local" {
+		t.Errorf("CommandBackend(Cancel) = %q, want the default", got)
+	}
+	if got := table.QueryBackend("Find"); got != "redis" {
+		t.Errorf("QueryBackend(Find) = %q, want redis", got)
+	}
+	if got := table.QueryBackend("Search"); got != "local" {
+		t.Errorf("QueryBackend(Search) = %q, want the default", got)
+	}
+
+	routes := []struct {
+		event string
+		want  pkgInfra.EventRoute
+	}{
+		{"Booked", pkgInfra.EventRoute{Publish: []string{"local", "redis"}, Subscribe: []string{"local"}}},
+		{"Cancelled", pkgInfra.EventRoute{Publish: []string{"kafka"}, Subscribe: []string{"local", "kafka"}}},
+		{"Delayed", pkgInfra.EventRoute{Publish: []string{"local"}, Subscribe: []string{"local"}}},
+		{"Unlisted", pkgInfra.EventRoute{Publish: []string{"local"}, Subscribe: []string{"local"}}},
+	}
+	for _, route := range routes {
+		if got := table.EventRoute(route.event); !reflect.DeepEqual(got, route.want) {
+			t.Errorf("EventRoute(%s) = %+v, want %+v", route.event, got, route.want)
+		}
+	}
+
+	if got, want := table.Backends(), []string{"kafka", "local", "redis"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Backends() = %v, want %v", got, want)
+	}
+	if got := (pkgInfra.RoutingTable{Default: "local"}).Backends(); !reflect.DeepEqual(got, []string{"local"}) {
+		t.Errorf("Backends() of a default-only table = %v, want [local]", got)
+	}
+}
+
+func TestRoutingBuses(t *testing.T) {
+	table := pkgInfra.RoutingTable{
+		Default:  "local",
+		Commands: map[string]string{"Reserve": "kafka"},
+		Queries:  map[string]string{"Find": "redis"},
+		Events:   map[string]pkgInfra.EventRoute{"Booked": {Publish: []string{"local", "redis"}}},
+	}
+	logger := testkit.NewLogger(t)
+
+	t.Run("dispatches a command to its backend", func(t *testing.T) {
+		local := testkit.NewRecordingCommandBus[conformance.Command, conformance.Payload]()
+		kafka := testkit.NewRecordingCommandBus[conformance.Command, conformance.Payload]()
+		redis := testkit.NewRecordingCommandBus[conformance.Command, conformance.Payload]()
+		bus, err := pkgInfra.NewRoutingCommandBus(table, map[string]pkgApp.CommandBus[conformance.Command, conformance.Payload]{"local": local, "kafka": kafka, "redis": redis}, logger)
+		if err != nil {
+			t.Fatalf("NewRoutingCommandBus() error = %v", err)
+		}
+
+		for _, name := range []string{"Reserve", "Cancel"} {
+			if err := bus.Dispatch(context.Background(), conformance.NewCommand(name, conformance.Payload{})); err != nil {
+				t.Fatalf("Dispatch(%s) error = %v", name, err)
+			}
+		}
+		kafka.ExpectDispatched("Reserve").Once(t)
+		kafka.ExpectDispatched("Cancel").Never(t)
+		local.ExpectDispatched("Cancel").Once(t)
+		local.ExpectDispatched("Reserve").Never(t)
+		if len(redis.Dispatched()) != 0 {
+			t.Fatalf("dispatched %d commands on redis, want none", len(redis.Dispatched()))
+		}
+	})
+
+	t.Run("dispatches a query to its backend", func(t *testing.T) {
+		local := testkit.NewRecordingQueryBus[conformance.Query, conformance.Payload, conformance.Payload]()
+		redis := testkit.NewRecordingQueryBus[conformance.Query, conformance.Payload, conformance.Payload]()
+		kafka := testkit.NewRecordingQueryBus[conformance.Query, conformance.Payload, conformance.Payload]()
+		redis.Respond("Find", conformance.Payload{ID: "from redis"}, nil)
+		bus, err := pkgInfra.NewRoutingQueryBus(table, map[string]pkgApp.QueryBus[conformance.Query, conformance.Payload, conformance.Payload]{"local": local, "kafka": kafka, "redis": redis}, logger)
+		if err != nil {
+			t.Fatalf("NewRoutingQueryBus() error = %v", err)
+		}
+
+		result, err := bus.Dispatch(context.Background(), conformance.NewQuery("Find", conformance.Payload{}))
+		if err != nil || result.ID != "from redis" {
+			t.Fatalf("Dispatch(Find) = %+v, %v, want the redis result", result, err)
+		}
+		local.ExpectDispatched("Find").Never(t)
+		kafka.ExpectDispatched("Find").Never(t)
+	})
+
+	t.Run("publishes an event to every backend and handles it on the first", func(t *testing.T) {
+		local := testkit.NewRecordingEventBus[conformance.Event, conformance.Payload]()
+		redis := testkit.NewRecordingEventBus[conformance.Event, conformance.Payload]()
+		kafka := testkit.NewRecordingEventBus[conformance.Event, conformance.Payload]()
+		bus, err := pkgInfra.NewRoutingEventBus(table, map[string]pkgApp.EventBus[conformance.Event, conformance.Payload]{"local": local, "kafka": kafka, "redis": redis}, logger)
+		if err != nil {
+			t.Fatalf("NewRoutingEventBus() error = %v", err)
+		}
+		handled := 0
+		if err := bus.RegisterHandler("Booked", eventHandlerFunc(func(context.Context, conformance.Event) error {
+			handled++
+			return nil
+		})); err != nil {
+			t.Fatalf("RegisterHandler() error = %v", err)
+		}
+
+		if err := bus.Publish(context.Background(), conformance.NewEvent("Booked", conformance.Payload{})); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+		local.ExpectPublished("Booked").Once(t)
+		redis.ExpectPublished("Booked").Once(t)
+		kafka.ExpectPublished("Booked").Never(t)
+		if handled != 1 {
+			t.Fatalf("handler ran %d times, want once", handled)
+		}
+	})
+
+	t.Run("refuses backends missing from the table", func(t *testing.T) {
+		local := testkit.NewRecordingCommandBus[conformance.Command, conformance.Payload]()
+		redis := testkit.NewRecordingCommandBus[conformance.Command, conformance.Payload]()
+		_, err := pkgInfra.NewRoutingCommandBus(table, map[string]pkgApp.CommandBus[conformance.Command, conformance.Payload]{"local": local, "redis": redis}, logger)
+		if err == nil || !strings.Contains(err.Error(), `unknown backend "kafka"`) {
+			t.Fatalf("NewRoutingCommandBus() error = %v, want the kafka route refused", err)
+		}
+	})
+
+	t.Run("joins the errors of failed publish backends", func(t *testing.T) {
+		local := testkit.NewRecordingEventBus[conformance.Event, conformance.Payload]()
+		redis := testkit.NewRecordingEventBus[conformance.Event, conformance.Payload]()
+		failure := errors.New("redis down")
+		redis.FailWith("Booked", failure)
+		kafka := testkit.NewRecordingEventBus[conformance.Event, conformance.Payload]()
+		bus, err := pkgInfra.NewRoutingEventBus(table, map[string]pkgApp.EventBus[conformance.Event, conformance.Payload]{"local": local, "kafka": kafka, "redis": redis}, logger)
+		if err != nil {
+			t.Fatalf("NewRoutingEventBus() error = %v", err)
+		}
+
+		if err := bus.Publish(context.Background(), conformance.NewEvent("Booked", conformance.Payload{})); !errors.Is(err, failure) {
+			t.Fatalf("Publish() error = %v, want %v", err, failure)
+		}
+		local.ExpectPublished("Booked").Once(t)
+	})
+}
+
+type eventHandlerFunc func(context.Context, conformance.Event) error
+
+func (f eventHandlerFunc) Handle(ctx context.Context, event conformance.Event) error {
+	return f(ctx, event)
+}
@@ -0,0 +1,65 @@
+package adapter_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	sqlAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/sql/adapter"
+	watermillAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/watermill/adapter"
+	"github.com/mateusmacedo/go-bff/pkg/testkit"
+	"github.com/mateusmacedo/go-bff/pkg/testkit/conformance"
+)
+
+func TestSQLBuses(t *testing.T) {
+	config := conformance.Config{Delivery: conformance.Asynchronous}
+
+	t.Run("command bus", func(t *testing.T) {
+		conformance.TestCommandBus(t, config, func(t *testing.T) conformance.CommandBus {
+			publisher, subscriber := newPubSub(t)
+			bus := sqlAdapter.NewSQLCommandBus[conformance.Command, conformance.Payload](publisher, subscriber, testkit.NewLogger(t))
+			t.Cleanup(func() { bus.Close() })
+			return bus
+		})
+	})
+	t.Run("query bus", func(t *testing.T) {
+		conformance.TestQueryBus(t, config, func(t *testing.T) conformance.QueryBus {
+			publisher, subscriber := newPubSub(t)
+			bus := sqlAdapter.NewSQLQueryBus[conformance.Query, conformance.Payload, conformance.Payload](publisher, subscriber, testkit.NewLogger(t))
+			t.Cleanup(func() { bus.Close() })
+			return bus
+		})
+	})
+	t.Run("event bus", func(t *testing.T) {
+		conformance.TestEventBus(t, config, func(t *testing.T) conformance.EventBus {
+			publisher, subscriber := newPubSub(t)
+			bus := sqlAdapter.NewSQLEventBus[conformance.Event, conformance.Payload](publisher, subscriber, testkit.NewLogger(t))
+			t.Cleanup(func() { bus.Close() })
+			return bus
+		})
+	})
+}
+
+// newPubSub opens the SQL transport on a fresh SQLite database.
+func newPubSub(t *testing.T) (*sqlAdapter.Publisher, *sqlAdapter.Subscriber) {
+	t.Helper()
+	db := newSQLiteDB(t, filepath.Join(t.TempDir(), "messages.db"))
+	logger := watermillAdapter.NewWatermillLoggerAdapter(testkit.NewLogger(t))
+
+	publisher, err := sqlAdapter.NewPublisher(db, sqlAdapter.SQLiteDialect{}, logger)
+	if err != nil {
+		t.Fatalf("NewPublisher() error = %v", err)
+	}
+
+	subscriber, err := sqlAdapter.NewSubscriber(db, sqlAdapter.SQLiteDialect{}, sqlAdapter.SubscriberConfig{
+		ConsumerGroup: "bff",
+		PollInterval:  10 * time.Millisecond,
+		NakDelay:      10 * time.Millisecond,
+		ClaimTimeout:  time.Minute,
+	}, logger)
+	if err != nil {
+		t.Fatalf("NewSubscriber() error = %v", err)
+	}
+	t.Cleanup(func() { subscriber.Close() })
+	return publisher, subscriber
+}
@@ -0,0 +1,51 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+)
+
+type executor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type txContextKey struct{}
+
+// WithTx makes publishers write to tx instead of opening their own
+// transaction, so messages commit or roll back together with the caller's
+// writes. The bus must hand ctx through to the message, as the watermill
+// buses do.
+func WithTx(ctx context.Context, tx *sql.Tx) context.Context {
+	return context.WithValue(ctx, txContextKey{}, tx)
+}
+
+func TxFromContext(ctx context.Context) (*sql.Tx, bool) {
+	tx, ok := ctx.Value(txContextKey{}).(*sql.Tx)
+	return tx, ok && tx != nil
+}
+
+func NewPostgresDB(dsn string) (*sql.DB, error) {
+	return sql.Open("pgx", dsn)
+}
+
+// SQLiteMemory opens a private in-memory database instead of a file.
+const SQLiteMemory = ":memory:"
+
+// NewSQLiteDB opens path in WAL mode so consumers can read while handlers
+// write; it is meant for tests and local development. SQLiteMemory gives
+// every connection its own database, so the pool is kept to one connection.
+func NewSQLiteDB(path string) (*sql.DB, error) {
+	if path == SQLiteMemory {
+		db, err := sql.Open("sqlite", "file::memory:?_pragma=busy_timeout(5000)")
+		if err != nil {
+			return nil, err
+		}
+		db.SetMaxOpenConns(1)
+		return db, nil
+	}
+	return sql.Open("sqlite", "file:"+path+"?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)")
+}
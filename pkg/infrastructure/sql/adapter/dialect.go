@@ -0,0 +1,161 @@
+package adapter
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// Dialect holds the statements that differ between the databases the SQL
+// transport runs on. Queries are written with "?" placeholders and rebound
+// by the dialect.
+type Dialect interface {
+	Name() string
+	Rebind(query string) string
+	CreateMessagesTable(table string) string
+	CreateOffsetsTable(table string) string
+	// LockMessagesTable serializes publishers of a topic until commit, so
+	// offsets become visible in the order they were assigned.
+	LockMessagesTable(table string) string
+	Notify() string
+	// BeginMigrations opens a transaction on a dedicated connection that
+	// excludes concurrent migrators until it ends.
+	BeginMigrations() []string
+	CreateMigrationsTable(table string) string
+	// IsUniqueViolation reports whether err was raised by the unique index
+	// named index.
+	IsUniqueViolation(err error, index string) bool
+}
+
+type PostgresDialect struct{}
+
+func (PostgresDialect) Rebind(query string) string {
+	var builder strings.Builder
+	index := 0
+	for _, char := range query {
+		if char == '?' {
+			index++
+			builder.WriteString("$" + strconv.Itoa(index))
+			continue
+		}
+		builder.WriteRune(char)
+	}
+	return builder.String()
+}
+
+func (PostgresDialect) CreateMessagesTable(table string) string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		message_offset BIGSERIAL PRIMARY KEY,
+		uuid VARCHAR(36) NOT NULL,
+		payload BYTEA,
+		metadata TEXT NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`, table)
+}
+
+func (PostgresDialect) CreateOffsetsTable(table string) string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		consumer_group VARCHAR(255) NOT NULL,
+		topic VARCHAR(255) NOT NULL,
+		offset_acked BIGINT NOT NULL DEFAULT 0,
+		claimed_until BIGINT NOT NULL DEFAULT 0,
+		PRIMARY KEY (consumer_group, topic)
+	)`, table)
+}
+
+func (PostgresDialect) LockMessagesTable(table string) string {
+	return fmt.Sprintf("LOCK TABLE %s IN EXCLUSIVE MODE", table)
+}
+
+func (PostgresDialect) Notify() string {
+	return "SELECT pg_notify($1, $2)"
+}
+
+// migrationsLockKey identifies the advisory lock migrators share.
+const migrationsLockKey = 4242170427
+
+func (PostgresDialect) Name() string {
+	return "postgres"
+}
+
+func (PostgresDialect) BeginMigrations() []string {
+	return []string{"BEGIN", fmt.Sprintf("SELECT pg_advisory_xact_lock(%d)", migrationsLockKey)}
+}
+
+func (PostgresDialect) CreateMigrationsTable(table string) string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		version BIGINT PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL
+	)`, table)
+}
+
+// pgUniqueViolation is the SQLSTATE of unique_violation.
+const pgUniqueViolation = "23505"
+
+func (PostgresDialect) IsUniqueViolation(err error, index string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation && pgErr.ConstraintName == index
+}
+
+type SQLiteDialect struct{}
+
+func (SQLiteDialect) Rebind(query string) string {
+	return query
+}
+
+func (SQLiteDialect) CreateMessagesTable(table string) string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		message_offset INTEGER PRIMARY KEY AUTOINCREMENT,
+		uuid VARCHAR(36) NOT NULL,
+		payload BLOB,
+		metadata TEXT NOT NULL,
+		created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`, table)
+}
+
+func (SQLiteDialect) CreateOffsetsTable(table string) string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		consumer_group VARCHAR(255) NOT NULL,
+		topic VARCHAR(255) NOT NULL,
+		offset_acked INTEGER NOT NULL DEFAULT 0,
+		claimed_until INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY (consumer_group, topic)
+	)`, table)
+}
+
+func (SQLiteDialect) LockMessagesTable(string) string {
+	return ""
+}
+
+func (SQLiteDialect) Notify() string {
+	return ""
+}
+
+func (SQLiteDialect) Name() string {
+	return "sqlite"
+}
+
+func (SQLiteDialect) BeginMigrations() []string {
+	return []string{"BEGIN IMMEDIATE"}
+}
+
+func (SQLiteDialect) CreateMigrationsTable(table string) string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		version INTEGER PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		applied_at DATETIME NOT NULL
+	)`, table)
+}
+
+// IsUniqueViolation cannot tell unique indexes apart, as SQLite only reports
+// their columns: it matches a violation of any of them but the primary key.
+func (SQLiteDialect) IsUniqueViolation(err error, _ string) bool {
+	var sqliteErr *sqlite.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE
+}
@@ -0,0 +1,17 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+)
+
+func NewDBHealthCheck(name string, db *sql.DB) application.HealthCheck {
+	return application.HealthCheck{
+		Name: name,
+		Check: func(ctx context.Context) error {
+			return db.PingContext(ctx)
+		},
+	}
+}
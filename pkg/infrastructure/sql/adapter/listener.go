@@ -0,0 +1,58 @@
+package adapter
+
+import (
+	"context"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/jackc/pgx/v5"
+)
+
+// Listener wakes subscribers up as soon as a topic receives messages instead
+// of waiting for the next poll.
+type Listener interface {
+	Listen(ctx context.Context, channel string) (<-chan struct{}, error)
+}
+
+type PostgresListener struct {
+	dsn    string
+	logger watermill.LoggerAdapter
+}
+
+func NewPostgresListener(dsn string, logger watermill.LoggerAdapter) *PostgresListener {
+	if logger == nil {
+		logger = watermill.NopLogger{}
+	}
+	return &PostgresListener{dsn: dsn, logger: logger}
+}
+
+// Listen holds a dedicated connection for channel until ctx is done. Missed
+// notifications are harmless: subscribers keep polling.
+func (l *PostgresListener) Listen(ctx context.Context, channel string) (<-chan struct{}, error) {
+	conn, err := pgx.Connect(ctx, l.dsn)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		_ = conn.Close(context.Background())
+		return nil, err
+	}
+
+	wakeups := make(chan struct{}, 1)
+	go func() {
+		defer close(wakeups)
+		defer conn.Close(context.Background())
+		for {
+			if _, err := conn.WaitForNotification(ctx); err != nil {
+				if ctx.Err() == nil {
+					l.logger.Error("Listening for notifications failed", err, watermill.LogFields{"channel": channel})
+				}
+				return
+			}
+			select {
+			case wakeups <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	return wakeups, nil
+}
@@ -0,0 +1,316 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+)
+
+const migrationsTable = "bff_schema_migrations"
+
+var (
+	ErrSchemaBehind    = errors.New("database schema is behind")
+	ErrNoDownMigration = errors.New("migration cannot be rolled back")
+)
+
+// Migration is one schema version, read from <version>_<name>.up.sql and the
+// optional <version>_<name>.down.sql.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+	// Unknown marks versions applied by a newer binary.
+	Unknown bool
+}
+
+func LoadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		base, direction, found := strings.Cut(strings.TrimSuffix(entry.Name(), ".sql"), ".")
+		if !found || !strings.HasSuffix(entry.Name(), ".sql") || (direction != "up" && direction != "down") {
+			return nil, fmt.Errorf("migration %s: name must be <version>_<name>.up.sql or .down.sql", entry.Name())
+		}
+		versionText, name, _ := strings.Cut(base, "_")
+		version, err := strconv.ParseInt(versionText, 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s: invalid version %q", entry.Name(), versionText)
+		}
+
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, found := byVersion[version]
+		if !found {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		}
+		if migration.Name != name {
+			return nil, fmt.Errorf("migration %d: conflicting names %q and %q", version, migration.Name, name)
+		}
+		if direction == "up" {
+			migration.Up = string(data)
+		} else {
+			migration.Down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if strings.TrimSpace(migration.Up) == "" {
+			return nil, fmt.Errorf("migration %d_%s: missing up file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Migrator applies migrations in version order. Every run happens in a
+// single transaction holding the dialect's migration lock, so pods booting
+// together apply each version once and a failed run leaves no trace;
+// statements that cannot run inside a transaction are not supported.
+type Migrator struct {
+	db         *sql.DB
+	dialect    Dialect
+	migrations []Migration
+	logger     application.AppLogger
+}
+
+func NewMigrator(db *sql.DB, dialect Dialect, migrations []Migration, logger application.AppLogger) *Migrator {
+	return &Migrator{
+		db:         db,
+		dialect:    dialect,
+		migrations: migrations,
+		logger:     logger,
+	}
+}
+
+// Up applies every pending migration and returns how many it applied.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	applied := 0
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		versions, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, found := versions[migration.Version]; found {
+				continue
+			}
+			if _, err := conn.ExecContext(ctx, migration.Up); err != nil {
+				return fmt.Errorf("applying migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			insert := m.dialect.Rebind(fmt.Sprintf("INSERT INTO %s (version, name, applied_at) VALUES (?, ?, ?)", migrationsTable))
+			if _, err := conn.ExecContext(ctx, insert, migration.Version, migration.Name, time.Now().UTC()); err != nil {
+				return err
+			}
+			application.LogInfo(ctx, m.logger, "migration applied", map[string]interface{}{
+				"version": migration.Version,
+				"name":    migration.Name,
+			})
+			applied++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return applied, nil
+}
+
+// Down rolls back the last steps applied migrations.
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	rolledBack := 0
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		versions, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		appliedVersions := make([]int64, 0, len(versions))
+		for version := range versions {
+			appliedVersions = append(appliedVersions, version)
+		}
+		sort.Slice(appliedVersions, func(i, j int) bool {
+			return appliedVersions[i] > appliedVersions[j]
+		})
+
+		for _, version := range appliedVersions {
+			if rolledBack == steps {
+				break
+			}
+			migration, found := m.find(version)
+			if !found {
+				return fmt.Errorf("rolling back migration %d: not known to this binary", version)
+			}
+			if strings.TrimSpace(migration.Down) == "" {
+				return fmt.Errorf("rolling back migration %d_%s: %w", migration.Version, migration.Name, ErrNoDownMigration)
+			}
+			if _, err := conn.ExecContext(ctx, migration.Down); err != nil {
+				return fmt.Errorf("rolling back migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			remove := m.dialect.Rebind(fmt.Sprintf("DELETE FROM %s WHERE version = ?", migrationsTable))
+			if _, err := conn.ExecContext(ctx, remove, migration.Version); err != nil {
+				return err
+			}
+			application.LogInfo(ctx, m.logger, "migration rolled back", map[string]interface{}{
+				"version": migration.Version,
+				"name":    migration.Name,
+			})
+			rolledBack++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return rolledBack, nil
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	if _, err := m.db.ExecContext(ctx, m.dialect.CreateMigrationsTable(migrationsTable)); err != nil {
+		return nil, err
+	}
+	versions, err := m.applied(ctx, m.db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		appliedAt, applied := versions[migration.Version]
+		statuses = append(statuses, MigrationStatus{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			Applied:   applied,
+			AppliedAt: appliedAt,
+		})
+		delete(versions, migration.Version)
+	}
+	for version, appliedAt := range versions {
+		statuses = append(statuses, MigrationStatus{
+			Version:   version,
+			Applied:   true,
+			AppliedAt: appliedAt,
+			Unknown:   true,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+	return statuses, nil
+}
+
+// Check returns ErrSchemaBehind while any migration is pending. Versions
+// applied by a newer binary are accepted so that rollouts can overlap.
+func (m *Migrator) Check(ctx context.Context) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	var pending []string
+	for _, status := range statuses {
+		if !status.Applied {
+			pending = append(pending, fmt.Sprintf("%d_%s", status.Version, status.Name))
+		}
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: pending migrations %s", ErrSchemaBehind, strings.Join(pending, ", "))
+	}
+	return nil
+}
+
+func (m *Migrator) find(version int64) (Migration, bool) {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration, true
+		}
+	}
+	return Migration{}, false
+}
+
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (m *Migrator) applied(ctx context.Context, q querier) (map[int64]time.Time, error) {
+	rows, err := q.QueryContext(ctx, fmt.Sprintf("SELECT version, applied_at FROM %s", migrationsTable))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := make(map[int64]time.Time)
+	for rows.Next() {
+		var version int64
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		versions[version] = appliedAt
+	}
+	return versions, rows.Err()
+}
+
+// locked runs fn inside the dialect's migration transaction, committing when
+// fn succeeds.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sql.Conn) error) (err error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	for _, statement := range m.dialect.BeginMigrations() {
+		if _, err := conn.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("locking migrations: %w", err)
+		}
+	}
+	defer func() {
+		if err != nil {
+			if _, rollbackErr := conn.ExecContext(context.Background(), "ROLLBACK"); rollbackErr != nil {
+				err = errors.Join(err, rollbackErr)
+			}
+		}
+	}()
+
+	if _, err := conn.ExecContext(ctx, m.dialect.CreateMigrationsTable(migrationsTable)); err != nil {
+		return err
+	}
+	if err := fn(conn); err != nil {
+		return err
+	}
+	_, err = conn.ExecContext(ctx, "COMMIT")
+	return err
+}
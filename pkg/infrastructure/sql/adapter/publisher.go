@@ -0,0 +1,106 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+)
+
+var ErrPublisherClosed = errors.New("sql publisher closed")
+
+type Publisher struct {
+	db      *sql.DB
+	dialect Dialect
+	schema  *schema
+	logger  watermill.LoggerAdapter
+	closed  bool
+	mu      sync.RWMutex
+}
+
+func NewPublisher(db *sql.DB, dialect Dialect, logger watermill.LoggerAdapter) (*Publisher, error) {
+	if db == nil {
+		return nil, errors.New("sql publisher: db is required")
+	}
+	if logger == nil {
+		logger = watermill.NopLogger{}
+	}
+	return &Publisher{
+		db:      db,
+		dialect: dialect,
+		schema:  newSchema(db, dialect),
+		logger:  logger,
+	}, nil
+}
+
+// Publish appends messages to the topic table. When the first message carries
+// a transaction in its context (see WithTx) every message is written in it.
+func (p *Publisher) Publish(topic string, messages ...*message.Message) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return ErrPublisherClosed
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+
+	ctx := messages[0].Context()
+	if err := p.schema.ensure(ctx, topic); err != nil {
+		return fmt.Errorf("initializing topic %s: %w", topic, err)
+	}
+
+	if tx, ok := TxFromContext(ctx); ok {
+		return p.insert(ctx, tx, topic, messages)
+	}
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := p.insert(ctx, tx, topic, messages); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (p *Publisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	return nil
+}
+
+func (p *Publisher) insert(ctx context.Context, tx executor, topic string, messages []*message.Message) error {
+	table := messagesTable(topic)
+	if lock := p.dialect.LockMessagesTable(table); lock != "" {
+		if _, err := tx.ExecContext(ctx, lock); err != nil {
+			return err
+		}
+	}
+
+	insert := p.dialect.Rebind(fmt.Sprintf("INSERT INTO %s (uuid, payload, metadata) VALUES (?, ?, ?)", table))
+	for _, msg := range messages {
+		metadata, err := json.Marshal(msg.Metadata)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, insert, msg.UUID, []byte(msg.Payload), string(metadata)); err != nil {
+			return err
+		}
+		p.logger.Trace("Message inserted", watermill.LogFields{"message_uuid": msg.UUID, "topic": topic})
+	}
+
+	if notify := p.dialect.Notify(); notify != "" {
+		if _, err := tx.ExecContext(ctx, notify, table, topic); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"sync"
+)
+
+const (
+	messagesTablePrefix = "bff_messages_"
+	offsetsTable        = "bff_offsets"
+)
+
+type schema struct {
+	db          *sql.DB
+	dialect     Dialect
+	initialized map[string]bool
+	mu          sync.Mutex
+}
+
+func newSchema(db *sql.DB, dialect Dialect) *schema {
+	return &schema{
+		db:          db,
+		dialect:     dialect,
+		initialized: make(map[string]bool),
+	}
+}
+
+// ensure creates the tables of topic in the transaction of ctx, if any, as
+// its connection may be the only one. Tables created there are not
+// remembered, since the transaction may still roll them back.
+func (s *schema) ensure(ctx context.Context, topic string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.initialized[topic] {
+		return nil
+	}
+
+	var querier executor = s.db
+	tx, inTx := TxFromContext(ctx)
+	if inTx {
+		querier = tx
+	}
+	for _, statement := range []string{
+		s.dialect.CreateMessagesTable(messagesTable(topic)),
+		s.dialect.CreateOffsetsTable(offsetsTable),
+	} {
+		if _, err := querier.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	s.initialized[topic] = !inTx
+	return nil
+}
+
+// messagesTable also names the NOTIFY channel of the topic.
+func messagesTable(topic string) string {
+	sanitized := strings.Map(func(char rune) rune {
+		switch {
+		case char >= 'a' && char <= 'z', char >= '0' && char <= '9':
+			return char
+		case char >= 'A' && char <= 'Z':
+			return char + ('a' - 'A')
+		default:
+			return '_'
+		}
+	}, topic)
+	return messagesTablePrefix + sanitized
+}
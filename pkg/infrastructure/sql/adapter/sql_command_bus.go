@@ -0,0 +1,17 @@
+package adapter
+
+import (
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	"github.com/mateusmacedo/go-bff/pkg/domain"
+	watermillAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/watermill/adapter"
+)
+
+type SQLCommandBus[C domain.Command[T], T any] struct {
+	*watermillAdapter.WatermillCommandBus[C, T]
+}
+
+func NewSQLCommandBus[C domain.Command[T], T any](publisher *Publisher, subscriber *Subscriber, logger application.AppLogger, options ...watermillAdapter.Option) *SQLCommandBus[C, T] {
+	return &SQLCommandBus[C, T]{
+		WatermillCommandBus: watermillAdapter.NewWatermillCommandBus[C, T](publisher, subscriber, logger, options...),
+	}
+}
@@ -0,0 +1,17 @@
+package adapter
+
+import (
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	"github.com/mateusmacedo/go-bff/pkg/domain"
+	watermillAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/watermill/adapter"
+)
+
+type SQLEventBus[E domain.Event[D], D any] struct {
+	*watermillAdapter.WatermillEventBus[E, D]
+}
+
+func NewSQLEventBus[E domain.Event[D], D any](publisher *Publisher, subscriber *Subscriber, logger application.AppLogger, options ...watermillAdapter.Option) *SQLEventBus[E, D] {
+	return &SQLEventBus[E, D]{
+		WatermillEventBus: watermillAdapter.NewWatermillEventBus[E, D](publisher, subscriber, logger, options...),
+	}
+}
@@ -0,0 +1,263 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+)
+
+var ErrSubscriberClosed = errors.New("sql subscriber closed")
+
+type SubscriberConfig struct {
+	ConsumerGroup string
+	PollInterval  time.Duration
+	NakDelay      time.Duration
+	// ClaimTimeout is how long a consumer may keep a message before the
+	// other consumers of its group deliver it again.
+	ClaimTimeout time.Duration
+	// Listener is optional; without it new messages are picked up on the
+	// next poll.
+	Listener Listener
+}
+
+func (c SubscriberConfig) withDefaults() SubscriberConfig {
+	if c.ConsumerGroup == "" {
+		c.ConsumerGroup = "default"
+	}
+	if c.PollInterval <= 0 {
+		c.PollInterval = time.Second
+	}
+	if c.NakDelay <= 0 {
+		c.NakDelay = time.Second
+	}
+	if c.ClaimTimeout <= 0 {
+		c.ClaimTimeout = 30 * time.Second
+	}
+	return c
+}
+
+type Subscriber struct {
+	db      *sql.DB
+	dialect Dialect
+	schema  *schema
+	config  SubscriberConfig
+	logger  watermill.LoggerAdapter
+	closing chan struct{}
+	closed  bool
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+}
+
+func NewSubscriber(db *sql.DB, dialect Dialect, config SubscriberConfig, logger watermill.LoggerAdapter) (*Subscriber, error) {
+	if db == nil {
+		return nil, errors.New("sql subscriber: db is required")
+	}
+	if logger == nil {
+		logger = watermill.NopLogger{}
+	}
+	return &Subscriber{
+		db:      db,
+		dialect: dialect,
+		schema:  newSchema(db, dialect),
+		config:  config.withDefaults(),
+		logger:  logger,
+		closing: make(chan struct{}),
+	}, nil
+}
+
+func (s *Subscriber) Subscribe(ctx context.Context, topic string) (<-chan *message.Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, ErrSubscriberClosed
+	}
+
+	if err := s.schema.ensure(ctx, topic); err != nil {
+		return nil, fmt.Errorf("initializing topic %s: %w", topic, err)
+	}
+	initOffset := fmt.Sprintf("INSERT INTO %s (consumer_group, topic, offset_acked) VALUES (?, ?, 0) ON CONFLICT DO NOTHING", offsetsTable)
+	if _, err := s.db.ExecContext(ctx, s.dialect.Rebind(initOffset), s.config.ConsumerGroup, topic); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	var wakeups <-chan struct{}
+	if s.config.Listener != nil {
+		var err error
+		if wakeups, err = s.config.Listener.Listen(ctx, messagesTable(topic)); err != nil {
+			cancel()
+			return nil, err
+		}
+	}
+
+	output := make(chan *message.Message)
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer cancel()
+		s.consume(ctx, topic, output, wakeups)
+	}()
+	return output, nil
+}
+
+func (s *Subscriber) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.closing)
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return nil
+}
+
+func (s *Subscriber) consume(ctx context.Context, topic string, output chan *message.Message, wakeups <-chan struct{}) {
+	defer close(output)
+	logFields := watermill.LogFields{"topic": topic, "consumer_group": s.config.ConsumerGroup}
+
+	for {
+		delivered, err := s.consumeNext(ctx, topic, output)
+		if ctx.Err() != nil || s.isClosing() {
+			return
+		}
+		if err != nil {
+			s.logger.Error("Consuming message failed", err, logFields)
+		}
+		if delivered && err == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.closing:
+			return
+		case _, ok := <-wakeups:
+			if !ok {
+				wakeups = nil
+			}
+		case <-time.After(s.config.PollInterval):
+		}
+	}
+}
+
+// consumeNext claims the group's offset, delivers the message after it and
+// advances the offset once the message is acked. The claim is a deadline in
+// the offset row, taken and released in single statements, so no transaction
+// or connection is held while the handler runs; the other consumers of the
+// group skip the offset until the claim is released or runs out, and then
+// deliver the message again. Consumers' clocks must agree within
+// ClaimTimeout. It reports false when there was nothing to deliver,
+// including when another consumer holds the claim.
+func (s *Subscriber) consumeNext(ctx context.Context, topic string, output chan *message.Message) (bool, error) {
+	now := time.Now()
+	claim := now.Add(s.config.ClaimTimeout).UnixMilli()
+	claimOffset := s.dialect.Rebind(fmt.Sprintf(
+		"UPDATE %s SET claimed_until = ? WHERE consumer_group = ? AND topic = ? AND claimed_until < ? RETURNING offset_acked",
+		offsetsTable,
+	))
+	var acked int64
+	err := s.db.QueryRowContext(ctx, claimOffset, claim, s.config.ConsumerGroup, topic, now.UnixMilli()).Scan(&acked)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	selectMessage := s.dialect.Rebind(fmt.Sprintf(
+		"SELECT message_offset, uuid, payload, metadata FROM %s WHERE message_offset > ? ORDER BY message_offset LIMIT 1",
+		messagesTable(topic),
+	))
+	var (
+		offset   int64
+		uuid     string
+		payload  []byte
+		metadata string
+	)
+	err = s.db.QueryRowContext(ctx, selectMessage, acked).Scan(&offset, &uuid, &payload, &metadata)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, s.release(topic, claim)
+	} else if err != nil {
+		return false, errors.Join(err, s.release(topic, claim))
+	}
+
+	msg := message.NewMessage(uuid, payload)
+	if err := json.Unmarshal([]byte(metadata), &msg.Metadata); err != nil {
+		return false, errors.Join(err, s.release(topic, claim))
+	}
+	msgCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	msg.SetContext(msgCtx)
+
+	select {
+	case output <- msg:
+	case <-ctx.Done():
+		return false, errors.Join(ctx.Err(), s.release(topic, claim))
+	case <-s.closing:
+		return false, errors.Join(ErrSubscriberClosed, s.release(topic, claim))
+	}
+
+	select {
+	case <-msg.Acked():
+		// The offset only advances under the claim taken above, unless it
+		// ran out and another consumer took it over.
+		advanceOffset := s.dialect.Rebind(fmt.Sprintf(
+			"UPDATE %s SET offset_acked = ?, claimed_until = 0 WHERE consumer_group = ? AND topic = ? AND offset_acked = ? AND claimed_until = ?",
+			offsetsTable,
+		))
+		if _, err := s.db.ExecContext(ctx, advanceOffset, offset, s.config.ConsumerGroup, topic, acked, claim); err != nil {
+			return false, err
+		}
+		return true, nil
+	case <-msg.Nacked():
+		s.logger.Trace("Message nacked, redelivering", watermill.LogFields{"message_uuid": uuid, "topic": topic})
+		if err := s.release(topic, claim); err != nil {
+			return false, err
+		}
+		select {
+		case <-time.After(s.config.NakDelay):
+			return true, nil
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-s.closing:
+			return false, ErrSubscriberClosed
+		}
+	case <-ctx.Done():
+		return false, errors.Join(ctx.Err(), s.release(topic, claim))
+	case <-s.closing:
+		return false, errors.Join(ErrSubscriberClosed, s.release(topic, claim))
+	}
+}
+
+// release gives up the claim on the group's offset of topic, if it is still
+// the one taken, so that the message is delivered again without waiting for
+// the claim to run out. It also runs when the subscription is cancelled.
+func (s *Subscriber) release(topic string, claim int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	releaseOffset := s.dialect.Rebind(fmt.Sprintf(
+		"UPDATE %s SET claimed_until = 0 WHERE consumer_group = ? AND topic = ? AND claimed_until = ?",
+		offsetsTable,
+	))
+	_, err := s.db.ExecContext(ctx, releaseOffset, s.config.ConsumerGroup, topic, claim)
+	return err
+}
+
+func (s *Subscriber) isClosing() bool {
+	select {
+	case <-s.closing:
+		return true
+	default:
+		return false
+	}
+}
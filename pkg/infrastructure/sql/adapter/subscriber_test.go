@@ -0,0 +1,158 @@
+package adapter_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+
+	sqlAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/sql/adapter"
+)
+
+func TestSubscriberClaimsOffsets(t *testing.T) {
+	t.Run("skips a message another consumer of the group holds", func(t *testing.T) {
+		db := newSQLiteDB(t, filepath.Join(t.TempDir(), "messages.db"))
+		first := subscribe(t, db, time.Minute)
+		second := subscribe(t, db, time.Minute)
+		publish(t, db, "m1", "m2")
+
+		held := receive(t, first, second)
+		expectNone(t, first, second)
+
+		held.Ack()
+		if next := receive(t, first, second); next.UUID != "m2" {
+			t.Fatalf("delivered %s after acking %s, want m2", next.UUID, held.UUID)
+		}
+	})
+
+	t.Run("redelivers a message whose claim ran out", func(t *testing.T) {
+		db := newSQLiteDB(t, filepath.Join(t.TempDir(), "messages.db"))
+		first := subscribe(t, db, 200*time.Millisecond)
+		publish(t, db, "m1")
+		if held := receive(t, first); held.UUID != "m1" {
+			t.Fatalf("delivered %s, want m1", held.UUID)
+		}
+
+		second := subscribe(t, db, 200*time.Millisecond)
+		redelivered := receive(t, second)
+		if redelivered.UUID != "m1" {
+			t.Fatalf("redelivered %s, want m1", redelivered.UUID)
+		}
+		redelivered.Ack()
+	})
+
+	t.Run("redelivers a nacked message", func(t *testing.T) {
+		db := newSQLiteDB(t, filepath.Join(t.TempDir(), "messages.db"))
+		messages := subscribe(t, db, time.Minute)
+		publish(t, db, "m1")
+
+		receive(t, messages).Nack()
+		if redelivered := receive(t, messages); redelivered.UUID != "m1" {
+			t.Fatalf("redelivered %s, want m1", redelivered.UUID)
+		}
+	})
+
+	t.Run("holds no connection while a message is in flight", func(t *testing.T) {
+		// An in-memory database has a single connection, which a transaction
+		// held by the subscriber would keep from everyone else.
+		db := newSQLiteDB(t, sqlAdapter.SQLiteMemory)
+		messages := subscribe(t, db, time.Minute)
+		publish(t, db, "m1")
+		held := receive(t, messages)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		if _, err := db.ExecContext(ctx, "SELECT 1"); err != nil {
+			t.Fatalf("ExecContext() while %s is in flight error = %v", held.UUID, err)
+		}
+		held.Ack()
+	})
+}
+
+const topic = "BusTicketBooked"
+
+func newSQLiteDB(t *testing.T, path string) *sql.DB {
+	t.Helper()
+	db, err := sqlAdapter.NewSQLiteDB(path)
+	if err != nil {
+		t.Fatalf("NewSQLiteDB() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func subscribe(t *testing.T, db *sql.DB, claimTimeout time.Duration) <-chan *message.Message {
+	t.Helper()
+	subscriber, err := sqlAdapter.NewSubscriber(db, sqlAdapter.SQLiteDialect{}, sqlAdapter.SubscriberConfig{
+		ConsumerGroup: "group",
+		PollInterval:  10 * time.Millisecond,
+		NakDelay:      10 * time.Millisecond,
+		ClaimTimeout:  claimTimeout,
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewSubscriber() error = %v", err)
+	}
+	t.Cleanup(func() { subscriber.Close() })
+
+	messages, err := subscriber.Subscribe(context.Background(), topic)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	return messages
+}
+
+func publish(t *testing.T, db *sql.DB, uuids ...string) {
+	t.Helper()
+	publisher, err := sqlAdapter.NewPublisher(db, sqlAdapter.SQLiteDialect{}, nil)
+	if err != nil {
+		t.Fatalf("NewPublisher() error = %v", err)
+	}
+	for _, uuid := range uuids {
+		if err := publisher.Publish(topic, message.NewMessage(uuid, []byte(uuid))); err != nil {
+			t.Fatalf("Publish(%s) error = %v", uuid, err)
+		}
+	}
+}
+
+// receive waits for a message from any of subscriptions.
+func receive(t *testing.T, subscriptions ...<-chan *message.Message) *message.Message {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		for _, messages := range subscriptions {
+			select {
+			case msg := <-messages:
+				return msg
+			default:
+			}
+		}
+		select {
+		case <-timeout:
+			t.Fatalf("no message delivered")
+			return nil
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
+
+func expectNone(t *testing.T, subscriptions ...<-chan *message.Message) {
+	t.Helper()
+	deadline := time.After(200 * time.Millisecond)
+	for {
+		for _, messages := range subscriptions {
+			select {
+			case msg := <-messages:
+				t.Fatalf("delivered %s while its offset was claimed", msg.UUID)
+			default:
+			}
+		}
+		select {
+		case <-deadline:
+			return
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
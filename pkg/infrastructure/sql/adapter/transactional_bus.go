@@ -0,0 +1,69 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	"github.com/mateusmacedo/go-bff/pkg/domain"
+)
+
+type transactionalCommandBus[C domain.Command[D], D any] struct {
+	next   application.CommandBus[C, D]
+	db     *sql.DB
+	logger application.AppLogger
+}
+
+// NewTransactionalCommandBus runs the handlers registered through it in a
+// transaction on db, handed to them with WithTx, so that what they save and
+// what the SQL publisher writes for them commit or roll back together.
+// Handlers called inside a transaction already join it.
+func NewTransactionalCommandBus[C domain.Command[D], D any](next application.CommandBus[C, D], db *sql.DB, logger application.AppLogger) application.CommandBus[C, D] {
+	return &transactionalCommandBus[C, D]{
+		next:   next,
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (bus *transactionalCommandBus[C, D]) RegisterHandler(commandName string, handler application.CommandHandler[C, D]) error {
+	return bus.next.RegisterHandler(commandName, &transactionalCommandHandler[C, D]{
+		next:   handler,
+		db:     bus.db,
+		logger: bus.logger,
+	})
+}
+
+func (bus *transactionalCommandBus[C, D]) Dispatch(ctx context.Context, command C) error {
+	return bus.next.Dispatch(ctx, command)
+}
+
+type transactionalCommandHandler[C domain.Command[D], D any] struct {
+	next   application.CommandHandler[C, D]
+	db     *sql.DB
+	logger application.AppLogger
+}
+
+func (h *transactionalCommandHandler[C, D]) Handle(ctx context.Context, command C) error {
+	if _, ok := TxFromContext(ctx); ok {
+		return h.next.Handle(ctx, command)
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	if err := h.next.Handle(WithTx(ctx, tx), command); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			application.LogError(ctx, h.logger, "error rolling back command", rollbackErr, map[string]interface{}{
+				"command_name": command.CommandName(),
+			})
+		}
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+	return nil
+}
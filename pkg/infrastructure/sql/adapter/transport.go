@@ -0,0 +1,18 @@
+package adapter
+
+import (
+	"database/sql"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+
+	watermillAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/watermill/adapter"
+)
+
+func SQLSubscriberFactory(db *sql.DB, dialect Dialect, config SubscriberConfig, logger watermill.LoggerAdapter) watermillAdapter.SubscriberFactory {
+	return func(consumerGroup string) (message.Subscriber, error) {
+		groupConfig := config
+		groupConfig.ConsumerGroup = consumerGroup
+		return NewSubscriber(db, dialect, groupConfig, logger)
+	}
+}
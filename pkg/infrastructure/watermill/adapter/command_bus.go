@@ -0,0 +1,188 @@
+package adapter
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	"github.com/mateusmacedo/go-bff/pkg/domain"
+)
+
+type WatermillCommandBus[C domain.Command[T], T any] struct {
+	publisher   message.Publisher
+	subscribers *subscriberPool
+	options     *busOptions
+	handlers    map[string]application.CommandHandler[C, T]
+	mu          sync.RWMutex
+	logger      application.AppLogger
+	ctx         context.Context
+	cancel      context.CancelFunc
+}
+
+func NewWatermillCommandBus[C domain.Command[T], T any](publisher message.Publisher, subscriber message.Subscriber, logger application.AppLogger, options ...Option) *WatermillCommandBus[C, T] {
+	ctx, cancel := context.WithCancel(context.Background())
+	busOptions := newBusOptions(options)
+	return &WatermillCommandBus[C, T]{
+		publisher:   publisher,
+		subscribers: newSubscriberPool(subscriber, busOptions),
+		options:     busOptions,
+		handlers:    make(map[string]application.CommandHandler[C, T]),
+		logger:      logger,
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+func (bus *WatermillCommandBus[C, T]) RegisterHandler(commandName string, handler application.CommandHandler[C, T]) error {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+
+	if _, subscribed := bus.handlers[commandName]; subscribed {
+		bus.handlers[commandName] = handler
+		return nil
+	}
+
+	// The handler is only stored once subscribed, so that a failed
+	// subscription is retried by the next registration.
+	messages, err := subscribe(bus.ctx, bus.subscribers, commandName, bus.options.topicName(commandName))
+	if err != nil {
+		application.LogError(bus.ctx, bus.logger, "error subscribing to command", err, map[string]interface{}{
+			"command_name": commandName,
+		})
+		return err
+	}
+	bus.handlers[commandName] = handler
+
+	go func() {
+		for msg := range messages {
+			go bus.processMessage(bus.ctx, commandName, msg)
+		}
+	}()
+	return nil
+}
+
+func (bus *WatermillCommandBus[C, T]) Dispatch(ctx context.Context, command C) error {
+	if err := ctx.Err(); err != nil {
+		application.LogError(ctx, bus.logger, "context done", err, map[string]interface{}{
+			"command_name": command.CommandName(),
+		})
+		return err
+	}
+
+	payload, err := bus.options.marshaler.Marshal(command.Payload())
+	if err != nil {
+		application.LogError(ctx, bus.logger, "error marshalling command payload", err, map[string]interface{}{
+			"command_name": command.CommandName(),
+		})
+		return err
+	}
+
+	msg := message.NewMessage(watermill.NewUUID(), payload)
+	msg.SetContext(ctx)
+	if err := InjectContextMetadata(ctx, msg); err != nil {
+		application.LogError(ctx, bus.logger, "error injecting command metadata", err, map[string]interface{}{
+			"command_name": command.CommandName(),
+		})
+		return err
+	}
+
+	if err := bus.publisher.Publish(bus.options.topicName(command.CommandName()), msg); err != nil {
+		application.LogError(ctx, bus.logger, "error publishing command", err, map[string]interface{}{
+			"command_name": command.CommandName(),
+		})
+		return err
+	}
+
+	application.LogInfo(ctx, bus.logger, "command dispatched", map[string]interface{}{
+		"command_name": command.CommandName(),
+	})
+	return nil
+}
+
+func (bus *WatermillCommandBus[C, T]) Close() error {
+	bus.cancel()
+	return bus.subscribers.Close()
+}
+
+func (bus *WatermillCommandBus[C, T]) processMessage(ctx context.Context, commandName string, msg *message.Message) {
+	ctx, err := ExtractContextMetadata(ctx, msg)
+	if err != nil {
+		application.LogError(ctx, bus.logger, "error extracting command metadata", err, map[string]interface{}{
+			"command_name": commandName,
+		})
+		msg.Nack()
+		return
+	}
+
+	var payload T
+	if err := bus.options.marshaler.Unmarshal(msg.Payload, &payload); err != nil {
+		application.LogError(ctx, bus.logger, "error unmarshalling command payload", err, map[string]interface{}{
+			"command_name": commandName,
+		})
+		msg.Nack()
+		return
+	}
+
+	command := &dynamicCommand[T]{
+		commandName: commandName,
+		payload:     payload,
+	}
+
+	typedCommand, ok := interface{}(command).(C)
+	if !ok {
+		application.LogError(ctx, bus.logger, "error asserting command type", nil, map[string]interface{}{
+			"command_name": commandName,
+		})
+		msg.Nack()
+		return
+	}
+
+	bus.mu.RLock()
+	handler := bus.handlers[commandName]
+	bus.mu.RUnlock()
+
+	if err := handler.Handle(ctx, typedCommand); err != nil {
+		if domain.Rejected(err) {
+			// Redelivering the command would fail the same way.
+			application.LogError(ctx, bus.logger, "command rejected", err, map[string]interface{}{
+				"command_name": commandName,
+			})
+			msg.Ack()
+			return
+		}
+		application.LogError(ctx, bus.logger, "error handling command", err, map[string]interface{}{
+			"command_name": commandName,
+		})
+		msg.Nack()
+		return
+	}
+
+	application.LogInfo(ctx, bus.logger, "command handled", map[string]interface{}{
+		"command_name": commandName,
+	})
+	msg.Ack()
+}
+
+func subscribe(ctx context.Context, subscribers *subscriberPool, messageName, topic string) (<-chan *message.Message, error) {
+	subscriber, err := subscribers.forMessage(messageName)
+	if err != nil {
+		return nil, err
+	}
+	return subscriber.Subscribe(ctx, topic)
+}
+
+type dynamicCommand[T any] struct {
+	commandName string
+	payload     T
+}
+
+func (c *dynamicCommand[T]) CommandName() string {
+	return c.commandName
+}
+
+func (c *dynamicCommand[T]) Payload() T {
+	return c.payload
+}
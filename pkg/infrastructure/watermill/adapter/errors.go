@@ -0,0 +1,55 @@
+package adapter
+
+import (
+	"errors"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	"github.com/mateusmacedo/go-bff/pkg/domain"
+)
+
+// errorCodes are the sentinel errors a reply keeps across the transport;
+// any other error only keeps its message.
+var errorCodes = map[string]error{
+	"not_found":            domain.ErrNotFound,
+	"invalid":              domain.ErrInvalid,
+	"conflict":             domain.ErrConflict,
+	"concurrency_conflict": domain.ErrConcurrencyConflict,
+	"forbidden":            application.ErrForbidden,
+}
+
+// SetErrorMetadata records err on a reply so that ErrorFromMetadata can
+// restore it on the dispatching side.
+func SetErrorMetadata(metadata message.Metadata, err error) {
+	metadata.Set(ErrorMetadataKey, err.Error())
+	for code, sentinel := range errorCodes {
+		if errors.Is(err, sentinel) {
+			metadata.Set(ErrorCodeMetadataKey, code)
+			return
+		}
+	}
+}
+
+// ErrorFromMetadata returns the error recorded by SetErrorMetadata, or nil
+// when the reply carries none.
+func ErrorFromMetadata(metadata message.Metadata) error {
+	msg := metadata.Get(ErrorMetadataKey)
+	if msg == "" {
+		return nil
+	}
+	return &remoteError{message: msg, sentinel: errorCodes[metadata.Get(ErrorCodeMetadataKey)]}
+}
+
+type remoteError struct {
+	message  string
+	sentinel error
+}
+
+func (e *remoteError) Error() string {
+	return e.message
+}
+
+func (e *remoteError) Unwrap() error {
+	return e.sentinel
+}
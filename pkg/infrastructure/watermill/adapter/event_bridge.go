@@ -0,0 +1,197 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	"github.com/mateusmacedo/go-bff/pkg/domain"
+)
+
+const OriginMetadataKey = "origin"
+
+type originContextKey struct{}
+
+type EventBridgeConfig struct {
+	// Origin identifies this process on the broker; defaults to a random ID.
+	Origin string
+	// Outbound events are forwarded from the local bus to the broker.
+	Outbound []string
+	// Inbound events are consumed from the broker and published locally. The
+	// subscriber must not share its consumer group with other replicas, or
+	// each event reaches only one of them.
+	Inbound []string
+}
+
+// EventBridge connects an in-process event bus to a broker. Events carry the
+// origin that published them: a replica drops its own events coming back from
+// the broker, and never forwards events it received from the broker.
+type EventBridge[E domain.Event[D], D any] struct {
+	local      application.EventBus[E, D]
+	publisher  message.Publisher
+	subscriber message.Subscriber
+	config     EventBridgeConfig
+	options    *busOptions
+	logger     application.AppLogger
+	ctx        context.Context
+	cancel     context.CancelFunc
+	started    bool
+	mu         sync.Mutex
+}
+
+func NewEventBridge[E domain.Event[D], D any](local application.EventBus[E, D], publisher message.Publisher, subscriber message.Subscriber, config EventBridgeConfig, logger application.AppLogger, options ...Option) *EventBridge[E, D] {
+	if config.Origin == "" {
+		config.Origin = watermill.NewShortUUID()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &EventBridge[E, D]{
+		local:      local,
+		publisher:  publisher,
+		subscriber: subscriber,
+		config:     config,
+		options:    newBusOptions(options),
+		logger:     logger,
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+func (b *EventBridge[E, D]) Origin() string {
+	return b.config.Origin
+}
+
+func (b *EventBridge[E, D]) Start() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.started {
+		return errors.New("event bridge already started")
+	}
+	b.started = true
+
+	for _, eventName := range b.config.Outbound {
+		if err := b.local.RegisterHandler(eventName, &outboundHandler[E, D]{bridge: b}); err != nil {
+			return err
+		}
+	}
+
+	for _, eventName := range b.config.Inbound {
+		messages, err := b.subscriber.Subscribe(b.ctx, b.options.topicName(eventName))
+		if err != nil {
+			application.LogError(b.ctx, b.logger, "error subscribing to bridged event", err, map[string]interface{}{
+				"event_name": eventName,
+			})
+			return err
+		}
+
+		eventName := eventName
+		go func() {
+			for msg := range messages {
+				go b.inbound(eventName, msg)
+			}
+		}()
+	}
+	return nil
+}
+
+func (b *EventBridge[E, D]) Close() error {
+	b.cancel()
+	return nil
+}
+
+func (b *EventBridge[E, D]) outbound(ctx context.Context, event E) error {
+	if origin, ok := ctx.Value(originContextKey{}).(string); ok && origin != b.config.Origin {
+		return nil
+	}
+
+	payload, err := b.options.marshaler.Marshal(event.Payload())
+	if err != nil {
+		application.LogError(ctx, b.logger, "error marshalling bridged event", err, map[string]interface{}{
+			"event_name": event.EventName(),
+		})
+		return err
+	}
+
+	msg := message.NewMessage(watermill.NewUUID(), payload)
+	msg.SetContext(ctx)
+	msg.Metadata.Set(OriginMetadataKey, b.config.Origin)
+	if err := InjectContextMetadata(ctx, msg); err != nil {
+		application.LogError(ctx, b.logger, "error injecting bridged event metadata", err, map[string]interface{}{
+			"event_name": event.EventName(),
+		})
+		return err
+	}
+
+	if err := b.publisher.Publish(b.options.topicName(event.EventName()), msg); err != nil {
+		application.LogError(ctx, b.logger, "error forwarding event to broker", err, map[string]interface{}{
+			"event_name": event.EventName(),
+		})
+		return err
+	}
+
+	application.LogInfo(ctx, b.logger, "event forwarded to broker", map[string]interface{}{
+		"event_name": event.EventName(),
+	})
+	return nil
+}
+
+func (b *EventBridge[E, D]) inbound(eventName string, msg *message.Message) {
+	origin := msg.Metadata.Get(OriginMetadataKey)
+	if origin == b.config.Origin {
+		msg.Ack()
+		return
+	}
+
+	ctx, err := ExtractContextMetadata(b.ctx, msg)
+	if err != nil {
+		application.LogError(ctx, b.logger, "error extracting bridged event metadata", err, map[string]interface{}{
+			"event_name": eventName,
+		})
+		msg.Nack()
+		return
+	}
+	ctx = context.WithValue(ctx, originContextKey{}, origin)
+
+	var payload D
+	if err := b.options.marshaler.Unmarshal(msg.Payload, &payload); err != nil {
+		application.LogError(ctx, b.logger, "error unmarshalling bridged event", err, map[string]interface{}{
+			"event_name": eventName,
+		})
+		msg.Nack()
+		return
+	}
+
+	event, ok := interface{}(&dynamicEvent[D]{eventName: eventName, payload: payload}).(E)
+	if !ok {
+		application.LogError(ctx, b.logger, "error asserting event type", nil, map[string]interface{}{
+			"event_name": eventName,
+		})
+		msg.Nack()
+		return
+	}
+
+	if err := b.local.Publish(ctx, event); err != nil {
+		application.LogError(ctx, b.logger, "error publishing bridged event locally", err, map[string]interface{}{
+			"event_name": eventName,
+		})
+		msg.Nack()
+		return
+	}
+
+	application.LogInfo(ctx, b.logger, "event received from broker", map[string]interface{}{
+		"event_name": eventName,
+		"origin":     origin,
+	})
+	msg.Ack()
+}
+
+type outboundHandler[E domain.Event[D], D any] struct {
+	bridge *EventBridge[E, D]
+}
+
+func (h *outboundHandler[E, D]) Handle(ctx context.Context, event E) error {
+	return h.bridge.outbound(ctx, event)
+}
@@ -0,0 +1,222 @@
+package adapter_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/pubsub/gochannel"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	"github.com/mateusmacedo/go-bff/pkg/domain"
+	pkgInfra "github.com/mateusmacedo/go-bff/pkg/infrastructure"
+	watermillAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/watermill/adapter"
+	"github.com/mateusmacedo/go-bff/pkg/testkit"
+)
+
+func TestEventBridge(t *testing.T) {
+	t.Run("delivers the events of one replica to the others once", func(t *testing.T) {
+		pubSub := newGoChannel(t)
+		broker := observe(t, pubSub, "Booked")
+		both := watermillAdapter.EventBridgeConfig{Outbound: []string{"Booked"}, Inbound: []string{"Booked"}}
+		first := newReplica(t, pubSub, pubSub, "first", both)
+		second := newReplica(t, pubSub, pubSub, "second", both)
+
+		first.publish(t, "Booked", "t1")
+
+		second.waitFor(t, "t1")
+		settle()
+		if got := first.received(); len(got) != 1 {
+			t.Fatalf("first replica handled %v, want its own event once", got)
+		}
+		if got := second.received(); len(got) != 1 {
+			t.Fatalf("second replica handled %v, want the event once", got)
+		}
+		if n := broker.count(); n != 1 {
+			t.Fatalf("broker carried %d messages, want the event forwarded once", n)
+		}
+	})
+
+	t.Run("forwards and receives only the configured events", func(t *testing.T) {
+		pubSub := newGoChannel(t)
+		booked, cancelled := observe(t, pubSub, "Booked"), observe(t, pubSub, "Cancelled")
+		first := newReplica(t, pubSub, pubSub, "first", watermillAdapter.EventBridgeConfig{Outbound: []string{"Booked", "Cancelled"}})
+		second := newReplica(t, pubSub, pubSub, "second", watermillAdapter.EventBridgeConfig{Inbound: []string{"Booked"}})
+		third := newReplica(t, pubSub, pubSub, "third", watermillAdapter.EventBridgeConfig{Inbound: []string{"Cancelled"}})
+
+		second.publish(t, "Booked", "t1")
+		first.publish(t, "Booked", "t2")
+		first.publish(t, "Cancelled", "t3")
+
+		second.waitFor(t, "t2")
+		third.waitFor(t, "t3")
+		settle()
+		if got := second.received(); len(got) != 2 {
+			t.Fatalf("second replica handled %v, want t1 and t2", got)
+		}
+		if got := third.received(); len(got) != 1 {
+			t.Fatalf("third replica handled %v, want only t3", got)
+		}
+		if booked.count() != 1 || cancelled.count() != 1 {
+			t.Fatalf("broker carried %d Booked and %d Cancelled messages, want one of each", booked.count(), cancelled.count())
+		}
+	})
+
+	t.Run("keeps the tenant of forwarded events", func(t *testing.T) {
+		pubSub := newGoChannel(t)
+		tenants := []string{"tenant-a"}
+		publisher := watermillAdapter.NewTenantTopicPublisher(pubSub, tenants)
+		subscriber := watermillAdapter.NewTenantTopicSubscriber(pubSub, tenants)
+		prefixed, base := observe(t, pubSub, "tenant-a.Booked"), observe(t, pubSub, "Booked")
+		first := newReplica(t, publisher, subscriber, "first", watermillAdapter.EventBridgeConfig{Outbound: []string{"Booked"}})
+		second := newReplica(t, publisher, subscriber, "second", watermillAdapter.EventBridgeConfig{Inbound: []string{"Booked"}})
+
+		first.publishAs(t, "tenant-a", "Booked", "t1")
+		first.publishAs(t, "tenant-b", "Booked", "t2")
+
+		second.waitFor(t, "t1")
+		second.waitFor(t, "t2")
+		settle()
+		if prefixed.count() != 1 || base.count() != 1 {
+			t.Fatalf("broker carried %d messages on tenant-a.Booked and %d on Booked, want one on each", prefixed.count(), base.count())
+		}
+		if tenantID := second.tenantOf("t1"); tenantID != "tenant-a" {
+			t.Fatalf("t1 handled in tenant %q, want tenant-a", tenantID)
+		}
+		if tenantID := second.tenantOf("t2"); tenantID != "tenant-b" {
+			t.Fatalf("t2 handled in tenant %q, want tenant-b", tenantID)
+		}
+	})
+}
+
+type event struct {
+	name    string
+	payload string
+}
+
+func (e event) EventName() string { return e.name }
+func (e event) Payload() string   { return e.payload }
+
+// replica is a process with an in-process event bus bridged to a broker,
+// which records the events its handlers receive with their tenant.
+type replica struct {
+	bus application.EventBus[domain.Event[string], string]
+
+	mu      sync.Mutex
+	tenants map[string]string
+	order   []string
+}
+
+func newReplica(t *testing.T, publisher message.Publisher, subscriber message.Subscriber, origin string, config watermillAdapter.EventBridgeConfig) *replica {
+	t.Helper()
+	logger := testkit.NewLogger(t)
+	r := &replica{
+		bus:     pkgInfra.NewSimpleEventBus[domain.Event[string], string](logger),
+		tenants: map[string]string{},
+	}
+	for _, eventName := range []string{"Booked", "Cancelled"} {
+		if err := r.bus.RegisterHandler(eventName, r); err != nil {
+			t.Fatalf("RegisterHandler() error = %v", err)
+		}
+	}
+
+	config.Origin = origin
+	bridge := watermillAdapter.NewEventBridge[domain.Event[string], string](r.bus, publisher, subscriber, config, logger)
+	if err := bridge.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	t.Cleanup(func() { bridge.Close() })
+	return r
+}
+
+func (r *replica) Handle(ctx context.Context, event domain.Event[string]) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tenants[event.Payload()] = application.TenantID(ctx)
+	r.order = append(r.order, event.Payload())
+	return nil
+}
+
+func (r *replica) publish(t *testing.T, name, payload string) {
+	t.Helper()
+	r.publishAs(t, "tenant-a", name, payload)
+}
+
+func (r *replica) publishAs(t *testing.T, tenantID, name, payload string) {
+	t.Helper()
+	ctx := application.WithTenant(context.Background(), tenantID)
+	if err := r.bus.Publish(ctx, event{name: name, payload: payload}); err != nil {
+		t.Fatalf("Publish(%s) error = %v", payload, err)
+	}
+}
+
+func (r *replica) received() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.order...)
+}
+
+func (r *replica) tenantOf(payload string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.tenants[payload]
+}
+
+func (r *replica) waitFor(t *testing.T, payload string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		r.mu.Lock()
+		_, ok := r.tenants[payload]
+		r.mu.Unlock()
+		if ok {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("%s not delivered, handled %v", payload, r.received())
+}
+
+// counter counts the messages a topic of the broker carries.
+type counter struct {
+	mu sync.Mutex
+	n  int
+}
+
+func (c *counter) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.n
+}
+
+func observe(t *testing.T, subscriber message.Subscriber, topic string) *counter {
+	t.Helper()
+	messages, err := subscriber.Subscribe(context.Background(), topic)
+	if err != nil {
+		t.Fatalf("Subscribe(%s) error = %v", topic, err)
+	}
+	c := &counter{}
+	go func() {
+		for msg := range messages {
+			c.mu.Lock()
+			c.n++
+			c.mu.Unlock()
+			msg.Ack()
+		}
+	}()
+	return c
+}
+
+func newGoChannel(t *testing.T) *gochannel.GoChannel {
+	pubSub := gochannel.NewGoChannel(gochannel.Config{}, watermill.NopLogger{})
+	t.Cleanup(func() { pubSub.Close() })
+	return pubSub
+}
+
+// settle leaves time for deliveries that should not happen.
+func settle() {
+	time.Sleep(200 * time.Millisecond)
+}
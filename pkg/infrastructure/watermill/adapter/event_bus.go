@@ -0,0 +1,174 @@
+package adapter
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	"github.com/mateusmacedo/go-bff/pkg/domain"
+)
+
+type WatermillEventBus[E domain.Event[D], D any] struct {
+	publisher   message.Publisher
+	subscribers *subscriberPool
+	options     *busOptions
+	handlers    map[string][]application.EventHandler[E, D]
+	mu          sync.RWMutex
+	logger      application.AppLogger
+	ctx         context.Context
+	cancel      context.CancelFunc
+}
+
+func NewWatermillEventBus[E domain.Event[D], D any](publisher message.Publisher, subscriber message.Subscriber, logger application.AppLogger, options ...Option) *WatermillEventBus[E, D] {
+	ctx, cancel := context.WithCancel(context.Background())
+	busOptions := newBusOptions(options)
+	return &WatermillEventBus[E, D]{
+		publisher:   publisher,
+		subscribers: newSubscriberPool(subscriber, busOptions),
+		options:     busOptions,
+		handlers:    make(map[string][]application.EventHandler[E, D]),
+		logger:      logger,
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+func (bus *WatermillEventBus[E, D]) RegisterHandler(eventName string, handler application.EventHandler[E, D]) error {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+
+	if _, subscribed := bus.handlers[eventName]; subscribed {
+		bus.handlers[eventName] = append(bus.handlers[eventName], handler)
+		return nil
+	}
+
+	messages, err := subscribe(bus.ctx, bus.subscribers, eventName, bus.options.topicName(eventName))
+	if err != nil {
+		application.LogError(bus.ctx, bus.logger, "error subscribing to event", err, map[string]interface{}{
+			"event_name": eventName,
+		})
+		return err
+	}
+	bus.handlers[eventName] = append(bus.handlers[eventName], handler)
+
+	go func() {
+		for msg := range messages {
+			go bus.processMessage(bus.ctx, eventName, msg)
+		}
+	}()
+	return nil
+}
+
+func (bus *WatermillEventBus[E, D]) Publish(ctx context.Context, event E) error {
+	eventName := event.EventName()
+
+	if err := ctx.Err(); err != nil {
+		application.LogError(ctx, bus.logger, "context done", err, map[string]interface{}{
+			"event_name": eventName,
+		})
+		return err
+	}
+
+	payload, err := bus.options.marshaler.Marshal(event.Payload())
+	if err != nil {
+		application.LogError(ctx, bus.logger, "error marshalling event payload", err, map[string]interface{}{
+			"event_name": eventName,
+		})
+		return err
+	}
+
+	msg := message.NewMessage(watermill.NewUUID(), payload)
+	msg.SetContext(ctx)
+	if err := InjectContextMetadata(ctx, msg); err != nil {
+		application.LogError(ctx, bus.logger, "error injecting event metadata", err, map[string]interface{}{
+			"event_name": eventName,
+		})
+		return err
+	}
+
+	if err := bus.publisher.Publish(bus.options.topicName(eventName), msg); err != nil {
+		application.LogError(ctx, bus.logger, "error publishing event", err, map[string]interface{}{
+			"event_name": eventName,
+		})
+		return err
+	}
+
+	application.LogInfo(ctx, bus.logger, "event published", map[string]interface{}{
+		"event_name": eventName,
+	})
+	return nil
+}
+
+func (bus *WatermillEventBus[E, D]) Close() error {
+	bus.cancel()
+	return bus.subscribers.Close()
+}
+
+func (bus *WatermillEventBus[E, D]) processMessage(ctx context.Context, eventName string, msg *message.Message) {
+	ctx, err := ExtractContextMetadata(ctx, msg)
+	if err != nil {
+		application.LogError(ctx, bus.logger, "error extracting event metadata", err, map[string]interface{}{
+			"event_name": eventName,
+		})
+		msg.Nack()
+		return
+	}
+
+	var payload D
+	if err := bus.options.marshaler.Unmarshal(msg.Payload, &payload); err != nil {
+		application.LogError(ctx, bus.logger, "error unmarshalling event payload", err, map[string]interface{}{
+			"event_name": eventName,
+		})
+		msg.Nack()
+		return
+	}
+
+	event := &dynamicEvent[D]{
+		eventName: eventName,
+		payload:   payload,
+	}
+
+	typedEvent, ok := interface{}(event).(E)
+	if !ok {
+		application.LogError(ctx, bus.logger, "error asserting event type", nil, map[string]interface{}{
+			"event_name": eventName,
+		})
+		msg.Nack()
+		return
+	}
+
+	bus.mu.RLock()
+	handlers := bus.handlers[eventName]
+	bus.mu.RUnlock()
+
+	for _, handler := range handlers {
+		if err := handler.Handle(ctx, typedEvent); err != nil {
+			application.LogError(ctx, bus.logger, "error handling event", err, map[string]interface{}{
+				"event_name": eventName,
+			})
+			msg.Nack()
+			return
+		}
+	}
+
+	application.LogInfo(ctx, bus.logger, "event handled", map[string]interface{}{
+		"event_name": eventName,
+	})
+	msg.Ack()
+}
+
+type dynamicEvent[D any] struct {
+	eventName string
+	payload   D
+}
+
+func (e *dynamicEvent[D]) EventName() string {
+	return e.eventName
+}
+
+func (e *dynamicEvent[D]) Payload() D {
+	return e.payload
+}
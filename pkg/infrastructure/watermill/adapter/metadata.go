@@ -0,0 +1,60 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+)
+
+const (
+	PrincipalMetadataKey     = "principal"
+	TenantMetadataKey        = "tenant"
+	CorrelationIDMetadataKey = "correlation_id"
+	ReplyToMetadataKey       = "reply_to"
+	ErrorMetadataKey         = "error"
+	ErrorCodeMetadataKey     = "error_code"
+)
+
+func InjectContextMetadata(ctx context.Context, msg *message.Message) error {
+	return InjectMetadata(ctx, msg.Metadata)
+}
+
+func ExtractContextMetadata(ctx context.Context, msg *message.Message) (context.Context, error) {
+	return ExtractMetadata(ctx, msg.Metadata)
+}
+
+// InjectMetadata writes the principal and tenant of ctx to metadata, for
+// transports that carry headers without a watermill message.
+func InjectMetadata(ctx context.Context, metadata message.Metadata) error {
+	if principal, ok := application.PrincipalFromContext(ctx); ok {
+		encoded, err := json.Marshal(principal)
+		if err != nil {
+			return err
+		}
+		metadata.Set(PrincipalMetadataKey, string(encoded))
+	}
+	if tenantID, ok := application.TenantFromContext(ctx); ok {
+		metadata.Set(TenantMetadataKey, tenantID)
+	}
+	return nil
+}
+
+func ExtractMetadata(ctx context.Context, metadata message.Metadata) (context.Context, error) {
+	if encoded := metadata.Get(PrincipalMetadataKey); encoded != "" {
+		var principal application.Principal
+		if err := json.Unmarshal([]byte(encoded), &principal); err != nil {
+			return ctx, err
+		}
+		ctx = application.WithPrincipal(ctx, principal)
+	}
+	if tenantID := metadata.Get(TenantMetadataKey); tenantID != "" {
+		if err := application.ValidateTenantID(tenantID); err != nil {
+			return ctx, err
+		}
+		ctx = application.WithTenant(ctx, tenantID)
+	}
+	return ctx, nil
+}
@@ -0,0 +1,135 @@
+package adapter
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+)
+
+type PayloadMarshaler interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+type JSONMarshaler struct{}
+
+func (JSONMarshaler) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JSONMarshaler) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+type SubscriberFactory func(consumerGroup string) (message.Subscriber, error)
+
+type busOptions struct {
+	topicName         func(messageName string) string
+	replyTopicName    func(queryName, instanceID string) string
+	marshaler         PayloadMarshaler
+	subscriberFactory SubscriberFactory
+	consumerGroup     func(messageName string) string
+}
+
+type Option func(*busOptions)
+
+func newBusOptions(options []Option) *busOptions {
+	o := &busOptions{
+		topicName: func(messageName string) string {
+			return messageName
+		},
+		replyTopicName: func(queryName, instanceID string) string {
+			return queryName + "_response." + instanceID
+		},
+		marshaler: JSONMarshaler{},
+	}
+	for _, option := range options {
+		option(o)
+	}
+	return o
+}
+
+func WithTopicName(topicName func(messageName string) string) Option {
+	return func(o *busOptions) {
+		o.topicName = topicName
+	}
+}
+
+func WithTopicPrefix(prefix string) Option {
+	return WithTopicName(func(messageName string) string {
+		return prefix + messageName
+	})
+}
+
+// WithReplyTopicName names the topic a query bus instance receives replies on;
+// it must be unique per instance when replicas share a consumer group.
+func WithReplyTopicName(replyTopicName func(queryName, instanceID string) string) Option {
+	return func(o *busOptions) {
+		o.replyTopicName = replyTopicName
+	}
+}
+
+func WithMarshaler(marshaler PayloadMarshaler) Option {
+	return func(o *busOptions) {
+		o.marshaler = marshaler
+	}
+}
+
+// WithConsumerGroups builds one subscriber per consumer group instead of
+// sharing the subscriber given to the constructor.
+func WithConsumerGroups(factory SubscriberFactory, consumerGroup func(messageName string) string) Option {
+	return func(o *busOptions) {
+		o.subscriberFactory = factory
+		o.consumerGroup = consumerGroup
+	}
+}
+
+type subscriberPool struct {
+	fallback    message.Subscriber
+	options     *busOptions
+	subscribers map[string]message.Subscriber
+	mu          sync.Mutex
+}
+
+func newSubscriberPool(fallback message.Subscriber, options *busOptions) *subscriberPool {
+	return &subscriberPool{
+		fallback:    fallback,
+		options:     options,
+		subscribers: make(map[string]message.Subscriber),
+	}
+}
+
+func (p *subscriberPool) forMessage(messageName string) (message.Subscriber, error) {
+	if p.options.subscriberFactory == nil {
+		return p.fallback, nil
+	}
+
+	group := p.options.consumerGroup(messageName)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if subscriber, found := p.subscribers[group]; found {
+		return subscriber, nil
+	}
+	subscriber, err := p.options.subscriberFactory(group)
+	if err != nil {
+		return nil, err
+	}
+	p.subscribers[group] = subscriber
+	return subscriber, nil
+}
+
+func (p *subscriberPool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var firstErr error
+	for group, subscriber := range p.subscribers {
+		if err := subscriber.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(p.subscribers, group)
+	}
+	return firstErr
+}
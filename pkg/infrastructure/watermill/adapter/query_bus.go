@@ -0,0 +1,280 @@
+package adapter
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	"github.com/mateusmacedo/go-bff/pkg/domain"
+)
+
+type WatermillQueryBus[Q domain.Query[D], D any, R any] struct {
+	publisher   message.Publisher
+	subscribers *subscriberPool
+	options     *busOptions
+	handlers    map[string]application.QueryHandler[Q, D, R]
+	mu          sync.RWMutex
+	logger      application.AppLogger
+	ctx         context.Context
+	cancel      context.CancelFunc
+	instanceID  string
+	replies     map[string]bool
+	pending     map[string]chan *message.Message
+	pendingMu   sync.Mutex
+}
+
+func NewWatermillQueryBus[Q domain.Query[D], D any, R any](publisher message.Publisher, subscriber message.Subscriber, logger application.AppLogger, options ...Option) *WatermillQueryBus[Q, D, R] {
+	ctx, cancel := context.WithCancel(context.Background())
+	busOptions := newBusOptions(options)
+	return &WatermillQueryBus[Q, D, R]{
+		publisher:   publisher,
+		subscribers: newSubscriberPool(subscriber, busOptions),
+		options:     busOptions,
+		handlers:    make(map[string]application.QueryHandler[Q, D, R]),
+		logger:      logger,
+		ctx:         ctx,
+		cancel:      cancel,
+		instanceID:  watermill.NewShortUUID(),
+		replies:     make(map[string]bool),
+		pending:     make(map[string]chan *message.Message),
+	}
+}
+
+func (bus *WatermillQueryBus[Q, D, R]) RegisterHandler(queryName string, handler application.QueryHandler[Q, D, R]) error {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+
+	if _, subscribed := bus.handlers[queryName]; subscribed {
+		bus.handlers[queryName] = handler
+		return nil
+	}
+
+	messages, err := subscribe(bus.ctx, bus.subscribers, queryName, bus.options.topicName(queryName))
+	if err != nil {
+		application.LogError(bus.ctx, bus.logger, "error subscribing to query", err, map[string]interface{}{
+			"query_name": queryName,
+		})
+		return err
+	}
+	bus.handlers[queryName] = handler
+
+	go func() {
+		for msg := range messages {
+			go bus.processMessage(bus.ctx, queryName, msg)
+		}
+	}()
+	return nil
+}
+
+func (bus *WatermillQueryBus[Q, D, R]) Dispatch(ctx context.Context, query Q) (R, error) {
+	var zero R
+	if err := ctx.Err(); err != nil {
+		application.LogError(ctx, bus.logger, "context done", err, map[string]interface{}{
+			"query_name": query.QueryName(),
+		})
+		return zero, err
+	}
+
+	payload, err := bus.options.marshaler.Marshal(query.Payload())
+	if err != nil {
+		application.LogError(ctx, bus.logger, "error marshalling query payload", err, map[string]interface{}{
+			"query_name": query.QueryName(),
+		})
+		return zero, err
+	}
+
+	// Replies go to a topic owned by this bus instance, so consumer groups
+	// shared between replicas cannot steal them.
+	replyTopic := bus.options.replyTopicName(bus.options.topicName(query.QueryName()), bus.instanceID)
+	if err := bus.subscribeReplies(query.QueryName(), replyTopic); err != nil {
+		application.LogError(ctx, bus.logger, "error subscribing to query response", err, map[string]interface{}{
+			"query_name": query.QueryName(),
+		})
+		return zero, err
+	}
+
+	msg := message.NewMessage(watermill.NewUUID(), payload)
+	msg.Metadata.Set(CorrelationIDMetadataKey, msg.UUID)
+	msg.Metadata.Set(ReplyToMetadataKey, replyTopic)
+	if err := InjectContextMetadata(ctx, msg); err != nil {
+		application.LogError(ctx, bus.logger, "error injecting query metadata", err, map[string]interface{}{
+			"query_name": query.QueryName(),
+		})
+		return zero, err
+	}
+
+	response := make(chan *message.Message, 1)
+	bus.pendingMu.Lock()
+	bus.pending[msg.UUID] = response
+	bus.pendingMu.Unlock()
+	defer func() {
+		bus.pendingMu.Lock()
+		delete(bus.pending, msg.UUID)
+		bus.pendingMu.Unlock()
+	}()
+
+	if err := bus.publisher.Publish(bus.options.topicName(query.QueryName()), msg); err != nil {
+		application.LogError(ctx, bus.logger, "error publishing query", err, map[string]interface{}{
+			"query_name": query.QueryName(),
+		})
+		return zero, err
+	}
+
+	select {
+	case responseMsg := <-response:
+		if err := ErrorFromMetadata(responseMsg.Metadata); err != nil {
+			application.LogError(ctx, bus.logger, "error handling query", err, map[string]interface{}{
+				"query_name": query.QueryName(),
+			})
+			return zero, err
+		}
+
+		var result R
+		if err := bus.options.marshaler.Unmarshal(responseMsg.Payload, &result); err != nil {
+			application.LogError(ctx, bus.logger, "error unmarshalling query response", err, map[string]interface{}{
+				"query_name": query.QueryName(),
+			})
+			return zero, err
+		}
+		return result, nil
+	case <-ctx.Done():
+		application.LogError(ctx, bus.logger, "context done", ctx.Err(), map[string]interface{}{
+			"query_name": query.QueryName(),
+		})
+		return zero, ctx.Err()
+	}
+}
+
+func (bus *WatermillQueryBus[Q, D, R]) Close() error {
+	bus.cancel()
+	return bus.subscribers.Close()
+}
+
+func (bus *WatermillQueryBus[Q, D, R]) subscribeReplies(queryName, replyTopic string) error {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	if bus.replies[replyTopic] {
+		return nil
+	}
+
+	messages, err := subscribe(bus.ctx, bus.subscribers, queryName, replyTopic)
+	if err != nil {
+		return err
+	}
+	bus.replies[replyTopic] = true
+
+	go func() {
+		for responseMsg := range messages {
+			bus.pendingMu.Lock()
+			response, found := bus.pending[responseMsg.Metadata.Get(CorrelationIDMetadataKey)]
+			bus.pendingMu.Unlock()
+
+			if found {
+				select {
+				case response <- responseMsg:
+				default:
+				}
+			}
+			responseMsg.Ack()
+		}
+	}()
+	return nil
+}
+
+func (bus *WatermillQueryBus[Q, D, R]) processMessage(ctx context.Context, queryName string, msg *message.Message) {
+	ctx, err := ExtractContextMetadata(ctx, msg)
+	if err != nil {
+		application.LogError(ctx, bus.logger, "error extracting query metadata", err, map[string]interface{}{
+			"query_name": queryName,
+		})
+		msg.Nack()
+		return
+	}
+
+	var payload D
+	if err := bus.options.marshaler.Unmarshal(msg.Payload, &payload); err != nil {
+		application.LogError(ctx, bus.logger, "error unmarshalling query payload", err, map[string]interface{}{
+			"query_name": queryName,
+		})
+		msg.Nack()
+		return
+	}
+
+	query := &dynamicQuery[D]{
+		queryName: queryName,
+		payload:   payload,
+	}
+
+	typedQuery, ok := interface{}(query).(Q)
+	if !ok {
+		application.LogError(ctx, bus.logger, "error asserting query type", nil, map[string]interface{}{
+			"query_name": queryName,
+		})
+		msg.Nack()
+		return
+	}
+
+	bus.mu.RLock()
+	handler := bus.handlers[queryName]
+	bus.mu.RUnlock()
+
+	var responsePayload []byte
+	result, handleErr := handler.Handle(ctx, typedQuery)
+	if handleErr != nil {
+		application.LogError(ctx, bus.logger, "error handling query", handleErr, map[string]interface{}{
+			"query_name": queryName,
+		})
+	} else if responsePayload, err = bus.options.marshaler.Marshal(result); err != nil {
+		application.LogError(ctx, bus.logger, "error marshalling query response", err, map[string]interface{}{
+			"query_name": queryName,
+		})
+		msg.Nack()
+		return
+	}
+
+	responseMsg := message.NewMessage(watermill.NewUUID(), responsePayload)
+	responseMsg.Metadata.Set(CorrelationIDMetadataKey, msg.Metadata.Get(CorrelationIDMetadataKey))
+	if handleErr != nil {
+		SetErrorMetadata(responseMsg.Metadata, handleErr)
+	}
+	if err := InjectContextMetadata(ctx, responseMsg); err != nil {
+		application.LogError(ctx, bus.logger, "error injecting query response metadata", err, map[string]interface{}{
+			"query_name": queryName,
+		})
+		msg.Nack()
+		return
+	}
+
+	replyTopic := msg.Metadata.Get(ReplyToMetadataKey)
+	if replyTopic == "" {
+		replyTopic = bus.options.topicName(queryName) + "_response"
+	}
+	if err := bus.publisher.Publish(replyTopic, responseMsg); err != nil {
+		application.LogError(ctx, bus.logger, "error publishing query response", err, map[string]interface{}{
+			"query_name": queryName,
+		})
+		msg.Nack()
+		return
+	}
+
+	application.LogInfo(ctx, bus.logger, "query handled", map[string]interface{}{
+		"query_name": queryName,
+	})
+	msg.Ack()
+}
+
+type dynamicQuery[D any] struct {
+	queryName string
+	payload   D
+}
+
+func (q *dynamicQuery[D]) QueryName() string {
+	return q.queryName
+}
+
+func (q *dynamicQuery[D]) Payload() D {
+	return q.payload
+}
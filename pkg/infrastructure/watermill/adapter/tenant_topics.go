@@ -0,0 +1,107 @@
+package adapter
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+)
+
+func TenantTopic(tenantID, topic string) string {
+	if tenantID == "" {
+		return topic
+	}
+	return tenantID + "." + topic
+}
+
+type tenantTopicPublisher struct {
+	publisher message.Publisher
+	tenants   map[string]bool
+}
+
+// NewTenantTopicPublisher routes the messages of the given tenants, found in
+// their metadata, to the topic prefixed with the tenant; the messages of
+// other tenants stay on the base topic, where NewTenantTopicSubscriber still
+// consumes them.
+func NewTenantTopicPublisher(publisher message.Publisher, tenants []string) message.Publisher {
+	p := &tenantTopicPublisher{publisher: publisher, tenants: make(map[string]bool, len(tenants))}
+	for _, tenantID := range tenants {
+		p.tenants[tenantID] = true
+	}
+	return p
+}
+
+func (p *tenantTopicPublisher) Publish(topic string, messages ...*message.Message) error {
+	for _, msg := range messages {
+		tenantID := msg.Metadata.Get(TenantMetadataKey)
+		if !p.tenants[tenantID] {
+			tenantID = ""
+		}
+		if err := p.publisher.Publish(TenantTopic(tenantID, topic), msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *tenantTopicPublisher) Close() error {
+	return p.publisher.Close()
+}
+
+type tenantTopicSubscriber struct {
+	subscriber message.Subscriber
+	tenants    []string
+}
+
+// NewTenantTopicSubscriber consumes the base topic and the prefixed topic of
+// every given tenant through a single channel.
+func NewTenantTopicSubscriber(subscriber message.Subscriber, tenants []string) message.Subscriber {
+	return &tenantTopicSubscriber{
+		subscriber: subscriber,
+		tenants:    tenants,
+	}
+}
+
+func (s *tenantTopicSubscriber) Subscribe(ctx context.Context, topic string) (<-chan *message.Message, error) {
+	topics := make([]string, 0, len(s.tenants)+1)
+	topics = append(topics, topic)
+	for _, tenantID := range s.tenants {
+		topics = append(topics, TenantTopic(tenantID, topic))
+	}
+
+	sources := make([]<-chan *message.Message, 0, len(topics))
+	for _, t := range topics {
+		messages, err := s.subscriber.Subscribe(ctx, t)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, messages)
+	}
+
+	output := make(chan *message.Message)
+	var wg sync.WaitGroup
+	for _, source := range sources {
+		wg.Add(1)
+		go func(source <-chan *message.Message) {
+			defer wg.Done()
+			for msg := range source {
+				select {
+				case output <- msg:
+				case <-ctx.Done():
+					msg.Nack()
+					return
+				}
+			}
+		}(source)
+	}
+	go func() {
+		wg.Wait()
+		close(output)
+	}()
+
+	return output, nil
+}
+
+func (s *tenantTopicSubscriber) Close() error {
+	return s.subscriber.Close()
+}
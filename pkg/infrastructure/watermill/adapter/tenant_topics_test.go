@@ -0,0 +1,136 @@
+package adapter_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	"github.com/mateusmacedo/go-bff/pkg/domain"
+	watermillAdapter "github.com/mateusmacedo/go-bff/pkg/infrastructure/watermill/adapter"
+	"github.com/mateusmacedo/go-bff/pkg/testkit"
+)
+
+func TestTenantTopic(t *testing.T) {
+	if got := watermillAdapter.TenantTopic("tenant-a", "Booked"); got != "tenant-a.Booked" {
+		t.Fatalf("TenantTopic(tenant-a) = %q, want tenant-a.Booked", got)
+	}
+	if got := watermillAdapter.TenantTopic("", "Booked"); got != "Booked" {
+		t.Fatalf("TenantTopic(\"\") = %q, want Booked", got)
+	}
+}
+
+func TestTenantTopicPublisher(t *testing.T) {
+	pubSub := newGoChannel(t)
+	publisher := watermillAdapter.NewTenantTopicPublisher(pubSub, []string{"tenant-a"})
+	prefixed, other, base := observe(t, pubSub, "tenant-a.Booked"), observe(t, pubSub, "tenant-b.Booked"), observe(t, pubSub, "Booked")
+
+	for _, tenantID := range []string{"tenant-a", "tenant-b", ""} {
+		msg := message.NewMessage(watermill.NewUUID(), []byte(`"t1"`))
+		if tenantID != "" {
+			msg.Metadata.Set(watermillAdapter.TenantMetadataKey, tenantID)
+		}
+		if err := publisher.Publish("Booked", msg); err != nil {
+			t.Fatalf("Publish(%q) error = %v", tenantID, err)
+		}
+	}
+
+	settle()
+	if prefixed.count() != 1 || other.count() != 0 || base.count() != 2 {
+		t.Fatalf("broker carried %d messages on tenant-a.Booked, %d on tenant-b.Booked and %d on Booked, want 1, 0 and 2",
+			prefixed.count(), other.count(), base.count())
+	}
+}
+
+func TestTenantTopicSubscriber(t *testing.T) {
+	t.Run("propagates the tenant of every topic it consumes", func(t *testing.T) {
+		pubSub := newGoChannel(t)
+		tenants := []string{"tenant-a", "tenant-b"}
+		logger := testkit.NewLogger(t)
+		publisher := watermillAdapter.NewWatermillEventBus[domain.Event[string], string](watermillAdapter.NewTenantTopicPublisher(pubSub, tenants), pubSub, logger)
+		consumer := watermillAdapter.NewWatermillEventBus[domain.Event[string], string](pubSub, watermillAdapter.NewTenantTopicSubscriber(pubSub, tenants), logger)
+		t.Cleanup(func() { consumer.Close() })
+		r := &replica{tenants: map[string]string{}}
+		if err := consumer.RegisterHandler("Booked", r); err != nil {
+			t.Fatalf("RegisterHandler() error = %v", err)
+		}
+
+		published := map[string]string{"t1": "tenant-a", "t2": "tenant-b", "t3": "tenant-c", "t4": ""}
+		for payload, tenantID := range published {
+			ctx := context.Background()
+			if tenantID != "" {
+				ctx = application.WithTenant(ctx, tenantID)
+			}
+			if err := publisher.Publish(ctx, event{name: "Booked", payload: payload}); err != nil {
+				t.Fatalf("Publish(%s) error = %v", payload, err)
+			}
+		}
+
+		for payload, tenantID := range published {
+			r.waitFor(t, payload)
+			if got := r.tenantOf(payload); got != tenantID {
+				t.Fatalf("%s handled in tenant %q, want %q", payload, got, tenantID)
+			}
+		}
+		settle()
+		if got := r.received(); len(got) != 4 {
+			t.Fatalf("handled %v, want every event once", got)
+		}
+	})
+
+	t.Run("ignores the topics of other tenants", func(t *testing.T) {
+		pubSub := newGoChannel(t)
+		subscriber := watermillAdapter.NewTenantTopicSubscriber(pubSub, []string{"tenant-a"})
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		messages, err := subscriber.Subscribe(ctx, "Booked")
+		if err != nil {
+			t.Fatalf("Subscribe() error = %v", err)
+		}
+
+		for _, topic := range []string{"tenant-b.Booked", "tenant-a.Booked"} {
+			msg := message.NewMessage(watermill.NewUUID(), []byte(topic))
+			if err := pubSub.Publish(topic, msg); err != nil {
+				t.Fatalf("Publish(%s) error = %v", topic, err)
+			}
+		}
+
+		select {
+		case msg := <-messages:
+			msg.Ack()
+			if got := string(msg.Payload); got != "tenant-a.Booked" {
+				t.Fatalf("received a message from %s, want only tenant-a.Booked", got)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("tenant-a.Booked not delivered")
+		}
+		select {
+		case msg := <-messages:
+			t.Fatalf("received a message from %s, want nothing else", msg.Payload)
+		case <-time.After(200 * time.Millisecond):
+		}
+	})
+
+	t.Run("closes its channel when the context is done", func(t *testing.T) {
+		pubSub := newGoChannel(t)
+		subscriber := watermillAdapter.NewTenantTopicSubscriber(pubSub, []string{"tenant-a"})
+		ctx, cancel := context.WithCancel(context.Background())
+		messages, err := subscriber.Subscribe(ctx, "Booked")
+		if err != nil {
+			t.Fatalf("Subscribe() error = %v", err)
+		}
+
+		cancel()
+		select {
+		case _, open := <-messages:
+			if open {
+				t.Fatal("received a message after the context was done")
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("channel still open after the context was done")
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package testkit
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	"github.com/mateusmacedo/go-bff/pkg/domain"
+)
+
+var ErrNoHandler = errors.New("no handler registered")
+
+type RecordingCommandBus[C domain.Command[T], T any] struct {
+	mu         sync.RWMutex
+	handlers   map[string]application.CommandHandler[C, T]
+	dispatched []C
+	failures   map[string]error
+}
+
+func NewRecordingCommandBus[C domain.Command[T], T any]() *RecordingCommandBus[C, T] {
+	return &RecordingCommandBus[C, T]{
+		handlers: make(map[string]application.CommandHandler[C, T]),
+		failures: make(map[string]error),
+	}
+}
+
+func (bus *RecordingCommandBus[C, T]) RegisterHandler(commandName string, handler application.CommandHandler[C, T]) error {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	bus.handlers[commandName] = handler
+	return nil
+}
+
+// Dispatch records the command and runs the registered handler, if any.
+func (bus *RecordingCommandBus[C, T]) Dispatch(ctx context.Context, command C) error {
+	bus.mu.Lock()
+	bus.dispatched = append(bus.dispatched, command)
+	handler, found := bus.handlers[command.CommandName()]
+	failure := bus.failures[command.CommandName()]
+	bus.mu.Unlock()
+
+	if failure != nil {
+		return failure
+	}
+	if !found {
+		return nil
+	}
+	return handler.Handle(ctx, command)
+}
+
+func (bus *RecordingCommandBus[C, T]) FailWith(commandName string, err error) {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	bus.failures[commandName] = err
+}
+
+func (bus *RecordingCommandBus[C, T]) Dispatched() []C {
+	bus.mu.RLock()
+	defer bus.mu.RUnlock()
+	return append([]C(nil), bus.dispatched...)
+}
+
+func (bus *RecordingCommandBus[C, T]) Reset() {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	bus.dispatched = nil
+}
+
+func (bus *RecordingCommandBus[C, T]) ExpectDispatched(commandName string) *Expectation[T] {
+	var payloads []T
+	for _, command := range bus.Dispatched() {
+		if command.CommandName() == commandName {
+			payloads = append(payloads, command.Payload())
+		}
+	}
+	return newExpectation("command", commandName, payloads)
+}
@@ -0,0 +1,162 @@
+package conformance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mateusmacedo/go-bff/pkg/domain"
+)
+
+var errHandler = errors.New("conformance: handler failed")
+
+// TestCommandBus runs the command bus contract against buses built by newBus;
+// every subtest gets a fresh bus.
+func TestCommandBus(t *testing.T, config Config, newBus func(t *testing.T) CommandBus) {
+	config = config.withDefaults()
+
+	t.Run("delivers dispatched command", func(t *testing.T) {
+		bus := newBus(t)
+		received := newRecorder()
+		register(t, bus.RegisterHandler("ConformanceDeliver", commandHandlerFunc(func(_ context.Context, command Command) error {
+			received.record(command.Payload())
+			return nil
+		})))
+
+		if err := bus.Dispatch(context.Background(), NewCommand("ConformanceDeliver", Payload{ID: "1", Value: 42})); err != nil {
+			t.Fatalf("Dispatch() error = %v", err)
+		}
+
+		if config.Delivery == Synchronous && received.count("1") != 1 {
+			t.Fatalf("synchronous bus returned before running the handler")
+		}
+		received.waitFor(t, config.Timeout, "command delivery", func() bool { return received.count("1") == 1 })
+		if payload := received.snapshot()[0]; payload.Value != 42 {
+			t.Fatalf("handler received %+v, want value 42", payload)
+		}
+	})
+
+	t.Run("propagates handler errors", func(t *testing.T) {
+		bus := newBus(t)
+		received := newRecorder()
+		var attempts atomic.Int32
+		register(t, bus.RegisterHandler("ConformanceFail", commandHandlerFunc(func(_ context.Context, command Command) error {
+			received.record(command.Payload())
+			if attempts.Add(1) == 1 {
+				return errHandler
+			}
+			return nil
+		})))
+
+		err := bus.Dispatch(context.Background(), NewCommand("ConformanceFail", Payload{ID: "1"}))
+		switch config.Delivery {
+		case Synchronous:
+			if !errors.Is(err, errHandler) {
+				t.Fatalf("Dispatch() error = %v, want %v", err, errHandler)
+			}
+		case Asynchronous:
+			if err != nil {
+				t.Fatalf("Dispatch() error = %v", err)
+			}
+			received.waitFor(t, config.Timeout, "redelivery of nacked command", func() bool { return received.count("1") >= 2 })
+		}
+	})
+
+	if config.Delivery == Asynchronous {
+		t.Run("acks handled command", func(t *testing.T) {
+			bus := newBus(t)
+			received := newRecorder()
+			register(t, bus.RegisterHandler("ConformanceAck", commandHandlerFunc(func(_ context.Context, command Command) error {
+				received.record(command.Payload())
+				return nil
+			})))
+
+			if err := bus.Dispatch(context.Background(), NewCommand("ConformanceAck", Payload{ID: "1"})); err != nil {
+				t.Fatalf("Dispatch() error = %v", err)
+			}
+			received.waitFor(t, config.Timeout, "command delivery", func() bool { return received.count("1") == 1 })
+			settle(config)
+			if n := received.count("1"); n != 1 {
+				t.Fatalf("acked command delivered %d times", n)
+			}
+		})
+
+		t.Run("drops rejected command", func(t *testing.T) {
+			bus := newBus(t)
+			received := newRecorder()
+			register(t, bus.RegisterHandler("ConformanceReject", commandHandlerFunc(func(_ context.Context, command Command) error {
+				received.record(command.Payload())
+				return fmt.Errorf("payload %w", domain.ErrConflict)
+			})))
+
+			if err := bus.Dispatch(context.Background(), NewCommand("ConformanceReject", Payload{ID: "1"})); err != nil {
+				t.Fatalf("Dispatch() error = %v", err)
+			}
+			received.waitFor(t, config.Timeout, "command delivery", func() bool { return received.count("1") == 1 })
+			settle(config)
+			if n := received.count("1"); n != 1 {
+				t.Fatalf("rejected command delivered %d times", n)
+			}
+		})
+	}
+
+	if config.Delivery == Synchronous {
+		t.Run("rejects command without handler", func(t *testing.T) {
+			bus := newBus(t)
+			if err := bus.Dispatch(context.Background(), NewCommand("ConformanceUnknown", Payload{ID: "1"})); err == nil {
+				t.Fatalf("Dispatch() without handler returned nil error")
+			}
+		})
+	}
+
+	t.Run("handles concurrent dispatches", func(t *testing.T) {
+		bus := newBus(t)
+		received := newRecorder()
+		register(t, bus.RegisterHandler("ConformanceConcurrent", commandHandlerFunc(func(_ context.Context, command Command) error {
+			received.record(command.Payload())
+			return nil
+		})))
+
+		const n = 50
+		var wg sync.WaitGroup
+		errs := make(chan error, n)
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				errs <- bus.Dispatch(context.Background(), NewCommand("ConformanceConcurrent", Payload{ID: fmt.Sprint(i), Value: i}))
+			}(i)
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			if err != nil {
+				t.Fatalf("Dispatch() error = %v", err)
+			}
+		}
+
+		received.waitFor(t, config.Timeout, "concurrent deliveries", func() bool { return received.unique() == n })
+	})
+
+	t.Run("refuses cancelled context", func(t *testing.T) {
+		bus := newBus(t)
+		received := newRecorder()
+		register(t, bus.RegisterHandler("ConformanceCancelled", commandHandlerFunc(func(_ context.Context, command Command) error {
+			received.record(command.Payload())
+			return nil
+		})))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if err := bus.Dispatch(ctx, NewCommand("ConformanceCancelled", Payload{ID: "1"})); !errors.Is(err, context.Canceled) {
+			t.Fatalf("Dispatch() error = %v, want %v", err, context.Canceled)
+		}
+		settle(config)
+		if n := received.count("1"); n != 0 {
+			t.Fatalf("cancelled command delivered %d times", n)
+		}
+	})
+}
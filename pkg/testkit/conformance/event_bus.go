@@ -0,0 +1,133 @@
+package conformance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestEventBus runs the event bus contract against buses built by newBus.
+func TestEventBus(t *testing.T, config Config, newBus func(t *testing.T) EventBus) {
+	config = config.withDefaults()
+
+	t.Run("fans out to every handler", func(t *testing.T) {
+		bus := newBus(t)
+		recorders := []*recorder{newRecorder(), newRecorder(), newRecorder()}
+		for _, r := range recorders {
+			r := r
+			register(t, bus.RegisterHandler("ConformanceFanOut", eventHandlerFunc(func(_ context.Context, event Event) error {
+				r.record(event.Payload())
+				return nil
+			})))
+		}
+
+		if err := bus.Publish(context.Background(), NewEvent("ConformanceFanOut", Payload{ID: "1"})); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+
+		for i, r := range recorders {
+			r.waitFor(t, config.Timeout, fmt.Sprintf("delivery to handler %d", i), func() bool { return r.count("1") >= 1 })
+		}
+		settle(config)
+		for i, r := range recorders {
+			if n := r.count("1"); n != 1 {
+				t.Fatalf("handler %d received the event %d times", i, n)
+			}
+		}
+	})
+
+	t.Run("publishes without handlers", func(t *testing.T) {
+		bus := newBus(t)
+		if err := bus.Publish(context.Background(), NewEvent("ConformanceNobody", Payload{ID: "1"})); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	})
+
+	t.Run("propagates handler errors", func(t *testing.T) {
+		bus := newBus(t)
+		received := newRecorder()
+		var attempts atomic.Int32
+		register(t, bus.RegisterHandler("ConformanceFail", eventHandlerFunc(func(_ context.Context, event Event) error {
+			received.record(event.Payload())
+			if attempts.Add(1) == 1 {
+				return errHandler
+			}
+			return nil
+		})))
+
+		err := bus.Publish(context.Background(), NewEvent("ConformanceFail", Payload{ID: "1"}))
+		switch config.Delivery {
+		case Synchronous:
+			if !errors.Is(err, errHandler) {
+				t.Fatalf("Publish() error = %v, want %v", err, errHandler)
+			}
+		case Asynchronous:
+			if err != nil {
+				t.Fatalf("Publish() error = %v", err)
+			}
+			received.waitFor(t, config.Timeout, "redelivery of nacked event", func() bool { return received.count("1") >= 2 })
+		}
+	})
+
+	t.Run("handles concurrent publishes", func(t *testing.T) {
+		bus := newBus(t)
+		first, second := newRecorder(), newRecorder()
+		for _, r := range []*recorder{first, second} {
+			r := r
+			register(t, bus.RegisterHandler("ConformanceConcurrent", eventHandlerFunc(func(_ context.Context, event Event) error {
+				r.record(event.Payload())
+				return nil
+			})))
+		}
+
+		const n = 50
+		var wg sync.WaitGroup
+		errs := make(chan error, n)
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				errs <- bus.Publish(context.Background(), NewEvent("ConformanceConcurrent", Payload{ID: fmt.Sprint(i)}))
+			}(i)
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			if err != nil {
+				t.Fatalf("Publish() error = %v", err)
+			}
+		}
+
+		first.waitFor(t, config.Timeout, "concurrent deliveries to first handler", func() bool { return first.unique() == n })
+		second.waitFor(t, config.Timeout, "concurrent deliveries to second handler", func() bool { return second.unique() == n })
+	})
+
+	t.Run("refuses cancelled context", func(t *testing.T) {
+		bus := newBus(t)
+		received := newRecorder()
+		register(t, bus.RegisterHandler("ConformanceCancelled", eventHandlerFunc(func(_ context.Context, event Event) error {
+			received.record(event.Payload())
+			return nil
+		})))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if err := bus.Publish(ctx, NewEvent("ConformanceCancelled", Payload{ID: "1"})); !errors.Is(err, context.Canceled) {
+			t.Fatalf("Publish() error = %v, want %v", err, context.Canceled)
+		}
+		settle(config)
+		if n := received.count("1"); n != 0 {
+			t.Fatalf("cancelled event delivered %d times", n)
+		}
+	})
+}
+
+func settle(config Config) {
+	if config.Delivery == Asynchronous {
+		time.Sleep(config.Settle)
+	}
+}
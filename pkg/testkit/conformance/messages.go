@@ -0,0 +1,92 @@
+package conformance
+
+import (
+	"context"
+	"time"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	"github.com/mateusmacedo/go-bff/pkg/domain"
+)
+
+type Delivery int
+
+const (
+	// Synchronous buses run handlers inside Dispatch/Publish and return their errors.
+	Synchronous Delivery = iota
+	// Asynchronous buses hand messages to a broker, ack handled messages and
+	// nack failed ones so they are redelivered.
+	Asynchronous
+)
+
+type Config struct {
+	Delivery Delivery
+	// Timeout bounds how long the suite waits for asynchronous delivery.
+	Timeout time.Duration
+	// Settle is how long the suite watches for unexpected redeliveries.
+	Settle time.Duration
+}
+
+func (c Config) withDefaults() Config {
+	if c.Timeout == 0 {
+		c.Timeout = 5 * time.Second
+	}
+	if c.Settle == 0 {
+		c.Settle = 200 * time.Millisecond
+	}
+	return c
+}
+
+type Payload struct {
+	ID    string `json:"id"`
+	Value int    `json:"value"`
+}
+
+type (
+	Command = domain.Command[Payload]
+	Query   = domain.Query[Payload]
+	Event   = domain.Event[Payload]
+
+	CommandBus = application.CommandBus[Command, Payload]
+	QueryBus   = application.QueryBus[Query, Payload, Payload]
+	EventBus   = application.EventBus[Event, Payload]
+)
+
+type message struct {
+	name    string
+	payload Payload
+}
+
+func (m message) CommandName() string { return m.name }
+func (m message) QueryName() string   { return m.name }
+func (m message) EventName() string   { return m.name }
+func (m message) Payload() Payload    { return m.payload }
+
+func NewCommand(name string, payload Payload) Command {
+	return message{name: name, payload: payload}
+}
+
+func NewQuery(name string, payload Payload) Query {
+	return message{name: name, payload: payload}
+}
+
+func NewEvent(name string, payload Payload) Event {
+	return message{name: name, payload: payload}
+}
+
+type commandHandlerFunc func(ctx context.Context, command Command) error
+
+func (f commandHandlerFunc) Handle(ctx context.Context, command Command) error {
+	return f(ctx, command)
+}
+
+type queryHandlerFunc func(ctx context.Context, query Query) (Payload, error)
+
+func (f queryHandlerFunc) Handle(ctx context.Context, query Query) (Payload, error) {
+	return f(ctx, query)
+}
+
+type eventHandlerFunc func(ctx context.Context, event Event) error
+
+func (f eventHandlerFunc) Handle(ctx context.Context, event Event) error {
+	return f(ctx, event)
+}
@@ -0,0 +1,132 @@
+package conformance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mateusmacedo/go-bff/pkg/domain"
+)
+
+// TestQueryBus runs the query bus contract; handlers answer with the query
+// payload with its value doubled.
+func TestQueryBus(t *testing.T, config Config, newBus func(t *testing.T) QueryBus) {
+	config = config.withDefaults()
+
+	double := queryHandlerFunc(func(_ context.Context, query Query) (Payload, error) {
+		payload := query.Payload()
+		payload.Value *= 2
+		return payload, nil
+	})
+
+	t.Run("returns handler result", func(t *testing.T) {
+		bus := newBus(t)
+		register(t, bus.RegisterHandler("ConformanceResult", double))
+
+		ctx, cancel := context.WithTimeout(context.Background(), config.Timeout)
+		defer cancel()
+		result, err := bus.Dispatch(ctx, NewQuery("ConformanceResult", Payload{ID: "1", Value: 21}))
+		if err != nil {
+			t.Fatalf("Dispatch() error = %v", err)
+		}
+		if result.ID != "1" || result.Value != 42 {
+			t.Fatalf("Dispatch() = %+v, want {ID:1 Value:42}", result)
+		}
+	})
+
+	t.Run("propagates handler errors", func(t *testing.T) {
+		bus := newBus(t)
+		register(t, bus.RegisterHandler("ConformanceFail", queryHandlerFunc(func(context.Context, Query) (Payload, error) {
+			return Payload{}, errHandler
+		})))
+
+		ctx, cancel := context.WithTimeout(context.Background(), config.Timeout)
+		defer cancel()
+		_, err := bus.Dispatch(ctx, NewQuery("ConformanceFail", Payload{ID: "1"}))
+		if err == nil {
+			t.Fatalf("Dispatch() returned nil error for failing handler")
+		}
+		// Remote buses cannot preserve error identity, only the message.
+		if !errors.Is(err, errHandler) && !strings.Contains(err.Error(), errHandler.Error()) {
+			t.Fatalf("Dispatch() error = %v, want %v", err, errHandler)
+		}
+	})
+
+	t.Run("keeps error classes", func(t *testing.T) {
+		bus := newBus(t)
+		register(t, bus.RegisterHandler("ConformanceNotFound", queryHandlerFunc(func(context.Context, Query) (Payload, error) {
+			return Payload{}, fmt.Errorf("payload %w", domain.ErrNotFound)
+		})))
+
+		ctx, cancel := context.WithTimeout(context.Background(), config.Timeout)
+		defer cancel()
+		_, err := bus.Dispatch(ctx, NewQuery("ConformanceNotFound", Payload{ID: "1"}))
+		if !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("Dispatch() error = %v, want %v", err, domain.ErrNotFound)
+		}
+	})
+
+	t.Run("correlates concurrent queries", func(t *testing.T) {
+		bus := newBus(t)
+		register(t, bus.RegisterHandler("ConformanceConcurrent", double))
+
+		const n = 20
+		var wg sync.WaitGroup
+		errs := make(chan error, n)
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				ctx, cancel := context.WithTimeout(context.Background(), config.Timeout)
+				defer cancel()
+				result, err := bus.Dispatch(ctx, NewQuery("ConformanceConcurrent", Payload{ID: fmt.Sprint(i), Value: i}))
+				if err != nil {
+					errs <- err
+					return
+				}
+				if result.ID != fmt.Sprint(i) || result.Value != 2*i {
+					errs <- fmt.Errorf("query %d answered with %+v", i, result)
+				}
+			}(i)
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			t.Error(err)
+		}
+	})
+
+	t.Run("honours context deadline", func(t *testing.T) {
+		bus := newBus(t)
+		release := make(chan struct{})
+		defer close(release)
+		register(t, bus.RegisterHandler("ConformanceSlow", queryHandlerFunc(func(ctx context.Context, query Query) (Payload, error) {
+			select {
+			case <-release:
+			case <-ctx.Done():
+			}
+			return query.Payload(), nil
+		})))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		if _, err := bus.Dispatch(ctx, NewQuery("ConformanceSlow", Payload{ID: "1"})); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Dispatch() error = %v, want %v", err, context.DeadlineExceeded)
+		}
+	})
+
+	t.Run("refuses cancelled context", func(t *testing.T) {
+		bus := newBus(t)
+		register(t, bus.RegisterHandler("ConformanceCancelled", double))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := bus.Dispatch(ctx, NewQuery("ConformanceCancelled", Payload{ID: "1"})); !errors.Is(err, context.Canceled) {
+			t.Fatalf("Dispatch() error = %v, want %v", err, context.Canceled)
+		}
+	})
+}
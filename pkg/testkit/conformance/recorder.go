@@ -0,0 +1,78 @@
+package conformance
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+type recorder struct {
+	mu       sync.Mutex
+	payloads []Payload
+	notify   chan struct{}
+}
+
+func newRecorder() *recorder {
+	return &recorder{notify: make(chan struct{}, 1)}
+}
+
+func (r *recorder) record(payload Payload) {
+	r.mu.Lock()
+	r.payloads = append(r.payloads, payload)
+	r.mu.Unlock()
+
+	select {
+	case r.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (r *recorder) snapshot() []Payload {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Payload(nil), r.payloads...)
+}
+
+func (r *recorder) count(id string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, payload := range r.payloads {
+		if payload.ID == id {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *recorder) unique() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	seen := make(map[string]bool)
+	for _, payload := range r.payloads {
+		seen[payload.ID] = true
+	}
+	return len(seen)
+}
+
+// waitFor polls until condition holds or the timeout expires.
+func (r *recorder) waitFor(t *testing.T, timeout time.Duration, description string, condition func() bool) {
+	t.Helper()
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	for !condition() {
+		select {
+		case <-r.notify:
+		case <-time.After(10 * time.Millisecond):
+		case <-deadline.C:
+			t.Fatalf("timed out waiting for %s", description)
+		}
+	}
+}
+
+func register(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("RegisterHandler() error = %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package testkit
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	"github.com/mateusmacedo/go-bff/pkg/domain"
+)
+
+type RecordingEventBus[E domain.Event[T], T any] struct {
+	mu        sync.RWMutex
+	handlers  map[string][]application.EventHandler[E, T]
+	published []E
+	failures  map[string]error
+}
+
+func NewRecordingEventBus[E domain.Event[T], T any]() *RecordingEventBus[E, T] {
+	return &RecordingEventBus[E, T]{
+		handlers: make(map[string][]application.EventHandler[E, T]),
+		failures: make(map[string]error),
+	}
+}
+
+func (bus *RecordingEventBus[E, T]) RegisterHandler(eventName string, handler application.EventHandler[E, T]) error {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	bus.handlers[eventName] = append(bus.handlers[eventName], handler)
+	return nil
+}
+
+// Publish records the event and runs every registered handler in order.
+func (bus *RecordingEventBus[E, T]) Publish(ctx context.Context, event E) error {
+	bus.mu.Lock()
+	bus.published = append(bus.published, event)
+	handlers := append([]application.EventHandler[E, T](nil), bus.handlers[event.EventName()]...)
+	failure := bus.failures[event.EventName()]
+	bus.mu.Unlock()
+
+	if failure != nil {
+		return failure
+	}
+
+	var errs []error
+	for _, handler := range handlers {
+		if err := handler.Handle(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (bus *RecordingEventBus[E, T]) FailWith(eventName string, err error) {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	bus.failures[eventName] = err
+}
+
+func (bus *RecordingEventBus[E, T]) Published() []E {
+	bus.mu.RLock()
+	defer bus.mu.RUnlock()
+	return append([]E(nil), bus.published...)
+}
+
+func (bus *RecordingEventBus[E, T]) Reset() {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	bus.published = nil
+}
+
+func (bus *RecordingEventBus[E, T]) ExpectPublished(eventName string) *Expectation[T] {
+	var payloads []T
+	for _, event := range bus.Published() {
+		if event.EventName() == eventName {
+			payloads = append(payloads, event.Payload())
+		}
+	}
+	return newExpectation("event", eventName, payloads)
+}
@@ -0,0 +1,86 @@
+package testkit
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// Expectation asserts on the payloads recorded for one message name, e.g.
+//
+//	eventBus.ExpectPublished("BusTicketBooked").WithPayload(matcher).Once(t)
+type Expectation[T any] struct {
+	kind     string
+	name     string
+	payloads []T
+	filters  []string
+}
+
+func newExpectation[T any](kind, name string, payloads []T) *Expectation[T] {
+	return &Expectation[T]{kind: kind, name: name, payloads: payloads}
+}
+
+func (e *Expectation[T]) WithPayload(match func(payload T) bool) *Expectation[T] {
+	var matched []T
+	for _, payload := range e.payloads {
+		if match(payload) {
+			matched = append(matched, payload)
+		}
+	}
+	return &Expectation[T]{kind: e.kind, name: e.name, payloads: matched, filters: append(e.filters, "matching payload")}
+}
+
+func (e *Expectation[T]) WithPayloadEqual(expected T) *Expectation[T] {
+	filtered := e.WithPayload(func(payload T) bool {
+		return reflect.DeepEqual(payload, expected)
+	})
+	filtered.filters[len(filtered.filters)-1] = "payload equal to " + describe(expected)
+	return filtered
+}
+
+func (e *Expectation[T]) Payloads() []T {
+	return append([]T(nil), e.payloads...)
+}
+
+func (e *Expectation[T]) Times(tb testing.TB, n int) []T {
+	tb.Helper()
+	if len(e.payloads) != n {
+		tb.Errorf("expected %s %q%s %d time(s), got %d", e.kind, e.name, e.describeFilters(), n, len(e.payloads))
+	}
+	return e.Payloads()
+}
+
+func (e *Expectation[T]) Once(tb testing.TB) T {
+	tb.Helper()
+	e.Times(tb, 1)
+	var zero T
+	if len(e.payloads) == 0 {
+		return zero
+	}
+	return e.payloads[0]
+}
+
+func (e *Expectation[T]) Never(tb testing.TB) {
+	tb.Helper()
+	e.Times(tb, 0)
+}
+
+func (e *Expectation[T]) AtLeast(tb testing.TB, n int) []T {
+	tb.Helper()
+	if len(e.payloads) < n {
+		tb.Errorf("expected %s %q%s at least %d time(s), got %d", e.kind, e.name, e.describeFilters(), n, len(e.payloads))
+	}
+	return e.Payloads()
+}
+
+func (e *Expectation[T]) describeFilters() string {
+	description := ""
+	for _, filter := range e.filters {
+		description += " with " + filter
+	}
+	return description
+}
+
+func describe(value interface{}) string {
+	return fmt.Sprintf("%+v", value)
+}
@@ -0,0 +1,69 @@
+package testkit
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mateusmacedo/go-bff/pkg/domain"
+)
+
+func SequentialIDs(prefix string) domain.IDGenerator[string] {
+	var counter atomic.Int64
+	return func() string {
+		return fmt.Sprintf("%s-%d", prefix, counter.Add(1))
+	}
+}
+
+func FixedIDs(ids ...string) domain.IDGenerator[string] {
+	var mu sync.Mutex
+	next := 0
+	return func() string {
+		mu.Lock()
+		defer mu.Unlock()
+		if next >= len(ids) {
+			panic(fmt.Sprintf("testkit: only %d ids were provided", len(ids)))
+		}
+		id := ids[next]
+		next++
+		return id
+	}
+}
+
+type ManualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func (c *ManualClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+func (c *ManualClock) Clock() domain.Clock {
+	return c.Now
+}
+
+func FixedClock(now time.Time) domain.Clock {
+	return func() time.Time {
+		return now
+	}
+}
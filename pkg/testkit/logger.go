@@ -0,0 +1,57 @@
+package testkit
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+type LogEntry struct {
+	Level   string
+	Message string
+	Fields  map[string]interface{}
+}
+
+type Logger struct {
+	tb      testing.TB
+	mu      sync.Mutex
+	entries []LogEntry
+}
+
+// NewLogger records every entry and mirrors it to tb when tb is not nil.
+func NewLogger(tb testing.TB) *Logger {
+	return &Logger{tb: tb}
+}
+
+func (l *Logger) Info(ctx context.Context, msg string, fields map[string]interface{}) {
+	l.record("info", msg, fields)
+}
+
+func (l *Logger) Debug(ctx context.Context, msg string, fields map[string]interface{}) {
+	l.record("debug", msg, fields)
+}
+
+func (l *Logger) Error(ctx context.Context, msg string, fields map[string]interface{}) {
+	l.record("error", msg, fields)
+}
+
+func (l *Logger) Trace(ctx context.Context, msg string, fields map[string]interface{}) {
+	l.record("trace", msg, fields)
+}
+
+func (l *Logger) Entries() []LogEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]LogEntry(nil), l.entries...)
+}
+
+func (l *Logger) record(level, msg string, fields map[string]interface{}) {
+	l.mu.Lock()
+	l.entries = append(l.entries, LogEntry{Level: level, Message: msg, Fields: fields})
+	l.mu.Unlock()
+
+	if l.tb != nil {
+		l.tb.Helper()
+		l.tb.Logf("[%s] %s %v", level, msg, fields)
+	}
+}
@@ -0,0 +1,82 @@
+package testkit
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mateusmacedo/go-bff/pkg/application"
+	"github.com/mateusmacedo/go-bff/pkg/domain"
+)
+
+type stubbedResponse[R any] struct {
+	result R
+	err    error
+}
+
+type RecordingQueryBus[Q domain.Query[D], D any, R any] struct {
+	mu         sync.RWMutex
+	handlers   map[string]application.QueryHandler[Q, D, R]
+	dispatched []Q
+	responses  map[string]stubbedResponse[R]
+}
+
+func NewRecordingQueryBus[Q domain.Query[D], D any, R any]() *RecordingQueryBus[Q, D, R] {
+	return &RecordingQueryBus[Q, D, R]{
+		handlers:  make(map[string]application.QueryHandler[Q, D, R]),
+		responses: make(map[string]stubbedResponse[R]),
+	}
+}
+
+func (bus *RecordingQueryBus[Q, D, R]) RegisterHandler(queryName string, handler application.QueryHandler[Q, D, R]) error {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	bus.handlers[queryName] = handler
+	return nil
+}
+
+// Dispatch records the query and answers with the stubbed response when one
+// was set, otherwise with the registered handler.
+func (bus *RecordingQueryBus[Q, D, R]) Dispatch(ctx context.Context, query Q) (R, error) {
+	bus.mu.Lock()
+	bus.dispatched = append(bus.dispatched, query)
+	handler, found := bus.handlers[query.QueryName()]
+	response, stubbed := bus.responses[query.QueryName()]
+	bus.mu.Unlock()
+
+	if stubbed {
+		return response.result, response.err
+	}
+	if !found {
+		var zero R
+		return zero, ErrNoHandler
+	}
+	return handler.Handle(ctx, query)
+}
+
+func (bus *RecordingQueryBus[Q, D, R]) Respond(queryName string, result R, err error) {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	bus.responses[queryName] = stubbedResponse[R]{result: result, err: err}
+}
+
+func (bus *RecordingQueryBus[Q, D, R]) Dispatched() []Q {
+	bus.mu.RLock()
+	defer bus.mu.RUnlock()
+	return append([]Q(nil), bus.dispatched...)
+}
+
+func (bus *RecordingQueryBus[Q, D, R]) Reset() {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	bus.dispatched = nil
+}
+
+func (bus *RecordingQueryBus[Q, D, R]) ExpectDispatched(queryName string) *Expectation[D] {
+	var payloads []D
+	for _, query := range bus.Dispatched() {
+		if query.QueryName() == queryName {
+			payloads = append(payloads, query.Payload())
+		}
+	}
+	return newExpectation("query", queryName, payloads)
+}